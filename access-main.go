@@ -138,7 +138,7 @@ func doSetAccess(targetURL string, targetPERMS accessPerms) *probe.Error {
 	if err != nil {
 		return err.Trace(targetURL)
 	}
-	if err = clnt.SetBucketAccess(targetPERMS.String()); err != nil {
+	if err = clnt.SetBucketAccess(globalContext, targetPERMS.String()); err != nil {
 		return err.Trace(targetURL, targetPERMS.String())
 	}
 	return nil
@@ -150,7 +150,7 @@ func doGetAccess(targetURL string) (perms accessPerms, err *probe.Error) {
 	if err != nil {
 		return "", err.Trace(targetURL)
 	}
-	acl, err := clnt.GetBucketAccess()
+	acl, err := clnt.GetBucketAccess(globalContext)
 	if err != nil {
 		return "", err.Trace(targetURL)
 	}
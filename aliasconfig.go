@@ -46,7 +46,8 @@ func getAliasURL(aliasedURL string) (string, *probe.Error) {
 	if err != nil {
 		return aliasedURL, err.Trace(aliasedURL)
 	}
-	if strings.HasPrefix(aliasedURL, "https") || strings.HasPrefix(aliasedURL, "http") {
+	if strings.HasPrefix(aliasedURL, "https") || strings.HasPrefix(aliasedURL, "http") || strings.HasPrefix(aliasedURL, client.HTTPSchemePrefix+"http") ||
+		strings.HasPrefix(aliasedURL, client.MemoryScheme+"://") || strings.HasPrefix(aliasedURL, client.SFTPScheme+"://") {
 		return aliasedURL, nil
 	}
 	for hostURL := range config.Hosts {
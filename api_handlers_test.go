@@ -32,8 +32,18 @@ type objectAPIHandler struct {
 	lock   *sync.Mutex
 	bucket string
 	object map[string][]byte
+	// Sources of server-side copies, by target path.
+	copies map[string]string
 }
 
+// objectVersions - versions of objects under ‘folder’, listed with fixed times.
+const objectVersions = `<Version><Key>folder/object1</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest><LastModified>2016-01-03T00:00:00.000Z</LastModified><Size>5</Size></Version>` +
+	`<Version><Key>folder/object1</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><LastModified>2016-01-01T00:00:00.000Z</LastModified><Size>3</Size></Version>` +
+	`<DeleteMarker><Key>folder/object2</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest><LastModified>2016-01-03T00:00:00.000Z</LastModified></DeleteMarker>` +
+	`<Version><Key>folder/object2</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><LastModified>2016-01-01T00:00:00.000Z</LastModified><Size>3</Size></Version>` +
+	`<Version><Key>folder/object3</Key><VersionId>v1</VersionId><IsLatest>true</IsLatest><LastModified>2016-01-03T00:00:00.000Z</LastModified><Size>4</Size></Version>` +
+	`<Version><Key>folder-other/object</Key><VersionId>v1</VersionId><IsLatest>true</IsLatest><LastModified>2016-01-01T00:00:00.000Z</LastModified><Size>5</Size></Version>`
+
 func (h objectAPIHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
@@ -51,9 +61,24 @@ func (h objectAPIHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 			w.Write(response)
 			return
 		}
+		if _, ok := r.URL.Query()["versions"]; ok {
+			response := []byte("<ListVersionsResult xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"><Name>bucket</Name><IsTruncated>false</IsTruncated>" + objectVersions + "</ListVersionsResult>")
+			w.Header().Set("Content-Length", strconv.Itoa(len(response)))
+			w.Write(response)
+			return
+		}
 		fallthrough
 	case r.URL.Path == "/bucket":
-		response := []byte("<ListBucketResult xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"><Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>object0</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents><Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>object1</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents><Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>object2</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents><Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>object3</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents><Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>object4</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents><Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>object5</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents><Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>object6</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents><Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>object7</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents><Delimiter></Delimiter><EncodingType></EncodingType><IsTruncated>false</IsTruncated><Marker></Marker><MaxKeys>1000</MaxKeys><Name>testbucket</Name><NextMarker></NextMarker><Prefix></Prefix></ListBucketResult>")
+		// Listings honor the prefix, so missing objects are not taken for folders.
+		var contents bytes.Buffer
+		for i := 0; i < 8; i++ {
+			key := "object" + strconv.Itoa(i)
+			if !strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+				continue
+			}
+			contents.WriteString("<Contents><ETag>b1946ac92492d2347c6235b4d2611184</ETag><Key>" + key + "</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>22061</Size><Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents>")
+		}
+		response := []byte("<ListBucketResult xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\">" + contents.String() + "<Delimiter></Delimiter><EncodingType></EncodingType><IsTruncated>false</IsTruncated><Marker></Marker><MaxKeys>1000</MaxKeys><Name>testbucket</Name><NextMarker></NextMarker><Prefix></Prefix></ListBucketResult>")
 		w.Header().Set("Content-Length", strconv.Itoa(len(response)))
 		w.Write(response)
 		return
@@ -81,7 +106,17 @@ func (h objectAPIHandler) headHandler(w http.ResponseWriter, r *http.Request) {
 	case r.URL.Path == "/bucket":
 		w.WriteHeader(http.StatusOK)
 		return
+	case r.URL.Query().Get("versionId") != "":
+		// Versions are only listed, any of them exists.
+		w.Header().Set("Content-Length", "3")
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		return
 	case r.URL.Path != "":
+		if _, ok := h.object[filepath.Base(r.URL.Path)]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
 		w.Header().Set("Content-Length", strconv.Itoa(len(h.object[filepath.Base(r.URL.Path)])))
 		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
 		w.Header().Set("ETag", "b1946ac92492d2347c6235b4d2611184")
@@ -137,6 +172,11 @@ func (h objectAPIHandler) putHandler(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		if source := r.Header.Get("X-Amz-Copy-Source"); source != "" {
+			h.copies[r.URL.Path] = source
+			w.Write([]byte("<CopyObjectResult><ETag>\"b1946ac92492d2347c6235b4d2611184\"</ETag></CopyObjectResult>"))
+			return
+		}
 
 		length, err := strconv.Atoi(r.Header.Get("Content-Length"))
 		if err != nil {
@@ -164,5 +204,8 @@ func (h objectAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.headHandler(w, r)
 	case r.Method == "PUT":
 		h.putHandler(w, r)
+	case r.Method == "POST":
+		// No S3 Select.
+		w.WriteHeader(http.StatusNotImplemented)
 	}
 }
@@ -0,0 +1,104 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/mc/pkg/client/archive"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestArchiveCopy(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	source := filepath.Join(root, "source")
+	c.Assert(os.MkdirAll(filepath.Join(source, "folder"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(source, "object1"), []byte("hello"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(source, "folder", "object2"), []byte("world"), 0600), IsNil)
+
+	// Folders are streamed into an archive, complete once closed.
+	archivePath := filepath.Join(root, "archive.tar.gz")
+	session := newSessionV5()
+	session.Header.CommandType = "cp"
+	session.Header.CommandBoolFlags["recursive"] = true
+	session.Header.CommandArgs = []string{source + string(filepath.Separator), archivePath}
+	doCopySession(session)
+	session.Delete()
+	c.Assert(archive.Close(), IsNil)
+
+	// Folders of the archive are copied out like any other.
+	target := filepath.Join(root, "target")
+	session = newSessionV5()
+	session.Header.CommandType = "cp"
+	session.Header.CommandBoolFlags["recursive"] = true
+	session.Header.CommandArgs = []string{archivePath + "/folder/", target + string(filepath.Separator)}
+	doCopySession(session)
+	session.Delete()
+
+	data, e := ioutil.ReadFile(filepath.Join(target, "object2"))
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "world")
+	_, e = os.Stat(filepath.Join(target, "object1"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
+
+func (s *TestSuite) TestArchiveResume(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	source := filepath.Join(root, "source")
+	c.Assert(os.MkdirAll(source, 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(source, "object1"), []byte("hello"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(source, "object2"), []byte("world"), 0600), IsNil)
+
+	// Archives are written anew once resumed, along with entries copied before.
+	archivePath := filepath.Join(root, "archive.tar")
+	session := newSessionV5()
+	session.Header.CommandType = "cp"
+	session.Header.CommandBoolFlags["recursive"] = true
+	session.Header.CommandArgs = []string{source + string(filepath.Separator), archivePath}
+	doPrepareCopyURLs(session, make(chan bool))
+	scanner := bufio.NewScanner(session.NewDataReader())
+	c.Assert(scanner.Scan(), Equals, true)
+	var cpURLs copyURLs
+	c.Assert(json.Unmarshal(scanner.Bytes(), &cpURLs), IsNil)
+	session.Header.LastCopied = cpURLs.SourceContent.URL.String()
+	c.Assert(session.Save(), IsNil)
+	doCopySession(session)
+	session.Delete()
+
+	// Archives are complete on every way out, including failures.
+	completeArchives()
+	for _, name := range []string{"object1", "object2"} {
+		reader, err := getSource(archivePath + "/" + name)
+		c.Assert(err, IsNil)
+		var data bytes.Buffer
+		_, e = data.ReadFrom(reader)
+		c.Assert(e, IsNil)
+		c.Assert(data.Len(), Equals, 5)
+	}
+}
@@ -20,11 +20,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
 	"github.com/minio/minio-xl/pkg/probe"
 )
 
@@ -34,6 +37,22 @@ var (
 			Name:  "help, h",
 			Usage: "Help of cat",
 		},
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Display a specific version of an object.",
+		},
+		cli.StringFlag{
+			Name:  "rewind",
+			Usage: "Display the version of an object as of a date, time or duration ago, e.g. 7d.",
+		},
+		cli.StringFlag{
+			Name:  "offset",
+			Usage: "Start displaying at this byte offset.",
+		},
+		cli.StringFlag{
+			Name:  "length",
+			Usage: "Display at most this many bytes.",
+		},
 	}
 )
 
@@ -62,6 +81,18 @@ EXAMPLES:
    3. Concantenate multiple files to one.
       $ mc {{.Name}} part.* > complete.img
 
+   4. Display a specific version of an object on Amazon S3 cloud storage.
+      $ mc {{.Name}} --version-id 3HL4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY s3.amazonaws.com/mybucket/config.json
+
+   5. Display an object as it was seven days ago.
+      $ mc {{.Name}} --rewind 7d s3/mybucket/config.json
+
+   6. Display 1024 bytes of an object starting at byte 4096, only these bytes are downloaded.
+      $ mc {{.Name}} --offset 4096 --length 1024 s3/mybucket/disk.img
+
+   7. Display a file within a tar archive without extracting it.
+      $ mc {{.Name}} backup.tar/etc/hosts
+
 `,
 }
 
@@ -76,25 +107,66 @@ func checkCatSyntax(ctx *cli.Context) {
 			fatalIf(probe.NewError(errors.New("")), fmt.Sprintf("Unknown flag ‘%s’ passed.", arg))
 		}
 	}
+	if ctx.String("version-id") != "" && ctx.String("rewind") != "" {
+		fatalIf(errInvalidArgument().Trace(), "‘--version-id’ and ‘--rewind’ cannot be used together.")
+	}
+	for _, name := range []string{"offset", "length"} {
+		if _, err := parseByteCount(ctx.String(name)); err != nil {
+			fatalIf(err.Trace(ctx.String(name)), "Unable to parse ‘--"+name+"’.")
+		}
+	}
 }
 
-// catURL displays contents of a URL to stdout.
-func catURL(sourceURL string) *probe.Error {
+// parseByteCount - parse a non negative number of bytes, empty is zero.
+func parseByteCount(value string) (int64, *probe.Error) {
+	if value == "" {
+		return 0, nil
+	}
+	count, e := strconv.ParseInt(value, 10, 64)
+	if e != nil || count < 0 {
+		return 0, errInvalidArgument().Trace(value)
+	}
+	return count, nil
+}
+
+// catURL displays contents of a URL to stdout, optionally a specific version
+// selected by versionID or rewind. Only length bytes from offset are read,
+// everything from offset if length is 0.
+func catURL(sourceURL, versionID, rewind string, offset, length int64) *probe.Error {
 	var reader io.ReadSeeker
 	switch sourceURL {
 	case "-":
+		// Standard input cannot seek, skip and limit while reading.
+		if _, e := io.CopyN(ioutil.Discard, os.Stdin, offset); e != nil && e != io.EOF {
+			return probe.NewError(e)
+		}
 		reader = os.Stdin
 	default:
 		sourceClnt, err := url2Client(sourceURL)
 		if err != nil {
 			return err.Trace(sourceURL)
 		}
-		// Ignore size, since os.Stat() would not return proper size all the
-		// time for local filesystem for example /proc files.
-		reader, err = sourceClnt.Get(0, 0)
+		var content *client.Content
+		content, err = resolveVersion(sourceClnt, versionID, rewind)
 		if err != nil {
 			return err.Trace(sourceURL)
 		}
+		if content != nil {
+			reader, err = sourceClnt.GetVersion(globalContext, content.VersionID, offset, length)
+		} else {
+			// Ignore size, since os.Stat() would not return proper size all the
+			// time for local filesystem for example /proc files.
+			reader, err = sourceClnt.Get(globalContext, offset, length)
+		}
+		if err != nil {
+			return err.Trace(sourceURL)
+		}
+		if closer, ok := reader.(io.Closer); ok {
+			defer closer.Close()
+		}
+	}
+	if length > 0 {
+		return catOut(io.LimitReader(reader, length)).Trace(sourceURL)
 	}
 	return catOut(reader).Trace(sourceURL)
 }
@@ -130,10 +202,14 @@ func mainCat(ctx *cli.Context) {
 	if !ctx.Args().Present() {
 		stdinMode = true
 	}
+	versionID := ctx.String("version-id")
+	rewind := ctx.String("rewind")
+	offset, _ := parseByteCount(ctx.String("offset"))
+	length, _ := parseByteCount(ctx.String("length"))
 
 	// handle std input data.
 	if stdinMode {
-		fatalIf(catOut(os.Stdin).Trace(), "Unable to read from standard input.")
+		fatalIf(catURL("-", "", "", offset, length).Trace(), "Unable to read from standard input.")
 		return
 	}
 
@@ -154,6 +230,6 @@ func mainCat(ctx *cli.Context) {
 	fatalIf(err.Trace(args...), "Unable to parse arguments.")
 
 	for _, url := range URLs {
-		fatalIf(catURL(url).Trace(url), "Unable to read from ‘"+url+"’.")
+		fatalIf(catURL(url, versionID, rewind, offset, length).Trace(url), "Unable to read from ‘"+url+"’.")
 	}
 }
@@ -46,9 +46,43 @@ func (s *TestSuite) TestCat(c *C) {
 	sourceURLs = append(sourceURLs, objectPath)
 	sourceURLs = append(sourceURLs, objectPathServer)
 	for _, sourceURL := range sourceURLs {
-		c.Assert(catURL(sourceURL), IsNil)
+		c.Assert(catURL(sourceURL, "", "", 0, 0), IsNil)
 	}
 
 	objectPath = filepath.Join(root, "object2")
-	c.Assert(catURL(objectPath), Not(IsNil))
+	c.Assert(catURL(objectPath, "", "", 0, 0), Not(IsNil))
+}
+
+func (s *TestSuite) TestTailStart(c *C) {
+	data := []byte("one\ntwo\nthree\n")
+	c.Assert(tailStart(data, 0), Equals, len(data))
+	c.Assert(string(data[tailStart(data, 1):]), Equals, "three\n")
+	c.Assert(string(data[tailStart(data, 2):]), Equals, "two\nthree\n")
+	c.Assert(tailStart(data, 3), Equals, -1)
+	data = []byte("one\ntwo")
+	c.Assert(string(data[tailStart(data, 1):]), Equals, "two")
+}
+
+func (s *TestSuite) TestHeadTail(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	// Lines spanning several ranged reads.
+	defer func(size int64) { lineChunkSize = size }(lineChunkSize)
+	lineChunkSize = 4
+
+	objectPath := filepath.Join(root, "object1")
+	data := "one\ntwo\nthree\nfour\n"
+	err := putTarget(objectPath, bytes.NewReader([]byte(data)), int64(len(data)))
+	c.Assert(err, IsNil)
+
+	c.Assert(headURL(objectPath, 2), IsNil)
+	size, err := tailURL(objectPath, 2)
+	c.Assert(err, IsNil)
+	c.Assert(size, Equals, int64(len(data)))
+
+	c.Assert(headURL(root, 2), Not(IsNil))
+	_, err = tailURL(filepath.Join(root, "object2"), 2)
+	c.Assert(err, Not(IsNil))
 }
@@ -17,14 +17,21 @@
 package main
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"io"
 	"os"
 	"runtime"
 	"strings"
 
 	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/client/archive"
 	"github.com/minio/mc/pkg/client/fs"
+	httpclient "github.com/minio/mc/pkg/client/http"
+	"github.com/minio/mc/pkg/client/memory"
 	"github.com/minio/mc/pkg/client/s3"
+	sftpclient "github.com/minio/mc/pkg/client/sftp"
+	"github.com/minio/mc/pkg/client/webdav"
 	"github.com/minio/minio-xl/pkg/probe"
 )
 
@@ -53,25 +60,207 @@ func getSource(sourceURL string) (reader io.ReadSeeker, err *probe.Error) {
 	if err != nil {
 		return nil, err.Trace(sourceURL)
 	}
-	return sourceClnt.Get(0, 0)
+	return sourceClnt.Get(globalContext, 0, 0)
+}
+
+// getSourceVersion gets a reader for a specific version of an object, the latest if versionID is empty.
+func getSourceVersion(sourceURL, versionID string) (reader io.ReadSeeker, err *probe.Error) {
+	if versionID == "" {
+		return getSource(sourceURL)
+	}
+	sourceClnt, err := url2Client(sourceURL)
+	if err != nil {
+		return nil, err.Trace(sourceURL)
+	}
+	return sourceClnt.GetVersion(globalContext, versionID, 0, 0)
 }
 
 // putTarget writes to URL from reader. If length=-1, read until EOF.
 func putTarget(targetURL string, reader io.ReadSeeker, size int64) *probe.Error {
+	return putTargetWithMetadata(targetURL, reader, size, nil)
+}
+
+// putTargetWithMetadata writes to URL from reader, storing metadata along with it.
+func putTargetWithMetadata(targetURL string, reader io.ReadSeeker, size int64, metadata map[string]string) *probe.Error {
 	targetClnt, err := url2Client(targetURL)
 	if err != nil {
 		return err.Trace(targetURL)
 	}
-	err = targetClnt.Put(reader, size)
+	err = targetClnt.PutWithMetadata(globalContext, reader, size, metadata)
 	if err != nil {
 		return err.Trace(targetURL)
 	}
 	return nil
 }
 
+// maxTransferAttempts - transfers failing verification are tried this many times.
+const maxTransferAttempts = 3
+
+// verifiedMetadata returns metadata of a target along with the MD5 checksum
+// its data is verified against, known for sources whose ETag is one or that
+// were stored with it. Listings do not tell, sources listed with an ETag are
+// stat'ed. Files uploaded to object storage are hashed beforehand, so that
+// the checksum is stored with objects uploaded in parts too.
+func verifiedMetadata(metadata map[string]string, sourceContent *client.Content, targetURL client.URL) map[string]string {
+	sum := sourceContent.MD5
+	if sum == "" {
+		sum = sourceContent.Metadata[client.MetadataMD5]
+	}
+	if sum == "" && (client.IsMD5(sourceContent.ETag) || client.IsMultipartETag(sourceContent.ETag)) {
+		if clnt, err := url2Client(sourceContent.URL.String()); err == nil {
+			var content *client.Content
+			if sourceContent.VersionID != "" {
+				content, err = clnt.StatVersion(globalContext, sourceContent.VersionID)
+			} else {
+				content, err = clnt.Stat(globalContext)
+			}
+			if err == nil && content.ETag == sourceContent.ETag {
+				sum = content.MD5
+				if sum == "" {
+					sum = content.Metadata[client.MetadataMD5]
+				}
+			}
+		}
+	}
+	if sum == "" && sourceContent.URL.Type == client.Filesystem && targetURL.Type == client.Object && sourceContent.Type.IsRegular() {
+		sum = sourceMD5(sourceContent.URL.String())
+	}
+	if sum == "" {
+		return metadata
+	}
+	verified := map[string]string{client.MetadataMD5: sum}
+	for key, value := range metadata {
+		verified[key] = value
+	}
+	return verified
+}
+
+// sourceMD5 returns the hex MD5 checksum of the data of a source, empty if it
+// cannot be read.
+func sourceMD5(sourceURL string) string {
+	reader, err := getSource(sourceURL)
+	if err != nil {
+		return ""
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	hasher := md5.New()
+	if _, e := io.Copy(hasher, reader); e != nil {
+		return ""
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// isArchiveURL returns true if a URL addresses an entry of an archive.
+func isArchiveURL(u client.URL) bool {
+	if u.Type != client.Filesystem {
+		return false
+	}
+	_, _, ok := archive.Split(u.Path)
+	return ok
+}
+
+// isChecksumMismatch returns true if data was corrupted in transfer.
+func isChecksumMismatch(err *probe.Error) bool {
+	_, ok := err.ToGoError().(client.ChecksumMismatch)
+	return ok
+}
+
+// getSourceAttributes returns the file attributes preserved by ‘--preserve’,
+// recorded in metadata of objects and read from the filesystem for files.
+// Targets of preserved links are returned along with them, or on their own
+// without isPreserve.
+func getSourceAttributes(sourceURL string, isPreserve bool) (map[string]string, *probe.Error) {
+	sourceClnt, err := url2Client(sourceURL)
+	if err != nil {
+		return nil, err.Trace(sourceURL)
+	}
+	content, err := sourceClnt.Stat(globalContext)
+	if err != nil {
+		return nil, err.Trace(sourceURL)
+	}
+	keys := []string{client.MetadataSymlink}
+	if isPreserve {
+		keys = append(keys, client.MetadataMode, client.MetadataUID, client.MetadataGID, client.MetadataAtime, client.MetadataMtime)
+	}
+	attributes := make(map[string]string)
+	for _, key := range keys {
+		if value, ok := content.Metadata[key]; ok {
+			attributes[key] = value
+		}
+	}
+	return attributes, nil
+}
+
+// taggedMetadata returns metadata setting tags on upload, along with the data.
+func taggedMetadata(metadata map[string]string, tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return metadata
+	}
+	tagged := map[string]string{client.MetadataTags: tagsToString(tags)}
+	for key, value := range metadata {
+		tagged[key] = value
+	}
+	return tagged
+}
+
+// listObjectClients lists all objects under a prefix recursively and returns a client for each of them.
+// Errors are reported as they happen, a listing error ends the listing.
+func listObjectClients(clnt client.Client) <-chan client.Client {
+	clntCh := make(chan client.Client)
+	go func() {
+		defer close(clntCh)
+		isRecursive := true
+		isIncomplete := false
+		for content := range clnt.List(globalContext, isRecursive, isIncomplete) {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list ‘"+clnt.GetURL().String()+"’.")
+				return
+			}
+			if !content.Type.IsRegular() {
+				continue
+			}
+			objectURL := content.URL.String()
+			objectClnt, err := url2Client(objectURL)
+			if err != nil {
+				errorIf(err.Trace(objectURL), "Unable to initialize target ‘"+objectURL+"’.")
+				continue
+			}
+			clntCh <- objectClnt
+		}
+	}()
+	return clntCh
+}
+
 // getNewClient gives a new client interface
 func getNewClient(urlStr string, auth hostConfig) (client.Client, *probe.Error) {
 	url := client.NewURL(urlStr)
+	if url.Type == client.Object && auth.API == "http" {
+		url.Type = client.HTTP
+	}
+	if url.Type == client.Object && auth.API == "memory" {
+		memoryClient, err := memory.New(urlStr)
+		if err != nil {
+			return nil, err.Trace(urlStr)
+		}
+		return memoryClient, nil
+	}
+	if url.Type == client.Object && auth.API == "webdav" {
+		webdavConfig := new(client.Config)
+		webdavConfig.AccessKeyID = auth.AccessKeyID
+		webdavConfig.SecretAccessKey = auth.SecretAccessKey
+		webdavConfig.AppName = "Minio"
+		webdavConfig.AppVersion = mcVersion
+		webdavConfig.HostURL = urlStr
+		webdavConfig.Debug = globalDebug
+
+		webdavClient, err := webdav.New(webdavConfig)
+		if err != nil {
+			return nil, err.Trace(urlStr)
+		}
+		return webdavClient, nil
+	}
 	switch url.Type {
 	case client.Object: // Minio and S3 compatible cloud storage
 		s3Config := new(client.Config)
@@ -99,8 +288,42 @@ func getNewClient(urlStr string, auth hostConfig) (client.Client, *probe.Error)
 			return nil, err.Trace(urlStr)
 		}
 		return s3Client, nil
+	case client.HTTP: // Read-only web servers
+		httpConfig := new(client.Config)
+		httpConfig.AppName = "Minio"
+		httpConfig.AppVersion = mcVersion
+		httpConfig.HostURL = urlStr
+		httpConfig.Debug = globalDebug
+
+		httpClient, err := httpclient.New(httpConfig)
+		if err != nil {
+			return nil, err.Trace(urlStr)
+		}
+		return httpClient, nil
+	case client.SFTP: // SSH file transfer servers
+		sftpConfig := new(client.Config)
+		sftpConfig.AccessKeyID = auth.AccessKeyID
+		sftpConfig.SecretAccessKey = auth.SecretAccessKey
+		sftpConfig.PrivateKey = auth.PrivateKey
+		sftpConfig.AppName = "Minio"
+		sftpConfig.AppVersion = mcVersion
+		sftpConfig.HostURL = urlStr
+
+		sftpClient, err := sftpclient.New(sftpConfig)
+		if err != nil {
+			return nil, err.Trace(urlStr)
+		}
+		return sftpClient, nil
 	case client.Filesystem:
-		fsClient, err := fs.New(urlStr)
+		// Paths going through tar and zip archives address their entries.
+		if _, _, ok := archive.Split(urlStr); ok {
+			archiveClient, err := archive.New(urlStr)
+			if err != nil {
+				return nil, err.Trace(urlStr)
+			}
+			return archiveClient, nil
+		}
+		fsClient, err := fs.NewWithSymlinks(urlStr, fsSymlinks)
 		if err != nil {
 			return nil, err.Trace(urlStr)
 		}
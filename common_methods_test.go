@@ -18,10 +18,17 @@ package main
 
 import (
 	"bytes"
+	"crypto/md5"
+	"encoding/hex"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
 
 	. "gopkg.in/check.v1"
 )
@@ -67,3 +74,51 @@ func (s *TestSuite) TestCommonMethods(c *C) {
 	_, err = url2Client(objectPathServer)
 	c.Assert(err, IsNil)
 }
+
+func (s *TestSuite) TestVerifyMultipartDownload(c *C) {
+	data := []byte("Hello, World")
+	sum := md5.Sum([]byte("Hello, world"))
+	storedMD5 := hex.EncodeToString(sum[:])
+	// An object uploaded in parts, corrupted since, whose checksum was stored.
+	newServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", "\"5eb63bbbe01eeed093cb22bb8f5acdc3-2\"")
+		w.Header().Set("X-Amz-Meta-Mc-Md5", storedMD5)
+		http.ServeContent(w, r, "", time.Now(), bytes.NewReader(data))
+	}))
+	defer newServer.Close()
+	config, err := loadMcConfig()
+	c.Assert(err, IsNil)
+	config.Hosts[newServer.URL] = config.Hosts[server.URL]
+	c.Assert(saveMcConfig(config), IsNil)
+
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	targetURL := filepath.Join(root, "object")
+
+	// Listings carry no metadata, the stored checksum is looked up.
+	sourceURL := newServer.URL + "/bucket/object"
+	listed := &client.Content{URL: *client.NewURL(sourceURL), ETag: "5eb63bbbe01eeed093cb22bb8f5acdc3-2", Size: int64(len(data))}
+	metadata := verifiedMetadata(nil, listed, *client.NewURL(targetURL))
+	c.Assert(metadata[client.MetadataMD5], Equals, storedMD5)
+
+	reader, err := getSource(sourceURL)
+	c.Assert(err, IsNil)
+	err = putTargetWithMetadata(targetURL, reader, listed.Size, metadata)
+	c.Assert(err, Not(IsNil))
+	c.Assert(isChecksumMismatch(err), Equals, true)
+	_, e = os.Stat(targetURL)
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	// Files are hashed before they are uploaded, so that their checksum is stored.
+	c.Assert(ioutil.WriteFile(targetURL, data, 0600), IsNil)
+	_, content, err := url2Stat(targetURL)
+	c.Assert(err, IsNil)
+	metadata = verifiedMetadata(nil, content, *client.NewURL(server.URL + "/bucket/object"))
+	sum = md5.Sum(data)
+	c.Assert(metadata[client.MetadataMD5], Equals, hex.EncodeToString(sum[:]))
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio-xl/pkg/probe"
 )
@@ -51,6 +52,7 @@ OPERATION:
    remove   Remove a host.
    list     list all hosts.
    add      Add new host.
+   trash    Set trash location of a host, removes it if empty.
 
 FLAGS:
   {{range .Flags}}{{.}}
@@ -75,6 +77,20 @@ EXAMPLES:
    5. Remove host config.
       $ mc config {{.Name}} remove https://s3.amazonaws.com
 
+   6. Move objects removed with ‘rm --trash’ to a dedicated bucket.
+      $ mc config {{.Name}} trash https://s3.amazonaws.com trash-bucket/removed
+
+   7. Read files of a web server that is not object storage, with a plain HTTP client and no keys.
+      $ mc config {{.Name}} add https://dl.example.com "" "" http
+
+   8. Add a WebDAV share, keys are its user name and password. For security reasons turn off bash history
+      $ set +o history
+      $ mc config {{.Name}} add https://dav.example.com alice secret-password webdav
+      $ set -o history
+
+   9. Add an SFTP server for user alice, authenticating with a private key file instead of a password.
+      $ mc config {{.Name}} add sftp://alice@files.example.com alice "" sftp /home/alice/.ssh/id_dropbox
+
 `,
 }
 
@@ -86,6 +102,8 @@ type hostMessage struct {
 	AccessKeyID     string `json:"accessKeyId,omitempty"`
 	SecretAccessKey string `json:"secretAccessKey,omitempty"`
 	API             string `json:"api,omitempty"`
+	PrivateKey      string `json:"privateKey,omitempty"`
+	Trash           string `json:"trash,omitempty"`
 }
 
 // String colorized host message
@@ -97,6 +115,12 @@ func (a hostMessage) String() string {
 			message += console.Colorize("SecretAccessKey", fmt.Sprintf(" %s,", a.SecretAccessKey))
 			message += console.Colorize("API", fmt.Sprintf(" %s", a.API))
 		}
+		if a.PrivateKey != "" {
+			message += console.Colorize("API", fmt.Sprintf(" key: %s", a.PrivateKey))
+		}
+		if a.Trash != "" {
+			message += console.Colorize("Trash", fmt.Sprintf(" trash: %s", a.Trash))
+		}
 		return message
 	}
 	if a.op == "remove" {
@@ -105,6 +129,12 @@ func (a hostMessage) String() string {
 	if a.op == "add" {
 		return console.Colorize("HostMessage", "Added host ‘"+a.Host+"’ successfully.")
 	}
+	if a.op == "trash" {
+		if a.Trash == "" {
+			return console.Colorize("HostMessage", "Removed trash location of host ‘"+a.Host+"’ successfully.")
+		}
+		return console.Colorize("HostMessage", "Set trash location of host ‘"+a.Host+"’ to ‘"+a.Trash+"’ successfully.")
+	}
 	// should never reach here
 	return ""
 }
@@ -126,7 +156,7 @@ func checkConfigHostSyntax(ctx *cli.Context) {
 	if strings.TrimSpace(ctx.Args().First()) == "" {
 		cli.ShowCommandHelpAndExit(ctx, "host", 1) // last argument is exit code
 	}
-	if len(ctx.Args().Tail()) > 4 {
+	if len(ctx.Args().Tail()) > 5 {
 		fatalIf(errDummy().Trace(ctx.Args().Tail()...), "Incorrect number of arguments to host command")
 	}
 	switch strings.TrimSpace(ctx.Args().First()) {
@@ -136,6 +166,8 @@ func checkConfigHostSyntax(ctx *cli.Context) {
 		checkConfigHostImportSyntax(ctx)
 	case "remove":
 		checkConfigHostRemoveSyntax(ctx)
+	case "trash":
+		checkConfigHostTrashSyntax(ctx)
 	case "list":
 	default:
 		cli.ShowCommandHelpAndExit(ctx, "host", 1) // last argument is exit code
@@ -156,24 +188,24 @@ func checkConfigHostImportSyntax(ctx *cli.Context) {
 
 	if !isValidHostURL(newHostURL) {
 		fatalIf(errDummy().Trace(newHostURL),
-			"Invalid host ‘"+newHostURL+"’. Valid options are [http://example.test.io, https://bucket.s3.amazonaws.com].")
+			"Invalid host ‘"+newHostURL+"’. Valid options are [http://example.test.io, https://bucket.s3.amazonaws.com, sftp://user@example.test.io].")
 	}
 	if strings.TrimSpace(credentialsFile) == "" {
 		fatalIf(errDummy().Trace(), "Credentials file cannot be empty.")
 	}
 	if strings.TrimSpace(api) == "" {
-		api = "S3v4"
+		api = defaultAPI(newHostURL)
 	}
-	if strings.TrimSpace(api) != "S3v2" && strings.TrimSpace(api) != "S3v4" {
+	if !isValidAPI(api) {
 		fatalIf(errInvalidArgument().Trace(api),
-			"Unrecognized api version. Valid options are ‘[ S3v4, S3v2 ]’.")
+			"Unrecognized api version. Valid options are ‘[ S3v4, S3v2, http, webdav, sftp ]’.")
 	}
 }
 
 // checkConfigHostAddSyntax - verifies input arguments to 'config host add'.
 func checkConfigHostAddSyntax(ctx *cli.Context) {
 	tailArgs := ctx.Args().Tail()
-	if len(ctx.Args().Tail()) < 3 || len(ctx.Args().Tail()) > 4 {
+	if len(ctx.Args().Tail()) < 3 || len(ctx.Args().Tail()) > 5 {
 		fatalIf(errInvalidArgument().Trace(ctx.Args().Tail()...),
 			"Incorrect number of arguments for host add command.")
 	}
@@ -183,18 +215,28 @@ func checkConfigHostAddSyntax(ctx *cli.Context) {
 	api := tailArgs.Get(3)
 	if !isValidHostURL(newHostURL) {
 		fatalIf(errDummy().Trace(newHostURL),
-			"Invalid host URL: ‘"+newHostURL+"’. Valid options are [http://example.test.io, https://bucket.s3.amazonaws.com].")
+			"Invalid host URL: ‘"+newHostURL+"’. Valid options are [http://example.test.io, https://bucket.s3.amazonaws.com, sftp://user@example.test.io].")
 	}
-	if !isValidKeys(accessKeyID, secretAccessKey) {
+	if strings.TrimSpace(api) == "" {
+		api = defaultAPI(newHostURL)
+	}
+	// SFTP hosts are only reached over SFTP, and only they take a private key file.
+	if (strings.TrimSpace(api) == "sftp") != (client.NewURL(newHostURL).Type == client.SFTP) {
+		fatalIf(errInvalidArgument().Trace(newHostURL, api),
+			"Api ‘sftp’ is only valid for sftp:// hosts and required by them.")
+	}
+	if len(tailArgs) > 4 && strings.TrimSpace(api) != "sftp" {
+		fatalIf(errInvalidArgument().Trace(ctx.Args().Tail()...),
+			"Private key files are only valid for SFTP hosts.")
+	}
+	// WebDAV shares and SFTP servers take any user name and password, or none.
+	if strings.TrimSpace(api) != "webdav" && strings.TrimSpace(api) != "sftp" && !isValidKeys(accessKeyID, secretAccessKey) {
 		fatalIf(errInvalidArgument().Trace(accessKeyID, secretAccessKey),
 			"Invalid access key id/secret access key for ‘"+newHostURL+"’")
 	}
-	if strings.TrimSpace(api) == "" {
-		api = "S3v4"
-	}
-	if strings.TrimSpace(api) != "S3v2" && strings.TrimSpace(api) != "S3v4" {
+	if !isValidAPI(api) {
 		fatalIf(errInvalidArgument().Trace(api),
-			"Unrecognized api version. Valid options are ‘[ S3v4, S3v2 ]’.")
+			"Unrecognized api version. Valid options are ‘[ S3v4, S3v2, http, webdav, sftp ]’.")
 	}
 }
 
@@ -207,7 +249,7 @@ func checkConfigHostRemoveSyntax(ctx *cli.Context) {
 	}
 	if !isValidHostURL(tailArgs.Get(0)) {
 		fatalIf(errDummy().Trace(tailArgs.Get(0)),
-			"Invalid host ‘"+tailArgs.Get(0)+"’. Valid options are [http://example.test.io, https://bucket.s3.amazonaws.com].")
+			"Invalid host ‘"+tailArgs.Get(0)+"’. Valid options are [http://example.test.io, https://bucket.s3.amazonaws.com, sftp://user@example.test.io].")
 	}
 	if strings.TrimSpace(tailArgs.Get(0)) == "https://dl.minio.io:9000" {
 		fatalIf(errDummy().Trace(tailArgs.Get(0)),
@@ -215,6 +257,19 @@ func checkConfigHostRemoveSyntax(ctx *cli.Context) {
 	}
 }
 
+// checkConfigHostTrashSyntax - verifies input arguments to 'config host trash'.
+func checkConfigHostTrashSyntax(ctx *cli.Context) {
+	tailArgs := ctx.Args().Tail()
+	if len(tailArgs) < 1 || len(tailArgs) > 2 {
+		fatalIf(errInvalidArgument().Trace(tailArgs...),
+			"Incorrect number of arguments for trash host command.")
+	}
+	if !isValidHostURL(tailArgs.Get(0)) {
+		fatalIf(errDummy().Trace(tailArgs.Get(0)),
+			"Invalid host ‘"+tailArgs.Get(0)+"’. Valid options are [http://example.test.io, https://bucket.s3.amazonaws.com, sftp://user@example.test.io].")
+	}
+}
+
 func mainConfigHost(ctx *cli.Context) {
 	// Set global flags from context.
 	setGlobalsFromContext(ctx)
@@ -228,6 +283,7 @@ func mainConfigHost(ctx *cli.Context) {
 	console.SetColor("HostMessage", color.New(color.FgGreen, color.Bold))
 	console.SetColor("AccessKeyID", color.New(color.FgBlue, color.Bold))
 	console.SetColor("SecretAccessKey", color.New(color.FgRed, color.Bold))
+	console.SetColor("Trash", color.New(color.FgMagenta, color.Bold))
 
 	arg := ctx.Args().First()
 	tailArgs := ctx.Args().Tail()
@@ -240,12 +296,13 @@ func mainConfigHost(ctx *cli.Context) {
 		secretAccessKey := tailArgs.Get(2)
 		api := tailArgs.Get(3)
 		if strings.TrimSpace(api) == "" {
-			api = "S3v4"
+			api = defaultAPI(hostURL)
 		}
 		hostCfg := hostConfig{
 			AccessKeyID:     accessKeyID,
 			SecretAccessKey: secretAccessKey,
 			API:             api,
+			PrivateKey:      tailArgs.Get(4),
 		}
 		addHost(hostURL, hostCfg) // Add a host with specified credentials.
 	case "import":
@@ -253,7 +310,7 @@ func mainConfigHost(ctx *cli.Context) {
 		credentialsFile := tailArgs.Get(1)
 		api := tailArgs.Get(2)
 		if strings.TrimSpace(api) == "" {
-			api = "S3v4"
+			api = defaultAPI(hostURL)
 		}
 		creds, err := getCredentials(credentialsFile)
 		fatalIf(err.Trace(credentialsFile), "Unable to unmarshal CSV credentials file ‘"+credentialsFile+"’")
@@ -268,6 +325,10 @@ func mainConfigHost(ctx *cli.Context) {
 	case "remove":
 		hostURL := tailArgs.Get(0)
 		removeHost(hostURL) // Remove a host.
+	case "trash":
+		hostURL := tailArgs.Get(0)
+		trash := strings.Trim(tailArgs.Get(1), "/")
+		setHostTrash(hostURL, trash) // Set where ‘rm --trash’ moves objects to.
 	case "list":
 		listHosts() // List all configured hosts.
 	}
@@ -278,7 +339,10 @@ func addHost(hostURL string, hostCfg hostConfig) {
 	conf, err := loadMcConfig()
 	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version ‘"+globalMCConfigVersion+"’.")
 
-	// Add new host.
+	// Add new host, a trash location survives new credentials.
+	if oldHostCfg, ok := conf.Hosts[hostURL]; ok && hostCfg.Trash == "" {
+		hostCfg.Trash = oldHostCfg.Trash
+	}
 	conf.Hosts[hostURL] = hostCfg
 
 	err = saveMcConfig(conf)
@@ -290,6 +354,7 @@ func addHost(hostURL string, hostCfg hostConfig) {
 		AccessKeyID:     hostCfg.AccessKeyID,
 		SecretAccessKey: hostCfg.SecretAccessKey,
 		API:             hostCfg.API,
+		PrivateKey:      hostCfg.PrivateKey,
 	})
 }
 
@@ -307,6 +372,24 @@ func removeHost(hostURL string) {
 	printMsg(hostMessage{op: "remove", Host: hostURL})
 }
 
+// setHostTrash - set trash location of an existing host.
+func setHostTrash(hostURL, trash string) {
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version ‘"+globalMCConfigVersion+"’.")
+
+	hostCfg, ok := conf.Hosts[hostURL]
+	if !ok {
+		fatalIf(errNoMatchingHost(hostURL).Trace(hostURL), "Please add host ‘"+hostURL+"’ first.")
+	}
+	hostCfg.Trash = trash
+	conf.Hosts[hostURL] = hostCfg
+
+	err = saveMcConfig(conf)
+	fatalIf(err.Trace(hostURL), "Unable to update hosts in config version ‘"+globalMCConfigVersion+"’.")
+
+	printMsg(hostMessage{op: "trash", Host: hostURL, Trash: trash})
+}
+
 // listHosts - list all host URLs.
 func listHosts() {
 	conf, err := loadMcConfig()
@@ -319,6 +402,8 @@ func listHosts() {
 			AccessKeyID:     v.AccessKeyID,
 			SecretAccessKey: v.SecretAccessKey,
 			API:             v.API,
+			PrivateKey:      v.PrivateKey,
+			Trash:           v.Trash,
 		})
 	}
 }
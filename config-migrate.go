@@ -68,7 +68,7 @@ func fixConfigV6ForHosts() {
 		url := new(client.URL)
 		for host, hostCfg := range config.Data().(*configV6).Hosts {
 			// Already fixed - move on.
-			if strings.HasPrefix(host, "https") || strings.HasPrefix(host, "http") {
+			if strings.HasPrefix(host, "https") || strings.HasPrefix(host, "http") || strings.HasPrefix(host, client.SFTPScheme+"://") {
 				newConfig.Hosts[host] = hostCfg
 				continue
 			}
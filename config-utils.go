@@ -33,12 +33,32 @@ func isValidKeys(accessKeyID, secretAccessKey string) bool {
 	return false
 }
 
+// isValidAPI - validate api of a host, signature versions of object storage or other protocols.
+func isValidAPI(api string) bool {
+	switch strings.TrimSpace(api) {
+	case "S3v2", "S3v4", "http", "webdav", "sftp":
+		return true
+	}
+	return false
+}
+
+// defaultAPI - api of hosts added without one, SFTP hosts are known by their scheme.
+func defaultAPI(hostURL string) string {
+	if client.NewURL(hostURL).Type == client.SFTP {
+		return "sftp"
+	}
+	return "S3v4"
+}
+
 // isValidHostURL - validate input host url.
 func isValidHostURL(hostURL string) bool {
 	if strings.TrimSpace(hostURL) == "" {
 		return false
 	}
 	url := client.NewURL(hostURL)
+	if url.Type == client.SFTP {
+		return url.Path == "/"
+	}
 	if url.Scheme != "https" && url.Scheme != "http" {
 		return false
 	}
@@ -0,0 +1,223 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// cors specific flags.
+var (
+	corsFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of cors.",
+		},
+	}
+)
+
+// Manage bucket CORS configuration.
+var corsCmd = cli.Command{
+	Name:   "cors",
+	Usage:  "Manage cross-origin resource sharing of buckets.",
+	Action: mainCORS,
+	Flags:  append(corsFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] set TARGET FILE.json
+   mc {{.Name}} [FLAGS] get|remove TARGET
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Allow GET requests from any web site, rules are read from a JSON document.
+      $ cat cors.json
+      {"CORSRules": [{"AllowedOrigins": ["*"], "AllowedMethods": ["GET"], "MaxAgeSeconds": 3000}]}
+      $ mc {{.Name}} set s3/assets cors.json
+
+   2. Save CORS rules of a bucket, the output can be passed back to ‘set’.
+      $ mc {{.Name}} get s3/assets > cors.json
+
+   3. Remove all CORS rules of a bucket.
+      $ mc {{.Name}} remove s3/assets
+`,
+}
+
+// Allowed methods of a CORS rule.
+var corsMethods = map[string]bool{"GET": true, "PUT": true, "POST": true, "DELETE": true, "HEAD": true}
+
+// corsDocument - JSON document of ‘mc cors set|get’.
+type corsDocument struct {
+	CORSRules []client.CORSRule `json:"CORSRules"`
+}
+
+// corsMessage container for CORS messages.
+type corsMessage struct {
+	Status    string            `json:"status"`
+	Operation string            `json:"operation"`
+	URL       string            `json:"url"`
+	CORSRules []client.CORSRule `json:"CORSRules,omitempty"`
+}
+
+// String colorized CORS message.
+func (c corsMessage) String() string {
+	switch c.Operation {
+	case "set":
+		return console.Colorize("CORS", "CORS rules of ‘"+c.URL+"’ are set.")
+	case "remove":
+		return console.Colorize("CORS", "CORS rules of ‘"+c.URL+"’ are removed.")
+	}
+	corsBytes, e := json.MarshalIndent(corsDocument{CORSRules: c.CORSRules}, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(corsBytes)
+}
+
+// JSON jsonified CORS message.
+func (c corsMessage) JSON() string {
+	c.Status = "success"
+	corsMessageBytes, e := json.Marshal(c)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(corsMessageBytes)
+}
+
+// validateCORSRules - reject what S3 would reject, before sending anything.
+func validateCORSRules(rules []client.CORSRule) *probe.Error {
+	if len(rules) == 0 {
+		return errInvalidCORS("at least one rule is required.").Trace()
+	}
+	if len(rules) > 100 {
+		return errInvalidCORS("at most 100 rules are allowed.").Trace(strconv.Itoa(len(rules)))
+	}
+	for i, rule := range rules {
+		ruleName := "rule #" + strconv.Itoa(i+1)
+		if len(rule.ID) > 255 {
+			return errInvalidCORS(ruleName + " has an ID longer than 255 characters.").Trace(rule.ID)
+		}
+		if len(rule.AllowedOrigins) == 0 {
+			return errInvalidCORS(ruleName + " has no allowed origin.").Trace()
+		}
+		for _, origin := range rule.AllowedOrigins {
+			if origin == "" || strings.Count(origin, "*") > 1 {
+				return errInvalidCORS(ruleName + " has an invalid origin ‘" + origin + "’, at most one ‘*’ wildcard is allowed.").Trace(origin)
+			}
+		}
+		if len(rule.AllowedMethods) == 0 {
+			return errInvalidCORS(ruleName + " has no allowed method.").Trace()
+		}
+		for _, method := range rule.AllowedMethods {
+			if !corsMethods[method] {
+				return errInvalidCORS(ruleName + " has an invalid method ‘" + method + "’, please use GET, PUT, POST, DELETE or HEAD.").Trace(method)
+			}
+		}
+		for _, header := range rule.AllowedHeaders {
+			if header == "" || strings.Count(header, "*") > 1 {
+				return errInvalidCORS(ruleName + " has an invalid header ‘" + header + "’, at most one ‘*’ wildcard is allowed.").Trace(header)
+			}
+		}
+		if rule.MaxAgeSeconds < 0 {
+			return errInvalidCORS(ruleName + " has a negative MaxAgeSeconds.").Trace(strconv.Itoa(rule.MaxAgeSeconds))
+		}
+	}
+	return nil
+}
+
+// readCORSFile - read and validate CORS rules from a JSON document.
+func readCORSFile(filename string) ([]client.CORSRule, *probe.Error) {
+	corsBytes, e := ioutil.ReadFile(filename)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	document := corsDocument{}
+	if e = json.Unmarshal(corsBytes, &document); e != nil {
+		return nil, probe.NewError(e)
+	}
+	if err := validateCORSRules(document.CORSRules); err != nil {
+		return nil, err.Trace(filename)
+	}
+	return document.CORSRules, nil
+}
+
+// checkCORSSyntax - validate all the passed arguments.
+func checkCORSSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	switch args.First() {
+	case "set":
+		if len(args) != 3 {
+			cli.ShowCommandHelpAndExit(ctx, "cors", 1) // last argument is exit code.
+		}
+		_, err := readCORSFile(args.Get(2))
+		fatalIf(err.Trace(args.Get(2)), "Unable to read CORS rules from ‘"+args.Get(2)+"’.")
+	case "get", "remove":
+		if len(args) != 2 {
+			cli.ShowCommandHelpAndExit(ctx, "cors", 1) // last argument is exit code.
+		}
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "cors", 1) // last argument is exit code.
+	}
+	if strings.TrimSpace(args.Get(1)) == "" {
+		fatalIf(errInvalidArgument().Trace(args...), "Unable to validate empty argument.")
+	}
+}
+
+// mainCORS - is a handler for mc cors command
+func mainCORS(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'cors' cli arguments.
+	checkCORSSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("CORS", color.New(color.FgGreen, color.Bold))
+
+	args := ctx.Args()
+	operation := args.First()
+
+	targetURL, err := getAliasURL(args.Get(1))
+	fatalIf(err.Trace(args...), "Unable to parse argument ‘"+args.Get(1)+"’.")
+	clnt, err := url2Client(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+	switch operation {
+	case "set":
+		rules, _ := readCORSFile(args.Get(2))
+		err = clnt.SetCORS(globalContext, rules)
+		fatalIf(err.Trace(targetURL), "Unable to set CORS rules of ‘"+targetURL+"’.")
+		printMsg(corsMessage{Operation: operation, URL: targetURL})
+	case "get":
+		rules, err := clnt.GetCORS(globalContext)
+		fatalIf(err.Trace(targetURL), "Unable to get CORS rules of ‘"+targetURL+"’.")
+		printMsg(corsMessage{Operation: operation, URL: targetURL, CORSRules: rules})
+	case "remove":
+		err = clnt.DeleteCORS(globalContext)
+		fatalIf(err.Trace(targetURL), "Unable to remove CORS rules of ‘"+targetURL+"’.")
+		printMsg(corsMessage{Operation: operation, URL: targetURL})
+	}
+}
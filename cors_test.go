@@ -0,0 +1,57 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestReadCORSFile(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "cors-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	filename := filepath.Join(root, "cors.json")
+	e = ioutil.WriteFile(filename, []byte(`{"CORSRules": [{"AllowedOrigins": ["https://*.example.com"], "AllowedMethods": ["GET", "HEAD"], "MaxAgeSeconds": 3000}]}`), 0600)
+	c.Assert(e, IsNil)
+	rules, err := readCORSFile(filename)
+	c.Assert(err, IsNil)
+	c.Assert(rules, DeepEquals, []client.CORSRule{{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{"GET", "HEAD"},
+		MaxAgeSeconds:  3000,
+	}})
+
+	c.Assert(validateCORSRules(nil), Not(IsNil))
+	c.Assert(validateCORSRules([]client.CORSRule{{AllowedMethods: []string{"GET"}}}), Not(IsNil))
+	c.Assert(validateCORSRules([]client.CORSRule{{AllowedOrigins: []string{"*"}}}), Not(IsNil))
+	c.Assert(validateCORSRules([]client.CORSRule{{AllowedOrigins: []string{"*.*"}, AllowedMethods: []string{"GET"}}}), Not(IsNil))
+	c.Assert(validateCORSRules([]client.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"PATCH"}}}), Not(IsNil))
+}
+
+func (s *TestSuite) TestValidateWebsite(c *C) {
+	c.Assert(validateWebsite(client.Website{IndexDocument: "index.html", ErrorDocument: "errors/404.html"}), IsNil)
+	c.Assert(validateWebsite(client.Website{}), Not(IsNil))
+	c.Assert(validateWebsite(client.Website{IndexDocument: "docs/index.html"}), Not(IsNil))
+	c.Assert(validateWebsite(client.Website{IndexDocument: "index.html", ErrorDocument: "/404.html"}), Not(IsNil))
+}
@@ -27,6 +27,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
@@ -47,6 +48,27 @@ var (
 			Name:  "recursive, r",
 			Usage: "Copy recursively.",
 		},
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Copy a specific version of the source object.",
+		},
+		cli.StringFlag{
+			Name:  "rewind",
+			Usage: "Copy source objects as of a date, time or duration ago, e.g. 7d.",
+		},
+		cli.StringFlag{
+			Name:  "tags",
+			Usage: "Tag uploaded objects, in the form key1=value1&key2=value2.",
+		},
+		cli.StringSliceFlag{
+			Name:  "tag",
+			Value: &cli.StringSlice{},
+			Usage: "Copy only source objects with this tag, in the form key=value.",
+		},
+		cli.BoolFlag{
+			Name:  "preserve, a",
+			Usage: "Preserve mode, ownership and timestamps of files.",
+		},
 	}
 )
 
@@ -55,7 +77,7 @@ var cpCmd = cli.Command{
 	Name:   "cp",
 	Usage:  "Copy one or more objects to a target.",
 	Action: mainCopy,
-	Flags:  append(cpFlags, globalFlags...),
+	Flags:  append(append(cpFlags, symlinksFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
    mc {{.Name}} - {{.Usage}}
 
@@ -83,6 +105,40 @@ EXAMPLES:
 
    6. Copy a local folder with space separated characters to Amazon S3 cloud storage.
       $ mc {{.Name}} --recursive 'workdir/documents/May 2014/' s3/miniocloud
+
+   7. Copy a specific version of an object from Amazon S3 cloud storage to local filesystem.
+      $ mc {{.Name}} --version-id 3HL4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY s3/mybucket/report.pdf report.pdf
+
+   8. Copy a folder recursively as it was on the first of January 2016.
+      $ mc {{.Name}} --recursive --rewind 2016-01-01 s3/mybucket/reports/ backup/
+
+   9. Copy a local folder recursively to Amazon S3 cloud storage, tagging all uploaded objects.
+      $ mc {{.Name}} --recursive --tags "project=mc&costcenter=42" backup/ s3/mybucket/backup/
+
+   10. Copy only objects tagged with project ‘mc’ to local filesystem.
+      $ mc {{.Name}} --recursive --tag project=mc s3/mybucket/ backup/
+
+   11. Copy all logs of 2015 from Amazon S3 cloud storage, quote wildcards to keep them from the shell.
+      $ mc {{.Name}} 's3/mybucket/logs/2015-*.log' backup/
+
+   12. Back up a local folder recursively to Amazon S3 cloud storage and restore it, keeping mode, ownership and timestamps of files.
+      $ mc {{.Name}} --recursive --preserve /home/shared/ s3/mybucket/shared/
+      $ mc {{.Name}} --recursive --preserve s3/mybucket/shared/ /home/shared/
+
+   13. Back up a local folder recursively to Amazon S3 cloud storage, keeping symbolic links as links when restored.
+      $ mc {{.Name}} --recursive --preserve-symlinks /home/shared/ s3/mybucket/shared/
+
+   14. Copy a public dataset from a web server to Amazon S3 cloud storage.
+      $ mc {{.Name}} web+https://example.com/dataset.tar.gz s3/mybucket/
+
+   15. Copy all files linked from the directory index of a web server to Amazon S3 cloud storage.
+      $ mc {{.Name}} --recursive web+https://example.com/datasets/ s3/mybucket/datasets/
+
+   16. Extract a folder of a zip archive to Amazon S3 cloud storage, archives are browsed like folders.
+      $ mc {{.Name}} --recursive backup.zip/photos/ s3/mybucket/photos/
+
+   17. Archive a prefix of Amazon S3 cloud storage into a compressed tar archive.
+      $ mc {{.Name}} --recursive s3/mybucket/photos/ photos.tar.gz
 `,
 }
 
@@ -128,8 +184,9 @@ func (c copyStatMessage) String() string {
 	return message
 }
 
-// doCopy - Copy a singe file from source to destination
-func doCopy(cpURLs copyURLs, progressReader *barSend, accountingReader *accounter, cpQueue <-chan bool, wg *sync.WaitGroup, statusCh chan<- copyURLs) {
+// doCopy - Copy a singe file from source to destination,
+// and remove the source afterwards if isMove is set.
+func doCopy(cpURLs copyURLs, isMove bool, progressReader *barSend, accountingReader *accounter, cpQueue <-chan bool, wg *sync.WaitGroup, statusCh chan<- copyURLs) {
 	defer wg.Done() // Notify that this copy routine is done.
 	defer func() {
 		<-cpQueue
@@ -145,44 +202,113 @@ func doCopy(cpURLs copyURLs, progressReader *barSend, accountingReader *accounte
 		progressReader.SetCaption(cpURLs.SourceContent.URL.String() + ": ")
 	}
 
-	reader, err := getSource(cpURLs.SourceContent.URL.String())
-	if err != nil {
-		if !globalQuiet && !globalJSON {
-			progressReader.ErrorGet(cpURLs.SourceContent.Size)
-		}
-		cpURLs.Error = err.Trace(cpURLs.SourceContent.URL.String())
+	// Moves within a filesystem are a simple rename.
+	if isMove && renameLocal(cpURLs) {
+		doMoveMessage(cpURLs, progressReader)
+		cpURLs.Error = nil
 		statusCh <- cpURLs
 		return
 	}
 
-	var newReader io.ReadSeeker
-	if globalQuiet || globalJSON {
-		printMsg(copyMessage{
-			Source: cpURLs.SourceContent.URL.String(),
-			Target: cpURLs.TargetContent.URL.String(),
-			Length: cpURLs.SourceContent.Size,
-		})
-		// No accounting necessary for JSON output.
-		if globalJSON {
-			newReader = reader
+	// Moves within a host copy objects on the server, before removing them.
+	if isMove {
+		copied, err := copyOnServer(cpURLs)
+		if err != nil && isMoved(cpURLs) {
+			// Resumed move whose source was already removed, nothing left to do.
+			doMoveMessage(cpURLs, progressReader)
+			cpURLs.Error = nil
+			statusCh <- cpURLs
+			return
 		}
-		// Proxy reader to accounting reader only during quiet mode.
-		if globalQuiet {
-			newReader = accountingReader.NewProxyReader(reader)
+		if err == nil && copied {
+			err = removeSource(cpURLs)
+		}
+		if err != nil {
+			if !globalQuiet && !globalJSON {
+				progressReader.ErrorPut(cpURLs.SourceContent.Size)
+			}
+			cpURLs.Error = err.Trace(cpURLs.SourceContent.URL.String())
+			statusCh <- cpURLs
+			return
+		}
+		if copied {
+			doMoveMessage(cpURLs, progressReader)
+			statusCh <- cpURLs
+			return
 		}
-	} else {
-		// set up progress
-		newReader = progressReader.NewProxyReader(reader)
 	}
-	if err := putTarget(cpURLs.TargetContent.URL.String(), newReader, cpURLs.SourceContent.Size); err != nil {
+
+	// Transfers failing verification are tried again from the start.
+	metadata := taggedMetadata(verifiedMetadata(cpURLs.TargetMetadata, cpURLs.SourceContent, cpURLs.TargetContent.URL), cpURLs.TargetTags)
+	for attempt := 1; ; attempt++ {
+		reader, err := getSourceVersion(cpURLs.SourceContent.URL.String(), cpURLs.SourceContent.VersionID)
+		if err != nil && isMove && isMoved(cpURLs) {
+			// Resumed move whose source was already removed, nothing left to do.
+			doMoveMessage(cpURLs, progressReader)
+			cpURLs.Error = nil
+			statusCh <- cpURLs
+			return
+		}
+		if err != nil {
+			if !globalQuiet && !globalJSON {
+				progressReader.ErrorGet(cpURLs.SourceContent.Size)
+			}
+			cpURLs.Error = err.Trace(cpURLs.SourceContent.URL.String())
+			statusCh <- cpURLs
+			return
+		}
+
+		var newReader io.ReadSeeker
+		if globalQuiet || globalJSON {
+			if attempt == 1 && isMove {
+				printMsg(moveMessage{
+					Source: cpURLs.SourceContent.URL.String(),
+					Target: cpURLs.TargetContent.URL.String(),
+					Length: cpURLs.SourceContent.Size,
+				})
+			} else if attempt == 1 {
+				printMsg(copyMessage{
+					Source: cpURLs.SourceContent.URL.String(),
+					Target: cpURLs.TargetContent.URL.String(),
+					Length: cpURLs.SourceContent.Size,
+				})
+			}
+			// No accounting necessary for JSON output.
+			if globalJSON {
+				newReader = reader
+			}
+			// Proxy reader to accounting reader only during quiet mode.
+			if globalQuiet {
+				newReader = accountingReader.NewProxyReader(reader)
+			}
+		} else {
+			// set up progress
+			newReader = progressReader.NewProxyReader(reader)
+		}
+		err = putTargetWithMetadata(cpURLs.TargetContent.URL.String(), newReader, cpURLs.SourceContent.Size, metadata)
+		if err == nil {
+			break
+		}
 		if !globalQuiet && !globalJSON {
 			progressReader.ErrorPut(cpURLs.SourceContent.Size)
 		}
+		if isChecksumMismatch(err) && attempt < maxTransferAttempts {
+			continue
+		}
 		cpURLs.Error = err.Trace(cpURLs.TargetContent.URL.String())
 		statusCh <- cpURLs
 		return
 	}
 
+	// Only remove the source once the target is safely written.
+	if isMove {
+		if err := removeSource(cpURLs); err != nil {
+			cpURLs.Error = err.Trace(cpURLs.SourceContent.URL.String())
+			statusCh <- cpURLs
+			return
+		}
+	}
+
 	cpURLs.Error = nil // just for safety
 	statusCh <- cpURLs
 }
@@ -207,6 +333,18 @@ func doPrepareCopyURLs(session *sessionV5, trapCh <-chan bool) {
 	// Access recursive flag inside the session header.
 	isRecursive := session.Header.CommandBoolFlags["recursive"]
 
+	// Access version flags inside the session header.
+	versionID := session.Header.CommandStringFlags["version-id"]
+	rewind := session.Header.CommandStringFlags["rewind"]
+
+	// Access tag flags inside the session header, both were validated before.
+	targetTags, _ := parseTagsString(session.Header.CommandStringFlags["tags"])
+	tagFilter, _ := parseTagsString(session.Header.CommandStringFlags["tag"])
+
+	// Access preserve flags inside the session header.
+	isPreserve := session.Header.CommandBoolFlags["preserve"]
+	isPreserveSymlinks := session.Header.CommandStringFlags["symlinks"] == symlinksPreserve
+
 	// Create a session data file to store the processed URLs.
 	dataFP := session.NewDataWriter()
 
@@ -215,7 +353,7 @@ func doPrepareCopyURLs(session *sessionV5, trapCh <-chan bool) {
 		scanBar = scanBarFactory()
 	}
 
-	URLsCh := prepareCopyURLs(sourceURLs, targetURL, isRecursive)
+	URLsCh := prepareCopyURLs(sourceURLs, targetURL, isRecursive, rewind)
 	done := false
 
 	for done == false {
@@ -225,6 +363,9 @@ func doPrepareCopyURLs(session *sessionV5, trapCh <-chan bool) {
 				done = true
 				break
 			}
+			if globalContext.Err() != nil { // Interrupted, scanning was aborted.
+				break
+			}
 			if cpURLs.Error != nil {
 				// Print in new line and adjust to top so that we don't print over the ongoing scan bar
 				if !globalQuiet && !globalJSON {
@@ -238,10 +379,47 @@ func doPrepareCopyURLs(session *sessionV5, trapCh <-chan bool) {
 				break
 			}
 
-			jsonData, err := json.Marshal(cpURLs)
+			matched, err := matchURLTags(cpURLs.SourceContent.URL.String(), tagFilter)
 			if err != nil {
+				if !globalQuiet && !globalJSON {
+					console.Eraseline()
+				}
+				errorIf(err.Trace(cpURLs.SourceContent.URL.String()), "Unable to get tags of ‘"+cpURLs.SourceContent.URL.String()+"’.")
+				break
+			}
+			if !matched {
+				break
+			}
+
+			// Recursive listings carry the versions as of rewind already.
+			if versionID != "" || (rewind != "" && !isRecursive) {
+				cpURLs = prepareCopyVersion(cpURLs, versionID, rewind)
+				if cpURLs.Error != nil {
+					if !globalQuiet && !globalJSON {
+						console.Eraseline()
+					}
+					errorIf(cpURLs.Error.Trace(), "Unable to prepare URL for copying.")
+					break
+				}
+			}
+
+			// Preserved links are empty, so are the objects they are stored as.
+			if isPreserve || (isPreserveSymlinks && cpURLs.SourceContent.Size == 0) {
+				cpURLs.TargetMetadata, err = getSourceAttributes(cpURLs.SourceContent.URL.String(), isPreserve)
+				if err != nil {
+					if !globalQuiet && !globalJSON {
+						console.Eraseline()
+					}
+					errorIf(err.Trace(cpURLs.SourceContent.URL.String()), "Unable to get attributes of ‘"+cpURLs.SourceContent.URL.String()+"’.")
+					break
+				}
+			}
+
+			cpURLs.TargetTags = targetTags
+			jsonData, e := json.Marshal(cpURLs)
+			if e != nil {
 				session.Delete()
-				fatalIf(probe.NewError(err), "Unable to prepare URL for copying. Error in JSON marshaling.")
+				fatalIf(probe.NewError(e), "Unable to prepare URL for copying. Error in JSON marshaling.")
 			}
 			fmt.Fprintln(dataFP, string(jsonData))
 			if !globalQuiet && !globalJSON {
@@ -251,13 +429,17 @@ func doPrepareCopyURLs(session *sessionV5, trapCh <-chan bool) {
 			totalBytes += cpURLs.SourceContent.Size
 			totalObjects++
 		case <-trapCh:
-			// Print in new line and adjust to top so that we don't print over the ongoing scan bar
-			if !globalQuiet && !globalJSON {
-				console.Eraseline()
-			}
-			session.Delete() // If we are interrupted during the URL scanning, we drop the session.
-			os.Exit(0)
+			done = true
+		}
+	}
+	// Scanning may have ended early as well, once its listings were aborted.
+	if globalContext.Err() != nil {
+		// Print in new line and adjust to top so that we don't print over the ongoing scan bar
+		if !globalQuiet && !globalJSON {
+			console.Eraseline()
 		}
+		session.Delete() // If we are interrupted during the URL scanning, we drop the session.
+		os.Exit(0)
 	}
 	session.Header.TotalBytes = totalBytes
 	session.Header.TotalObjects = totalObjects
@@ -267,10 +449,17 @@ func doPrepareCopyURLs(session *sessionV5, trapCh <-chan bool) {
 func doCopySession(session *sessionV5) {
 	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM)
 
+	// Filesystem sources handle links the same way when resumed.
+	setSymlinks(session.Header.CommandStringFlags["symlinks"])
+
 	if !session.HasData() {
 		doPrepareCopyURLs(session, trapCh)
 	}
 
+	// Sources are removed once copied for ‘mv’, along with the folders they leave empty.
+	isMove := session.Header.CommandType == "mv"
+	moved := new(movedFolders)
+
 	// Enable accounting reader by default.
 	accntReader := newAccounter(session.Header.TotalBytes)
 
@@ -285,6 +474,8 @@ func doCopySession(session *sessionV5) {
 	// isCopied returns true if an object has been already copied
 	// or not. This is useful when we resume from a session.
 	isCopied := isCopiedFactory(session.Header.LastCopied)
+	// Only entries before all unfinished ones are saved as copied.
+	prefix := new(copiedPrefix)
 
 	wg := new(sync.WaitGroup)
 	// Limit number of copy routines based on available CPU resources.
@@ -294,57 +485,70 @@ func doCopySession(session *sessionV5) {
 	// Status channel for receiveing copy return status.
 	statusCh := make(chan copyURLs)
 
-	// Go routine to monitor doCopy status and signal traps.
+	// Go routine to monitor doCopy status.
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		for {
-			select {
-			case cpURLs, ok := <-statusCh: // Receive status.
-				if !ok { // We are done here. Top level function has returned.
+			// Receive status.
+			cpURLs, ok := <-statusCh
+			if !ok { // We are done here. Top level function has returned.
+				if globalContext.Err() != nil {
+					// Interrupted, copies in progress have returned, save the session.
 					if !globalQuiet && !globalJSON {
-						progressReader.Finish()
+						console.Eraseline()
 					}
-					if globalQuiet {
-						accntStat := accntReader.Stat()
-						cpStatMessage := copyStatMessage{
-							Total:       accntStat.Total,
-							Transferred: accntStat.Transferred,
-							Speed:       accntStat.Speed,
-						}
-						console.Println(console.Colorize("Copy", cpStatMessage.String()))
+					session.CloseAndDie()
+				}
+				if !globalQuiet && !globalJSON {
+					progressReader.Finish()
+				}
+				if globalQuiet {
+					accntStat := accntReader.Stat()
+					cpStatMessage := copyStatMessage{
+						Total:       accntStat.Total,
+						Transferred: accntStat.Transferred,
+						Speed:       accntStat.Speed,
 					}
-					return
+					console.Println(console.Colorize("Copy", cpStatMessage.String()))
 				}
-				if cpURLs.Error == nil {
-					session.Header.LastCopied = cpURLs.SourceContent.URL.String()
+				return
+			}
+			if cpURLs.Error == nil {
+				if isMove {
+					moved.add(cpURLs)
+				}
+				if lastCopied, ok := prefix.done(cpURLs.index, cpURLs.SourceContent.URL.String()); ok {
+					session.Header.LastCopied = lastCopied
 					session.Save()
-				} else {
-					// Print in new line and adjust to top so that we don't print over the ongoing progress bar
-					if !globalQuiet && !globalJSON {
-						console.Eraseline()
-					}
-					errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
-						fmt.Sprintf("Failed to copy ‘%s’.", cpURLs.SourceContent.URL.String()))
-					// for all non critical errors we can continue for the remaining files
-					switch cpURLs.Error.ToGoError().(type) {
-					// handle this specifically for filesystem related errors.
-					case client.BrokenSymlink:
-						continue
-					case client.TooManyLevelsSymlink:
-						continue
-					case client.PathNotFound:
-						continue
-					case client.PathInsufficientPermission:
-						continue
-					}
-					// for critical errors we should exit. Session can be resumed after the user figures out the problem
-					session.CloseAndDie()
 				}
-			case <-trapCh: // Receive interrupt notification.
+			} else if globalContext.Err() != nil {
+				// Aborted upon interrupt, copied again once resumed.
+				continue
+			} else {
+				// Print in new line and adjust to top so that we don't print over the ongoing progress bar
 				if !globalQuiet && !globalJSON {
 					console.Eraseline()
 				}
+				operation := "copy"
+				if isMove {
+					operation = "move"
+				}
+				errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
+					fmt.Sprintf("Failed to %s ‘%s’.", operation, cpURLs.SourceContent.URL.String()))
+				// for all non critical errors we can continue for the remaining files
+				switch cpURLs.Error.ToGoError().(type) {
+				// handle this specifically for filesystem related errors.
+				case client.BrokenSymlink:
+					continue
+				case client.TooManyLevelsSymlink:
+					continue
+				case client.PathNotFound:
+					continue
+				case client.PathInsufficientPermission:
+					continue
+				}
+				// for critical errors we should exit. Session can be resumed after the user figures out the problem
 				session.CloseAndDie()
 			}
 		}
@@ -358,9 +562,23 @@ func doCopySession(session *sessionV5) {
 		defer close(statusCh)
 
 		for scanner.Scan() {
+			// Nothing more is copied once interrupted.
+			if globalContext.Err() != nil {
+				break
+			}
 			var cpURLs copyURLs
 			json.Unmarshal([]byte(scanner.Text()), &cpURLs)
-			if isCopied(cpURLs.SourceContent.URL.String()) {
+			cpURLs.index = prefix.add()
+			// Archives are written anew, copies into them resume from the start.
+			isArchive := isArchiveURL(cpURLs.TargetContent.URL)
+			if isArchive && isMove && session.Header.LastCopied != "" {
+				fatalIf(errArchiveMoveResume(cpURLs.TargetContent.URL.String()).Trace(), "Unable to resume session.")
+			}
+			if isCopied(cpURLs.SourceContent.URL.String()) && !isArchive {
+				if isMove {
+					moved.add(cpURLs)
+				}
+				prefix.done(cpURLs.index, cpURLs.SourceContent.URL.String())
 				doCopyFake(cpURLs, progressReader)
 			} else {
 				// Wait for other copy routines to
@@ -370,12 +588,20 @@ func doCopySession(session *sessionV5) {
 				// Account for each copy routines we start.
 				copyWg.Add(1)
 				// Do copying in background concurrently.
-				go doCopy(cpURLs, progressReader, accntReader, cpQueue, copyWg, statusCh)
+				go doCopy(cpURLs, isMove, progressReader, accntReader, cpQueue, copyWg, statusCh)
 			}
 		}
 		copyWg.Wait()
 	}()
 	wg.Wait()
+
+	// Moved folders leave empty folders behind.
+	if isMove && session.Header.CommandBoolFlags["recursive"] {
+		sourceURLs := session.Header.CommandArgs[:len(session.Header.CommandArgs)-1]
+		for _, sourceURL := range sourceURLs {
+			moved.removeEmpty(sourceURL)
+		}
+	}
 }
 
 // mainCopy is the entry point for cp command.
@@ -383,6 +609,11 @@ func mainCopy(ctx *cli.Context) {
 	// Set global flags from context.
 	setGlobalsFromContext(ctx)
 
+	// Links are handled as requested from the syntax check onwards.
+	symlinks, err := symlinksFromContext(ctx)
+	fatalIf(err.Trace(), "Only one of ‘--follow-symlinks’, ‘--skip-symlinks’ and ‘--preserve-symlinks’ can be used.")
+	setSymlinks(symlinks)
+
 	// check 'copy' cli arguments.
 	checkCopySyntax(ctx)
 
@@ -392,6 +623,22 @@ func mainCopy(ctx *cli.Context) {
 	session := newSessionV5()
 	session.Header.CommandType = "cp"
 	session.Header.CommandBoolFlags["recursive"] = ctx.Bool("recursive")
+	session.Header.CommandBoolFlags["preserve"] = ctx.Bool("preserve")
+	session.Header.CommandStringFlags["symlinks"] = symlinks
+	session.Header.CommandStringFlags["version-id"] = ctx.String("version-id")
+	if rewind := ctx.String("rewind"); rewind != "" {
+		// Save an absolute time, so that resumed sessions copy the same versions.
+		t, err := parseRewind(rewind)
+		if err != nil {
+			session.Delete()
+			fatalIf(err.Trace(rewind), "Unable to parse ‘--rewind’ value.")
+		}
+		session.Header.CommandStringFlags["rewind"] = t.Format(time.RFC3339Nano)
+	}
+	targetTags, _ := parseTagsString(ctx.String("tags"))
+	session.Header.CommandStringFlags["tags"] = tagsToString(targetTags)
+	tagFilter, _ := parseTags(ctx.StringSlice("tag"))
+	session.Header.CommandStringFlags["tag"] = tagsToString(tagFilter)
 
 	var e error
 	if session.Header.RootPath, e = os.Getwd(); e != nil {
@@ -400,8 +647,7 @@ func mainCopy(ctx *cli.Context) {
 	}
 
 	// extract URLs.
-	var err *probe.Error
-	if session.Header.CommandArgs, err = args2URLs(ctx.Args()); err != nil {
+	if session.Header.CommandArgs, err = args2CopyURLs(ctx.Args()); err != nil {
 		session.Delete()
 		fatalIf(err.Trace(ctx.Args()...), "One or more unknown URL types passed.")
 	}
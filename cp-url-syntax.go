@@ -29,7 +29,7 @@ func checkCopySyntax(ctx *cli.Context) {
 	}
 
 	// extract URLs.
-	URLs, err := args2URLs(ctx.Args())
+	URLs, err := args2CopyURLs(ctx.Args())
 	fatalIf(err.Trace(ctx.Args()...), fmt.Sprintf("Argument parsing failed."))
 
 	if len(URLs) < 2 {
@@ -40,6 +40,27 @@ func checkCopySyntax(ctx *cli.Context) {
 	tgtURL := URLs[len(URLs)-1]
 	isRecursive := ctx.Bool("recursive")
 
+	// A version id identifies exactly one object.
+	if ctx.String("version-id") != "" {
+		if ctx.String("rewind") != "" {
+			fatalIf(errInvalidArgument().Trace(), "‘--version-id’ and ‘--rewind’ cannot be used together.")
+		}
+		if len(srcURLs) != 1 || isRecursive {
+			fatalIf(errInvalidArgument().Trace(), "‘--version-id’ can only be used with a single source object.")
+		}
+	}
+
+	// Tags can only be set on object storage targets.
+	if ctx.String("tags") != "" {
+		_, err := parseTagsString(ctx.String("tags"))
+		fatalIf(err.Trace(ctx.String("tags")), "Unable to parse tags.")
+		if client.NewURL(tgtURL).Type != client.Object {
+			fatalIf(errInvalidArgument().Trace(tgtURL), "‘--tags’ requires an object storage target.")
+		}
+	}
+	_, err = parseTags(ctx.StringSlice("tag"))
+	fatalIf(err.Trace(ctx.StringSlice("tag")...), "Unable to parse tag filter.")
+
 	/****** Generic Invalid Rules *******/
 	// Check if bucket name is passed for URL type arguments.
 	url := client.NewURL(tgtURL)
@@ -78,7 +99,7 @@ func checkCopySyntaxTypeA(srcURLs []string, tgtURL string) {
 	_, srcContent, err := url2Stat(srcURL)
 	fatalIf(err.Trace(srcURL), "Unable to stat source ‘"+srcURL+"’.")
 
-	if !srcContent.Type.IsRegular() {
+	if !isCopyable(srcContent) {
 		fatalIf(errInvalidArgument().Trace(), "Source ‘"+srcURL+"’ is not a file.")
 	}
 }
@@ -93,7 +114,7 @@ func checkCopySyntaxTypeB(srcURLs []string, tgtURL string) {
 	_, srcContent, err := url2Stat(srcURL)
 	fatalIf(err.Trace(srcURL), "Unable to stat source ‘"+srcURL+"’.")
 
-	if !srcContent.Type.IsRegular() {
+	if !isCopyable(srcContent) {
 		fatalIf(errInvalidArgument().Trace(srcURL), "Source ‘"+srcURL+"’ is not a file.")
 	}
 
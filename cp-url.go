@@ -25,9 +25,13 @@ import (
 )
 
 type copyURLs struct {
-	SourceContent *client.Content
-	TargetContent *client.Content
-	Error         *probe.Error `json:"-"`
+	SourceContent  *client.Content
+	TargetContent  *client.Content
+	TargetTags     map[string]string `json:",omitempty"`
+	TargetMetadata map[string]string `json:",omitempty"`
+	Error          *probe.Error      `json:"-"`
+	// Position of the entry in its session, see copiedPrefix.
+	index int
 }
 
 type copyURLsType uint8
@@ -99,7 +103,7 @@ func prepareCopyURLsTypeA(sourceURL string, targetURL string) copyURLs {
 		// Source does not exist or insufficient privileges.
 		return copyURLs{Error: err.Trace(sourceURL)}
 	}
-	if !sourceContent.Type.IsRegular() {
+	if !isCopyable(sourceContent) {
 		// Source is not a regular file
 		return copyURLs{Error: errInvalidSource(sourceURL).Trace(sourceURL)}
 	}
@@ -121,7 +125,7 @@ func prepareCopyURLsTypeB(sourceURL string, targetURL string) copyURLs {
 		return copyURLs{Error: err.Trace(sourceURL)}
 	}
 
-	if !sourceContent.Type.IsRegular() {
+	if !isCopyable(sourceContent) {
 		if sourceContent.Type.IsDir() {
 			return copyURLs{Error: errSourceIsDir(sourceURL).Trace(sourceURL)}
 		}
@@ -143,7 +147,7 @@ func makeCopyContentTypeB(sourceContent *client.Content, targetURL string) copyU
 
 // SINGLE SOURCE - Type C: copy(d1..., d2) -> []copy(d1/f, d1/d2/f) -> []A
 // prepareCopyRecursiveURLTypeC - prepares target and source URLs for copying.
-func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool) <-chan copyURLs {
+func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool, rewind string) <-chan copyURLs {
 	copyURLsCh := make(chan copyURLs)
 	go func(sourceURL, targetURL string, copyURLsCh chan copyURLs) {
 		defer close(copyURLsCh)
@@ -154,14 +158,25 @@ func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool) <-chan
 			return
 		}
 
-		for sourceContent := range sourceClient.List(isRecursive, false) {
+		// Rewound sources are the versions current back then, removed objects included.
+		sourceContents := sourceClient.List(globalContext, isRecursive, false)
+		if rewind != "" {
+			t, err := parseRewind(rewind)
+			if err != nil {
+				copyURLsCh <- copyURLs{Error: err.Trace(rewind)}
+				return
+			}
+			sourceContents = versionsAt(sourceClient, isRecursive, t)
+		}
+
+		for sourceContent := range sourceContents {
 			if sourceContent.Err != nil {
 				// Listing failed.
 				copyURLsCh <- copyURLs{Error: sourceContent.Err.Trace(sourceClient.GetURL().String())}
 				continue
 			}
 
-			if !sourceContent.Type.IsRegular() {
+			if !isCopyable(sourceContent) {
 				// Source is not a regular file. Skip it for copy.
 				continue
 			}
@@ -187,12 +202,12 @@ func makeCopyContentTypeC(sourceURL client.URL, sourceContent *client.Content, t
 
 // MULTI-SOURCE - Type D: copy([](f|d...), d) -> []B
 // prepareCopyURLsTypeE - prepares target and source URLs for copying.
-func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive bool) <-chan copyURLs {
+func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive bool, rewind string) <-chan copyURLs {
 	copyURLsCh := make(chan copyURLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan copyURLs) {
 		defer close(copyURLsCh)
 		for _, sourceURL := range sourceURLs {
-			for cpURLs := range prepareCopyURLsTypeC(sourceURL, targetURL, isRecursive) {
+			for cpURLs := range prepareCopyURLsTypeC(sourceURL, targetURL, isRecursive, rewind) {
 				copyURLsCh <- cpURLs
 			}
 		}
@@ -200,22 +215,30 @@ func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive boo
 	return copyURLsCh
 }
 
-// prepareCopyURLs - prepares target and source URLs for copying.
-func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool) <-chan copyURLs {
+// prepareCopyURLs - prepares target and source URLs for copying. Sources
+// copied recursively as of rewind are listed as they were back then.
+func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool, rewind string) <-chan copyURLs {
 	copyURLsCh := make(chan copyURLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan copyURLs) {
 		defer close(copyURLsCh)
+		// Folders whose objects were all removed since exist no more.
+		if isRecursive && rewind != "" {
+			for cURLs := range prepareCopyURLsTypeD(sourceURLs, targetURL, isRecursive, rewind) {
+				copyURLsCh <- cURLs
+			}
+			return
+		}
 		switch guessCopyURLType(sourceURLs, targetURL, isRecursive) {
 		case copyURLsTypeA:
 			copyURLsCh <- prepareCopyURLsTypeA(sourceURLs[0], targetURL)
 		case copyURLsTypeB:
 			copyURLsCh <- prepareCopyURLsTypeB(sourceURLs[0], targetURL)
 		case copyURLsTypeC:
-			for cURLs := range prepareCopyURLsTypeC(sourceURLs[0], targetURL, isRecursive) {
+			for cURLs := range prepareCopyURLsTypeC(sourceURLs[0], targetURL, isRecursive, "") {
 				copyURLsCh <- cURLs
 			}
 		case copyURLsTypeD:
-			for cURLs := range prepareCopyURLsTypeD(sourceURLs, targetURL, isRecursive) {
+			for cURLs := range prepareCopyURLsTypeD(sourceURLs, targetURL, isRecursive, "") {
 				copyURLsCh <- cURLs
 			}
 		default:
@@ -225,3 +248,20 @@ func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool) <-
 
 	return copyURLsCh
 }
+
+// prepareCopyVersion - replace source content with the version selected by versionID or rewind.
+func prepareCopyVersion(cpURLs copyURLs, versionID, rewind string) copyURLs {
+	sourceURL := cpURLs.SourceContent.URL.String()
+	sourceClient, err := url2Client(sourceURL)
+	if err != nil {
+		return copyURLs{Error: err.Trace(sourceURL)}
+	}
+	sourceContent, err := resolveVersion(sourceClient, versionID, rewind)
+	if err != nil {
+		return copyURLs{Error: err.Trace(sourceURL)}
+	}
+	cpURLs.SourceContent.VersionID = sourceContent.VersionID
+	cpURLs.SourceContent.Size = sourceContent.Size
+	cpURLs.SourceContent.Time = sourceContent.Time
+	return cpURLs
+}
@@ -138,7 +138,7 @@ func doDiffMain(firstURL, secondURL string) {
 	}
 	isRecursive := true
 	isIncomplete := false
-	for sourceContent := range firstClient.List(isRecursive, isIncomplete) {
+	for sourceContent := range firstClient.List(globalContext, isRecursive, isIncomplete) {
 		if sourceContent.Err != nil {
 			switch sourceContent.Err.ToGoError().(type) {
 			// handle this specifically for filesystem related errors.
@@ -45,7 +45,7 @@ func objectDifferenceFactory(targetURL string) (objectDifference, *probe.Error)
 	}
 	isIncomplete := false
 	isRecursive := true
-	ch := clnt.List(isRecursive, isIncomplete)
+	ch := clnt.List(globalContext, isRecursive, isIncomplete)
 	current := targetURL
 	reachedEOF := false
 	ok := false
@@ -44,6 +44,7 @@ func fatalIf(err *probe.Error, msg string) {
 	if err == nil {
 		return
 	}
+	completeArchives()
 	if globalJSON {
 		errorMsg := errorMessage{
 			Message: msg,
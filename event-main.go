@@ -0,0 +1,214 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// event specific flags.
+var (
+	eventFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of event.",
+		},
+		cli.StringFlag{
+			Name:  "events",
+			Value: "put,delete",
+			Usage: "Comma separated list of event types: put, delete, get.",
+		},
+		cli.StringFlag{
+			Name:  "prefix",
+			Usage: "Only notify for object names starting with this prefix.",
+		},
+		cli.StringFlag{
+			Name:  "suffix",
+			Usage: "Only notify for object names ending with this suffix.",
+		},
+	}
+)
+
+// Manage bucket notifications.
+var eventCmd = cli.Command{
+	Name:   "event",
+	Usage:  "Manage bucket notifications.",
+	Action: mainEvent,
+	Flags:  append(eventFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] add TARGET ARN
+   mc {{.Name}} [FLAGS] list TARGET [ARN]
+   mc {{.Name}} [FLAGS] remove TARGET ARN
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Notify an SQS queue of all uploads and removals of JPEG images.
+      $ mc {{.Name}} --events put,delete --suffix .jpg add s3/photos arn:aws:sqs:us-east-1:444455556666:photos
+
+   2. List all notification targets of a bucket.
+      $ mc {{.Name}} list s3/photos
+
+   3. Stop notifying an SQS queue.
+      $ mc {{.Name}} remove s3/photos arn:aws:sqs:us-east-1:444455556666:photos
+`,
+}
+
+// eventMessage container for bucket notification messages.
+type eventMessage struct {
+	Status    string   `json:"status"`
+	Operation string   `json:"operation"`
+	URL       string   `json:"url"`
+	ID        string   `json:"id,omitempty"`
+	ARN       string   `json:"arn"`
+	Events    []string `json:"events,omitempty"`
+	Prefix    string   `json:"prefix,omitempty"`
+	Suffix    string   `json:"suffix,omitempty"`
+}
+
+// String colorized bucket notification message.
+func (e eventMessage) String() string {
+	switch e.Operation {
+	case "add":
+		return console.Colorize("Event", "Notifications of ‘"+e.URL+"’ are now sent to ‘"+e.ARN+"’.")
+	case "remove":
+		return console.Colorize("Event", "Notifications of ‘"+e.URL+"’ are no longer sent to ‘"+e.ARN+"’.")
+	}
+	message := console.Colorize("EventARN", e.ARN) + "   " + console.Colorize("Event", strings.Join(e.Events, ","))
+	if e.Prefix != "" {
+		message += "   prefix:" + e.Prefix
+	}
+	if e.Suffix != "" {
+		message += "   suffix:" + e.Suffix
+	}
+	return message
+}
+
+// JSON jsonified bucket notification message.
+func (e eventMessage) JSON() string {
+	e.Status = "success"
+	eventMessageBytes, err := json.Marshal(e)
+	fatalIf(probe.NewError(err), "Unable to marshal into JSON.")
+
+	return string(eventMessageBytes)
+}
+
+// parseEvents - parse a comma separated list of event types.
+func parseEvents(eventsStr string) ([]string, *probe.Error) {
+	var events []string
+	for _, event := range strings.Split(eventsStr, ",") {
+		event = strings.ToLower(strings.TrimSpace(event))
+		switch event {
+		case client.EventCreate, client.EventRemove, client.EventAccess:
+			events = append(events, event)
+		default:
+			return nil, errInvalidEvent(event).Trace(eventsStr)
+		}
+	}
+	return events, nil
+}
+
+// checkEventSyntax - validate all the passed arguments.
+func checkEventSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	switch args.First() {
+	case "add", "remove":
+		if len(args) != 3 {
+			cli.ShowCommandHelpAndExit(ctx, "event", 1) // last argument is exit code.
+		}
+	case "list":
+		if len(args) != 2 && len(args) != 3 {
+			cli.ShowCommandHelpAndExit(ctx, "event", 1) // last argument is exit code.
+		}
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "event", 1) // last argument is exit code.
+	}
+	for _, arg := range args.Tail() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(args...), "Unable to validate empty argument.")
+		}
+	}
+	_, err := parseEvents(ctx.String("events"))
+	fatalIf(err.Trace(ctx.String("events")), "Unable to parse event types.")
+}
+
+// mainEvent - is a handler for mc event command
+func mainEvent(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'event' cli arguments.
+	checkEventSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Event", color.New(color.FgGreen, color.Bold))
+	console.SetColor("EventARN", color.New(color.FgCyan, color.Bold))
+
+	args := ctx.Args()
+	operation := args.First()
+	arn := args.Get(2)
+
+	targetURL, err := getAliasURL(args.Get(1))
+	fatalIf(err.Trace(args...), "Unable to parse argument ‘"+args.Get(1)+"’.")
+	clnt, err := url2Client(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+	switch operation {
+	case "add":
+		events, _ := parseEvents(ctx.String("events"))
+		err = clnt.AddNotification(globalContext, client.NotificationConfig{
+			ARN:    arn,
+			Events: events,
+			Prefix: ctx.String("prefix"),
+			Suffix: ctx.String("suffix"),
+		})
+		fatalIf(err.Trace(targetURL, arn), "Unable to add notification target ‘"+arn+"’ to ‘"+targetURL+"’.")
+		printMsg(eventMessage{Operation: operation, URL: targetURL, ARN: arn, Events: events, Prefix: ctx.String("prefix"), Suffix: ctx.String("suffix")})
+	case "remove":
+		err = clnt.RemoveNotification(globalContext, arn)
+		fatalIf(err.Trace(targetURL, arn), "Unable to remove notification target ‘"+arn+"’ from ‘"+targetURL+"’.")
+		printMsg(eventMessage{Operation: operation, URL: targetURL, ARN: arn})
+	case "list":
+		configs, err := clnt.GetNotifications(globalContext)
+		fatalIf(err.Trace(targetURL), "Unable to list notification targets of ‘"+targetURL+"’.")
+		for _, config := range configs {
+			if arn != "" && config.ARN != arn {
+				continue
+			}
+			printMsg(eventMessage{
+				Operation: operation,
+				URL:       targetURL,
+				ID:        config.ID,
+				ARN:       config.ARN,
+				Events:    config.Events,
+				Prefix:    config.Prefix,
+				Suffix:    config.Suffix,
+			})
+		}
+	}
+}
@@ -0,0 +1,34 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestParseEvents(c *C) {
+	events, err := parseEvents("put, DELETE,get")
+	c.Assert(err, IsNil)
+	c.Assert(events, DeepEquals, []string{client.EventCreate, client.EventRemove, client.EventAccess})
+
+	_, err = parseEvents("put,")
+	c.Assert(err, Not(IsNil))
+	_, err = parseEvents("create")
+	c.Assert(err, Not(IsNil))
+}
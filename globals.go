@@ -18,6 +18,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/console"
 )
@@ -34,6 +36,9 @@ const (
 	globalSessionDir        = "session"
 	globalSharedURLsDataDir = "share"
 
+	// local trash folder, object storage keeps its trash on the host
+	globalTrashDir = "trash"
+
 	// default access and secret key
 	// do not pass accesskeyid and secretaccesskey through cli
 	// users should manually edit them, add a stub entry
@@ -52,6 +57,10 @@ var (
 	// WHEN YOU ADD NEXT GLOBAL FLAG, MAKE SURE TO ALSO UPDATE SESSION CODE AND CODE BELOW.
 )
 
+// Context of all client operations, cancelled upon interrupt so that
+// commands can shut down gracefully.
+var globalContext, globalCancel = context.WithCancel(context.Background())
+
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
 func setGlobals(quiet, debug, json, noColor bool) {
 	globalQuiet = quiet
@@ -0,0 +1,172 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// lineChunkSize - bytes fetched per ranged read while looking for lines.
+var lineChunkSize int64 = 64 * 1024
+
+var (
+	headFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of head.",
+		},
+		cli.IntFlag{
+			Name:  "lines, n",
+			Value: 10,
+			Usage: "Number of lines to display.",
+		},
+	}
+)
+
+// Display first lines of a file.
+var headCmd = cli.Command{
+	Name:   "head",
+	Usage:  "Display first lines of a file.",
+	Action: mainHead,
+	Flags:  append(headFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SOURCE [SOURCE...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Display the first 10 lines of a CSV object on Amazon S3 cloud storage, only these are downloaded.
+      $ mc {{.Name}} s3.amazonaws.com/mybucket/report.csv
+
+   2. Display the first line of several files.
+      $ mc {{.Name}} -n 1 s3/mybucket/reports/2015-01.csv s3/mybucket/reports/2015-02.csv
+`,
+}
+
+// checkHeadSyntax - validate all the passed arguments.
+func checkHeadSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "head", 1) // last argument is exit code.
+	}
+	if ctx.Int("lines") < 0 {
+		fatalIf(errInvalidArgument().Trace(), "‘--lines’ cannot be negative.")
+	}
+}
+
+// readRange - read length bytes from offset of an object.
+func readRange(clnt client.Client, offset, length int64) ([]byte, *probe.Error) {
+	reader, err := clnt.Get(globalContext, offset, length)
+	if err != nil {
+		return nil, err.Trace(clnt.GetURL().String())
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	data, e := ioutil.ReadAll(io.LimitReader(reader, length))
+	if e != nil {
+		return nil, probe.NewError(e).Trace(clnt.GetURL().String())
+	}
+	return data, nil
+}
+
+// statFile - client and size of a URL which must not be a folder.
+func statFile(url string) (client.Client, int64, *probe.Error) {
+	clnt, content, err := url2Stat(url)
+	if err != nil {
+		return nil, 0, err.Trace(url)
+	}
+	if content.Type.IsDir() {
+		return nil, 0, errSourceIsDir(url).Trace(url)
+	}
+	return clnt, content.Size, nil
+}
+
+// printFileHeader - name a file like head and tail do for more than one file.
+func printFileHeader(url string, isFirst bool) {
+	if !isFirst {
+		catOut(strings.NewReader("\n"))
+	}
+	catOut(strings.NewReader("==> " + url + " <==\n"))
+}
+
+// headURL - display the first lines of a URL, fetching chunks until enough lines are read.
+func headURL(url string, lines int) *probe.Error {
+	clnt, size, err := statFile(url)
+	if err != nil {
+		return err.Trace(url)
+	}
+	var offset int64
+	for lines > 0 && offset < size {
+		length := lineChunkSize
+		if size-offset < length {
+			length = size - offset
+		}
+		data, err := readRange(clnt, offset, length)
+		if err != nil {
+			return err.Trace(url)
+		}
+		if len(data) == 0 {
+			break
+		}
+		offset += int64(len(data))
+		end := len(data)
+		for i, b := range data {
+			if b != '\n' {
+				continue
+			}
+			if lines--; lines == 0 {
+				end = i + 1
+				break
+			}
+		}
+		if err = catOut(bytes.NewReader(data[:end])); err != nil {
+			return err.Trace(url)
+		}
+	}
+	return nil
+}
+
+// mainHead is the entry point for head command.
+func mainHead(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'head' cli arguments.
+	checkHeadSyntax(ctx)
+
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
+
+	lines := ctx.Int("lines")
+	for i, url := range URLs {
+		if len(URLs) > 1 {
+			printFileHeader(url, i == 0)
+		}
+		fatalIf(headURL(url, lines).Trace(url), "Unable to read from ‘"+url+"’.")
+	}
+}
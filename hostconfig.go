@@ -28,6 +28,12 @@ type hostConfig struct {
 	AccessKeyID     string `json:"accessKeyId"`
 	SecretAccessKey string `json:"secretAccessKey"`
 	API             string `json:"api"`
+	// Trash is a bucket and optional prefix on this host for ‘rm --trash’,
+	// a ‘.trash’ prefix in the bucket of each object if empty.
+	Trash string `json:"trash,omitempty"`
+	// PrivateKey is a private key file for SFTP hosts, default keys of
+	// the user are tried if neither a key file nor a password is set.
+	PrivateKey string `json:"privateKey,omitempty"`
 }
 
 // getHostConfig retrieves host specific configuration such as access keys, signature type.
@@ -46,6 +52,18 @@ func getHostConfig(URL string) (hostConfig, *probe.Error) {
 		}
 		return hostCfg, nil
 	}
+	// No keys needed for files read from web servers either.
+	if url.Type == client.HTTP {
+		return hostConfig{API: "http"}, nil
+	}
+	// Nor for objects kept in memory.
+	if url.Type == client.Object && url.Scheme == client.MemoryScheme {
+		return hostConfig{API: "memory"}, nil
+	}
+	// SFTP hosts are matched by host and user, keys are optional.
+	if url.Type == client.SFTP {
+		return getSFTPHostConfig(config.Hosts, url), nil
+	}
 	// if host is exact return quickly.
 	if _, ok := config.Hosts[url.String()]; ok {
 		return config.Hosts[url.String()], nil
@@ -60,3 +78,23 @@ func getHostConfig(URL string) (hostConfig, *probe.Error) {
 	// return error if cannot be matched.
 	return hostConfig{}, errNoMatchingHost(URL).Trace(URL)
 }
+
+// getSFTPHostConfig - configuration of the SFTP host matching url, hosts
+// saved with a user only match that user. Without a match default keys of
+// the user are used.
+func getSFTPHostConfig(hosts map[string]hostConfig, url *client.URL) hostConfig {
+	matched := hostConfig{API: "sftp"}
+	for savedURLStr, hostCfg := range hosts {
+		savedURL := client.NewURL(savedURLStr)
+		if savedURL.Type != client.SFTP || savedURL.Host != url.Host {
+			continue
+		}
+		if savedURL.User == url.User {
+			return hostCfg
+		}
+		if savedURL.User == "" {
+			matched = hostCfg
+		}
+	}
+	return matched
+}
@@ -0,0 +1,173 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// legalhold specific flags.
+var (
+	legalHoldFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of legalhold.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Apply to all objects under a prefix recursively.",
+		},
+	}
+)
+
+// Manage object legal holds.
+var legalHoldCmd = cli.Command{
+	Name:   "legalhold",
+	Usage:  "Manage legal hold of objects.",
+	Action: mainLegalHold,
+	Flags:  append(legalHoldFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] set|clear|info TARGET [TARGET...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Place a legal hold on an object, it cannot be removed until the hold is cleared.
+      $ mc {{.Name}} set s3/backups/2016-01.tgz
+
+   2. Clear legal hold of all objects under a prefix recursively.
+      $ mc {{.Name}} --recursive clear s3/backups/ledger/
+
+   3. Display legal hold status of an object.
+      $ mc {{.Name}} info s3/backups/2016-01.tgz
+`,
+}
+
+// legalHoldMessage container for legal hold messages.
+type legalHoldMessage struct {
+	Status    string `json:"status"`
+	Operation string `json:"operation"`
+	URL       string `json:"url"`
+	LegalHold bool   `json:"legalHold"`
+}
+
+// String colorized legal hold message.
+func (l legalHoldMessage) String() string {
+	switch l.Operation {
+	case "set":
+		return console.Colorize("LegalHold", "Legal hold set on ‘"+l.URL+"’.")
+	case "clear":
+		return console.Colorize("LegalHold", "Legal hold cleared on ‘"+l.URL+"’.")
+	}
+	if l.LegalHold {
+		return console.Colorize("LegalHold", "‘"+l.URL+"’ is under legal hold.")
+	}
+	return console.Colorize("LegalHold", "‘"+l.URL+"’ is not under legal hold.")
+}
+
+// JSON jsonified legal hold message.
+func (l legalHoldMessage) JSON() string {
+	l.Status = "success"
+	legalHoldMessageBytes, e := json.Marshal(l)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(legalHoldMessageBytes)
+}
+
+// checkLegalHoldSyntax - validate all the passed arguments.
+func checkLegalHoldSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "legalhold", 1) // last argument is exit code.
+	}
+	switch ctx.Args().First() {
+	case "set", "clear", "info":
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "legalhold", 1) // last argument is exit code.
+	}
+	for _, arg := range ctx.Args().Tail() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+}
+
+// doLegalHold - set, clear or show legal hold of a single object.
+func doLegalHold(clnt client.Client, operation string) *probe.Error {
+	var legalHold bool
+	var err *probe.Error
+	switch operation {
+	case "set":
+		legalHold = true
+		err = clnt.SetLegalHold(globalContext, legalHold)
+	case "clear":
+		err = clnt.SetLegalHold(globalContext, legalHold)
+	case "info":
+		legalHold, err = clnt.GetLegalHold(globalContext)
+	}
+	if err != nil {
+		return err.Trace(clnt.GetURL().String(), operation)
+	}
+	printMsg(legalHoldMessage{
+		Operation: operation,
+		URL:       clnt.GetURL().String(),
+		LegalHold: legalHold,
+	})
+	return nil
+}
+
+// mainLegalHold - is a handler for mc legalhold command
+func mainLegalHold(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'legalhold' cli arguments.
+	checkLegalHoldSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("LegalHold", color.New(color.FgGreen, color.Bold))
+
+	// Set command flags from context.
+	isRecursive := ctx.Bool("recursive")
+	operation := ctx.Args().First()
+
+	URLs, err := args2URLs(ctx.Args().Tail())
+	fatalIf(err.Trace(ctx.Args()...), "One or more unknown URL types passed.")
+	for _, targetURL := range URLs {
+		clnt, err := url2Client(targetURL)
+		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+		if !isRecursive {
+			errorIf(doLegalHold(clnt, operation).Trace(targetURL), "Unable to "+operation+" legal hold of ‘"+targetURL+"’.")
+			continue
+		}
+		for objectClnt := range listObjectClients(clnt) {
+			objectURL := objectClnt.GetURL().String()
+			errorIf(doLegalHold(objectClnt, operation).Trace(objectURL), "Unable to "+operation+" legal hold of ‘"+objectURL+"’.")
+		}
+	}
+}
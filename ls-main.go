@@ -41,6 +41,35 @@ var (
 			Name:  "incomplete, I",
 			Usage: "Remove incomplete uploads.",
 		},
+		cli.BoolFlag{
+			Name:  "versions",
+			Usage: "List all versions of objects.",
+		},
+		cli.StringSliceFlag{
+			Name:  "tag",
+			Value: &cli.StringSlice{},
+			Usage: "List only objects with this tag, in the form key=value.",
+		},
+		cli.BoolFlag{
+			Name:  "long, l",
+			Usage: "Show ETag, storage class and content type of objects.",
+		},
+		cli.StringFlag{
+			Name:  "sort",
+			Usage: "Sort by ‘name’, ‘size’ or ‘time’ instead of listing order.",
+		},
+		cli.BoolFlag{
+			Name:  "reverse",
+			Usage: "Reverse the sort order, sorts by name unless --sort is given.",
+		},
+		cli.BoolFlag{
+			Name:  "bytes",
+			Usage: "Show sizes in bytes.",
+		},
+		cli.BoolFlag{
+			Name:  "summarize",
+			Usage: "Show total number of objects and their size at the end.",
+		},
 	}
 )
 
@@ -77,6 +106,24 @@ EXAMPLES:
     
    6. List incomplete (previously failed) uploads of objects on Amazon S3. 
       $ mc {{.Name}} --incomplete s3/mybucket
+
+   7. List all versions of objects, including delete markers, on Amazon S3.
+      $ mc {{.Name}} --versions s3/mybucket/photos/
+
+   8. List objects tagged with project ‘mc’ recursively.
+      $ mc {{.Name}} --recursive --tag project=mc s3/mybucket
+
+   9. List objects matching a wildcard pattern on Amazon S3 cloud storage.
+      $ mc {{.Name}} 's3/mybucket/photos/2015-0[1-6]-*.jpg'
+
+  10. List the largest objects first with their ETag, storage class and content type.
+      $ mc {{.Name}} --long --sort size --reverse s3/mybucket/photos/
+
+  11. Show total number of objects and their exact size in bytes.
+      $ mc {{.Name}} --recursive --summarize --bytes s3/mybucket
+
+  12. List the contents of a compressed tar archive.
+      $ mc {{.Name}} backup.tar.gz/
 `,
 }
 
@@ -95,11 +142,21 @@ func checkListSyntax(ctx *cli.Context) {
 	URLs, err := args2URLs(ctx.Args())
 	fatalIf(err.Trace(ctx.Args()...), fmt.Sprintf("One or more unknown URL types passed."))
 
+	_, err = parseTags(ctx.StringSlice("tag"))
+	fatalIf(err.Trace(ctx.StringSlice("tag")...), "Unable to parse tag filter.")
+
+	if sortBy := ctx.String("sort"); sortBy != "" && !isValidSortKey(sortBy) {
+		fatalIf(errInvalidArgument().Trace(sortBy),
+			"Unrecognized sort key ‘"+sortBy+"’. Valid options are ‘["+strings.Join(lsSortKeys, ", ")+"]’.")
+	}
+
 	isIncomplete := ctx.Bool("incomplete")
+	// Deleted objects may still have versions.
+	isVersions := ctx.Bool("versions")
 
 	for _, url := range URLs {
 		_, _, err := url2Stat(url)
-		if err != nil && !isURLPrefixExists(url, isIncomplete) {
+		if err != nil && !isVersions && !isURLPrefixExists(url, isIncomplete) {
 			fatalIf(err.Trace(url), "Unable to stat ‘"+url+"’.")
 		}
 	}
@@ -112,6 +169,12 @@ func mainList(ctx *cli.Context) {
 	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
 	console.SetColor("Size", color.New(color.FgYellow))
 	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Version", color.New(color.FgMagenta))
+	console.SetColor("DeleteMarker", color.New(color.FgRed, color.Bold))
+	console.SetColor("StorageClass", color.New(color.FgBlue))
+	console.SetColor("ETag", color.New(color.FgMagenta))
+	console.SetColor("ContentType", color.New(color.FgWhite))
+	console.SetColor("Summary", color.New(color.FgGreen, color.Bold))
 
 	// Set global flags from context.
 	setGlobalsFromContext(ctx)
@@ -122,6 +185,18 @@ func mainList(ctx *cli.Context) {
 	// Set command flags from context.
 	isRecursive := ctx.Bool("recursive")
 	isIncomplete := ctx.Bool("incomplete")
+	isVersions := ctx.Bool("versions")
+	tagFilter, _ := parseTags(ctx.StringSlice("tag"))
+	format := listFormat{
+		isLong:    ctx.Bool("long"),
+		isBytes:   ctx.Bool("bytes"),
+		sortBy:    ctx.String("sort"),
+		isReverse: ctx.Bool("reverse"),
+	}
+	if format.isReverse && format.sortBy == "" {
+		format.sortBy = "name"
+	}
+	summary := listSummaryMessage{isBytes: format.isBytes}
 
 	args := ctx.Args()
 	// mimic operating system tool behavior.
@@ -136,10 +211,13 @@ func mainList(ctx *cli.Context) {
 		clnt, err = url2Client(targetURL)
 		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
 
-		err = doList(clnt, isRecursive, isIncomplete)
+		err = doList(clnt, isRecursive, isIncomplete, isVersions, tagFilter, format, &summary)
 		if err != nil {
 			errorIf(err.Trace(clnt.GetURL().String()), "Unable to list target ‘"+clnt.GetURL().String()+"’.")
 			continue
 		}
 	}
+	if ctx.Bool("summarize") {
+		printMsg(summary)
+	}
 }
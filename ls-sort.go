@@ -0,0 +1,202 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// lsSortChunkSize - number of entries sorted in memory, larger listings
+// are sorted in chunks spilled to temporary files and merged.
+var lsSortChunkSize = 100000
+
+// lsSortKeys - valid values of ‘--sort’.
+var lsSortKeys = []string{"name", "size", "time"}
+
+// isValidSortKey - true if key is a valid value of ‘--sort’.
+func isValidSortKey(key string) bool {
+	for _, sortKey := range lsSortKeys {
+		if key == sortKey {
+			return true
+		}
+	}
+	return false
+}
+
+// contentLess - order of two entries by a sort key, ties are ordered by name.
+func contentLess(sortBy string, isReverse bool) func(a, b contentMessage) bool {
+	less := func(a, b contentMessage) bool {
+		switch sortBy {
+		case "size":
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case "time":
+			if !a.Time.Equal(b.Time) {
+				return a.Time.Before(b.Time)
+			}
+		}
+		return a.Key < b.Key
+	}
+	if isReverse {
+		return func(a, b contentMessage) bool { return less(b, a) }
+	}
+	return less
+}
+
+// contentChunk - entries sorted in memory.
+type contentChunk struct {
+	contents []contentMessage
+	less     func(a, b contentMessage) bool
+}
+
+func (c contentChunk) Len() int           { return len(c.contents) }
+func (c contentChunk) Swap(i, j int)      { c.contents[i], c.contents[j] = c.contents[j], c.contents[i] }
+func (c contentChunk) Less(i, j int) bool { return c.less(c.contents[i], c.contents[j]) }
+
+// contentSorter - sort any number of entries with bounded memory.
+type contentSorter struct {
+	less  func(a, b contentMessage) bool
+	chunk []contentMessage
+	files []*os.File
+}
+
+// newContentSorter - sorter for ‘--sort’ and ‘--reverse’.
+func newContentSorter(sortBy string, isReverse bool) *contentSorter {
+	return &contentSorter{less: contentLess(sortBy, isReverse)}
+}
+
+// Add - add an entry, spilling to a temporary file once a chunk is full.
+func (s *contentSorter) Add(content contentMessage) *probe.Error {
+	s.chunk = append(s.chunk, content)
+	if len(s.chunk) < lsSortChunkSize {
+		return nil
+	}
+	return s.spill().Trace()
+}
+
+// spill - sort the current chunk and write it to a temporary file.
+func (s *contentSorter) spill() *probe.Error {
+	sort.Sort(contentChunk{contents: s.chunk, less: s.less})
+	file, e := ioutil.TempFile("", "mc-ls-")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	s.files = append(s.files, file)
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, content := range s.chunk {
+		if e = encoder.Encode(content); e != nil {
+			return probe.NewError(e).Trace(file.Name())
+		}
+	}
+	if e = writer.Flush(); e != nil {
+		return probe.NewError(e).Trace(file.Name())
+	}
+	if _, e = file.Seek(0, 0); e != nil {
+		return probe.NewError(e).Trace(file.Name())
+	}
+	s.chunk = nil
+	return nil
+}
+
+// Sorted - call fn for every entry in order. Chunks spilled to temporary
+// files are merged, holding only one entry of each of them in memory.
+func (s *contentSorter) Sorted(fn func(contentMessage)) *probe.Error {
+	if len(s.files) == 0 {
+		sort.Sort(contentChunk{contents: s.chunk, less: s.less})
+		for _, content := range s.chunk {
+			fn(content)
+		}
+		return nil
+	}
+	if len(s.chunk) > 0 {
+		if err := s.spill(); err != nil {
+			return err.Trace()
+		}
+	}
+	merger := &contentMerger{less: s.less}
+	for _, file := range s.files {
+		decoder := json.NewDecoder(bufio.NewReader(file))
+		head := contentMessage{}
+		if e := decoder.Decode(&head); e != nil {
+			if e == io.EOF {
+				continue
+			}
+			return probe.NewError(e).Trace(file.Name())
+		}
+		merger.sources = append(merger.sources, contentSource{head: head, decoder: decoder})
+	}
+	heap.Init(merger)
+	for merger.Len() > 0 {
+		source := &merger.sources[0]
+		fn(source.head)
+		// Decode into a new entry, omitted fields must not keep old values.
+		next := contentMessage{}
+		e := source.decoder.Decode(&next)
+		source.head = next
+		switch {
+		case e == io.EOF:
+			heap.Pop(merger)
+		case e != nil:
+			return probe.NewError(e)
+		default:
+			heap.Fix(merger, 0)
+		}
+	}
+	return nil
+}
+
+// Close - remove all temporary files.
+func (s *contentSorter) Close() {
+	for _, file := range s.files {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	s.files = nil
+	s.chunk = nil
+}
+
+// contentSource - next entry of a spilled chunk.
+type contentSource struct {
+	head    contentMessage
+	decoder *json.Decoder
+}
+
+// contentMerger - heap of spilled chunks ordered by their next entry.
+type contentMerger struct {
+	sources []contentSource
+	less    func(a, b contentMessage) bool
+}
+
+func (m contentMerger) Len() int            { return len(m.sources) }
+func (m contentMerger) Swap(i, j int)       { m.sources[i], m.sources[j] = m.sources[j], m.sources[i] }
+func (m contentMerger) Less(i, j int) bool  { return m.less(m.sources[i].head, m.sources[j].head) }
+func (m *contentMerger) Push(x interface{}) { m.sources = append(m.sources, x.(contentSource)) }
+func (m *contentMerger) Pop() interface{} {
+	source := m.sources[len(m.sources)-1]
+	m.sources = m.sources[:len(m.sources)-1]
+	return source
+}
@@ -41,18 +41,53 @@ type contentMessage struct {
 	Time     time.Time `json:"lastModified"`
 	Size     int64     `json:"size"`
 	Key      string    `json:"key"`
+
+	// Set only for versioned listings.
+	VersionID      string `json:"versionId,omitempty"`
+	IsLatest       bool   `json:"isLatest,omitempty"`
+	IsDeleteMarker bool   `json:"isDeleteMarker,omitempty"`
+
+	// Set only where known.
+	ETag         string `json:"etag,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+	ContentType  string `json:"contentType,omitempty"`
+
+	isLong  bool // Print ETag, storage class and content type.
+	isBytes bool // Print sizes in bytes.
+}
+
+// formatSize - human readable size, or exact number of bytes if isBytes.
+func formatSize(size int64, isBytes bool) string {
+	if isBytes {
+		return fmt.Sprintf("%d", size)
+	}
+	return humanize.IBytes(uint64(size))
 }
 
 // String colorized string message.
 func (c contentMessage) String() string {
-	message := console.Colorize("Time", fmt.Sprintf("[%s] ", c.Time.Format(printDate)))
-	message = message + console.Colorize("Size", fmt.Sprintf("%6s ", humanize.IBytes(uint64(c.Size))))
+	message := console.Colorize("Time", fmt.Sprintf("[%s] ", c.Time.Local().Format(printDate)))
+	message = message + console.Colorize("Size", fmt.Sprintf("%6s ", formatSize(c.Size, c.isBytes)))
+	if c.isLong {
+		message = message + console.Colorize("StorageClass", fmt.Sprintf("%-8s ", valueOrDash(c.StorageClass)))
+		message = message + console.Colorize("ETag", fmt.Sprintf("%-32s ", valueOrDash(c.ETag)))
+		message = message + console.Colorize("ContentType", fmt.Sprintf("%-24s ", valueOrDash(c.ContentType)))
+	}
 	message = func() string {
 		if c.Filetype == "folder" {
 			return message + console.Colorize("Dir", fmt.Sprintf("%s", c.Key))
 		}
 		return message + console.Colorize("File", fmt.Sprintf("%s", c.Key))
 	}()
+	if c.VersionID != "" {
+		message = message + console.Colorize("Version", fmt.Sprintf(" (%s)", c.VersionID))
+		if c.IsLatest {
+			message = message + console.Colorize("Version", " [LATEST]")
+		}
+		if c.IsDeleteMarker {
+			message = message + console.Colorize("DeleteMarker", " [DELETED]")
+		}
+	}
 	return message
 }
 
@@ -65,6 +100,56 @@ func (c contentMessage) JSON() string {
 	return string(jsonMessageBytes)
 }
 
+// valueOrDash - placeholder for unknown values of long listings.
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// listSummaryMessage container for totals of a listing.
+type listSummaryMessage struct {
+	Status  string `json:"status"`
+	Objects int64  `json:"objects"`
+	Folders int64  `json:"folders"`
+	Size    int64  `json:"size"`
+
+	isBytes bool
+}
+
+// add - count a listed entry.
+func (s *listSummaryMessage) add(content contentMessage) {
+	if content.Filetype == "folder" {
+		s.Folders++
+		return
+	}
+	s.Objects++
+	s.Size += content.Size
+}
+
+// String colorized listing summary.
+func (s listSummaryMessage) String() string {
+	return console.Colorize("Summary", fmt.Sprintf("Total: %d objects, %d folders, %s", s.Objects, s.Folders, formatSize(s.Size, s.isBytes)))
+}
+
+// JSON jsonified listing summary.
+func (s listSummaryMessage) JSON() string {
+	s.Status = "success"
+	summaryJSONBytes, e := json.Marshal(s)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(summaryJSONBytes)
+}
+
+// listFormat - how entries of a listing are ordered and printed.
+type listFormat struct {
+	isLong    bool
+	isBytes   bool
+	sortBy    string // Listing order if empty.
+	isReverse bool
+}
+
 // parseContent parse client Content container into printer struct.
 func parseContent(c *client.Content) contentMessage {
 	content := contentMessage{}
@@ -79,6 +164,12 @@ func parseContent(c *client.Content) contentMessage {
 	}()
 
 	content.Size = c.Size
+	content.VersionID = c.VersionID
+	content.IsLatest = c.IsLatest
+	content.IsDeleteMarker = c.IsDeleteMarker
+	content.ETag = c.ETag
+	content.StorageClass = c.StorageClass
+	content.ContentType = c.ContentType
 	// Convert OS Type to match console file printing style.
 	content.Key = func() string {
 		switch {
@@ -103,14 +194,42 @@ func parseContent(c *client.Content) contentMessage {
 	return content
 }
 
-// doList - list all entities inside a folder.
-func doList(clnt client.Client, isRecursive, isIncomplete bool) *probe.Error {
+// statContentType - fill in the content type of a listed object, which
+// listings do not report. Costs one request per object on object storage.
+func statContentType(content *client.Content) {
+	if content.ContentType != "" || !content.Type.IsRegular() {
+		return
+	}
+	_, stat, err := url2Stat(content.URL.String())
+	if err != nil {
+		return // Printed as unknown.
+	}
+	content.ContentType = stat.ContentType
+	if content.ETag == "" {
+		content.ETag = stat.ETag
+	}
+}
+
+// doList - list all entities inside a folder, or all their versions if isVersions is set.
+// Only objects carrying all the tags of tagFilter are listed. Entries are
+// printed in listing order unless format asks for sorting, and counted in summary.
+func doList(clnt client.Client, isRecursive, isIncomplete, isVersions bool, tagFilter map[string]string, format listFormat, summary *listSummaryMessage) *probe.Error {
+	var sorter *contentSorter
+	if format.sortBy != "" {
+		sorter = newContentSorter(format.sortBy, format.isReverse)
+		defer sorter.Close()
+	}
+
 	prefixPath := clnt.GetURL().Path
 	separator := string(clnt.GetURL().Separator)
 	if !strings.HasSuffix(prefixPath, separator) {
 		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
 	}
-	for content := range clnt.List(isRecursive, isIncomplete) {
+	contentCh := clnt.List(globalContext, isRecursive, isIncomplete)
+	if isVersions {
+		contentCh = clnt.ListVersions(globalContext, isRecursive)
+	}
+	for content := range contentCh {
 		// fmt.Println(content)
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
@@ -131,12 +250,47 @@ func doList(clnt client.Client, isRecursive, isIncomplete bool) *probe.Error {
 			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
 			continue
 		}
+		if len(tagFilter) > 0 {
+			// Folders do not carry any tags.
+			if content.Type.IsDir() {
+				continue
+			}
+			matched, err := matchURLTags(content.URL.String(), tagFilter)
+			if err != nil {
+				errorIf(err.Trace(content.URL.String()), "Unable to get tags of ‘"+content.URL.String()+"’.")
+				continue
+			}
+			if !matched {
+				continue
+			}
+		}
+		if format.isLong && !isIncomplete && !isVersions {
+			statContentType(content)
+		}
 		contentURL := content.URL.Path
 		contentURL = strings.TrimPrefix(contentURL, prefixPath)
 		content.URL.Path = contentURL
 		parsedContent := parseContent(content)
-		// print colorized or jsonized content info.
-		printMsg(parsedContent)
+		summary.add(parsedContent)
+		if sorter != nil {
+			if err := sorter.Add(parsedContent); err != nil {
+				return err.Trace(clnt.GetURL().String())
+			}
+			continue
+		}
+		printContent(parsedContent, format)
 	}
-	return nil
+	if sorter == nil {
+		return nil
+	}
+	return sorter.Sorted(func(parsedContent contentMessage) {
+		printContent(parsedContent, format)
+	}).Trace(clnt.GetURL().String())
+}
+
+// printContent - print colorized or jsonized content info.
+func printContent(parsedContent contentMessage, format listFormat) {
+	parsedContent.isLong = format.isLong
+	parsedContent.isBytes = format.isBytes
+	printMsg(parsedContent)
 }
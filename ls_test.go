@@ -15,3 +15,46 @@
  */
 
 package main
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestContentSorter(c *C) {
+	now := time.Now()
+	contents := []contentMessage{
+		{Key: "c", Size: 3, Time: now.Add(-time.Hour), VersionID: "v1"},
+		{Key: "a", Size: 5, Time: now},
+		{Key: "e", Size: 1, Time: now.Add(-2 * time.Hour)},
+		{Key: "b", Size: 3, Time: now.Add(time.Hour)},
+		{Key: "d", Size: 4, Time: now.Add(-3 * time.Hour)},
+	}
+	sortKeys := func(sortBy string, isReverse bool) (keys string) {
+		sorter := newContentSorter(sortBy, isReverse)
+		defer sorter.Close()
+		for _, content := range contents {
+			c.Assert(sorter.Add(content), IsNil)
+		}
+		c.Assert(sorter.Sorted(func(content contentMessage) {
+			keys += content.Key
+			// Fields omitted in spilled chunks must not leak into other entries.
+			if content.Key != "c" {
+				c.Assert(content.VersionID, Equals, "")
+			}
+		}), IsNil)
+		return keys
+	}
+
+	c.Assert(sortKeys("name", false), Equals, "abcde")
+	c.Assert(sortKeys("size", false), Equals, "ebcda")
+	c.Assert(sortKeys("time", true), Equals, "baced")
+
+	// Spill to temporary files and merge.
+	defer func(chunkSize int) { lsSortChunkSize = chunkSize }(lsSortChunkSize)
+	lsSortChunkSize = 2
+	c.Assert(sortKeys("name", false), Equals, "abcde")
+	c.Assert(sortKeys("size", true), Equals, "adcbe")
+	c.Assert(sortKeys("time", false), Equals, "decab")
+}
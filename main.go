@@ -24,6 +24,7 @@ import (
 	"strconv"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client/archive"
 	"github.com/minio/minio-xl/pkg/probe"
 	"github.com/minio/pb"
 	"github.com/olekukonko/ts"
@@ -134,6 +135,18 @@ func registerBefore(ctx *cli.Context) error {
 	return nil
 }
 
+// registerAfter completes archives written to by the command.
+func registerAfter(ctx *cli.Context) error {
+	fatalIf(archive.Close().Trace(), "Unable to complete archives.")
+	return nil
+}
+
+// completeArchives completes archives written to so far on the way out of a
+// failed or interrupted command, unfinished archives cannot be read at all.
+func completeArchives() {
+	errorIf(archive.Close().Trace(), "Unable to complete archives.")
+}
+
 // findClosestCommands to match a given string with commands trie tree.
 func findClosestCommands(command string) []string {
 	var closestCommands []string
@@ -146,20 +159,35 @@ func findClosestCommands(command string) []string {
 
 func registerApp() *cli.App {
 	// Register all the commands (refer flags.go)
-	registerCmd(lsCmd)      // List contents of a bucket.
-	registerCmd(mbCmd)      // Make a bucket.
-	registerCmd(catCmd)     // Display contents of a file.
-	registerCmd(pipeCmd)    // Write contents of stdin to a file.
-	registerCmd(shareCmd)   // Share documents via URL.
-	registerCmd(cpCmd)      // Copy objects and files from multiple sources to single destination.
-	registerCmd(mirrorCmd)  // Mirror objects and files from single source to multiple destinations.
-	registerCmd(diffCmd)    // Computer differences between two files or folders.
-	registerCmd(rmCmd)      // Remove a file or bucket
-	registerCmd(accessCmd)  // Set access permissions.
-	registerCmd(sessionCmd) // Manage sessions for copy and mirror.
-	registerCmd(configCmd)  // Configure minio client.
-	registerCmd(updateCmd)  // Check for new software updates.
-	registerCmd(versionCmd) // Print version.
+	registerCmd(lsCmd)        // List contents of a bucket.
+	registerCmd(treeCmd)      // List folders and prefixes as a tree.
+	registerCmd(mbCmd)        // Make a bucket.
+	registerCmd(rbCmd)        // Remove a bucket.
+	registerCmd(catCmd)       // Display contents of a file.
+	registerCmd(headCmd)      // Display first lines of a file.
+	registerCmd(tailCmd)      // Display last lines of a file.
+	registerCmd(sqlCmd)       // Query CSV and JSON objects with SQL.
+	registerCmd(pipeCmd)      // Write contents of stdin to a file.
+	registerCmd(shareCmd)     // Share documents via URL.
+	registerCmd(cpCmd)        // Copy objects and files from multiple sources to single destination.
+	registerCmd(mirrorCmd)    // Mirror objects and files from single source to multiple destinations.
+	registerCmd(mvCmd)        // Move objects and files from multiple sources to single destination.
+	registerCmd(diffCmd)      // Computer differences between two files or folders.
+	registerCmd(rmCmd)        // Remove a file or bucket
+	registerCmd(trashCmd)     // Manage objects removed with rm --trash.
+	registerCmd(accessCmd)    // Set access permissions.
+	registerCmd(restoreCmd)   // Restore old versions of objects.
+	registerCmd(tagCmd)       // Manage tags of buckets and objects.
+	registerCmd(retentionCmd) // Manage object lock retention.
+	registerCmd(legalHoldCmd) // Manage legal hold of objects.
+	registerCmd(eventCmd)     // Manage bucket notifications.
+	registerCmd(watchCmd)     // Watch for changes of files and objects.
+	registerCmd(corsCmd)      // Manage cross-origin resource sharing of buckets.
+	registerCmd(websiteCmd)   // Manage static website hosting of buckets.
+	registerCmd(sessionCmd)   // Manage sessions for copy and mirror.
+	registerCmd(configCmd)    // Configure minio client.
+	registerCmd(updateCmd)    // Check for new software updates.
+	registerCmd(versionCmd)   // Print version.
 
 	app := cli.NewApp()
 	app.Usage = "Minio Client for cloud storage and filesystems."
@@ -178,6 +206,7 @@ func main() {
 
 	app := registerApp()
 	app.Before = registerBefore
+	app.After = registerAfter
 
 	app.ExtraInfo = func() map[string]string {
 		if _, e := ts.GetSize(); e != nil {
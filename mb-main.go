@@ -32,6 +32,10 @@ var (
 			Name:  "help, h",
 			Usage: "Help of mb.",
 		},
+		cli.BoolFlag{
+			Name:  "with-lock",
+			Usage: "Enable object lock on the new bucket, this also enables versioning.",
+		},
 	}
 )
 
@@ -62,6 +66,9 @@ EXAMPLES:
 
    3. Create a new directory including its missing parents (equivalent to ‘mkdir -p’).
       $ mc {{.Name}} /tmp/this/new/dir1
+
+   4. Create a new bucket with object lock enabled on Amazon S3 cloud storage.
+      $ mc {{.Name}} --with-lock s3.amazonaws.com/compliance-backups
 `,
 }
 
@@ -107,7 +114,10 @@ func mainMakeBucket(ctx *cli.Context) {
 	// Additional command speific theme customization.
 	console.SetColor("MakeBucket", color.New(color.FgGreen, color.Bold))
 
-	URLs, err := args2URLs(ctx.Args())
+	// Set command flags from context.
+	withLock := ctx.Bool("with-lock")
+
+	URLs, err := args2TargetURLs(ctx.Args())
 	fatalIf(err.Trace(ctx.Args()...), "Unable to convert args to URLs.")
 
 	for _, targetURL := range URLs {
@@ -116,7 +126,11 @@ func mainMakeBucket(ctx *cli.Context) {
 		fatalIf(err.Trace(targetURL), "Invalid target ‘"+targetURL+"’.")
 
 		// Make bucket.
-		err = clnt.MakeBucket()
+		if withLock {
+			err = clnt.MakeBucketWithLock(globalContext)
+		} else {
+			err = clnt.MakeBucket(globalContext)
+		}
 		// Upon error print error and continue.
 		if err != nil {
 			errorIf(err.Trace(targetURL), "Unable to make bucket ‘"+targetURL+"’.")
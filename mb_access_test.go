@@ -24,7 +24,7 @@ func (s *TestSuite) TestMbAndAccess(c *C) {
 	c.Assert(err, IsNil)
 
 	// Make bucket.
-	err = clnt.MakeBucket()
+	err = clnt.MakeBucket(globalContext)
 	c.Assert(err, IsNil)
 
 	err = doSetAccess(server.URL+"/bucket", "public-read-write")
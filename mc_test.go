@@ -43,7 +43,7 @@ var server *httptest.Server
 var app *cli.App
 
 func (s *TestSuite) SetUpSuite(c *C) {
-	objectAPI := objectAPIHandler(objectAPIHandler{lock: &sync.Mutex{}, bucket: "bucket", object: make(map[string][]byte)})
+	objectAPI := objectAPIHandler(objectAPIHandler{lock: &sync.Mutex{}, bucket: "bucket", object: make(map[string][]byte), copies: make(map[string]string)})
 	server = httptest.NewServer(objectAPI)
 
 	// do not set it elsewhere, leads to data races since this is a global flag
@@ -0,0 +1,94 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/client/memory"
+
+	. "gopkg.in/check.v1"
+)
+
+// putMemory - create an object in memory.
+func putMemory(c *C, url, data string) {
+	clnt, err := url2Client(url)
+	c.Assert(err, IsNil)
+	c.Assert(clnt.Put(globalContext, bytes.NewReader([]byte(data)), int64(len(data))), IsNil)
+}
+
+// readMemory - data of an object in memory.
+func readMemory(c *C, url string) string {
+	clnt, err := url2Client(url)
+	c.Assert(err, IsNil)
+	reader, err := clnt.Get(globalContext, 0, 0)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	return string(data)
+}
+
+func (s *TestSuite) TestMemoryCopyMirrorDiffRm(c *C) {
+	memory.Reset()
+	defer memory.Reset()
+	for _, bucket := range []string{"mem://source", "mem://copy", "mem://mirror"} {
+		clnt, err := url2Client(bucket)
+		c.Assert(err, IsNil)
+		c.Assert(clnt.MakeBucket(globalContext), IsNil)
+	}
+	putMemory(c, "mem://source/object1", "hello")
+	putMemory(c, "mem://source/folder/object2", "world")
+
+	session := newSessionV5()
+	session.Header.CommandType = "cp"
+	session.Header.CommandBoolFlags["recursive"] = true
+	session.Header.CommandArgs = []string{"mem://source/", "mem://copy/"}
+	doCopySession(session)
+	session.Delete()
+	c.Assert(readMemory(c, "mem://copy/object1"), Equals, "hello")
+	c.Assert(readMemory(c, "mem://copy/folder/object2"), Equals, "world")
+
+	session = newSessionV5()
+	session.Header.CommandType = "mirror"
+	session.Header.CommandArgs = []string{"mem://source/", "mem://mirror/"}
+	doMirrorSession(session)
+	session.Delete()
+	c.Assert(readMemory(c, "mem://mirror/folder/object2"), Equals, "world")
+
+	// Only the changed object differs, suffixes are compared in listing order.
+	putMemory(c, "mem://mirror/object1", "hello, world")
+	difference, err := objectDifferenceFactory("mem://mirror/")
+	c.Assert(err, IsNil)
+	differ, err := difference("folder/object2", os.FileMode(0664), 5)
+	c.Assert(err, IsNil)
+	c.Assert(differ, Equals, differNone)
+	differ, err = difference("object1", os.FileMode(0664), 5)
+	c.Assert(err, IsNil)
+	c.Assert(differ, Equals, differSize)
+
+	rmAll("mem://copy/", true, false, false, false, rmFilter{})
+	clnt, err := url2Client("mem://copy/")
+	c.Assert(err, IsNil)
+	var contents []*client.Content
+	for content := range clnt.List(globalContext, true, false) {
+		contents = append(contents, content)
+	}
+	c.Assert(contents, HasLen, 0)
+}
@@ -46,6 +46,14 @@ var (
 			Name:  "force",
 			Usage: "Force overwrite of an existing target(s).",
 		},
+		cli.StringFlag{
+			Name:  "tags",
+			Usage: "Tag uploaded objects, in the form key1=value1&key2=value2.",
+		},
+		cli.BoolFlag{
+			Name:  "preserve, a",
+			Usage: "Preserve mode, ownership and timestamps of files.",
+		},
 	}
 )
 
@@ -54,7 +62,7 @@ var mirrorCmd = cli.Command{
 	Name:   "mirror",
 	Usage:  "Mirror folders recursively from a single source to single destination.",
 	Action: mainMirror,
-	Flags:  append(mirrorFlags, globalFlags...),
+	Flags:  append(append(mirrorFlags, symlinksFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
    mc {{.Name}} - {{.Usage}}
 
@@ -73,6 +81,15 @@ EXAMPLES:
 
    3. Mirror a bucket from aliased Amazon S3 cloud storage to a folder on Windows.
       $ mc {{.Name}} s3/documents/2014/ C:\backup\2014
+
+   4. Mirror a local folder recursively to Amazon S3 cloud storage, tagging all uploaded objects.
+      $ mc {{.Name}} --tags "project=mc&costcenter=42" backup/ s3.amazonaws.com/archive
+
+   5. Mirror a local folder to Amazon S3 cloud storage, keeping mode, ownership and timestamps of files.
+      $ mc {{.Name}} --preserve /home/shared/ s3.amazonaws.com/archive/shared
+
+   6. Mirror a local folder to Amazon S3 cloud storage, leaving out symbolic links.
+      $ mc {{.Name}} --skip-symlinks /home/shared/ s3.amazonaws.com/archive/shared
 `,
 }
 
@@ -138,37 +155,47 @@ func doMirror(sURLs mirrorURLs, progressReader *barSend, accountingReader *accou
 		progressReader.SetCaption(sourceURL + ": ")
 	}
 
-	reader, err := getSource(sourceURL)
-	if err != nil {
-		if !globalQuiet && !globalJSON {
-			progressReader.ErrorGet(length)
+	// Transfers failing verification are tried again from the start.
+	metadata := taggedMetadata(verifiedMetadata(sURLs.TargetMetadata, sURLs.SourceContent, sURLs.TargetContent.URL), sURLs.TargetTags)
+	for attempt := 1; ; attempt++ {
+		reader, err := getSource(sourceURL)
+		if err != nil {
+			if !globalQuiet && !globalJSON {
+				progressReader.ErrorGet(length)
+			}
+			sURLs.Error = err.Trace(sourceURL)
+			statusCh <- sURLs
+			return
 		}
-		sURLs.Error = err.Trace(sourceURL)
-		statusCh <- sURLs
-		return
-	}
 
-	var newReader io.ReadSeeker
-	if globalQuiet || globalJSON {
-		printMsg(mirrorMessage{
-			Source: sourceURL,
-			Target: targetURL,
-		})
-		if globalJSON {
-			newReader = reader
+		var newReader io.ReadSeeker
+		if globalQuiet || globalJSON {
+			if attempt == 1 {
+				printMsg(mirrorMessage{
+					Source: sourceURL,
+					Target: targetURL,
+				})
+			}
+			if globalJSON {
+				newReader = reader
+			}
+			if globalQuiet {
+				newReader = accountingReader.NewProxyReader(reader)
+			}
+		} else {
+			// set up progress
+			newReader = progressReader.NewProxyReader(reader)
 		}
-		if globalQuiet {
-			newReader = accountingReader.NewProxyReader(reader)
+		err = putTargetWithMetadata(targetURL, newReader, length, metadata)
+		if err == nil {
+			break
 		}
-	} else {
-		// set up progress
-		newReader = progressReader.NewProxyReader(reader)
-	}
-	err = putTarget(targetURL, newReader, length)
-	if err != nil {
 		if !globalQuiet && !globalJSON {
 			progressReader.ErrorPut(length)
 		}
+		if isChecksumMismatch(err) && attempt < maxTransferAttempts {
+			continue
+		}
 		sURLs.Error = err.Trace(targetURL)
 		statusCh <- sURLs
 		return
@@ -192,6 +219,13 @@ func doPrepareMirrorURLs(session *sessionV5, isForce bool, trapCh <-chan bool) {
 	var totalBytes int64
 	var totalObjects int
 
+	// Access tags inside the session header, they were validated before.
+	targetTags, _ := parseTagsString(session.Header.CommandStringFlags["tags"])
+
+	// Access preserve flags inside the session header.
+	isPreserve := session.Header.CommandBoolFlags["preserve"]
+	isPreserveSymlinks := session.Header.CommandStringFlags["symlinks"] == symlinksPreserve
+
 	// Create a session data file to store the processed URLs.
 	dataFP := session.NewDataWriter()
 
@@ -209,6 +243,9 @@ func doPrepareMirrorURLs(session *sessionV5, isForce bool, trapCh <-chan bool) {
 				done = true
 				break
 			}
+			if globalContext.Err() != nil { // Interrupted, scanning was aborted.
+				break
+			}
 			if sURLs.Error != nil {
 				// Print in new line and adjust to top so that we don't print over the ongoing scan bar
 				if !globalQuiet && !globalJSON {
@@ -220,6 +257,19 @@ func doPrepareMirrorURLs(session *sessionV5, isForce bool, trapCh <-chan bool) {
 			if sURLs.isEmpty() {
 				break
 			}
+			// Preserved links are empty, so are the objects they are stored as.
+			if isPreserve || (isPreserveSymlinks && sURLs.SourceContent.Size == 0) {
+				var err *probe.Error
+				sURLs.TargetMetadata, err = getSourceAttributes(sURLs.SourceContent.URL.String(), isPreserve)
+				if err != nil {
+					if !globalQuiet && !globalJSON {
+						console.Eraseline()
+					}
+					errorIf(err.Trace(sURLs.SourceContent.URL.String()), "Unable to get attributes of ‘"+sURLs.SourceContent.URL.String()+"’.")
+					break
+				}
+			}
+			sURLs.TargetTags = targetTags
 			jsonData, err := json.Marshal(sURLs)
 			if err != nil {
 				session.Delete()
@@ -233,13 +283,17 @@ func doPrepareMirrorURLs(session *sessionV5, isForce bool, trapCh <-chan bool) {
 			totalBytes += sURLs.SourceContent.Size
 			totalObjects++
 		case <-trapCh:
-			// Print in new line and adjust to top so that we don't print over the ongoing scan bar
-			if !globalQuiet && !globalJSON {
-				console.Eraseline()
-			}
-			session.Delete() // If we are interrupted during the URL scanning, we drop the session.
-			os.Exit(0)
+			done = true
+		}
+	}
+	// Scanning may have ended early as well, once its listings were aborted.
+	if globalContext.Err() != nil {
+		// Print in new line and adjust to top so that we don't print over the ongoing scan bar
+		if !globalQuiet && !globalJSON {
+			console.Eraseline()
 		}
+		session.Delete() // If we are interrupted during the URL scanning, we drop the session.
+		os.Exit(0)
 	}
 	session.Header.TotalBytes = totalBytes
 	session.Header.TotalObjects = totalObjects
@@ -251,6 +305,9 @@ func doMirrorSession(session *sessionV5) {
 	isForce := session.Header.CommandBoolFlags["force"]
 	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM)
 
+	// Filesystem sources handle links the same way when resumed.
+	setSymlinks(session.Header.CommandStringFlags["symlinks"])
+
 	if !session.HasData() {
 		doPrepareMirrorURLs(session, isForce, trapCh)
 	}
@@ -277,56 +334,59 @@ func doMirrorSession(session *sessionV5) {
 	// Status channel for receiveing mirror return status.
 	statusCh := make(chan mirrorURLs)
 
-	// Go routine to monitor doMirror status and signal traps.
+	// Go routine to monitor doMirror status.
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		for {
-			select {
-			case sURLs, ok := <-statusCh: // Receive status.
-				if !ok { // We are done here. Top level function has returned.
+			// Receive status.
+			sURLs, ok := <-statusCh
+			if !ok { // We are done here. Top level function has returned.
+				if globalContext.Err() != nil {
+					// Interrupted, mirrors in progress have returned, save the session.
 					if !globalQuiet && !globalJSON {
-						progressReader.Finish()
-					} else {
-						accntStat := accntReader.Stat()
-						mrStatMessage := mirrorStatMessage{
-							Total:       accntStat.Total,
-							Transferred: accntStat.Transferred,
-							Speed:       accntStat.Speed,
-						}
-						console.Println(console.Colorize("Mirror", mrStatMessage.String()))
+						console.Eraseline()
 					}
-					return
+					session.CloseAndDie()
 				}
-				if sURLs.Error == nil {
-					session.Header.LastCopied = sURLs.SourceContent.URL.String()
-					session.Save()
+				if !globalQuiet && !globalJSON {
+					progressReader.Finish()
 				} else {
-					// Print in new line and adjust to top so that we don't print over the ongoing progress bar
-					if !globalQuiet && !globalJSON {
-						console.Eraseline()
-					}
-					errorIf(sURLs.Error.Trace(), fmt.Sprintf("Failed to mirror ‘%s’.", sURLs.SourceContent.URL.String()))
-					// for all non critical errors we can continue for the remaining files
-					switch sURLs.Error.ToGoError().(type) {
-					// handle this specifically for filesystem related errors.
-					case client.BrokenSymlink:
-						continue
-					case client.TooManyLevelsSymlink:
-						continue
-					case client.PathNotFound:
-						continue
-					case client.PathInsufficientPermission:
-						continue
+					accntStat := accntReader.Stat()
+					mrStatMessage := mirrorStatMessage{
+						Total:       accntStat.Total,
+						Transferred: accntStat.Transferred,
+						Speed:       accntStat.Speed,
 					}
-					// for critical errors we should exit. Session can be resumed after the user figures out the problem
-					session.CloseAndDie()
+					console.Println(console.Colorize("Mirror", mrStatMessage.String()))
 				}
-			case <-trapCh: // Receive interrupt notification.
+				return
+			}
+			if sURLs.Error == nil {
+				session.Header.LastCopied = sURLs.SourceContent.URL.String()
+				session.Save()
+			} else if globalContext.Err() != nil {
+				// Aborted upon interrupt, mirrored again once resumed.
+				continue
+			} else {
 				// Print in new line and adjust to top so that we don't print over the ongoing progress bar
 				if !globalQuiet && !globalJSON {
 					console.Eraseline()
 				}
+				errorIf(sURLs.Error.Trace(), fmt.Sprintf("Failed to mirror ‘%s’.", sURLs.SourceContent.URL.String()))
+				// for all non critical errors we can continue for the remaining files
+				switch sURLs.Error.ToGoError().(type) {
+				// handle this specifically for filesystem related errors.
+				case client.BrokenSymlink:
+					continue
+				case client.TooManyLevelsSymlink:
+					continue
+				case client.PathNotFound:
+					continue
+				case client.PathInsufficientPermission:
+					continue
+				}
+				// for critical errors we should exit. Session can be resumed after the user figures out the problem
 				session.CloseAndDie()
 			}
 		}
@@ -340,9 +400,14 @@ func doMirrorSession(session *sessionV5) {
 		defer close(statusCh)
 
 		for scanner.Scan() {
+			// Nothing more is mirrored once interrupted.
+			if globalContext.Err() != nil {
+				break
+			}
 			var sURLs mirrorURLs
 			json.Unmarshal([]byte(scanner.Text()), &sURLs)
-			if isCopied(sURLs.SourceContent.URL.String()) {
+			// Archives are written anew, mirrors into them resume from the start.
+			if isCopied(sURLs.SourceContent.URL.String()) && !isArchiveURL(sURLs.TargetContent.URL) {
 				doMirrorFake(sURLs, progressReader)
 			} else {
 				// Wait for other mirror routines to
@@ -366,6 +431,11 @@ func mainMirror(ctx *cli.Context) {
 	// Set global flags from context.
 	setGlobalsFromContext(ctx)
 
+	// Links are handled as requested from the syntax check onwards.
+	symlinks, err := symlinksFromContext(ctx)
+	fatalIf(err.Trace(), "Only one of ‘--follow-symlinks’, ‘--skip-symlinks’ and ‘--preserve-symlinks’ can be used.")
+	setSymlinks(symlinks)
+
 	// check 'mirror' cli arguments.
 	checkMirrorSyntax(ctx)
 
@@ -384,10 +454,13 @@ func mainMirror(ctx *cli.Context) {
 	// Set command flags from context.
 	isForce := ctx.Bool("force")
 	session.Header.CommandBoolFlags["force"] = isForce
+	session.Header.CommandBoolFlags["preserve"] = ctx.Bool("preserve")
+	session.Header.CommandStringFlags["symlinks"] = symlinks
+	targetTags, _ := parseTagsString(ctx.String("tags"))
+	session.Header.CommandStringFlags["tags"] = tagsToString(targetTags)
 
 	// extract URLs.
-	var err *probe.Error
-	session.Header.CommandArgs, err = args2URLs(ctx.Args())
+	session.Header.CommandArgs, err = args2CopyURLs(ctx.Args())
 	if err != nil {
 		session.Delete()
 		fatalIf(err.Trace(ctx.Args()...), fmt.Sprintf("One or more unknown argument types found in ‘%s’.", ctx.Args()))
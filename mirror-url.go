@@ -26,9 +26,11 @@ import (
 )
 
 type mirrorURLs struct {
-	SourceContent *client.Content
-	TargetContent *client.Content
-	Error         *probe.Error `json:"-"`
+	SourceContent  *client.Content
+	TargetContent  *client.Content
+	TargetTags     map[string]string `json:",omitempty"`
+	TargetMetadata map[string]string `json:",omitempty"`
+	Error          *probe.Error      `json:"-"`
 }
 
 func (m mirrorURLs) isEmpty() bool {
@@ -53,8 +55,11 @@ func checkMirrorSyntax(ctx *cli.Context) {
 	}
 
 	// extract URLs.
-	URLs, err := args2URLs(ctx.Args())
+	URLs, err := args2CopyURLs(ctx.Args())
 	fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
+	if len(URLs) != 2 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Mirror source must be a single folder.")
+	}
 
 	srcURL := URLs[0]
 	tgtURL := URLs[1]
@@ -76,6 +81,14 @@ func checkMirrorSyntax(ctx *cli.Context) {
 	}
 
 	url := client.NewURL(tgtURL)
+	// Tags can only be set on object storage targets.
+	if ctx.String("tags") != "" {
+		_, err := parseTagsString(ctx.String("tags"))
+		fatalIf(err.Trace(ctx.String("tags")), "Unable to parse tags.")
+		if url.Type != client.Object {
+			fatalIf(errInvalidArgument().Trace(tgtURL), "‘--tags’ requires an object storage target.")
+		}
+	}
 	if url.Host != "" {
 		if !isURLVirtualHostStyle(url.Host) {
 			if url.Path == string(url.Separator) {
@@ -116,7 +129,7 @@ func deltaSourceTargets(sourceURL string, targetURL string, isForce bool, mirror
 		return
 	}
 
-	for sourceContent := range sourceClient.List(true, false) {
+	for sourceContent := range sourceClient.List(globalContext, true, false) {
 		if sourceContent.Err != nil {
 			mirrorURLsCh <- mirrorURLs{Error: sourceContent.Err.Trace(sourceClient.GetURL().String())}
 			continue
@@ -0,0 +1,269 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// mv command flags.
+var (
+	mvFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of mv.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Move recursively.",
+		},
+	}
+)
+
+// Move command.
+var mvCmd = cli.Command{
+	Name:   "mv",
+	Usage:  "Move one or more objects to a target.",
+	Action: mainMove,
+	Flags:  append(mvFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SOURCE [SOURCE...] TARGET
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Move a list of objects from local file system to Amazon S3 cloud storage.
+      $ mc {{.Name}} Music/*.ogg s3.amazonaws.com/jukebox/
+
+   2. Move a folder recursively from Minio cloud storage to Amazon S3 cloud storage.
+      $ mc {{.Name}} --recursive play.minio.io:9000/mybucket/burningman2011/ s3.amazonaws.com/mybucket/
+
+   3. Rename a local folder, this does not copy any data.
+      $ mc {{.Name}} --recursive backup/2015/ archive/2015/
+`,
+}
+
+// moveMessage container for file move messages
+type moveMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Length int64  `json:"length"`
+}
+
+// String colorized move message
+func (m moveMessage) String() string {
+	return console.Colorize("Copy", fmt.Sprintf("‘%s’ -> ‘%s’", m.Source, m.Target))
+}
+
+// JSON jsonified move message
+func (m moveMessage) JSON() string {
+	m.Status = "success"
+	moveMessageBytes, err := json.Marshal(m)
+	fatalIf(probe.NewError(err), "Failed to marshal move message.")
+
+	return string(moveMessageBytes)
+}
+
+// doMoveMessage - report a move which did not transfer any data.
+func doMoveMessage(cpURLs copyURLs, progressReader *barSend) {
+	if globalQuiet || globalJSON {
+		printMsg(moveMessage{
+			Source: cpURLs.SourceContent.URL.String(),
+			Target: cpURLs.TargetContent.URL.String(),
+			Length: cpURLs.SourceContent.Size,
+		})
+		return
+	}
+	progressReader.Progress(cpURLs.SourceContent.Size)
+}
+
+// renameLocal - rename a file if source and target are both on the
+// filesystem, returns false if the data has to be copied instead. Existing
+// targets are left to the copy, which replaces and reports them as cp does.
+func renameLocal(cpURLs copyURLs) bool {
+	if cpURLs.SourceContent.URL.Type != client.Filesystem || cpURLs.TargetContent.URL.Type != client.Filesystem {
+		return false
+	}
+	sourcePath := cpURLs.SourceContent.URL.Path
+	targetPath := cpURLs.TargetContent.URL.Path
+	if _, e := os.Lstat(targetPath); !os.IsNotExist(e) {
+		return false
+	}
+	if e := os.MkdirAll(filepath.Dir(targetPath), 0700); e != nil {
+		return false
+	}
+	// Fails across devices, fall back to copying then.
+	return os.Rename(sourcePath, targetPath) == nil
+}
+
+// copyOnServer - copy an object on the server if source and target are on the
+// same host, returns false if the data has to be streamed instead.
+func copyOnServer(cpURLs copyURLs) (bool, *probe.Error) {
+	source, target := cpURLs.SourceContent.URL, cpURLs.TargetContent.URL
+	if source.Type != client.Object || target.Type != client.Object || source.Scheme != target.Scheme || source.Host != target.Host {
+		return false, nil
+	}
+	targetClnt, err := url2Client(target.String())
+	if err != nil {
+		return false, err.Trace(target.String())
+	}
+	err = targetClnt.Copy(globalContext, source, cpURLs.SourceContent.VersionID)
+	if _, ok := err.ToGoError().(client.APINotImplemented); ok {
+		return false, nil
+	}
+	if err != nil {
+		return false, err.Trace(source.String(), target.String())
+	}
+	return true, nil
+}
+
+// isMoved - true if the source is gone and the target is its copy, which
+// happens when a move is resumed after the source was already removed.
+// Targets must have the size and ETag of the source, or without ETags
+// have been written since the source was last modified.
+func isMoved(cpURLs copyURLs) bool {
+	if _, _, err := url2Stat(cpURLs.SourceContent.URL.String()); err == nil {
+		return false
+	}
+	_, targetContent, err := url2Stat(cpURLs.TargetContent.URL.String())
+	if err != nil {
+		return false
+	}
+	if targetContent.Size != cpURLs.SourceContent.Size {
+		return false
+	}
+	if cpURLs.SourceContent.ETag != "" && targetContent.ETag != "" {
+		return targetContent.ETag == cpURLs.SourceContent.ETag
+	}
+	return !targetContent.Time.Before(cpURLs.SourceContent.Time)
+}
+
+// removeSource - remove a source after it was copied.
+func removeSource(cpURLs copyURLs) *probe.Error {
+	sourceClnt, err := url2Client(cpURLs.SourceContent.URL.String())
+	if err != nil {
+		return err.Trace(cpURLs.SourceContent.URL.String())
+	}
+	if err = sourceClnt.Remove(globalContext, false); err != nil {
+		return err.Trace(cpURLs.SourceContent.URL.String())
+	}
+	return nil
+}
+
+// movedFolders - local folders files were moved out of, safe for concurrent use.
+type movedFolders struct {
+	mutex   sync.Mutex
+	folders map[string]bool
+}
+
+// add - record the folder of a moved source.
+func (m *movedFolders) add(cpURLs copyURLs) {
+	if cpURLs.SourceContent.URL.Type != client.Filesystem {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.folders == nil {
+		m.folders = make(map[string]bool)
+	}
+	m.folders[filepath.Dir(filepath.Clean(cpURLs.SourceContent.URL.Path))] = true
+}
+
+// removeEmpty - remove folders files were moved out of, and their parents
+// up to sourceURL, as long as they are empty. Other folders are kept.
+func (m *movedFolders) removeEmpty(sourceURL string) {
+	url := client.NewURL(sourceURL)
+	if url.Type != client.Filesystem {
+		return
+	}
+	root := filepath.Clean(url.Path)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for folder := range m.folders {
+		for isPathUnder(folder, root) {
+			// Removing a non empty folder simply fails.
+			if os.Remove(folder) != nil || folder == root {
+				break
+			}
+			folder = filepath.Dir(folder)
+		}
+	}
+}
+
+// isPathUnder - true if path is root or inside of it.
+func isPathUnder(path, root string) bool {
+	if path == root {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(root, string(os.PathSeparator))+string(os.PathSeparator))
+}
+
+// checkMoveSyntax - validate all the passed arguments, same rules as cp.
+func checkMoveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "mv", 1) // last argument is exit code.
+	}
+	checkCopySyntax(ctx)
+}
+
+// mainMove is the entry point for mv command.
+func mainMove(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'mv' cli arguments.
+	checkMoveSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+
+	session := newSessionV5()
+	session.Header.CommandType = "mv"
+	session.Header.CommandBoolFlags["recursive"] = ctx.Bool("recursive")
+
+	var e error
+	if session.Header.RootPath, e = os.Getwd(); e != nil {
+		session.Delete()
+		fatalIf(probe.NewError(e), "Unable to get current working folder.")
+	}
+
+	// extract URLs.
+	var err *probe.Error
+	if session.Header.CommandArgs, err = args2CopyURLs(ctx.Args()); err != nil {
+		session.Delete()
+		fatalIf(err.Trace(ctx.Args()...), "One or more unknown URL types passed.")
+	}
+
+	doCopySession(session)
+	session.Delete()
+}
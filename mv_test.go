@@ -0,0 +1,165 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/client/memory"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestMove(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "mv-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	source := filepath.Join(root, "source")
+	target := filepath.Join(root, "target")
+	c.Assert(os.MkdirAll(filepath.Join(source, "folder"), 0700), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(source, "empty"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(source, "object1"), []byte("hello"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(source, "folder", "object2"), []byte("world"), 0600), IsNil)
+
+	session := newSessionV5()
+	session.Header.CommandType = "mv"
+	session.Header.CommandBoolFlags["recursive"] = true
+	session.Header.CommandArgs = []string{source + string(os.PathSeparator), target + string(os.PathSeparator)}
+	doCopySession(session)
+	session.Delete()
+
+	data, e := ioutil.ReadFile(filepath.Join(target, "object1"))
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "hello")
+	data, e = ioutil.ReadFile(filepath.Join(target, "folder", "object2"))
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "world")
+	// Only folders files were moved out of are removed.
+	_, e = os.Stat(filepath.Join(source, "folder"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+	_, e = os.Stat(filepath.Join(source, "empty"))
+	c.Assert(e, IsNil)
+
+	// Existing targets are replaced by a copy, not a rename.
+	c.Assert(ioutil.WriteFile(filepath.Join(source, "object1"), []byte("again"), 0600), IsNil)
+	cpURLs := copyURLs{
+		SourceContent: &client.Content{URL: *client.NewURL(filepath.Join(source, "object1")), Size: 5},
+		TargetContent: &client.Content{URL: *client.NewURL(filepath.Join(target, "object1"))},
+	}
+	c.Assert(renameLocal(cpURLs), Equals, false)
+	c.Assert(os.Remove(filepath.Join(source, "object1")), IsNil)
+
+	// A resumed move whose source is gone is complete if the target is its copy.
+	c.Assert(isMoved(cpURLs), Equals, true)
+	cpURLs.SourceContent.Size = 6
+	c.Assert(isMoved(cpURLs), Equals, false)
+	// Targets older than their source are not a copy of it.
+	cpURLs.SourceContent.Size = 5
+	cpURLs.SourceContent.Time = time.Now().Add(time.Hour)
+	c.Assert(isMoved(cpURLs), Equals, false)
+}
+
+func (s *TestSuite) TestMoveOnServer(c *C) {
+	memory.Reset()
+	defer memory.Reset()
+	clnt, err := url2Client("mem://bucket")
+	c.Assert(err, IsNil)
+	c.Assert(clnt.MakeBucket(globalContext), IsNil)
+	clnt, err = url2Client("mem://bucket/object")
+	c.Assert(err, IsNil)
+	c.Assert(clnt.PutWithMetadata(globalContext, bytes.NewReader([]byte("hello")), 5, map[string]string{"Color": "blue"}), IsNil)
+
+	session := newSessionV5()
+	session.Header.CommandType = "mv"
+	session.Header.CommandArgs = []string{"mem://bucket/object", "mem://bucket/moved"}
+	doCopySession(session)
+	session.Delete()
+
+	// Copied on the server, metadata is kept.
+	_, err = clnt.Stat(globalContext)
+	c.Assert(err, Not(IsNil))
+	movedClnt, err := url2Client("mem://bucket/moved")
+	c.Assert(err, IsNil)
+	content, err := movedClnt.Stat(globalContext)
+	c.Assert(err, IsNil)
+	c.Assert(content.Metadata, DeepEquals, map[string]string{"Color": "blue"})
+	c.Assert(readMemory(c, "mem://bucket/moved"), Equals, "hello")
+}
+
+func (s *TestSuite) TestMoveResume(c *C) {
+	memory.Reset()
+	defer memory.Reset()
+	for _, bucket := range []string{"mem://source", "mem://target"} {
+		clnt, err := url2Client(bucket)
+		c.Assert(err, IsNil)
+		c.Assert(clnt.MakeBucket(globalContext), IsNil)
+	}
+	for _, name := range []string{"object1", "object2", "object3"} {
+		putMemory(c, "mem://source/"+name, name)
+	}
+
+	session := newSessionV5()
+	session.Header.CommandType = "mv"
+	session.Header.CommandBoolFlags["recursive"] = true
+	session.Header.CommandArgs = []string{"mem://source/", "mem://target/"}
+	doPrepareCopyURLs(session, make(chan bool))
+	var entries []copyURLs
+	scanner := bufio.NewScanner(session.NewDataReader())
+	for scanner.Scan() {
+		var cpURLs copyURLs
+		c.Assert(json.Unmarshal(scanner.Bytes(), &cpURLs), IsNil)
+		entries = append(entries, cpURLs)
+	}
+	c.Assert(entries, HasLen, 3)
+
+	// The last entry finishes first, the session is interrupted while the
+	// second one is still being moved.
+	prefix := new(copiedPrefix)
+	for i := range entries {
+		c.Assert(prefix.add(), Equals, i)
+	}
+	for _, i := range []int{2, 0} {
+		copied, err := copyOnServer(entries[i])
+		c.Assert(err, IsNil)
+		c.Assert(copied, Equals, true)
+		c.Assert(removeSource(entries[i]), IsNil)
+		if lastCopied, ok := prefix.done(i, entries[i].SourceContent.URL.String()); ok {
+			session.Header.LastCopied = lastCopied
+		}
+	}
+	c.Assert(session.Header.LastCopied, Equals, entries[0].SourceContent.URL.String())
+	c.Assert(session.Save(), IsNil)
+
+	// Resuming moves the second entry, the third is found moved already.
+	doCopySession(session)
+	session.Delete()
+	for _, name := range []string{"object1", "object2", "object3"} {
+		c.Assert(readMemory(c, "mem://target/"+name), Equals, name)
+		clnt, err := url2Client("mem://source/" + name)
+		c.Assert(err, IsNil)
+		_, err = clnt.Stat(globalContext)
+		c.Assert(err, Not(IsNil))
+	}
+}
@@ -17,9 +17,10 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
-	"syscall"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/minio/minio-xl/pkg/probe"
 )
@@ -30,20 +31,25 @@ var (
 			Name:  "help, h",
 			Usage: "Help of pipe.",
 		},
+		cli.StringFlag{
+			Name:  "max-memory",
+			Value: "64MiB",
+			Usage: "Memory used to buffer stdin for targets which are behind.",
+		},
 	}
 )
 
 // Display contents of a file.
 var pipeCmd = cli.Command{
 	Name:   "pipe",
-	Usage:  "Write contents of stdin to one target. When no target is specified, it writes to stdout.",
+	Usage:  "Write contents of stdin to one or more targets. When no target is specified, it writes to stdout.",
 	Action: mainPipe,
 	Flags:  append(pipeFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
    mc {{.Name}} - {{.Usage}}
 
 USAGE:
-   mc {{.Name}} [FLAGS] [TARGET]
+   mc {{.Name}} [FLAGS] [TARGET...]
 
 FLAGS:
   {{range .Flags}}{{.}}
@@ -56,38 +62,46 @@ EXAMPLES:
       $ mc {{.Name}} s3.amazonaws.com/personalbuck/meeting-notes.txt
 
    3. Copy an ISO image to an object on Amazon S3 cloud storage and Google Cloud Storage simultaneously.
-      $ cat debian-8.2.iso | mc {{.Name}} s3.amazonaws.com/ferenginar/gnuos.iso
+      $ cat debian-8.2.iso | mc {{.Name}} s3.amazonaws.com/ferenginar/gnuos.iso storage.googleapis.com/miniocloud/gnuos.iso
 
    4. Stream MySQL database dump to Amazon S3 directly.
       $ mysqldump -u root -p ******* accountsdb | mc {{.Name}} s3.amazonaws.com/ferenginar/backups/accountsdb-oct-9-2015.sql
+
+   5. Stream a backup to a local disk and Amazon S3, buffering at most 256MiB for the slower one.
+      $ tar cz /home | mc {{.Name}} --max-memory 256MiB /mnt/backup/home.tar.gz s3/backups/home.tar.gz
 `,
 }
 
-func pipe(targetURL string) *probe.Error {
-	if targetURL == "" {
-		// When no target is specified, pipe cat's stdin to stdout.
-		return catOut(os.Stdin).Trace()
-	}
+// pipeMessage container for the status of one target.
+type pipeMessage struct {
+	Status string `json:"status"`
+	Target string `json:"target"`
+	Size   int64  `json:"size"`
+	Error  string `json:"error,omitempty"`
+}
 
-	// Stream from stdin to multiple objects until EOF.
-	// Ignore size, since os.Stat() would not return proper size all the time
-	// for local filesystem for example /proc files.
-	err := putTarget(targetURL, os.Stdin, -1)
-	// TODO: See if this check is necessary.
-	switch e := err.ToGoError().(type) {
-	case *os.PathError:
-		if e.Err == syscall.EPIPE {
-			// stdin closed by the user. Gracefully exit.
-			return nil
-		}
+// String pipe message, only printed in JSON mode.
+func (p pipeMessage) String() string {
+	return "Wrote " + humanize.IBytes(uint64(p.Size)) + " to ‘" + p.Target + "’."
+}
+
+// JSON jsonified pipe message.
+func (p pipeMessage) JSON() string {
+	if p.Status == "" {
+		p.Status = "success"
 	}
-	return err.Trace(targetURL)
+	pipeMessageBytes, e := json.Marshal(p)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(pipeMessageBytes)
 }
 
 // check pipe input arguments.
 func checkPipeSyntax(ctx *cli.Context) {
-	if len(ctx.Args()) > 1 {
-		cli.ShowCommandHelpAndExit(ctx, "pipe", 1) // last argument is exit code.
+	maxMemory, err := parseSize(ctx.String("max-memory"))
+	fatalIf(err.Trace(ctx.String("max-memory")), "Unable to parse ‘--max-memory’.")
+	if *maxMemory == 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("max-memory")), "‘--max-memory’ cannot be zero.")
 	}
 }
 
@@ -100,13 +114,33 @@ func mainPipe(ctx *cli.Context) {
 	checkPipeSyntax(ctx)
 
 	if len(ctx.Args()) == 0 {
-		err := pipe("")
+		// When no target is specified, pipe cat's stdin to stdout.
+		err := catOut(os.Stdin)
 		fatalIf(err.Trace("stdout"), "Unable to write to one or more targets.")
-	} else {
-		// extract URLs.
-		URLs, err := args2URLs(ctx.Args())
-		fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
-		err = pipe(URLs[0])
-		fatalIf(err.Trace(URLs[0]), "Unable to write to one or more targets.")
+		return
+	}
+
+	// extract URLs.
+	URLs, err := args2TargetURLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
+
+	maxMemory, _ := parseSize(ctx.String("max-memory"))
+	failed := 0
+	for _, result := range pipeTargets(os.Stdin, URLs, *maxMemory) {
+		if result.Err == nil {
+			if globalJSON {
+				printMsg(pipeMessage{Target: result.URL, Size: result.Size})
+			}
+			continue
+		}
+		failed++
+		if globalJSON {
+			printMsg(pipeMessage{Status: "error", Target: result.URL, Size: result.Size, Error: result.Err.ToGoError().Error()})
+			continue
+		}
+		errorIf(result.Err.Trace(result.URL), "Unable to write to ‘"+result.URL+"’.")
+	}
+	if failed > 0 {
+		fatalIf(errTargetsFailed(failed, len(URLs)).Trace(URLs...), "Unable to write to one or more targets.")
 	}
 }
@@ -0,0 +1,155 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// pipeChunkSize - bytes of stdin read at a time and handed to every target.
+var pipeChunkSize int64 = 1024 * 1024
+
+// pipeResult - outcome of streaming stdin to one target.
+type pipeResult struct {
+	URL  string
+	Size int64
+	Err  *probe.Error
+}
+
+// pipeTarget - stream of chunks of the input for one target. It is handed
+// to Put as its reader, which is only seekable to where it already is.
+type pipeTarget struct {
+	queue   chan []byte
+	done    chan struct{} // Closed once Put returned.
+	chunk   []byte
+	offset  int64
+	readErr error // Set before queue is closed if the input failed.
+}
+
+// Read - read the queued chunks, io.EOF once the input ended.
+func (t *pipeTarget) Read(p []byte) (int, error) {
+	for len(t.chunk) == 0 {
+		chunk, ok := <-t.queue
+		if !ok {
+			if t.readErr != nil {
+				return 0, t.readErr
+			}
+			return 0, io.EOF
+		}
+		t.chunk = chunk
+	}
+	n := copy(p, t.chunk)
+	t.chunk = t.chunk[n:]
+	t.offset += int64(n)
+	return n, nil
+}
+
+// Seek - a stream cannot go back, seeking to the current offset is allowed
+// since targets resuming a partial upload seek to where it ended.
+func (t *pipeTarget) Seek(offset int64, whence int) (int64, error) {
+	switch {
+	case whence == 0 && offset == t.offset, whence == 1 && offset == 0:
+		return t.offset, nil
+	}
+	return t.offset, errors.New("Unable to seek in a stream.")
+}
+
+// pipeTargets - stream reader to all targets concurrently. Chunks are shared
+// by all targets and each target queues at most maxMemory worth of them, so
+// the slowest target holds back reading for all of them. A failed target is
+// dropped and does not affect the others. Returns one result per target.
+func pipeTargets(reader io.Reader, targetURLs []string, maxMemory int64) []pipeResult {
+	chunkSize := pipeChunkSize
+	depth := int(maxMemory / chunkSize)
+	if depth < 1 {
+		chunkSize = maxMemory
+		depth = 1
+	}
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+
+	results := make([]pipeResult, len(targetURLs))
+	targets := make([]*pipeTarget, len(targetURLs))
+	wg := new(sync.WaitGroup)
+	for i, targetURL := range targetURLs {
+		target := &pipeTarget{
+			queue: make(chan []byte, depth),
+			done:  make(chan struct{}),
+		}
+		targets[i] = target
+		results[i].URL = targetURL
+		wg.Add(1)
+		go func(i int, target *pipeTarget) {
+			defer wg.Done()
+			defer close(target.done)
+			// Size is unknown, even for local files, for example /proc files.
+			err := putTarget(results[i].URL, target, -1)
+			if err != nil {
+				results[i].Err = err.Trace(results[i].URL)
+			}
+			results[i].Size = target.offset
+		}(i, target)
+	}
+
+	var readErr error
+	live := len(targets)
+	for live > 0 {
+		chunk := make([]byte, chunkSize)
+		n, e := io.ReadFull(reader, chunk)
+		if n > 0 {
+			for i, target := range targets {
+				if target == nil {
+					continue
+				}
+				select {
+				case target.queue <- chunk[:n]:
+				case <-target.done:
+					// Failed, or finished without reading everything.
+					targets[i] = nil
+					live--
+				}
+			}
+		}
+		if e == io.EOF || e == io.ErrUnexpectedEOF {
+			break
+		}
+		if e != nil {
+			if pathErr, ok := e.(*os.PathError); ok && pathErr.Err == syscall.EPIPE {
+				// stdin closed by the user. Gracefully end.
+				break
+			}
+			readErr = e
+			break
+		}
+	}
+	for _, target := range targets {
+		if target == nil {
+			continue
+		}
+		target.readErr = readErr
+		close(target.queue)
+	}
+	wg.Wait()
+	return results
+}
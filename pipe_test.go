@@ -0,0 +1,63 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestPipeTargets(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	// Many small chunks, with room for only two of them.
+	defer func(size int64) { pipeChunkSize = size }(pipeChunkSize)
+	pipeChunkSize = 3
+
+	notFolder := filepath.Join(root, "file")
+	c.Assert(ioutil.WriteFile(notFolder, []byte("data"), 0600), IsNil)
+
+	data := bytes.Repeat([]byte("hello world\n"), 100)
+	targetURLs := []string{
+		filepath.Join(root, "target1"),
+		filepath.Join(notFolder, "target2"),
+		filepath.Join(root, "folder", "target3"),
+	}
+	results := pipeTargets(bytes.NewReader(data), targetURLs, 6)
+	c.Assert(len(results), Equals, 3)
+
+	for _, i := range []int{0, 2} {
+		c.Assert(results[i].URL, Equals, targetURLs[i])
+		c.Assert(results[i].Err, IsNil)
+		c.Assert(results[i].Size, Equals, int64(len(data)))
+		written, e := ioutil.ReadFile(targetURLs[i])
+		c.Assert(e, IsNil)
+		c.Assert(written, DeepEquals, data)
+	}
+	c.Assert(results[1].Err, Not(IsNil))
+
+	// Smaller than a chunk.
+	results = pipeTargets(bytes.NewReader(data), targetURLs[:1], 1)
+	c.Assert(results[0].Err, IsNil)
+	c.Assert(results[0].Size, Equals, int64(len(data)))
+}
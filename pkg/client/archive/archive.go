@@ -0,0 +1,476 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package archive implements a client of entries within tar and zip
+// archives, addressed by filesystem paths going through the archive as if
+// it were a folder, as in ‘backup.tar.gz/folder/file’. Archives are read in
+// place and written as a stream, entries are appended in the order they
+// are put and the archive is complete once Close is called.
+package archive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// Archive formats, told apart by extension.
+const (
+	formatTar   = "tar"
+	formatTarGz = "tar.gz"
+	formatZip   = "zip"
+)
+
+// archiveFormat - format of an archive by the extension of its name, empty if not an archive.
+func archiveFormat(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar"):
+		return formatTar
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return formatTarGz
+	case strings.HasSuffix(lower, ".zip"):
+		return formatZip
+	}
+	return ""
+}
+
+// Split - path of the archive a filesystem path goes through and the name
+// of the entry within it, ok is false for paths not going through an
+// archive. Archives have a known extension and are followed by a separator,
+// ‘backup.tar’ on its own is the archive file, ‘backup.tar/’ its root.
+// Folders named like archives are left alone.
+func Split(fpath string) (archivePath, entryName string, ok bool) {
+	for i := 1; i < len(fpath); i++ {
+		if !os.IsPathSeparator(fpath[i]) || archiveFormat(fpath[:i]) == "" {
+			continue
+		}
+		if st, e := os.Stat(fpath[:i]); e == nil && st.IsDir() {
+			continue
+		}
+		return fpath[:i], filepath.ToSlash(fpath[i+1:]), true
+	}
+	return "", "", false
+}
+
+// archiveClient - client of an entry within an archive.
+type archiveClient struct {
+	PathURL     *client.URL
+	archivePath string
+	// Slash separated, ends with a slash for folders given as such.
+	entryName string
+}
+
+// New - instantiate a new archive client.
+func New(fpath string) (client.Client, *probe.Error) {
+	archivePath, entryName, ok := Split(fpath)
+	if !ok {
+		return nil, probe.NewError(errors.New("‘" + fpath + "’ is not within an archive."))
+	}
+	return &archiveClient{
+		PathURL:     client.NewURL(fpath),
+		archivePath: archivePath,
+		entryName:   entryName,
+	}, nil
+}
+
+// GetURL get url.
+func (c *archiveClient) GetURL() client.URL {
+	return *c.PathURL
+}
+
+// entryURL - URL of an entry of the archive.
+func (c *archiveClient) entryURL(name string) client.URL {
+	url := *c.PathURL
+	url.Path = c.archivePath + string(url.Separator) + filepath.FromSlash(name)
+	return url
+}
+
+// notFound - error of a missing entry.
+func (c *archiveClient) notFound() *probe.Error {
+	return probe.NewError(client.PathNotFound{Path: c.PathURL.Path})
+}
+
+// getIndex - index of the archive, a missing archive is a missing path.
+func (c *archiveClient) getIndex() (*index, *probe.Error) {
+	idx, err := getIndex(c.archivePath)
+	if err != nil {
+		if os.IsNotExist(err.ToGoError()) {
+			return nil, c.notFound()
+		}
+		return nil, err.Trace(c.PathURL.Path)
+	}
+	return idx, nil
+}
+
+// entryContent - content of an entry, file attributes are only set on stats.
+func (c *archiveClient) entryContent(en *entry, withMetadata bool) *client.Content {
+	content := &client.Content{
+		URL:  c.entryURL(en.name),
+		Time: en.modTime,
+		Size: en.size,
+		Type: en.mode,
+	}
+	if !withMetadata || en.mode.IsDir() {
+		return content
+	}
+	content.Metadata = map[string]string{
+		client.MetadataMode:  strconv.FormatUint(uint64(en.mode.Perm()), 8),
+		client.MetadataMtime: en.modTime.UTC().Format(time.RFC3339Nano),
+	}
+	if en.hasOwner {
+		content.Metadata[client.MetadataUID] = strconv.Itoa(en.uid)
+		content.Metadata[client.MetadataGID] = strconv.Itoa(en.gid)
+	}
+	if en.mode&os.ModeSymlink != 0 {
+		// Links within archives cannot be followed, they are always preserved.
+		content.Metadata[client.MetadataSymlink] = en.linkname
+	}
+	return content
+}
+
+// Stat - metadata of an entry, the root of an archive is a folder.
+func (c *archiveClient) Stat(ctx context.Context) (*client.Content, *probe.Error) {
+	idx, err := c.getIndex()
+	if err != nil {
+		return nil, err.Trace(c.PathURL.Path)
+	}
+	name := strings.TrimSuffix(c.entryName, "/")
+	if name == "" {
+		return &client.Content{URL: *c.PathURL, Time: idx.modTime, Type: os.ModeDir}, nil
+	}
+	en, ok := idx.entries[name]
+	if !ok || (c.entryName != name && !en.mode.IsDir()) {
+		return nil, c.notFound()
+	}
+	content := c.entryContent(en, true)
+	content.URL = *c.PathURL
+	return content, nil
+}
+
+// List - list entries the way folders are listed, entries of a folder
+// given with a trailing separator, or entries matching the name otherwise.
+// Recursive listings contain files and links only.
+func (c *archiveClient) List(ctx context.Context, recursive, incomplete bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content)
+	go func() {
+		defer close(contentCh)
+		// Archives are complete once written, there are no incomplete entries.
+		if incomplete {
+			return
+		}
+		idx, err := c.getIndex()
+		if err != nil {
+			contentCh <- &client.Content{Err: err.Trace(c.PathURL.Path)}
+			return
+		}
+		for _, content := range c.list(idx, recursive) {
+			select {
+			case contentCh <- content:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return contentCh
+}
+
+func (c *archiveClient) list(idx *index, recursive bool) []*client.Content {
+	name := strings.TrimSuffix(c.entryName, "/")
+	en, exists := idx.entries[name]
+	if name != "" && !exists && c.entryName != name {
+		return []*client.Content{{Err: c.notFound()}}
+	}
+	var contents []*client.Content
+	switch {
+	case exists && !en.mode.IsDir():
+		// A file lists as itself.
+		contents = append(contents, c.entryContent(en, false))
+	case recursive:
+		prefix := ""
+		if name != "" {
+			prefix = name + "/"
+			if !exists {
+				prefix = name
+			}
+		}
+		for _, entryName := range idx.names {
+			if en := idx.entries[entryName]; strings.HasPrefix(entryName, prefix) && !en.mode.IsDir() {
+				contents = append(contents, c.entryContent(en, false))
+			}
+		}
+	case exists && c.entryName == name:
+		// A folder without a trailing separator lists as itself.
+		contents = append(contents, c.entryContent(en, false))
+	case name == "" || exists:
+		for _, entryName := range idx.names {
+			if parentOf(entryName) == name {
+				contents = append(contents, c.entryContent(idx.entries[entryName], false))
+			}
+		}
+	default:
+		parent := parentOf(name)
+		for _, entryName := range idx.names {
+			if parentOf(entryName) == parent && strings.HasPrefix(entryName, name) {
+				contents = append(contents, c.entryContent(idx.entries[entryName], false))
+			}
+		}
+	}
+	if len(contents) == 0 && name != "" && !exists {
+		return []*client.Content{{Err: c.notFound()}}
+	}
+	return contents
+}
+
+// parentOf - folder of an entry, empty at the root of the archive.
+func parentOf(name string) string {
+	parent := path.Dir(name)
+	if parent == "." {
+		return ""
+	}
+	return parent
+}
+
+// Get - reader of [offset, offset+length) of an entry, length '0' reads till
+// the end. Links read as empty files.
+func (c *archiveClient) Get(ctx context.Context, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	idx, err := c.getIndex()
+	if err != nil {
+		return nil, err.Trace(c.PathURL.Path)
+	}
+	en, ok := idx.entries[strings.TrimSuffix(c.entryName, "/")]
+	if !ok {
+		return nil, c.notFound()
+	}
+	if en.mode.IsDir() {
+		return nil, probe.NewError(client.PathIsDir{Path: c.PathURL.Path})
+	}
+	if offset < 0 || (en.size > 0 && offset > en.size) {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	return newEntryReader(idx, en, offset, length), nil
+}
+
+// Put - append an entry to the archive.
+func (c *archiveClient) Put(ctx context.Context, data io.ReadSeeker, size int64) *probe.Error {
+	return c.PutWithMetadata(ctx, data, size, nil)
+}
+
+// PutWithMetadata - append an entry with file attributes and links taken
+// from metadata. The archive is created anew by the first entry put into
+// it, existing archives cannot be added to.
+func (c *archiveClient) PutWithMetadata(ctx context.Context, data io.ReadSeeker, size int64, metadata map[string]string) *probe.Error {
+	if _, ok := metadata[client.MetadataTags]; ok {
+		return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "archive"})
+	}
+	name := cleanName(c.entryName)
+	if name == "" || strings.HasSuffix(c.entryName, "/") {
+		return probe.NewError(client.PathIsDir{Path: c.PathURL.Path})
+	}
+	w, err := getWriter(c.archivePath)
+	if err != nil {
+		return err.Trace(c.archivePath)
+	}
+	if err = w.put(name, client.ContextReader(ctx, data), size, metadata); err != nil {
+		return err.Trace(c.PathURL.Path)
+	}
+	return nil
+}
+
+// Copy - entries are copied by reading them.
+func (c *archiveClient) Copy(ctx context.Context, source client.URL, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "Copy", APIType: "archive"})
+}
+
+// MakeBucket - folders come into being with the entries put in them.
+func (c *archiveClient) MakeBucket(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "MakeBucket", APIType: "archive"})
+}
+
+// GetBucketAccess - access policies not implemented for archives.
+func (c *archiveClient) GetBucketAccess(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetBucketAccess", APIType: "archive"})
+}
+
+// SetBucketAccess - access policies not implemented for archives.
+func (c *archiveClient) SetBucketAccess(ctx context.Context, access string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetBucketAccess", APIType: "archive"})
+}
+
+// ShareDownload - sharing not implemented for archives.
+func (c *archiveClient) ShareDownload(ctx context.Context, expires time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "ShareDownload", APIType: "archive"})
+}
+
+// ShareUpload - sharing not implemented for archives.
+func (c *archiveClient) ShareUpload(ctx context.Context, recursive bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "ShareUpload", APIType: "archive"})
+}
+
+// Remove - entries of archives cannot be removed.
+func (c *archiveClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "Remove", APIType: "archive"})
+}
+
+// RemoveBatch - entries of archives cannot be removed, every content is sent back with an error.
+func (c *archiveClient) RemoveBatch(ctx context.Context, contentCh <-chan *client.Content) <-chan *client.Content {
+	resultCh := make(chan *client.Content)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			content.Err = probe.NewError(client.APINotImplemented{API: "RemoveBatch", APIType: "archive"})
+			resultCh <- content
+		}
+	}()
+	return client.ForwardContents(ctx, resultCh)
+}
+
+// GetVersioning - versioning not implemented for archives.
+func (c *archiveClient) GetVersioning(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetVersioning", APIType: "archive"})
+}
+
+// SetVersioning - versioning not implemented for archives.
+func (c *archiveClient) SetVersioning(ctx context.Context, status string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetVersioning", APIType: "archive"})
+}
+
+// ListVersions - versioning not implemented for archives.
+func (c *archiveClient) ListVersions(ctx context.Context, recursive bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content, 1)
+	contentCh <- &client.Content{Err: probe.NewError(client.APINotImplemented{API: "ListVersions", APIType: "archive"})}
+	close(contentCh)
+	return contentCh
+}
+
+// StatVersion - versioning not implemented for archives.
+func (c *archiveClient) StatVersion(ctx context.Context, versionID string) (*client.Content, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "StatVersion", APIType: "archive"})
+}
+
+// GetVersion - versioning not implemented for archives.
+func (c *archiveClient) GetVersion(ctx context.Context, versionID string, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetVersion", APIType: "archive"})
+}
+
+// RemoveVersion - versioning not implemented for archives.
+func (c *archiveClient) RemoveVersion(ctx context.Context, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveVersion", APIType: "archive"})
+}
+
+// GetTags - tagging not implemented for archives.
+func (c *archiveClient) GetTags(ctx context.Context) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetTags", APIType: "archive"})
+}
+
+// SetTags - tagging not implemented for archives.
+func (c *archiveClient) SetTags(ctx context.Context, tags map[string]string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "archive"})
+}
+
+// DeleteTags - tagging not implemented for archives.
+func (c *archiveClient) DeleteTags(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteTags", APIType: "archive"})
+}
+
+// MakeBucketWithLock - object lock not implemented for archives.
+func (c *archiveClient) MakeBucketWithLock(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "MakeBucketWithLock", APIType: "archive"})
+}
+
+// GetRetention - object lock not implemented for archives.
+func (c *archiveClient) GetRetention(ctx context.Context) (client.Retention, *probe.Error) {
+	return client.Retention{}, probe.NewError(client.APINotImplemented{API: "GetRetention", APIType: "archive"})
+}
+
+// SetRetention - object lock not implemented for archives.
+func (c *archiveClient) SetRetention(ctx context.Context, retention client.Retention) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetRetention", APIType: "archive"})
+}
+
+// GetLegalHold - object lock not implemented for archives.
+func (c *archiveClient) GetLegalHold(ctx context.Context) (bool, *probe.Error) {
+	return false, probe.NewError(client.APINotImplemented{API: "GetLegalHold", APIType: "archive"})
+}
+
+// SetLegalHold - object lock not implemented for archives.
+func (c *archiveClient) SetLegalHold(ctx context.Context, on bool) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetLegalHold", APIType: "archive"})
+}
+
+// GetNotifications - notifications not implemented for archives.
+func (c *archiveClient) GetNotifications(ctx context.Context) ([]client.NotificationConfig, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetNotifications", APIType: "archive"})
+}
+
+// AddNotification - notifications not implemented for archives.
+func (c *archiveClient) AddNotification(ctx context.Context, config client.NotificationConfig) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "AddNotification", APIType: "archive"})
+}
+
+// RemoveNotification - notifications not implemented for archives.
+func (c *archiveClient) RemoveNotification(ctx context.Context, arn string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveNotification", APIType: "archive"})
+}
+
+// Watch - notifications not implemented for archives.
+func (c *archiveClient) Watch(ctx context.Context, events []string, recursive bool) (<-chan *client.Event, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Watch", APIType: "archive"})
+}
+
+// GetCORS - CORS configuration not implemented for archives.
+func (c *archiveClient) GetCORS(ctx context.Context) ([]client.CORSRule, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetCORS", APIType: "archive"})
+}
+
+// SetCORS - CORS configuration not implemented for archives.
+func (c *archiveClient) SetCORS(ctx context.Context, rules []client.CORSRule) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetCORS", APIType: "archive"})
+}
+
+// DeleteCORS - CORS configuration not implemented for archives.
+func (c *archiveClient) DeleteCORS(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteCORS", APIType: "archive"})
+}
+
+// GetWebsite - static website configuration not implemented for archives.
+func (c *archiveClient) GetWebsite(ctx context.Context) (client.Website, *probe.Error) {
+	return client.Website{}, probe.NewError(client.APINotImplemented{API: "GetWebsite", APIType: "archive"})
+}
+
+// SetWebsite - static website configuration not implemented for archives.
+func (c *archiveClient) SetWebsite(ctx context.Context, website client.Website) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetWebsite", APIType: "archive"})
+}
+
+// DeleteWebsite - static website configuration not implemented for archives.
+func (c *archiveClient) DeleteWebsite(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteWebsite", APIType: "archive"})
+}
+
+// Select - queries are not implemented for archives, the caller evaluates them.
+func (c *archiveClient) Select(ctx context.Context, query client.SelectQuery) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Select", APIType: "archive"})
+}
@@ -0,0 +1,211 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// testEntries - entries of the test archives in the order they are put.
+var testEntries = []struct {
+	name string
+	data string
+}{
+	{"dataset/README", "hello world"},
+	{"dataset/parts/part.1", "part one"},
+	{"dataset/parts/part.2", "part two"},
+	{"index", "0123456789"},
+}
+
+// newTestArchive - archive of the test entries written through the client.
+func newTestArchive(c *C, archivePath string) {
+	for _, en := range testEntries {
+		clnt, err := New(filepath.Join(archivePath, en.name))
+		c.Assert(err, IsNil)
+		metadata := map[string]string{client.MetadataMode: "640"}
+		c.Assert(clnt.PutWithMetadata(context.Background(), bytes.NewReader([]byte(en.data)), int64(len(en.data)), metadata), IsNil)
+	}
+	c.Assert(Close(), IsNil)
+}
+
+// listNames - names relative to the archive listed under a path.
+func listNames(c *C, archivePath, fpath string, recursive bool) []string {
+	clnt, err := New(fpath)
+	c.Assert(err, IsNil)
+	var names []string
+	for content := range clnt.List(context.Background(), recursive, false) {
+		c.Assert(content.Err, IsNil)
+		rel, e := filepath.Rel(archivePath, content.URL.Path)
+		c.Assert(e, IsNil)
+		names = append(names, filepath.ToSlash(rel))
+	}
+	return names
+}
+
+func (s *MySuite) TestSplit(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "archive-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	c.Assert(os.Mkdir(filepath.Join(root, "folder.zip"), 0700), IsNil)
+
+	archivePath, entryName, ok := Split(filepath.Join(root, "backup.tar.gz") + "/dir/file")
+	c.Assert(ok, Equals, true)
+	c.Assert(archivePath, Equals, filepath.Join(root, "backup.tar.gz"))
+	c.Assert(entryName, Equals, "dir/file")
+
+	_, entryName, ok = Split(filepath.Join(root, "backup.TGZ") + "/")
+	c.Assert(ok, Equals, true)
+	c.Assert(entryName, Equals, "")
+
+	_, _, ok = Split(filepath.Join(root, "backup.tar"))
+	c.Assert(ok, Equals, false)
+	_, _, ok = Split(filepath.Join(root, "folder.zip", "file"))
+	c.Assert(ok, Equals, false)
+}
+
+func (s *MySuite) TestList(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "archive-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"test.tar", "test.tar.gz", "test.zip"} {
+		archivePath := filepath.Join(root, name)
+		newTestArchive(c, archivePath)
+
+		c.Assert(listNames(c, archivePath, archivePath+"/", false), DeepEquals, []string{"dataset", "index"})
+		c.Assert(listNames(c, archivePath, archivePath+"/dataset/", false), DeepEquals, []string{"dataset/README", "dataset/parts"})
+		c.Assert(listNames(c, archivePath, archivePath+"/dataset/parts/part", false), DeepEquals, []string{"dataset/parts/part.1", "dataset/parts/part.2"})
+		c.Assert(listNames(c, archivePath, archivePath+"/index", false), DeepEquals, []string{"index"})
+		c.Assert(listNames(c, archivePath, archivePath+"/dataset", true), DeepEquals,
+			[]string{"dataset/README", "dataset/parts/part.1", "dataset/parts/part.2"})
+		c.Assert(listNames(c, archivePath, archivePath+"/", true), DeepEquals,
+			[]string{"dataset/README", "dataset/parts/part.1", "dataset/parts/part.2", "index"})
+
+		clnt, err := New(archivePath + "/dataset/parts")
+		c.Assert(err, IsNil)
+		content, err := clnt.Stat(context.Background())
+		c.Assert(err, IsNil)
+		c.Assert(content.Type.IsDir(), Equals, true)
+
+		clnt, err = New(archivePath + "/dataset/README")
+		c.Assert(err, IsNil)
+		content, err = clnt.Stat(context.Background())
+		c.Assert(err, IsNil)
+		c.Assert(content.Type.IsRegular(), Equals, true)
+		c.Assert(content.Size, Equals, int64(len("hello world")))
+		c.Assert(content.Metadata[client.MetadataMode], Equals, "640")
+
+		clnt, err = New(archivePath + "/missing")
+		c.Assert(err, IsNil)
+		_, err = clnt.Stat(context.Background())
+		c.Assert(err, Not(IsNil))
+		_, ok := err.ToGoError().(client.PathNotFound)
+		c.Assert(ok, Equals, true)
+	}
+}
+
+func (s *MySuite) TestGetRange(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "archive-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"test.tar", "test.tgz", "test.zip"} {
+		archivePath := filepath.Join(root, name)
+		newTestArchive(c, archivePath)
+
+		// Entries are read out of order, compressed tar archives are read again from the start.
+		for i := len(testEntries) - 1; i >= 0; i-- {
+			clnt, err := New(filepath.Join(archivePath, testEntries[i].name))
+			c.Assert(err, IsNil)
+			reader, err := clnt.Get(context.Background(), 0, 0)
+			c.Assert(err, IsNil)
+			data, e := ioutil.ReadAll(reader)
+			c.Assert(e, IsNil)
+			c.Assert(string(data), Equals, testEntries[i].data)
+		}
+
+		clnt, err := New(archivePath + "/index")
+		c.Assert(err, IsNil)
+		reader, err := clnt.Get(context.Background(), 2, 5)
+		c.Assert(err, IsNil)
+		data, e := ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(string(data), Equals, "23456")
+
+		_, e = reader.Seek(3, 0)
+		c.Assert(e, IsNil)
+		data, e = ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(string(data), Equals, "56")
+
+		clnt, err = New(archivePath + "/dataset")
+		c.Assert(err, IsNil)
+		_, err = clnt.Get(context.Background(), 0, 0)
+		c.Assert(err, Not(IsNil))
+	}
+}
+
+func (s *MySuite) TestPutVerified(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "archive-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	archivePath := filepath.Join(root, "test.tar.gz")
+	clnt, err := New(archivePath + "/object")
+	c.Assert(err, IsNil)
+
+	data := []byte("hello")
+	metadata := map[string]string{client.MetadataMD5: hex.EncodeToString(make([]byte, md5.Size))}
+	err = clnt.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), metadata)
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.ChecksumMismatch)
+	c.Assert(ok, Equals, true)
+
+	// Entries put again supersede earlier ones of the same name.
+	sum := md5.Sum(data)
+	metadata = map[string]string{client.MetadataMD5: hex.EncodeToString(sum[:])}
+	c.Assert(clnt.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), metadata), IsNil)
+
+	// Data of unknown size is read till the end.
+	link, err := New(archivePath + "/link")
+	c.Assert(err, IsNil)
+	c.Assert(link.PutWithMetadata(context.Background(), bytes.NewReader(nil), -1, map[string]string{client.MetadataSymlink: "object"}), IsNil)
+	c.Assert(Close(), IsNil)
+
+	c.Assert(listNames(c, archivePath, archivePath+"/", true), DeepEquals, []string{"link", "object"})
+	content, err := link.Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Type&os.ModeSymlink, Equals, os.ModeSymlink)
+	c.Assert(content.Metadata[client.MetadataSymlink], Equals, "object")
+}
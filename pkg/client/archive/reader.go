@@ -0,0 +1,506 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// entry - a file, folder or symbolic link within an archive.
+type entry struct {
+	name     string
+	size     int64
+	modTime  time.Time
+	mode     os.FileMode
+	uid      int
+	gid      int
+	hasOwner bool
+	linkname string
+
+	// Position of the header among all headers of a tar archive, and its
+	// offset in uncompressed ones.
+	position     int
+	headerOffset int64
+	// Set only for zip archives.
+	zipFile *zip.File
+}
+
+// index - entries of an archive, cached for as long as the archive is unchanged.
+type index struct {
+	archivePath string
+	format      string
+	size        int64
+	modTime     time.Time
+
+	entries map[string]*entry
+	// Names of all entries in lexical order.
+	names []string
+
+	// Set only for zip archives, kept open to read entries from.
+	zipFile   *os.File
+	zipReader *zip.Reader
+
+	// Readers of compressed tar archives left after an entry, reused to
+	// read later entries without decompressing from the start again.
+	cursorMutex sync.Mutex
+	cursors     []*cursor
+}
+
+// maxCursors - number of idle cursors kept per compressed tar archive.
+const maxCursors = 4
+
+var indexes = struct {
+	sync.Mutex
+	m map[string]*index
+}{m: make(map[string]*index)}
+
+// getIndex - index of an archive, read anew if the archive changed since.
+func getIndex(archivePath string) (*index, *probe.Error) {
+	st, e := os.Stat(archivePath)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	indexes.Lock()
+	defer indexes.Unlock()
+	if idx, ok := indexes.m[archivePath]; ok {
+		if idx.size == st.Size() && idx.modTime.Equal(st.ModTime()) {
+			return idx, nil
+		}
+		idx.close()
+		delete(indexes.m, archivePath)
+	}
+	idx := &index{
+		archivePath: archivePath,
+		format:      archiveFormat(archivePath),
+		size:        st.Size(),
+		modTime:     st.ModTime(),
+		entries:     make(map[string]*entry),
+	}
+	var err *probe.Error
+	switch idx.format {
+	case formatZip:
+		err = idx.readZip()
+	default:
+		err = idx.readTar()
+	}
+	if err != nil {
+		idx.close()
+		return nil, err.Trace(archivePath)
+	}
+	sort.Strings(idx.names)
+	indexes.m[archivePath] = idx
+	return idx, nil
+}
+
+// forgetIndex - drop the cached index of an archive being rewritten.
+func forgetIndex(archivePath string) {
+	indexes.Lock()
+	defer indexes.Unlock()
+	if idx, ok := indexes.m[archivePath]; ok {
+		idx.close()
+		delete(indexes.m, archivePath)
+	}
+}
+
+// cleanName - name of an entry relative to the root of the archive, without
+// leading "./" or "/" and trailing slashes.
+func cleanName(name string) string {
+	name = path.Clean("/" + strings.Replace(name, "\\", "/", -1))
+	return strings.TrimPrefix(name, "/")
+}
+
+// add - record an entry along with folders leading to it which have no
+// entry of their own. Later entries of the same name supersede earlier ones.
+func (idx *index) add(en *entry) {
+	if _, ok := idx.entries[en.name]; !ok {
+		idx.names = append(idx.names, en.name)
+	}
+	idx.entries[en.name] = en
+	for dir := path.Dir(en.name); dir != "."; dir = path.Dir(dir) {
+		if _, ok := idx.entries[dir]; ok {
+			break
+		}
+		idx.entries[dir] = &entry{name: dir, mode: os.ModeDir | 0755, modTime: en.modTime}
+		idx.names = append(idx.names, dir)
+	}
+}
+
+// countingReader - counts bytes read, tar.Reader reads no further than the
+// header it returns, so the count is the offset of its data.
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, e := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, e
+}
+
+// blockSize - tar archives are made of blocks of this size.
+const blockSize = 512
+
+// readTar - index the headers of a tar archive, compressed or not.
+func (idx *index) readTar() *probe.Error {
+	file, e := os.Open(idx.archivePath)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer file.Close()
+	counter := &countingReader{Reader: file}
+	var r io.Reader = counter
+	if idx.format == formatTarGz {
+		gz, e := gzip.NewReader(file)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	var headerOffset int64
+	for position := 0; ; position++ {
+		entryOffset := headerOffset
+		hdr, e := tr.Next()
+		if e == io.EOF {
+			return nil
+		}
+		if e != nil {
+			return probe.NewError(e)
+		}
+		// Data is padded to whole blocks, the next header follows.
+		headerOffset = counter.n + (hdr.Size+blockSize-1)/blockSize*blockSize
+		name := cleanName(hdr.Name)
+		if name == "" {
+			continue
+		}
+		en := &entry{
+			name:         name,
+			size:         hdr.Size,
+			modTime:      hdr.ModTime,
+			mode:         os.FileMode(hdr.Mode).Perm(),
+			uid:          hdr.Uid,
+			gid:          hdr.Gid,
+			hasOwner:     true,
+			position:     position,
+			headerOffset: entryOffset,
+		}
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+		case tar.TypeDir:
+			en.mode |= os.ModeDir
+			en.size = 0
+		case tar.TypeSymlink:
+			en.mode |= os.ModeSymlink
+			en.linkname = hdr.Linkname
+			en.size = 0
+		default:
+			// Hard links, devices and the like have no content of their own.
+			continue
+		}
+		idx.add(en)
+	}
+}
+
+// readZip - index the central directory of a zip archive.
+func (idx *index) readZip() *probe.Error {
+	file, e := os.Open(idx.archivePath)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	idx.zipFile = file
+	zr, e := zip.NewReader(file, idx.size)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	idx.zipReader = zr
+	for _, zf := range zr.File {
+		name := cleanName(zf.Name)
+		if name == "" {
+			continue
+		}
+		en := &entry{
+			name:    name,
+			size:    int64(zf.UncompressedSize64),
+			modTime: zf.Modified,
+			mode:    zf.Mode(),
+			zipFile: zf,
+		}
+		switch {
+		case zf.Mode().IsDir():
+			en.size = 0
+		case zf.Mode()&os.ModeSymlink != 0:
+			// Targets of symbolic links are stored as their content.
+			rc, e := zf.Open()
+			if e != nil {
+				return probe.NewError(e)
+			}
+			target, e := ioutil.ReadAll(rc)
+			rc.Close()
+			if e != nil {
+				return probe.NewError(e)
+			}
+			en.linkname = string(target)
+			en.size = 0
+		case !zf.Mode().IsRegular():
+			continue
+		}
+		idx.add(en)
+	}
+	return nil
+}
+
+// close - release the files kept open by the index and its cursors.
+func (idx *index) close() {
+	if idx.zipFile != nil {
+		idx.zipFile.Close()
+	}
+	idx.cursorMutex.Lock()
+	defer idx.cursorMutex.Unlock()
+	for _, cur := range idx.cursors {
+		cur.close()
+	}
+	idx.cursors = nil
+}
+
+// cursor - a sequential reader of a compressed tar archive, positioned after
+// the header of the entry at position.
+type cursor struct {
+	file     *os.File
+	gzip     *gzip.Reader
+	tar      *tar.Reader
+	position int
+}
+
+func (cur *cursor) close() {
+	cur.gzip.Close()
+	cur.file.Close()
+}
+
+// takeCursor - the idle cursor furthest along but not past position, or a
+// new one at the start of the archive.
+func (idx *index) takeCursor(position int) (*cursor, error) {
+	idx.cursorMutex.Lock()
+	best := -1
+	for i, cur := range idx.cursors {
+		if cur.position < position && (best < 0 || cur.position > idx.cursors[best].position) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		cur := idx.cursors[best]
+		idx.cursors = append(idx.cursors[:best], idx.cursors[best+1:]...)
+		idx.cursorMutex.Unlock()
+		return cur, nil
+	}
+	idx.cursorMutex.Unlock()
+
+	file, e := os.Open(idx.archivePath)
+	if e != nil {
+		return nil, e
+	}
+	gz, e := gzip.NewReader(file)
+	if e != nil {
+		file.Close()
+		return nil, e
+	}
+	return &cursor{file: file, gzip: gz, tar: tar.NewReader(gz), position: -1}, nil
+}
+
+// parkCursor - keep a cursor for later reads, unless enough are kept already.
+func (idx *index) parkCursor(cur *cursor) {
+	idx.cursorMutex.Lock()
+	defer idx.cursorMutex.Unlock()
+	if len(idx.cursors) >= maxCursors {
+		cur.close()
+		return
+	}
+	idx.cursors = append(idx.cursors, cur)
+}
+
+// open - reader of the content of an entry, done releases it and is told
+// whether reading failed.
+func (idx *index) open(en *entry) (data io.Reader, done func(failed bool), e error) {
+	switch idx.format {
+	case formatZip:
+		rc, e := en.zipFile.Open()
+		if e != nil {
+			return nil, nil, e
+		}
+		return rc, func(bool) { rc.Close() }, nil
+	case formatTar:
+		// Uncompressed archives are read from the header of the entry on.
+		file, e := os.Open(idx.archivePath)
+		if e != nil {
+			return nil, nil, e
+		}
+		if _, e = file.Seek(en.headerOffset, 0); e != nil {
+			file.Close()
+			return nil, nil, e
+		}
+		tr := tar.NewReader(file)
+		if _, e = tr.Next(); e != nil {
+			file.Close()
+			return nil, nil, e
+		}
+		return tr, func(bool) { file.Close() }, nil
+	}
+	cur, e := idx.takeCursor(en.position)
+	if e != nil {
+		return nil, nil, e
+	}
+	for cur.position < en.position {
+		if _, e = cur.tar.Next(); e != nil {
+			cur.close()
+			if e == io.EOF {
+				e = io.ErrUnexpectedEOF
+			}
+			return nil, nil, e
+		}
+		cur.position++
+	}
+	return cur.tar, func(failed bool) {
+		if failed {
+			cur.close()
+			return
+		}
+		idx.parkCursor(cur)
+	}, nil
+}
+
+// entryReader is an io.ReadSeeker over the content of an entry. The entry
+// is opened lazily upon first Read() and opened again after every Seek().
+type entryReader struct {
+	mutex *sync.Mutex
+
+	idx    *index
+	en     *entry
+	offset int64
+	length int64
+	start  int64
+	body   io.Reader
+	done   func(failed bool)
+}
+
+// newEntryReader - reader for [offset, offset+length) of an entry, length '0' reads till the end.
+func newEntryReader(idx *index, en *entry, offset, length int64) *entryReader {
+	return &entryReader{
+		mutex:  new(sync.Mutex),
+		idx:    idx,
+		en:     en,
+		offset: offset,
+		start:  offset,
+		length: length,
+	}
+}
+
+// open - open the entry and skip to the current offset.
+func (r *entryReader) open() error {
+	end := r.en.size
+	if r.length > 0 && r.start+r.length < end {
+		end = r.start + r.length
+	}
+	if r.offset >= end {
+		return io.EOF
+	}
+	data, done, e := r.idx.open(r.en)
+	if e != nil {
+		return e
+	}
+	if _, e = io.CopyN(ioutil.Discard, data, r.offset); e != nil {
+		done(true)
+		return e
+	}
+	r.body = io.LimitReader(data, end-r.offset)
+	r.done = done
+	return nil
+}
+
+// release - let go of the opened entry.
+func (r *entryReader) release(failed bool) {
+	if r.body != nil {
+		r.done(failed)
+		r.body = nil
+		r.done = nil
+	}
+}
+
+// Read reads up to len(p) bytes into p.
+func (r *entryReader) Read(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.body == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if err != nil {
+		r.release(err != io.EOF)
+		// Data is handed over first, the next Read reports the end.
+		if err == io.EOF && n > 0 {
+			return n, nil
+		}
+		return n, err
+	}
+	return n, nil
+}
+
+// Seek sets the offset for the next Read, only whence '0' and '1' are supported.
+func (r *entryReader) Seek(offset int64, whence int) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	switch whence {
+	case 0:
+		offset = r.start + offset
+	case 1:
+		offset = r.offset + offset
+	default:
+		return 0, errors.New("entryReader: seeking relative to the end is not supported")
+	}
+	if offset < r.start {
+		return 0, errors.New("entryReader: negative position")
+	}
+	if offset != r.offset {
+		r.release(false)
+	}
+	r.offset = offset
+	return offset - r.start, nil
+}
+
+// Close - release the opened entry if any.
+func (r *entryReader) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.release(false)
+	return nil
+}
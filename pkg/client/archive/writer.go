@@ -0,0 +1,281 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// writer - an archive being written, entries are appended one at a time
+// and the archive is only complete once closed.
+type writer struct {
+	mutex sync.Mutex
+
+	archivePath string
+	file        *os.File
+	gzip        *gzip.Writer
+	tar         *tar.Writer
+	zip         *zip.Writer
+}
+
+var writers = struct {
+	sync.Mutex
+	m map[string]*writer
+}{m: make(map[string]*writer)}
+
+// getWriter - writer of an archive, the archive is created anew on first use.
+func getWriter(archivePath string) (*writer, *probe.Error) {
+	writers.Lock()
+	defer writers.Unlock()
+	if w, ok := writers.m[archivePath]; ok {
+		return w, nil
+	}
+	if e := os.MkdirAll(filepath.Dir(archivePath), 0775); e != nil {
+		return nil, probe.NewError(e)
+	}
+	file, e := os.Create(archivePath)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	forgetIndex(archivePath)
+	w := &writer{archivePath: archivePath, file: file}
+	switch archiveFormat(archivePath) {
+	case formatZip:
+		w.zip = zip.NewWriter(file)
+	case formatTarGz:
+		w.gzip = gzip.NewWriter(file)
+		w.tar = tar.NewWriter(w.gzip)
+	default:
+		w.tar = tar.NewWriter(file)
+	}
+	writers.m[archivePath] = w
+	return w, nil
+}
+
+// Close - complete all archives written to so far, entries cannot be added
+// to them afterwards.
+func Close() *probe.Error {
+	writers.Lock()
+	defer writers.Unlock()
+	var err *probe.Error
+	for archivePath, w := range writers.m {
+		if e := w.close(); e != nil && err == nil {
+			err = probe.NewError(e).Trace(archivePath)
+		}
+		delete(writers.m, archivePath)
+	}
+	return err
+}
+
+// close - write the trailer of the archive and close it.
+func (w *writer) close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	var e error
+	if w.zip != nil {
+		e = w.zip.Close()
+	} else {
+		e = w.tar.Close()
+		if w.gzip != nil && e == nil {
+			e = w.gzip.Close()
+		}
+	}
+	if ce := w.file.Close(); e == nil {
+		e = ce
+	}
+	forgetIndex(w.archivePath)
+	return e
+}
+
+// header - attributes of an entry taken from file attributes in metadata.
+type header struct {
+	mode     os.FileMode
+	modTime  time.Time
+	uid      int
+	gid      int
+	linkname string
+}
+
+func newHeader(metadata map[string]string) (header, *probe.Error) {
+	hdr := header{mode: 0644, modTime: time.Now().UTC()}
+	if value, ok := metadata[client.MetadataMode]; ok {
+		mode, e := strconv.ParseUint(value, 8, 32)
+		if e != nil {
+			return hdr, probe.NewError(e)
+		}
+		hdr.mode = os.FileMode(mode).Perm()
+	}
+	if value, ok := metadata[client.MetadataMtime]; ok {
+		mtime, e := time.Parse(time.RFC3339Nano, value)
+		if e != nil {
+			return hdr, probe.NewError(e)
+		}
+		hdr.modTime = mtime
+	}
+	var e error
+	if value, ok := metadata[client.MetadataUID]; ok {
+		if hdr.uid, e = strconv.Atoi(value); e != nil {
+			return hdr, probe.NewError(e)
+		}
+	}
+	if value, ok := metadata[client.MetadataGID]; ok {
+		if hdr.gid, e = strconv.Atoi(value); e != nil {
+			return hdr, probe.NewError(e)
+		}
+	}
+	if target, ok := metadata[client.MetadataSymlink]; ok {
+		hdr.linkname = target
+		hdr.mode |= os.ModeSymlink
+	}
+	return hdr, nil
+}
+
+// put - append an entry. Archives cannot be rewritten, if data turns out
+// short or corrupted the entry is still written and an error returned, an
+// entry put again under the same name supersedes it.
+func (w *writer) put(name string, data io.Reader, size int64, metadata map[string]string) *probe.Error {
+	hdr, err := newHeader(metadata)
+	if err != nil {
+		return err.Trace(name)
+	}
+	if hdr.linkname != "" {
+		data, size = bytes.NewReader(nil), 0
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	hasher := md5.New()
+	if w.zip != nil {
+		err = w.putZip(name, hdr, io.TeeReader(data, hasher), size)
+	} else {
+		err = w.putTar(name, hdr, io.TeeReader(data, hasher), size)
+	}
+	if err != nil {
+		return err.Trace(name)
+	}
+	if hdr.linkname != "" {
+		return nil
+	}
+	return verifyMD5(name, hasher, metadata)
+}
+
+// verifyMD5 - compare what was written with the checksum given in metadata, if any.
+func verifyMD5(name string, hasher hash.Hash, metadata map[string]string) *probe.Error {
+	expected, ok := metadata[client.MetadataMD5]
+	if !ok {
+		return nil
+	}
+	computed := hex.EncodeToString(hasher.Sum(nil))
+	if computed != expected {
+		return probe.NewError(client.ChecksumMismatch{Path: name, Expected: expected, Computed: computed})
+	}
+	return nil
+}
+
+// putTar - tar headers carry the size, data of unknown size is read into memory first.
+func (w *writer) putTar(name string, hdr header, data io.Reader, size int64) *probe.Error {
+	if size < 0 {
+		buf, e := ioutil.ReadAll(data)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		data, size = bytes.NewReader(buf), int64(len(buf))
+	}
+	th := &tar.Header{
+		Name:     name,
+		Mode:     int64(hdr.mode.Perm()),
+		Uid:      hdr.uid,
+		Gid:      hdr.gid,
+		ModTime:  hdr.modTime,
+		Typeflag: tar.TypeReg,
+		Size:     size,
+	}
+	if hdr.linkname != "" {
+		th.Typeflag = tar.TypeSymlink
+		th.Linkname = hdr.linkname
+	}
+	if e := w.tar.WriteHeader(th); e != nil {
+		return probe.NewError(e)
+	}
+	n, e := io.CopyN(w.tar, data, size)
+	if e != nil {
+		// Keep the archive well formed, pad the entry to its size.
+		if _, pe := io.CopyN(w.tar, zeroReader{}, size-n); pe != nil {
+			return probe.NewError(pe)
+		}
+		if e == io.EOF {
+			e = io.ErrUnexpectedEOF
+		}
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// putZip - zip entries are compressed, targets of symbolic links are their content.
+func (w *writer) putZip(name string, hdr header, data io.Reader, size int64) *probe.Error {
+	fh := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: hdr.modTime,
+	}
+	fh.SetMode(hdr.mode)
+	if hdr.linkname != "" {
+		fh.Method = zip.Store
+		data, size = bytes.NewReader([]byte(hdr.linkname)), int64(len(hdr.linkname))
+	}
+	zw, e := w.zip.CreateHeader(fh)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if size < 0 {
+		_, e = io.Copy(zw, data)
+	} else {
+		_, e = io.CopyN(zw, data, size)
+	}
+	if e != nil {
+		if e == io.EOF {
+			e = io.ErrUnexpectedEOF
+		}
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// zeroReader - an endless stream of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
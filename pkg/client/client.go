@@ -17,34 +17,81 @@
 package client
 
 import (
+	"context"
+	"encoding/hex"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-xl/pkg/probe"
 )
 
-// Client - client interface
+// Client - client interface. Cancelling ctx aborts requests in flight,
+// and closes channels returned by List, ListVersions, RemoveBatch and Watch.
 type Client interface {
 	// Common operations
-	Stat() (content *Content, err *probe.Error)
-	List(recursive, incomplete bool) <-chan *Content
+	Stat(ctx context.Context) (content *Content, err *probe.Error)
+	List(ctx context.Context, recursive, incomplete bool) <-chan *Content
 
 	// Bucket operations
-	MakeBucket() *probe.Error
-	GetBucketAccess() (access string, error *probe.Error)
-	SetBucketAccess(access string) *probe.Error
+	MakeBucket(ctx context.Context) *probe.Error
+	GetBucketAccess(ctx context.Context) (access string, error *probe.Error)
+	SetBucketAccess(ctx context.Context, access string) *probe.Error
 
 	// I/O operations
-	Get(offset, length int64) (body io.ReadSeeker, err *probe.Error)
-	Put(data io.ReadSeeker, size int64) *probe.Error
+	Get(ctx context.Context, offset, length int64) (body io.ReadSeeker, err *probe.Error)
+	Put(ctx context.Context, data io.ReadSeeker, size int64) *probe.Error
+	PutWithMetadata(ctx context.Context, data io.ReadSeeker, size int64, metadata map[string]string) *probe.Error
+	// Copy copies an object of the same host, a version of it if versionID
+	// is set, to this URL without transferring its data.
+	Copy(ctx context.Context, source URL, versionID string) *probe.Error
 
 	// I/O operations with expiration
-	ShareDownload(expires time.Duration) (string, *probe.Error)
-	ShareUpload(bool, time.Duration, string) (map[string]string, *probe.Error)
+	ShareDownload(ctx context.Context, expires time.Duration) (string, *probe.Error)
+	ShareUpload(ctx context.Context, startsWith bool, expires time.Duration, contentType string) (map[string]string, *probe.Error)
 
 	// Delete operations
-	Remove(incomplete bool) *probe.Error
+	Remove(ctx context.Context, incomplete bool) *probe.Error
+	RemoveBatch(ctx context.Context, contentCh <-chan *Content) <-chan *Content
+
+	// Versioning operations
+	GetVersioning(ctx context.Context) (status string, err *probe.Error)
+	SetVersioning(ctx context.Context, status string) *probe.Error
+	ListVersions(ctx context.Context, recursive bool) <-chan *Content
+	StatVersion(ctx context.Context, versionID string) (content *Content, err *probe.Error)
+	GetVersion(ctx context.Context, versionID string, offset, length int64) (body io.ReadSeeker, err *probe.Error)
+	RemoveVersion(ctx context.Context, versionID string) *probe.Error
+
+	// Tagging operations
+	GetTags(ctx context.Context) (tags map[string]string, err *probe.Error)
+	SetTags(ctx context.Context, tags map[string]string) *probe.Error
+	DeleteTags(ctx context.Context) *probe.Error
+
+	// Object lock operations
+	MakeBucketWithLock(ctx context.Context) *probe.Error
+	GetRetention(ctx context.Context) (retention Retention, err *probe.Error)
+	SetRetention(ctx context.Context, retention Retention) *probe.Error
+	GetLegalHold(ctx context.Context) (on bool, err *probe.Error)
+	SetLegalHold(ctx context.Context, on bool) *probe.Error
+
+	// Notification operations
+	GetNotifications(ctx context.Context) (configs []NotificationConfig, err *probe.Error)
+	AddNotification(ctx context.Context, config NotificationConfig) *probe.Error
+	RemoveNotification(ctx context.Context, arn string) *probe.Error
+	Watch(ctx context.Context, events []string, recursive bool) (eventCh <-chan *Event, err *probe.Error)
+
+	// Bucket configuration operations
+	GetCORS(ctx context.Context) (rules []CORSRule, err *probe.Error)
+	SetCORS(ctx context.Context, rules []CORSRule) *probe.Error
+	DeleteCORS(ctx context.Context) *probe.Error
+	GetWebsite(ctx context.Context) (website Website, err *probe.Error)
+	SetWebsite(ctx context.Context, website Website) *probe.Error
+	DeleteWebsite(ctx context.Context) *probe.Error
+
+	// Query operations
+	Select(ctx context.Context, query SelectQuery) (records io.ReadCloser, err *probe.Error)
 
 	// GetURL returns back internal url
 	GetURL() URL
@@ -57,6 +104,192 @@ type Content struct {
 	Size int64
 	Type os.FileMode
 	Err  *probe.Error
+
+	// Set only for versioned listings and stats.
+	VersionID      string `json:",omitempty"`
+	IsLatest       bool   `json:",omitempty"`
+	IsDeleteMarker bool   `json:",omitempty"`
+
+	// Set only where known, object storage reports ETag and storage class
+	// on listings, content type only on stats.
+	ETag         string `json:",omitempty"`
+	StorageClass string `json:",omitempty"`
+	ContentType  string `json:",omitempty"`
+
+	// Set only where the ETag is known to be the MD5 checksum of the data,
+	// which object storage listings can not tell.
+	MD5 string `json:",omitempty"`
+
+	// Set only on stats, user metadata of objects and file attributes of files.
+	Metadata map[string]string `json:",omitempty"`
+}
+
+// ForwardContents - forward contents of contentCh until it is closed or ctx is
+// cancelled. Once cancelled the returned channel is closed and the rest of
+// contentCh drained, so that its sender finishes.
+func ForwardContents(ctx context.Context, contentCh <-chan *Content) <-chan *Content {
+	forwardCh := make(chan *Content)
+	go func() {
+		defer close(forwardCh)
+		for content := range contentCh {
+			select {
+			case forwardCh <- content:
+			case <-ctx.Done():
+				go func() {
+					for range contentCh {
+					}
+				}()
+				return
+			}
+		}
+	}()
+	return forwardCh
+}
+
+// contextReader - reader failing with the error of ctx once it is cancelled.
+type contextReader struct {
+	ctx context.Context
+	io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if e := r.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return r.Reader.Read(p)
+}
+
+// ContextReader - reader of data until ctx is cancelled, for backends copying
+// data without requests which could be aborted.
+func ContextReader(ctx context.Context, data io.Reader) io.Reader {
+	return contextReader{ctx: ctx, Reader: data}
+}
+
+// Metadata keys of file attributes, preserved by cp and mirror. Mode is
+// octal, times are RFC3339 with nanoseconds.
+const (
+	MetadataMode  = "Mc-Mode"
+	MetadataUID   = "Mc-Uid"
+	MetadataGID   = "Mc-Gid"
+	MetadataAtime = "Mc-Atime"
+	MetadataMtime = "Mc-Mtime"
+)
+
+// MetadataMD5 - metadata key of the hex MD5 checksum data given to
+// PutWithMetadata is verified against before it is committed. Object storage
+// keeps it as user metadata, objects uploaded in parts have no other.
+const MetadataMD5 = "Mc-Md5"
+
+// MetadataTags - metadata key of tags in URL query form given to
+// PutWithMetadata, set on the object as it is stored, not as metadata.
+const MetadataTags = "Mc-Tags"
+
+// IsMD5 - etag has the format of a hex MD5 checksum, as ETags of objects
+// uploaded in a single part have. It need not be one.
+func IsMD5(etag string) bool {
+	if len(etag) != 32 {
+		return false
+	}
+	_, e := hex.DecodeString(etag)
+	return e == nil
+}
+
+// IsMultipartETag - etag has the format of ETags of objects uploaded in
+// parts, a hex MD5 checksum of their checksums followed by their number.
+func IsMultipartETag(etag string) bool {
+	i := strings.LastIndex(etag, "-")
+	if i < 0 || !IsMD5(etag[:i]) {
+		return false
+	}
+	_, e := strconv.Atoi(etag[i+1:])
+	return e == nil
+}
+
+// MetadataSymlink - metadata key of the target of a preserved symbolic link,
+// stored on an empty object in place of the link.
+const MetadataSymlink = "Mc-Symlink"
+
+// Bucket versioning states.
+const (
+	VersioningEnabled   = "Enabled"
+	VersioningSuspended = "Suspended"
+)
+
+// Object lock retention modes.
+const (
+	RetentionGovernance = "GOVERNANCE"
+	RetentionCompliance = "COMPLIANCE"
+)
+
+// Retention - object lock retention of an object, or default retention of a bucket.
+type Retention struct {
+	Mode string
+	// Days is only used by bucket default retention.
+	Days int
+	// Until is only used by object retention.
+	Until time.Time
+}
+
+// Event types, reported by Watch and used by notification configurations.
+const (
+	EventCreate = "put"
+	EventRemove = "delete"
+	EventAccess = "get"
+)
+
+// NotificationConfig - a single notification target of a bucket.
+type NotificationConfig struct {
+	ID     string
+	ARN    string
+	Events []string
+	Prefix string
+	Suffix string
+}
+
+// Event - a change of an object reported by Watch.
+type Event struct {
+	URL  URL
+	Time time.Time
+	Size int64
+	Type string
+	Err  *probe.Error
+}
+
+// CORSRule - a cross-origin resource sharing rule of a bucket.
+type CORSRule struct {
+	ID             string   `json:"ID,omitempty"`
+	AllowedOrigins []string `json:"AllowedOrigins"`
+	AllowedMethods []string `json:"AllowedMethods"`
+	AllowedHeaders []string `json:"AllowedHeaders,omitempty"`
+	ExposeHeaders  []string `json:"ExposeHeaders,omitempty"`
+	MaxAgeSeconds  int      `json:"MaxAgeSeconds,omitempty"`
+}
+
+// Website - static website configuration of a bucket, empty if not configured.
+type Website struct {
+	IndexDocument string
+	ErrorDocument string
+}
+
+// Select input and output formats.
+const (
+	SelectCSV  = "CSV"
+	SelectJSON = "JSON"
+)
+
+// SelectQuery - an SQL expression evaluated on a single CSV or JSON
+// object, only the matching records are returned.
+type SelectQuery struct {
+	Expression string
+	// InputFormat and OutputFormat are SelectCSV or SelectJSON.
+	InputFormat  string
+	OutputFormat string
+	Gzip         bool
+	// CSVHeader is USE, IGNORE or NONE, how the first line of CSV input is treated.
+	CSVHeader    string
+	CSVDelimiter string
+	// JSONType is LINES or DOCUMENT.
+	JSONType string
 }
 
 // Config - see http://docs.amazonwebservices.com/AmazonS3/latest/dev/index.html?RESTAuthentication.html
@@ -69,4 +302,6 @@ type Config struct {
 	AppVersion      string
 	AppComments     []string
 	Debug           bool
+	// PrivateKey is a private key file, used by SFTP.
+	PrivateKey string
 }
@@ -17,6 +17,9 @@
 package client
 
 import (
+	"context"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	. "gopkg.in/check.v1"
@@ -67,3 +70,38 @@ func (s *MySuite) TestPathParse(c *C) {
 	c.Assert(u.Path, Equals, "/path/test")
 	c.Assert(u.SchemeSeparator, Equals, "")
 }
+
+func (s *MySuite) TestForwardContents(c *C) {
+	contentCh := make(chan *Content)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		defer close(contentCh)
+		for i := 0; i < 10; i++ {
+			contentCh <- &Content{Size: int64(i)}
+		}
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	forwardCh := ForwardContents(ctx, contentCh)
+	content := <-forwardCh
+	c.Assert(content.Size, Equals, int64(0))
+
+	// The sender finishes without anyone reading what is left.
+	cancel()
+	for range forwardCh {
+	}
+	<-doneCh
+}
+
+func (s *MySuite) TestContextReader(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := ContextReader(ctx, strings.NewReader("hello"))
+	buf := make([]byte, 2)
+	n, e := reader.Read(buf)
+	c.Assert(e, IsNil)
+	c.Assert(string(buf[:n]), Equals, "he")
+
+	cancel()
+	_, e = ioutil.ReadAll(reader)
+	c.Assert(e, Equals, context.Canceled)
+}
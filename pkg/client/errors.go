@@ -147,3 +147,40 @@ type ObjectMissing struct{}
 func (e ObjectMissing) Error() string {
 	return "Object key is missing, object key cannot be empty"
 }
+
+// VersionNotFound - requested version of an object does not exist.
+type VersionNotFound struct {
+	Path      string
+	VersionID string
+}
+
+func (e VersionNotFound) Error() string {
+	return "Requested version ‘" + e.VersionID + "’ of ‘" + e.Path + "’ not found."
+}
+
+// ObjectLocked - object is protected by a retention period or legal hold.
+type ObjectLocked GenericFileError
+
+func (e ObjectLocked) Error() string {
+	return "Object ‘" + e.Path + "’ is locked by a retention period or legal hold."
+}
+
+// InvalidARN - notification target is not an SQS, SNS or Lambda ARN.
+type InvalidARN struct {
+	ARN string
+}
+
+func (e InvalidARN) Error() string {
+	return "Invalid notification target ‘" + e.ARN + "’, only SQS, SNS and Lambda ARNs are supported."
+}
+
+// ChecksumMismatch - data transferred does not match the checksum it was verified against.
+type ChecksumMismatch struct {
+	Path     string
+	Expected string
+	Computed string
+}
+
+func (e ChecksumMismatch) Error() string {
+	return "Checksum of ‘" + e.Path + "’ is ‘" + e.Computed + "’, expected ‘" + e.Expected + "’."
+}
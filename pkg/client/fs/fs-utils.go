@@ -21,6 +21,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+
+	"github.com/minio/mc/pkg/client"
 )
 
 // readDirNames reads the directory named by dirname and returns
@@ -69,7 +71,18 @@ func Walk(root string, walkFn WalkFunc) error {
 	if err != nil {
 		return walkFn(root, nil, err)
 	}
-	return walk(root, info, walkFn)
+	return walk(root, info, walkFn, nil)
+}
+
+// WalkFollow walks the file tree rooted at root like Walk, but walks into
+// symbolic links to directories as well. Links to a directory which is being
+// walked already are reported to walkFn with a TooManyLevelsSymlink error.
+func WalkFollow(root string, walkFn WalkFunc) error {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return walkFn(root, nil, err)
+	}
+	return walk(root, info, walkFn, make(map[fileID]bool))
 }
 
 // WalkFunc is the type of the function called for each file or directory
@@ -89,8 +102,27 @@ var ErrSkipDir = errors.New("skip this directory")
 // as an error by any function.
 var ErrSkipFile = errors.New("skip this file")
 
-// walk recursively descends path, calling w.
-func walk(path string, info os.FileInfo, walkFn WalkFunc) error {
+// walk recursively descends path, calling w. Directories being walked are
+// tracked in ancestors while links are followed, nil ancestors do not follow.
+func walk(path string, info os.FileInfo, walkFn WalkFunc, ancestors map[fileID]bool) error {
+	if ancestors != nil && info.Mode()&os.ModeSymlink == os.ModeSymlink {
+		// Links to directories are walked as directories, as long as
+		// cycles can be detected through the identity of directories.
+		if st, err := os.Stat(path); err == nil && st.IsDir() {
+			if _, ok := getFileID(st); ok {
+				info = st
+			}
+		}
+	}
+	if ancestors != nil && info.IsDir() {
+		if id, ok := getFileID(info); ok {
+			if ancestors[id] {
+				return walkFn(path, info, client.TooManyLevelsSymlink{Path: path})
+			}
+			ancestors[id] = true
+			defer delete(ancestors, id)
+		}
+	}
 	err := walkFn(path, info, nil)
 	if err != nil {
 		if info.Mode().IsDir() && err == ErrSkipDir {
@@ -116,7 +148,7 @@ func walk(path string, info os.FileInfo, walkFn WalkFunc) error {
 				return err
 			}
 		} else {
-			err = walk(filename, fileInfo, walkFn)
+			err = walk(filename, fileInfo, walkFn, ancestors)
 			if err != nil {
 				if err == ErrSkipDir || err == ErrSkipFile {
 					return nil
@@ -17,10 +17,16 @@
 package fs
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"io"
+	"mime"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,20 +38,40 @@ import (
 
 // filesystem client
 type fsClient struct {
-	PathURL *client.URL
+	PathURL  *client.URL
+	symlinks Symlinks
 }
 
 const (
 	partSuffix = ".part.mc"
 )
 
+// Symlinks - handling of symbolic links found on the filesystem.
+type Symlinks int
+
+const (
+	// FollowSymlinks - links are read as the files and folders they point to.
+	FollowSymlinks Symlinks = iota
+	// SkipSymlinks - links are left out of listings.
+	SkipSymlinks
+	// PreserveSymlinks - links are read as empty files with their target in
+	// metadata, Put creates a link again from such metadata.
+	PreserveSymlinks
+)
+
 // New - instantiate a new fs client.
 func New(path string) (client.Client, *probe.Error) {
+	return NewWithSymlinks(path, FollowSymlinks)
+}
+
+// NewWithSymlinks - instantiate a new fs client handling symbolic links as requested.
+func NewWithSymlinks(path string, symlinks Symlinks) (client.Client, *probe.Error) {
 	if strings.TrimSpace(path) == "" {
 		return nil, probe.NewError(client.EmptyPath{})
 	}
 	return &fsClient{
-		PathURL: client.NewURL(normalizePath(path)),
+		PathURL:  client.NewURL(normalizePath(path)),
+		symlinks: symlinks,
 	}, nil
 }
 
@@ -57,7 +83,16 @@ func (f *fsClient) GetURL() client.URL {
 /// Object operations.
 
 // Put - create a new file.
-func (f *fsClient) Put(data io.ReadSeeker, size int64) *probe.Error {
+func (f *fsClient) Put(ctx context.Context, data io.ReadSeeker, size int64) *probe.Error {
+	return f.PutWithMetadata(ctx, data, size, nil)
+}
+
+// PutWithMetadata - create a new file, restoring the file attributes found
+// in metadata before it is renamed in place.
+func (f *fsClient) PutWithMetadata(ctx context.Context, data io.ReadSeeker, size int64, metadata map[string]string) *probe.Error {
+	if _, ok := metadata[client.MetadataTags]; ok {
+		return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "filesystem"})
+	}
 	// Extract dir name.
 	objectDir, _ := filepath.Split(f.PathURL.Path)
 	objectPath := f.PathURL.Path
@@ -91,6 +126,11 @@ func (f *fsClient) Put(data io.ReadSeeker, size int64) *probe.Error {
 		}
 	}
 
+	// Preserved links carry no data, only their target.
+	if target, ok := metadata[client.MetadataSymlink]; ok {
+		return f.putSymlink(objectPartPath, target, metadata)
+	}
+
 	// If exists, open in append mode. If not create it the part file.
 	partFile, e := os.OpenFile(objectPartPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
 	if e != nil {
@@ -104,22 +144,50 @@ func (f *fsClient) Put(data io.ReadSeeker, size int64) *probe.Error {
 		return probe.NewError(e)
 	}
 
+	// Data is hashed as written when verified, starting with any part written before.
+	expectedMD5 := metadata[client.MetadataMD5]
+	hasher := md5.New()
+	var writer io.Writer = partFile
+	if expectedMD5 != "" {
+		if err := hashFile(hasher, objectPartPath); err != nil {
+			partFile.Close()
+			return err.Trace(objectPartPath)
+		}
+		writer = io.MultiWriter(partFile, hasher)
+	}
+
 	// Seek to current position for incoming reader.
 	data.Seek(partSt.Size(), 0)
 
-	// Write to the part file.
+	// Write to the part file, until cancelled.
+	reader := client.ContextReader(ctx, data)
 	if size < 0 { // Read till EOF.
-		_, e = io.Copy(partFile, data)
+		_, e = io.Copy(writer, reader)
 	} else { // Read till N bytes.
-		_, e = io.CopyN(partFile, data, size)
+		_, e = io.CopyN(writer, reader, size)
 	}
 	if e != nil {
+		partFile.Close()
 		err := f.toClientError(e, objectPartPath)
 		return err.Trace(objectPartPath)
 	}
 	// Close the file before rename.
 	partFile.Close()
 
+	if sum := hex.EncodeToString(hasher.Sum(nil)); expectedMD5 != "" && sum != expectedMD5 {
+		// Corrupted parts cannot be resumed, start over next time.
+		os.Remove(objectPartPath)
+		return probe.NewError(client.ChecksumMismatch{
+			Path:     objectPath,
+			Expected: expectedMD5,
+			Computed: sum,
+		})
+	}
+
+	if err := applyAttributes(objectPartPath, metadata); err != nil {
+		return err.Trace(objectPartPath)
+	}
+
 	// Safely completed put. Now commit by renaming to actual filename.
 	if e = os.Rename(objectPartPath, objectPath); e != nil {
 		err := f.toClientError(e, objectPath)
@@ -128,8 +196,37 @@ func (f *fsClient) Put(data io.ReadSeeker, size int64) *probe.Error {
 	return nil
 }
 
+// Copy - files are copied by reading them.
+func (f *fsClient) Copy(ctx context.Context, source client.URL, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "Copy", APIType: "filesystem"})
+}
+
+// putSymlink - create a symbolic link to target, in place of the file once
+// complete just like regular files.
+func (f *fsClient) putSymlink(linkPartPath, target string, metadata map[string]string) *probe.Error {
+	objectPath := f.PathURL.Path
+	// Remove leftovers of an interrupted put, links cannot be appended to.
+	if e := os.Remove(linkPartPath); e != nil && !os.IsNotExist(e) {
+		err := f.toClientError(e, linkPartPath)
+		return err.Trace(linkPartPath)
+	}
+	if e := os.Symlink(target, linkPartPath); e != nil {
+		err := f.toClientError(e, linkPartPath)
+		return err.Trace(linkPartPath, target)
+	}
+	// Mode and times would apply to the target, only ownership is restored.
+	if err := applyOwner(linkPartPath, metadata); err != nil {
+		return err.Trace(linkPartPath)
+	}
+	if e := os.Rename(linkPartPath, objectPath); e != nil {
+		err := f.toClientError(e, objectPath)
+		return err.Trace(linkPartPath, objectPath)
+	}
+	return nil
+}
+
 // ShareDownload - share download not implemented for filesystem.
-func (f *fsClient) ShareDownload(expires time.Duration) (string, *probe.Error) {
+func (f *fsClient) ShareDownload(ctx context.Context, expires time.Duration) (string, *probe.Error) {
 	return "", probe.NewError(client.APINotImplemented{
 		API:     "ShareDownload",
 		APIType: "filesystem",
@@ -137,7 +234,7 @@ func (f *fsClient) ShareDownload(expires time.Duration) (string, *probe.Error) {
 }
 
 // ShareUpload - share upload not implemented for filesystem.
-func (f *fsClient) ShareUpload(startsWith bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+func (f *fsClient) ShareUpload(ctx context.Context, startsWith bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
 	return nil, probe.NewError(client.APINotImplemented{
 		API:     "ShareUpload",
 		APIType: "filesystem",
@@ -146,11 +243,16 @@ func (f *fsClient) ShareUpload(startsWith bool, expires time.Duration, contentTy
 
 // Get download an full or part object from bucket.
 // returns a reader, length and nil for no errors.
-func (f *fsClient) Get(offset, length int64) (io.ReadSeeker, *probe.Error) {
+func (f *fsClient) Get(ctx context.Context, offset, length int64) (io.ReadSeeker, *probe.Error) {
 	if offset < 0 || length < 0 {
 		return nil, probe.NewError(client.InvalidRange{Offset: offset})
 	}
 
+	// Preserved links are read as empty files.
+	if _, ok := f.preservedSymlink(); ok {
+		return bytes.NewReader(nil), nil
+	}
+
 	tmppath := f.PathURL.Path
 	// Golang strips trailing / if you clean(..) or
 	// EvalSymlinks(..). Adding '.' prevents it from doing so.
@@ -169,14 +271,25 @@ func (f *fsClient) Get(offset, length int64) (io.ReadSeeker, *probe.Error) {
 		err := f.toClientError(e, f.PathURL.Path)
 		return nil, err.Trace(f.PathURL.Path)
 	}
-	if offset == 0 && length == 0 {
+	if length == 0 {
+		// Till the end.
+		if _, e = body.Seek(offset, 0); e != nil {
+			body.Close()
+			return nil, probe.NewError(e).Trace(f.PathURL.Path)
+		}
 		return body, nil
 	}
-	return io.NewSectionReader(body, offset, length), nil
+	return sectionReadCloser{io.NewSectionReader(body, offset, length), body}, nil
+}
+
+// sectionReadCloser - a section of a file which closes the file.
+type sectionReadCloser struct {
+	*io.SectionReader
+	io.Closer
 }
 
 // Remove - remove the path.
-func (f *fsClient) Remove(incomplete bool) *probe.Error {
+func (f *fsClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
 	if incomplete {
 		return nil
 	}
@@ -185,16 +298,34 @@ func (f *fsClient) Remove(incomplete bool) *probe.Error {
 	return err.Trace(f.PathURL.Path)
 }
 
+// RemoveBatch - remove all files received on contentCh one by one,
+// each of them is sent back with Err set if it could not be removed.
+func (f *fsClient) RemoveBatch(ctx context.Context, contentCh <-chan *client.Content) <-chan *client.Content {
+	resultCh := make(chan *client.Content)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			if e := ctx.Err(); e != nil {
+				content.Err = probe.NewError(e)
+			} else if e := os.Remove(content.URL.Path); e != nil {
+				content.Err = f.toClientError(e, content.URL.Path).Trace(content.URL.Path)
+			}
+			resultCh <- content
+		}
+	}()
+	return client.ForwardContents(ctx, resultCh)
+}
+
 // List - list files and folders.
-func (f *fsClient) List(recursive, incomplete bool) <-chan *client.Content {
+func (f *fsClient) List(ctx context.Context, recursive, incomplete bool) <-chan *client.Content {
 	contentCh := make(chan *client.Content)
 	switch recursive {
 	case true:
-		go f.listRecursiveInRoutine(contentCh, incomplete)
+		go f.listRecursiveInRoutine(ctx, contentCh, incomplete)
 	default:
-		go f.listInRoutine(contentCh, incomplete)
+		go f.listInRoutine(ctx, contentCh, incomplete)
 	}
-	return contentCh
+	return client.ForwardContents(ctx, contentCh)
 }
 
 // listPrefixes - list all files for any given prefix.
@@ -211,6 +342,12 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *client.Content,
 	pathURL := *f.PathURL
 	for _, fi := range files {
 		file := filepath.Join(dirName, fi.Name())
+		if fi.Mode()&os.ModeSymlink == os.ModeSymlink && f.symlinks != FollowSymlinks {
+			if f.symlinks == PreserveSymlinks && !incomplete && strings.HasPrefix(file, prefix) {
+				contentCh <- symlinkContent(*client.NewURL(file), fi)
+			}
+			continue
+		}
 		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
 			st, e := os.Stat(file)
 			if e != nil {
@@ -314,7 +451,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *client.Content,
 	return
 }
 
-func (f *fsClient) listInRoutine(contentCh chan<- *client.Content, incomplete bool) {
+func (f *fsClient) listInRoutine(ctx context.Context, contentCh chan<- *client.Content, incomplete bool) {
 	// close the channel when the function returns.
 	defer close(contentCh)
 
@@ -352,6 +489,14 @@ func (f *fsClient) listInRoutine(contentCh chan<- *client.Content, incomplete bo
 		}
 		for _, file := range files {
 			fi := file
+			if fi.Mode()&os.ModeSymlink == os.ModeSymlink && f.symlinks != FollowSymlinks {
+				if f.symlinks == PreserveSymlinks && !incomplete {
+					pathURL := *f.PathURL
+					pathURL.Path = filepath.Join(pathURL.Path, fi.Name())
+					contentCh <- symlinkContent(pathURL, fi)
+				}
+				continue
+			}
 			if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
 				fi, e = os.Stat(filepath.Join(fpath, fi.Name()))
 				if os.IsPermission(e) {
@@ -448,13 +593,17 @@ func (f *fsClient) listInRoutine(contentCh chan<- *client.Content, incomplete bo
 	}
 }
 
-func (f *fsClient) listRecursiveInRoutine(contentCh chan *client.Content, incomplete bool) {
+func (f *fsClient) listRecursiveInRoutine(ctx context.Context, contentCh chan *client.Content, incomplete bool) {
 	// close channels upon return.
 	defer close(contentCh)
 	var dirName string
 	var filePrefix string
 	pathURL := *f.PathURL
 	visitFS := func(fp string, fi os.FileInfo, e error) error {
+		// Stop walking once the listing is cancelled.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		// If file path ends with os.PathSeparator and equals to root path, skip it.
 		if strings.HasSuffix(fp, string(pathURL.Separator)) {
 			if fp == dirName {
@@ -530,8 +679,21 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *client.Content, incomp
 				}
 				return nil
 			}
+			// Links back to a folder being listed are not followed.
+			if _, ok := e.(client.TooManyLevelsSymlink); ok {
+				contentCh <- &client.Content{
+					Err: probe.NewError(e),
+				}
+				return nil
+			}
 			return e
 		}
+		if fi.Mode()&os.ModeSymlink == os.ModeSymlink && f.symlinks != FollowSymlinks {
+			if f.symlinks == PreserveSymlinks && !incomplete {
+				contentCh <- symlinkContent(*client.NewURL(fp), fi)
+			}
+			return nil
+		}
 		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
 			fi, e = os.Stat(fp)
 			if e != nil {
@@ -622,8 +784,12 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *client.Content, incomp
 		// filePrefix is kept for filtering incoming contents through WalkFunc.
 		filePrefix = pathURL.Path
 	}
-	// Walks invokes our custom function.
-	e := Walk(dirName, visitFS)
+	// Walks invokes our custom function, followed links are walked into.
+	walkFn := Walk
+	if f.symlinks == FollowSymlinks {
+		walkFn = WalkFollow
+	}
+	e := walkFn(dirName, visitFS)
 	if e != nil {
 		contentCh <- &client.Content{
 			Err: probe.NewError(e),
@@ -632,7 +798,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *client.Content, incomp
 }
 
 // MakeBucket - create a new bucket.
-func (f *fsClient) MakeBucket() *probe.Error {
+func (f *fsClient) MakeBucket(ctx context.Context) *probe.Error {
 	e := os.MkdirAll(f.PathURL.Path, 0775)
 	if e != nil {
 		return probe.NewError(e)
@@ -641,17 +807,203 @@ func (f *fsClient) MakeBucket() *probe.Error {
 }
 
 // GetBucketACL - get bucket access.
-func (f *fsClient) GetBucketAccess() (acl string, error *probe.Error) {
+func (f *fsClient) GetBucketAccess(ctx context.Context) (acl string, error *probe.Error) {
 	return "", probe.NewError(client.APINotImplemented{API: "GetBucketAccess", APIType: "filesystem"})
 }
 
 // SetBucketAccess - set bucket access.
-func (f *fsClient) SetBucketAccess(acl string) *probe.Error {
+func (f *fsClient) SetBucketAccess(ctx context.Context, acl string) *probe.Error {
 	return probe.NewError(client.APINotImplemented{API: "SetBucketAccess", APIType: "filesystem"})
 }
 
+// GetVersioning - versioning not implemented for filesystem.
+func (f *fsClient) GetVersioning(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetVersioning", APIType: "filesystem"})
+}
+
+// SetVersioning - versioning not implemented for filesystem.
+func (f *fsClient) SetVersioning(ctx context.Context, status string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetVersioning", APIType: "filesystem"})
+}
+
+// ListVersions - versioning not implemented for filesystem.
+func (f *fsClient) ListVersions(ctx context.Context, recursive bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content, 1)
+	contentCh <- &client.Content{Err: probe.NewError(client.APINotImplemented{API: "ListVersions", APIType: "filesystem"})}
+	close(contentCh)
+	return contentCh
+}
+
+// StatVersion - versioning not implemented for filesystem.
+func (f *fsClient) StatVersion(ctx context.Context, versionID string) (*client.Content, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "StatVersion", APIType: "filesystem"})
+}
+
+// GetVersion - versioning not implemented for filesystem.
+func (f *fsClient) GetVersion(ctx context.Context, versionID string, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetVersion", APIType: "filesystem"})
+}
+
+// RemoveVersion - versioning not implemented for filesystem.
+func (f *fsClient) RemoveVersion(ctx context.Context, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveVersion", APIType: "filesystem"})
+}
+
+// GetTags - tagging not implemented for filesystem.
+func (f *fsClient) GetTags(ctx context.Context) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetTags", APIType: "filesystem"})
+}
+
+// SetTags - tagging not implemented for filesystem.
+func (f *fsClient) SetTags(ctx context.Context, tags map[string]string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "filesystem"})
+}
+
+// DeleteTags - tagging not implemented for filesystem.
+func (f *fsClient) DeleteTags(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteTags", APIType: "filesystem"})
+}
+
+// MakeBucketWithLock - object lock not implemented for filesystem.
+func (f *fsClient) MakeBucketWithLock(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "MakeBucketWithLock", APIType: "filesystem"})
+}
+
+// GetRetention - object lock not implemented for filesystem.
+func (f *fsClient) GetRetention(ctx context.Context) (client.Retention, *probe.Error) {
+	return client.Retention{}, probe.NewError(client.APINotImplemented{API: "GetRetention", APIType: "filesystem"})
+}
+
+// SetRetention - object lock not implemented for filesystem.
+func (f *fsClient) SetRetention(ctx context.Context, retention client.Retention) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetRetention", APIType: "filesystem"})
+}
+
+// GetLegalHold - object lock not implemented for filesystem.
+func (f *fsClient) GetLegalHold(ctx context.Context) (bool, *probe.Error) {
+	return false, probe.NewError(client.APINotImplemented{API: "GetLegalHold", APIType: "filesystem"})
+}
+
+// SetLegalHold - object lock not implemented for filesystem.
+func (f *fsClient) SetLegalHold(ctx context.Context, on bool) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetLegalHold", APIType: "filesystem"})
+}
+
+// GetNotifications - bucket notifications not implemented for filesystem.
+func (f *fsClient) GetNotifications(ctx context.Context) ([]client.NotificationConfig, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetNotifications", APIType: "filesystem"})
+}
+
+// AddNotification - bucket notifications not implemented for filesystem.
+func (f *fsClient) AddNotification(ctx context.Context, config client.NotificationConfig) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "AddNotification", APIType: "filesystem"})
+}
+
+// RemoveNotification - bucket notifications not implemented for filesystem.
+func (f *fsClient) RemoveNotification(ctx context.Context, arn string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveNotification", APIType: "filesystem"})
+}
+
+// GetCORS - CORS configuration not implemented for filesystem.
+func (f *fsClient) GetCORS(ctx context.Context) ([]client.CORSRule, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetCORS", APIType: "filesystem"})
+}
+
+// SetCORS - CORS configuration not implemented for filesystem.
+func (f *fsClient) SetCORS(ctx context.Context, rules []client.CORSRule) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetCORS", APIType: "filesystem"})
+}
+
+// DeleteCORS - CORS configuration not implemented for filesystem.
+func (f *fsClient) DeleteCORS(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteCORS", APIType: "filesystem"})
+}
+
+// GetWebsite - static website configuration not implemented for filesystem.
+func (f *fsClient) GetWebsite(ctx context.Context) (client.Website, *probe.Error) {
+	return client.Website{}, probe.NewError(client.APINotImplemented{API: "GetWebsite", APIType: "filesystem"})
+}
+
+// SetWebsite - static website configuration not implemented for filesystem.
+func (f *fsClient) SetWebsite(ctx context.Context, website client.Website) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetWebsite", APIType: "filesystem"})
+}
+
+// DeleteWebsite - static website configuration not implemented for filesystem.
+func (f *fsClient) DeleteWebsite(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteWebsite", APIType: "filesystem"})
+}
+
+// Select - queries are not implemented for filesystem, the caller evaluates them.
+func (f *fsClient) Select(ctx context.Context, query client.SelectQuery) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Select", APIType: "filesystem"})
+}
+
+// fsEvent - a raw change reported by the platform specific watchers.
+type fsEvent struct {
+	path      string
+	eventType string
+	err       error
+}
+
+// Watch - stream events of files under this path. A path that is not a
+// directory is treated as a prefix, just like object storage does.
+func (f *fsClient) Watch(ctx context.Context, events []string, recursive bool) (<-chan *client.Event, *probe.Error) {
+	dir, prefix := f.PathURL.Path, f.PathURL.Path
+	if st, e := os.Stat(dir); e != nil || !st.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	fsEventCh, err := watchDir(dir, recursive, ctx.Done())
+	if err != nil {
+		return nil, err.Trace(dir)
+	}
+	wanted := make(map[string]bool)
+	for _, event := range events {
+		wanted[event] = true
+	}
+	eventCh := make(chan *client.Event)
+	go func() {
+		defer close(eventCh)
+		for fsEvent := range fsEventCh {
+			event := &client.Event{}
+			switch {
+			case fsEvent.err != nil:
+				event.Err = f.toClientError(fsEvent.err, dir)
+			case !wanted[fsEvent.eventType]:
+				continue
+			case !strings.HasPrefix(fsEvent.path, prefix), strings.HasSuffix(fsEvent.path, partSuffix):
+				continue
+			default:
+				event.URL = *client.NewURL(fsEvent.path)
+				event.Type = fsEvent.eventType
+				event.Time = time.Now().UTC()
+				if st, e := os.Stat(fsEvent.path); e == nil && fsEvent.eventType != client.EventRemove {
+					event.Size = st.Size()
+				}
+			}
+			select {
+			case eventCh <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
 // Stat - get metadata from path.
-func (f *fsClient) Stat() (content *client.Content, err *probe.Error) {
+func (f *fsClient) Stat(ctx context.Context) (content *client.Content, err *probe.Error) {
+	if lst, ok := f.preservedSymlink(); ok {
+		target, e := os.Readlink(f.PathURL.Path)
+		if e != nil {
+			err := f.toClientError(e, f.PathURL.Path)
+			return nil, err.Trace(f.PathURL.Path)
+		}
+		content = symlinkContent(*f.PathURL, lst)
+		content.Metadata = fileAttributes(lst)
+		content.Metadata[client.MetadataSymlink] = target
+		return content, nil
+	}
 	st, err := f.fsStat()
 	if err != nil {
 		return nil, err.Trace(f.PathURL.String())
@@ -661,5 +1013,90 @@ func (f *fsClient) Stat() (content *client.Content, err *probe.Error) {
 	content.Size = st.Size()
 	content.Time = st.ModTime()
 	content.Type = st.Mode()
+	if content.Type.IsRegular() {
+		content.ContentType = guessContentType(f.PathURL.Path)
+	}
+	content.Metadata = fileAttributes(st)
 	return content, nil
 }
+
+// hashFile - add contents of a file to hasher.
+func hashFile(hasher io.Writer, fpath string) *probe.Error {
+	file, e := os.Open(fpath)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer file.Close()
+	if _, e = io.Copy(hasher, file); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// preservedSymlink - stat of the path if it is a link to be preserved.
+func (f *fsClient) preservedSymlink() (os.FileInfo, bool) {
+	if f.symlinks != PreserveSymlinks {
+		return nil, false
+	}
+	st, e := os.Lstat(f.PathURL.Path)
+	if e != nil || st.Mode()&os.ModeSymlink != os.ModeSymlink {
+		return nil, false
+	}
+	return st, true
+}
+
+// symlinkContent - a preserved link, listed as an empty file.
+func symlinkContent(url client.URL, fi os.FileInfo) *client.Content {
+	return &client.Content{
+		URL:  url,
+		Time: fi.ModTime(),
+		Size: 0,
+		Type: fi.Mode(),
+		Err:  nil,
+	}
+}
+
+// applyAttributes - set mode, ownership and times of a file from metadata,
+// attributes missing from metadata are left as is.
+func applyAttributes(fpath string, metadata map[string]string) *probe.Error {
+	if value, ok := metadata[client.MetadataMode]; ok {
+		mode, e := strconv.ParseUint(value, 8, 32)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		if e = os.Chmod(fpath, os.FileMode(mode).Perm()); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	if err := applyOwner(fpath, metadata); err != nil {
+		return err.Trace(fpath)
+	}
+	mtimeValue, ok := metadata[client.MetadataMtime]
+	if !ok {
+		return nil
+	}
+	mtime, e := time.Parse(time.RFC3339Nano, mtimeValue)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	// Without a recorded access time, it is set to the modification time.
+	atime := mtime
+	if atimeValue, ok := metadata[client.MetadataAtime]; ok {
+		if atime, e = time.Parse(time.RFC3339Nano, atimeValue); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	if e = os.Chtimes(fpath, atime, mtime); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// guessContentType - content type of a file from its extension, as object storage would default it.
+func guessContentType(fpath string) string {
+	contentType := mime.TypeByExtension(filepath.Ext(fpath))
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+	return contentType
+}
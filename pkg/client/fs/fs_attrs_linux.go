@@ -0,0 +1,71 @@
+// +build linux
+
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fs
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// fileAttributes - mode, ownership and times of a file as metadata.
+func fileAttributes(st os.FileInfo) map[string]string {
+	metadata := map[string]string{
+		client.MetadataMode:  strconv.FormatUint(uint64(st.Mode().Perm()), 8),
+		client.MetadataMtime: st.ModTime().UTC().Format(time.RFC3339Nano),
+	}
+	if sys, ok := st.Sys().(*syscall.Stat_t); ok {
+		metadata[client.MetadataUID] = strconv.FormatUint(uint64(sys.Uid), 10)
+		metadata[client.MetadataGID] = strconv.FormatUint(uint64(sys.Gid), 10)
+		atime := time.Unix(int64(sys.Atim.Sec), int64(sys.Atim.Nsec))
+		metadata[client.MetadataAtime] = atime.UTC().Format(time.RFC3339Nano)
+	}
+	return metadata
+}
+
+// applyOwner - change ownership of a file to the recorded uid and gid. Only
+// privileged users may give files away, lack of permission is not an error.
+func applyOwner(fpath string, metadata map[string]string) *probe.Error {
+	uidValue, uidOK := metadata[client.MetadataUID]
+	gidValue, gidOK := metadata[client.MetadataGID]
+	if !uidOK && !gidOK {
+		return nil
+	}
+	// -1 leaves the id unchanged.
+	uid, gid := -1, -1
+	var e error
+	if uidOK {
+		if uid, e = strconv.Atoi(uidValue); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	if gidOK {
+		if gid, e = strconv.Atoi(gidValue); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	if e = os.Lchown(fpath, uid, gid); e != nil && !os.IsPermission(e) {
+		return probe.NewError(e)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// +build !linux
+
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fs
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// fileAttributes - mode and modification time of a file as metadata,
+// ownership and access time are only recorded on Linux.
+func fileAttributes(st os.FileInfo) map[string]string {
+	return map[string]string{
+		client.MetadataMode:  strconv.FormatUint(uint64(st.Mode().Perm()), 8),
+		client.MetadataMtime: st.ModTime().UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// applyOwner - ownership is only restored on Linux.
+func applyOwner(fpath string, metadata map[string]string) *probe.Error {
+	return nil
+}
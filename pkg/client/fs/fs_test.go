@@ -18,11 +18,16 @@ package fs_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/minio/mc/pkg/client"
 	"github.com/minio/mc/pkg/client/fs"
@@ -46,21 +51,21 @@ func (s *MySuite) TestList(c *C) {
 
 	data := "hello"
 
-	err = fsc.Put(bytes.NewReader([]byte(data)), int64(len(data)))
+	err = fsc.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)))
 	c.Assert(err, IsNil)
 
 	objectPath = filepath.Join(root, "object2")
 	fsc, err = fs.New(objectPath)
 	c.Assert(err, IsNil)
 
-	err = fsc.Put(bytes.NewReader([]byte(data)), int64(len(data)))
+	err = fsc.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)))
 	c.Assert(err, IsNil)
 
 	fsc, err = fs.New(root)
 	c.Assert(err, IsNil)
 
 	var contents []*client.Content
-	for content := range fsc.List(false, false) {
+	for content := range fsc.List(context.Background(), false, false) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -75,14 +80,14 @@ func (s *MySuite) TestList(c *C) {
 	fsc, err = fs.New(objectPath)
 	c.Assert(err, IsNil)
 
-	err = fsc.Put(bytes.NewReader([]byte(data)), int64(len(data)))
+	err = fsc.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)))
 	c.Assert(err, IsNil)
 
 	fsc, err = fs.New(root)
 	c.Assert(err, IsNil)
 
 	contents = nil
-	for content := range fsc.List(false, false) {
+	for content := range fsc.List(context.Background(), false, false) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -97,7 +102,7 @@ func (s *MySuite) TestList(c *C) {
 	c.Assert(err, IsNil)
 
 	contents = nil
-	for content := range fsc.List(true, false) {
+	for content := range fsc.List(context.Background(), true, false) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -132,7 +137,7 @@ func (s *MySuite) TestPutBucket(c *C) {
 	bucketPath := filepath.Join(root, "bucket")
 	fsc, err := fs.New(bucketPath)
 	c.Assert(err, IsNil)
-	err = fsc.MakeBucket()
+	err = fsc.MakeBucket(context.Background())
 	c.Assert(err, IsNil)
 }
 
@@ -145,9 +150,9 @@ func (s *MySuite) TestStatBucket(c *C) {
 
 	fsc, err := fs.New(bucketPath)
 	c.Assert(err, IsNil)
-	err = fsc.MakeBucket()
+	err = fsc.MakeBucket(context.Background())
 	c.Assert(err, IsNil)
-	_, err = fsc.Stat()
+	_, err = fsc.Stat(context.Background())
 	c.Assert(err, IsNil)
 }
 
@@ -159,13 +164,13 @@ func (s *MySuite) TestBucketACLFails(c *C) {
 	bucketPath := filepath.Join(root, "bucket")
 	fsc, err := fs.New(bucketPath)
 	c.Assert(err, IsNil)
-	err = fsc.MakeBucket()
+	err = fsc.MakeBucket(context.Background())
 	c.Assert(err, IsNil)
 
-	err = fsc.SetBucketAccess("private")
+	err = fsc.SetBucketAccess(context.Background(), "private")
 	c.Assert(err, Not(IsNil))
 
-	_, err = fsc.GetBucketAccess()
+	_, err = fsc.GetBucketAccess(context.Background())
 	c.Assert(err, Not(IsNil))
 }
 
@@ -179,7 +184,7 @@ func (s *MySuite) TestPut(c *C) {
 	c.Assert(err, IsNil)
 
 	data := "hello"
-	err = fsc.Put(bytes.NewReader([]byte(data)), int64(len(data)))
+	err = fsc.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)))
 	c.Assert(err, IsNil)
 }
 
@@ -194,10 +199,10 @@ func (s *MySuite) TestGet(c *C) {
 
 	data := "hello"
 
-	err = fsc.Put(bytes.NewReader([]byte(data)), int64(len(data)))
+	err = fsc.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)))
 	c.Assert(err, IsNil)
 
-	reader, err := fsc.Get(0, 0)
+	reader, err := fsc.Get(context.Background(), 0, 0)
 	c.Assert(err, IsNil)
 	var results bytes.Buffer
 	_, e = io.Copy(&results, reader)
@@ -217,15 +222,29 @@ func (s *MySuite) TestGetRange(c *C) {
 
 	data := "hello world"
 
-	err = fsc.Put(bytes.NewReader([]byte(data)), int64(len(data)))
+	err = fsc.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)))
 	c.Assert(err, IsNil)
 
-	reader, err := fsc.Get(0, 5)
+	reader, err := fsc.Get(context.Background(), 0, 5)
 	c.Assert(err, IsNil)
 	var results bytes.Buffer
 	_, e = io.Copy(&results, reader)
 	c.Assert(e, IsNil)
 	c.Assert([]byte("hello"), DeepEquals, results.Bytes())
+
+	reader, err = fsc.Get(context.Background(), 6, 3)
+	c.Assert(err, IsNil)
+	results.Reset()
+	_, e = io.Copy(&results, reader)
+	c.Assert(e, IsNil)
+	c.Assert([]byte("wor"), DeepEquals, results.Bytes())
+
+	reader, err = fsc.Get(context.Background(), 6, 0)
+	c.Assert(err, IsNil)
+	results.Reset()
+	_, e = io.Copy(&results, reader)
+	c.Assert(e, IsNil)
+	c.Assert([]byte("world"), DeepEquals, results.Bytes())
 }
 
 func (s *MySuite) TestStatObject(c *C) {
@@ -240,10 +259,206 @@ func (s *MySuite) TestStatObject(c *C) {
 	data := "hello"
 	dataLen := len(data)
 
-	err = fsc.Put(bytes.NewReader([]byte(data)), int64(len(data)))
+	err = fsc.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)))
 	c.Assert(err, IsNil)
 
-	content, err := fsc.Stat()
+	content, err := fsc.Stat(context.Background())
 	c.Assert(err, IsNil)
 	c.Assert(content.Size, Equals, int64(dataLen))
 }
+
+func (s *MySuite) TestPutWithMetadata(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	fsc, err := fs.New(objectPath)
+	c.Assert(err, IsNil)
+
+	mtime := time.Date(2015, time.May, 21, 18, 24, 21, 0, time.UTC)
+	metadata := map[string]string{
+		client.MetadataMode:  "640",
+		client.MetadataMtime: mtime.Format(time.RFC3339Nano),
+	}
+	data := "hello"
+	err = fsc.PutWithMetadata(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)), metadata)
+	c.Assert(err, IsNil)
+
+	st, e := os.Stat(objectPath)
+	c.Assert(e, IsNil)
+	c.Assert(st.ModTime().Equal(mtime), Equals, true)
+
+	// Attributes are reported as metadata by Stat.
+	content, err := fsc.Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Metadata[client.MetadataMtime], Equals, metadata[client.MetadataMtime])
+	if runtime.GOOS != "windows" {
+		c.Assert(st.Mode().Perm(), Equals, os.FileMode(0640))
+		c.Assert(content.Metadata[client.MetadataMode], Equals, "640")
+	}
+}
+
+func (s *MySuite) TestPutVerified(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	fsc, err := fs.New(objectPath)
+	c.Assert(err, IsNil)
+
+	data := []byte("hello")
+	metadata := map[string]string{client.MetadataMD5: hex.EncodeToString(make([]byte, md5.Size))}
+	err = fsc.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), metadata)
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.ChecksumMismatch)
+	c.Assert(ok, Equals, true)
+	// Neither the file nor its part are left behind.
+	_, e = os.Stat(objectPath)
+	c.Assert(os.IsNotExist(e), Equals, true)
+	_, e = os.Stat(objectPath + ".part.mc")
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	sum := md5.Sum(data)
+	metadata[client.MetadataMD5] = hex.EncodeToString(sum[:])
+	err = fsc.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), metadata)
+	c.Assert(err, IsNil)
+	written, e := ioutil.ReadFile(objectPath)
+	c.Assert(e, IsNil)
+	c.Assert(written, DeepEquals, data)
+}
+
+func (s *MySuite) TestListSymlinks(c *C) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	c.Assert(os.MkdirAll(filepath.Join(root, "dir"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "dir", "object"), []byte("hello"), 0600), IsNil)
+	// A link to a folder, and a link back to an ancestor folder.
+	c.Assert(os.Symlink("dir", filepath.Join(root, "link")), IsNil)
+	c.Assert(os.Symlink("..", filepath.Join(root, "dir", "loop")), IsNil)
+
+	list := func(symlinks fs.Symlinks) (names []string, errs int) {
+		fsc, err := fs.NewWithSymlinks(root+string(os.PathSeparator), symlinks)
+		c.Assert(err, IsNil)
+		for content := range fsc.List(context.Background(), true, false) {
+			if content.Err != nil {
+				errs++
+				continue
+			}
+			if !content.Type.IsDir() {
+				name, e := filepath.Rel(root, content.URL.Path)
+				c.Assert(e, IsNil)
+				names = append(names, name)
+			}
+		}
+		return names, errs
+	}
+
+	// Followed links are walked into, the loop is reported under dir and link.
+	names, errs := list(fs.FollowSymlinks)
+	c.Assert(names, DeepEquals, []string{filepath.Join("dir", "object"), filepath.Join("link", "object")})
+	c.Assert(errs, Equals, 2)
+
+	names, errs = list(fs.SkipSymlinks)
+	c.Assert(names, DeepEquals, []string{filepath.Join("dir", "object")})
+	c.Assert(errs, Equals, 0)
+
+	names, errs = list(fs.PreserveSymlinks)
+	c.Assert(names, DeepEquals, []string{filepath.Join("dir", "loop"), filepath.Join("dir", "object"), "link"})
+	c.Assert(errs, Equals, 0)
+}
+
+func (s *MySuite) TestPreserveSymlink(c *C) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	linkPath := filepath.Join(root, "link")
+	c.Assert(os.Symlink("target", linkPath), IsNil)
+
+	fsc, err := fs.NewWithSymlinks(linkPath, fs.PreserveSymlinks)
+	c.Assert(err, IsNil)
+	content, err := fsc.Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(0))
+	c.Assert(content.Metadata[client.MetadataSymlink], Equals, "target")
+
+	// Links are read as empty files, even if broken.
+	reader, err := fsc.Get(context.Background(), 0, 0)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(len(data), Equals, 0)
+
+	// Put creates the link again from its metadata.
+	copyPath := filepath.Join(root, "copy")
+	fsc, err = fs.New(copyPath)
+	c.Assert(err, IsNil)
+	err = fsc.PutWithMetadata(context.Background(), bytes.NewReader(nil), 0, content.Metadata)
+	c.Assert(err, IsNil)
+	target, e := os.Readlink(copyPath)
+	c.Assert(e, IsNil)
+	c.Assert(target, Equals, "target")
+}
+
+func (s *MySuite) TestWatch(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	fsClient, err := fs.New(root)
+	c.Assert(err, IsNil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eventCh, err := fsClient.Watch(ctx, []string{client.EventCreate, client.EventRemove}, true)
+	c.Assert(err, IsNil)
+
+	objectPath := filepath.Join(root, "object1")
+	fsClient, err = fs.New(objectPath)
+	c.Assert(err, IsNil)
+	data := "hello"
+	err = fsClient.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)))
+	c.Assert(err, IsNil)
+
+	event := <-eventCh
+	c.Assert(event.Err, IsNil)
+	c.Assert(event.Type, Equals, client.EventCreate)
+	c.Assert(event.URL.Path, Equals, objectPath)
+	c.Assert(event.Size, Equals, int64(len(data)))
+
+	err = fsClient.Remove(context.Background(), false)
+	c.Assert(err, IsNil)
+	event = <-eventCh
+	c.Assert(event.Err, IsNil)
+	c.Assert(event.Type, Equals, client.EventRemove)
+	c.Assert(event.URL.Path, Equals, objectPath)
+
+	// Files of folders moved in are reported, as objects appearing under a prefix are.
+	outside, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(outside)
+	c.Assert(os.MkdirAll(filepath.Join(outside, "folder", "nested"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(outside, "folder", "nested", "object2"), []byte(data), 0600), IsNil)
+	c.Assert(os.Rename(filepath.Join(outside, "folder"), filepath.Join(root, "folder")), IsNil)
+	event = <-eventCh
+	c.Assert(event.Err, IsNil)
+	c.Assert(event.Type, Equals, client.EventCreate)
+	c.Assert(event.URL.Path, Equals, filepath.Join(root, "folder", "nested", "object2"))
+
+	// New sub-folders are watched as well.
+	objectPath = filepath.Join(root, "folder", "nested", "object3")
+	c.Assert(ioutil.WriteFile(objectPath, []byte(data), 0600), IsNil)
+	event = <-eventCh
+	c.Assert(event.Err, IsNil)
+	c.Assert(event.Type, Equals, client.EventCreate)
+	c.Assert(event.URL.Path, Equals, objectPath)
+}
@@ -18,6 +18,26 @@
 
 package fs
 
+import (
+	"os"
+	"syscall"
+)
+
 func normalizePath(path string) string {
 	return path
 }
+
+// fileID - identity of a file, its device and inode.
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+// getFileID - identity of a file from its stat.
+func getFileID(st os.FileInfo) (fileID, bool) {
+	sys, ok := st.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(sys.Dev), ino: uint64(sys.Ino)}, true
+}
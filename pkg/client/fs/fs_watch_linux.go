@@ -0,0 +1,163 @@
+// +build linux
+
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// inotify events of interest, directory creations are needed to watch new sub-directories.
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_ACCESS | syscall.IN_CREATE
+
+// watchDir - watch a directory, and all its sub-directories if recursive, using inotify.
+func watchDir(dir string, recursive bool, doneCh <-chan struct{}) (<-chan fsEvent, *probe.Error) {
+	fd, e := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	// A non-blocking descriptor is handled by the runtime poller, which
+	// lets Close() interrupt a pending Read().
+	inotifyFile := os.NewFile(uintptr(fd), "inotify")
+
+	watches := make(map[int32]string)
+	addWatch := func(path string) error {
+		wd, e := syscall.InotifyAddWatch(fd, path, inotifyMask)
+		if e != nil {
+			return e
+		}
+		watches[int32(wd)] = path
+		return nil
+	}
+	if e = addWatch(dir); e != nil {
+		inotifyFile.Close()
+		return nil, probe.NewError(e)
+	}
+	if recursive {
+		filepath.Walk(dir, func(path string, info os.FileInfo, e error) error {
+			if e == nil && info.IsDir() && path != dir {
+				addWatch(path)
+			}
+			return nil
+		})
+	}
+
+	eventCh := make(chan fsEvent)
+	stopCh := make(chan struct{})
+	go func() {
+		select {
+		case <-doneCh:
+		case <-stopCh:
+		}
+		inotifyFile.Close()
+	}()
+	go func() {
+		defer close(eventCh)
+		defer close(stopCh)
+		send := func(event fsEvent) bool {
+			select {
+			case eventCh <- event:
+				return true
+			case <-doneCh:
+				return false
+			}
+		}
+		buf := make([]byte, (syscall.SizeofInotifyEvent+syscall.NAME_MAX+1)*64)
+		for {
+			n, e := inotifyFile.Read(buf)
+			if e != nil {
+				select {
+				case <-doneCh:
+				default:
+					send(fsEvent{err: e})
+				}
+				return
+			}
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				raw := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+				nameBytes := buf[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(raw.Len)]
+				offset += syscall.SizeofInotifyEvent + int(raw.Len)
+
+				if raw.Mask&syscall.IN_IGNORED != 0 {
+					delete(watches, raw.Wd)
+					continue
+				}
+				parent, ok := watches[raw.Wd]
+				if !ok {
+					continue
+				}
+				path := filepath.Join(parent, strings.TrimRight(string(nameBytes), "\x00"))
+				if raw.Mask&syscall.IN_ISDIR != 0 {
+					// Folders are not objects, only keep track of new ones
+					// along with files they already hold.
+					if recursive && raw.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+						for _, filePath := range watchNewDir(path, addWatch) {
+							if !send(fsEvent{path: filePath, eventType: client.EventCreate}) {
+								return
+							}
+						}
+					}
+					continue
+				}
+				var eventType string
+				switch {
+				case raw.Mask&(syscall.IN_CLOSE_WRITE|syscall.IN_MOVED_TO) != 0:
+					eventType = client.EventCreate
+				case raw.Mask&(syscall.IN_DELETE|syscall.IN_MOVED_FROM) != 0:
+					eventType = client.EventRemove
+				case raw.Mask&syscall.IN_ACCESS != 0:
+					eventType = client.EventAccess
+				default:
+					continue
+				}
+				if !send(fsEvent{path: path, eventType: eventType}) {
+					return
+				}
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
+// watchNewDir - watch a new directory and all its sub-directories, returns
+// the files found in them. Directories moved in, or filled before they were
+// watched, bring files without events of their own. Each directory is watched
+// before it is read, so files are reported twice at worst, never missed.
+func watchNewDir(dir string, addWatch func(string) error) []string {
+	var files []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, e error) error {
+		if e != nil {
+			return nil
+		}
+		if info.IsDir() {
+			addWatch(path)
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	return files
+}
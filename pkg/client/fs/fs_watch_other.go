@@ -0,0 +1,97 @@
+// +build !linux
+
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// watchInterval - how often directories are scanned for changes.
+const watchInterval = time.Second
+
+// snapshotDir - modification time and size of all files under a directory.
+func snapshotDir(dir string, recursive bool) map[string]os.FileInfo {
+	files := make(map[string]os.FileInfo)
+	filepath.Walk(dir, func(path string, info os.FileInfo, e error) error {
+		if e != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files[path] = info
+		return nil
+	})
+	return files
+}
+
+// watchDir - watch a directory by periodically comparing snapshots, access events are not reported.
+func watchDir(dir string, recursive bool, doneCh <-chan struct{}) (<-chan fsEvent, *probe.Error) {
+	if _, e := os.Stat(dir); e != nil {
+		return nil, probe.NewError(e)
+	}
+	eventCh := make(chan fsEvent)
+	go func() {
+		defer close(eventCh)
+		send := func(event fsEvent) bool {
+			select {
+			case eventCh <- event:
+				return true
+			case <-doneCh:
+				return false
+			}
+		}
+		previous := snapshotDir(dir, recursive)
+		for {
+			select {
+			case <-doneCh:
+				return
+			case <-time.After(watchInterval):
+			}
+			current := snapshotDir(dir, recursive)
+			for path, info := range current {
+				old, ok := previous[path]
+				if ok && old.ModTime().Equal(info.ModTime()) && old.Size() == info.Size() {
+					continue
+				}
+				if !send(fsEvent{path: path, eventType: client.EventCreate}) {
+					return
+				}
+			}
+			for path := range previous {
+				if _, ok := current[path]; ok {
+					continue
+				}
+				if !send(fsEvent{path: path, eventType: client.EventRemove}) {
+					return
+				}
+			}
+			previous = current
+		}
+	}()
+	return eventCh, nil
+}
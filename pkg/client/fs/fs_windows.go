@@ -19,6 +19,7 @@
 package fs
 
 import (
+	"os"
 	"path/filepath"
 	"syscall"
 )
@@ -33,3 +34,12 @@ func normalizePath(path string) string {
 	}
 	return path
 }
+
+// fileID - identity of a file, not available from stat on windows.
+type fileID struct{}
+
+// getFileID - windows stat carries no file identity, cycles of directory
+// links cannot be detected and such links are not walked into.
+func getFileID(st os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
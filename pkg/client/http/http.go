@@ -0,0 +1,486 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package http implements a read-only client for files served by web servers.
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/httptracer"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// httpClient - read-only client of a web server.
+type httpClient struct {
+	hostURL   *client.URL
+	transport http.RoundTripper
+	userAgent string
+}
+
+// New - instantiate a new http client.
+func New(config *client.Config) (client.Client, *probe.Error) {
+	transport := http.DefaultTransport
+	if config.Debug == true {
+		transport = httptracer.GetNewTraceTransport(NewTrace(), http.DefaultTransport)
+	}
+	return &httpClient{
+		hostURL:   client.NewURL(config.HostURL),
+		transport: transport,
+		userAgent: "Minio (" + runtime.GOOS + "; " + runtime.GOARCH + ") " + config.AppName + "/" + config.AppVersion,
+	}, nil
+}
+
+// GetURL get url.
+func (c *httpClient) GetURL() client.URL {
+	return *c.hostURL
+}
+
+// requestURL - URL of a path on the web server.
+func (c *httpClient) requestURL(path string) string {
+	u := url.URL{
+		Scheme: c.hostURL.Scheme,
+		Host:   c.hostURL.Host,
+		Path:   path,
+	}
+	return u.String()
+}
+
+// executeMethod - send a request and translate non 2xx replies into client errors.
+func (c *httpClient) executeMethod(ctx context.Context, method, path string, header http.Header) (*http.Response, *probe.Error) {
+	req, e := http.NewRequest(method, c.requestURL(path), nil)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", c.userAgent)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	// RoundTrip is used directly instead of http.Client{}, redirects are followed below.
+	resp, e := c.transport.RoundTrip(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	switch {
+	case resp.StatusCode/100 == 2:
+		return resp, nil
+	case resp.StatusCode/100 == 3 && resp.Header.Get("Location") != "":
+		closeResponse(resp)
+		return c.followRedirect(ctx, method, path, resp.Header.Get("Location"), header)
+	}
+	closeResponse(resp)
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, probe.NewError(client.PathNotFound{Path: c.requestURL(path)})
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, probe.NewError(client.PathInsufficientPermission{Path: c.requestURL(path)})
+	case http.StatusRequestedRangeNotSatisfiable:
+		return nil, probe.NewError(client.InvalidRange{})
+	}
+	return nil, probe.NewError(errors.New("Unable to access ‘" + c.requestURL(path) + "’: " + resp.Status + "."))
+}
+
+// maxRedirects - redirects followed before giving up.
+const maxRedirects = 10
+
+// followRedirect - send the request to the location a server redirected it
+// to, which may be on another host.
+func (c *httpClient) followRedirect(ctx context.Context, method, path, location string, header http.Header) (*http.Response, *probe.Error) {
+	base, e := url.Parse(c.requestURL(path))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	for i := 0; i < maxRedirects; i++ {
+		target, e := base.Parse(location)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		req, e := http.NewRequest(method, target.String(), nil)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("User-Agent", c.userAgent)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, e := c.transport.RoundTrip(req)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		if resp.StatusCode/100 == 2 {
+			return resp, nil
+		}
+		closeResponse(resp)
+		if resp.StatusCode/100 != 3 || resp.Header.Get("Location") == "" {
+			return nil, probe.NewError(errors.New("Unable to access ‘" + target.String() + "’: " + resp.Status + "."))
+		}
+		base, location = target, resp.Header.Get("Location")
+	}
+	return nil, probe.NewError(errors.New("Too many redirects for ‘" + c.requestURL(path) + "’."))
+}
+
+// closeResponse - drain and close the body, so that the connection can be re-used.
+func closeResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// Stat - get metadata of a file with a HEAD request, paths ending with a
+// separator are folders.
+func (c *httpClient) Stat(ctx context.Context) (*client.Content, *probe.Error) {
+	if strings.HasSuffix(c.hostURL.Path, string(c.hostURL.Separator)) {
+		resp, err := c.executeMethod(ctx, "HEAD", c.hostURL.Path, nil)
+		if err != nil {
+			return nil, err.Trace(c.hostURL.String())
+		}
+		closeResponse(resp)
+		return &client.Content{URL: *c.hostURL, Type: os.ModeDir}, nil
+	}
+	content, err := c.headFile(ctx, *c.hostURL)
+	if err != nil {
+		return nil, err.Trace(c.hostURL.String())
+	}
+	return content, nil
+}
+
+// headFile - metadata of a file from the reply to a HEAD request.
+func (c *httpClient) headFile(ctx context.Context, fileURL client.URL) (*client.Content, *probe.Error) {
+	resp, err := c.executeMethod(ctx, "HEAD", fileURL.Path, nil)
+	if err != nil {
+		return nil, err.Trace(fileURL.String())
+	}
+	defer closeResponse(resp)
+	content := new(client.Content)
+	content.URL = fileURL
+	content.Type = os.FileMode(0664)
+	// Sizes of generated content are unknown, it is read till the end.
+	content.Size = -1
+	if size, e := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); e == nil {
+		content.Size = size
+	}
+	if date, e := time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified")); e == nil {
+		content.Time = date
+	}
+	content.ContentType = resp.Header.Get("Content-Type")
+	// ETags of web servers are not checksums, only a Content-MD5 is.
+	if sum, e := base64.StdEncoding.DecodeString(resp.Header.Get("Content-MD5")); e == nil && len(sum) == 16 {
+		content.ETag = hex.EncodeToString(sum)
+		content.MD5 = content.ETag
+	}
+	return content, nil
+}
+
+// Get - read a file, ranges are fetched with a Range request.
+func (c *httpClient) Get(ctx context.Context, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	if offset < 0 || length < 0 {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	return newFileReader(ctx, c, c.hostURL.Path, offset, length), nil
+}
+
+// hrefRegexp - links of an HTML directory index.
+var hrefRegexp = regexp.MustCompile(`(?i)<a\s[^>]*href\s*=\s*["']([^"'#?]+)`)
+
+// List - list a file, or files and folders linked from the directory index
+// of a folder, as web servers generate them for paths ending with a separator.
+func (c *httpClient) List(ctx context.Context, recursive, incomplete bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content)
+	go func() {
+		defer close(contentCh)
+		// There are no incomplete uploads on a web server.
+		if incomplete {
+			return
+		}
+		if !strings.HasSuffix(c.hostURL.Path, string(c.hostURL.Separator)) {
+			content, err := c.headFile(ctx, *c.hostURL)
+			if err != nil {
+				contentCh <- &client.Content{Err: err.Trace(c.hostURL.String())}
+				return
+			}
+			contentCh <- content
+			return
+		}
+		c.listIndex(ctx, *c.hostURL, recursive, contentCh)
+	}()
+	return client.ForwardContents(ctx, contentCh)
+}
+
+// listIndex - send files and folders linked from the index of folderURL,
+// folders are listed as well if recursive.
+func (c *httpClient) listIndex(ctx context.Context, folderURL client.URL, recursive bool, contentCh chan<- *client.Content) {
+	paths, err := c.readIndex(ctx, folderURL.Path)
+	if err != nil {
+		contentCh <- &client.Content{Err: err.Trace(folderURL.String())}
+		return
+	}
+	for _, path := range paths {
+		contentURL := folderURL
+		contentURL.Path = path
+		if strings.HasSuffix(path, string(folderURL.Separator)) {
+			contentCh <- &client.Content{URL: contentURL, Type: os.ModeDir}
+			if recursive {
+				c.listIndex(ctx, contentURL, recursive, contentCh)
+			}
+			continue
+		}
+		content, err := c.headFile(ctx, contentURL)
+		if err != nil {
+			contentCh <- &client.Content{Err: err.Trace(contentURL.String())}
+			continue
+		}
+		contentCh <- content
+	}
+}
+
+// readIndex - paths linked from the HTML index of a folder, only links to
+// entries below the folder on the same host are kept.
+func (c *httpClient) readIndex(ctx context.Context, folderPath string) ([]string, *probe.Error) {
+	resp, err := c.executeMethod(ctx, "GET", folderPath, nil)
+	if err != nil {
+		return nil, err.Trace(folderPath)
+	}
+	defer closeResponse(resp)
+	body, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	base, e := url.Parse(c.requestURL(folderPath))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	var paths []string
+	seen := make(map[string]bool)
+	for _, match := range hrefRegexp.FindAllSubmatch(body, -1) {
+		link, e := base.Parse(string(match[1]))
+		if e != nil || link.Host != base.Host {
+			continue
+		}
+		// Only entries right below the folder, parents and siblings are linked as well.
+		name := strings.TrimSuffix(strings.TrimPrefix(link.Path, folderPath), "/")
+		if !strings.HasPrefix(link.Path, folderPath) || name == "" || strings.Contains(name, "/") || seen[link.Path] {
+			continue
+		}
+		seen[link.Path] = true
+		paths = append(paths, link.Path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// MakeBucket - web servers are read-only.
+func (c *httpClient) MakeBucket(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "MakeBucket", APIType: "http"})
+}
+
+// GetBucketAccess - access policies not implemented for web servers.
+func (c *httpClient) GetBucketAccess(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetBucketAccess", APIType: "http"})
+}
+
+// SetBucketAccess - access policies not implemented for web servers.
+func (c *httpClient) SetBucketAccess(ctx context.Context, access string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetBucketAccess", APIType: "http"})
+}
+
+// Put - web servers are read-only.
+func (c *httpClient) Put(ctx context.Context, data io.ReadSeeker, size int64) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "Put", APIType: "http"})
+}
+
+// PutWithMetadata - web servers are read-only.
+func (c *httpClient) PutWithMetadata(ctx context.Context, data io.ReadSeeker, size int64, metadata map[string]string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "PutWithMetadata", APIType: "http"})
+}
+
+// Copy - web servers are read-only.
+func (c *httpClient) Copy(ctx context.Context, source client.URL, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "Copy", APIType: "http"})
+}
+
+// ShareDownload - files on a web server are shared by their URL already.
+func (c *httpClient) ShareDownload(ctx context.Context, expires time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "ShareDownload", APIType: "http"})
+}
+
+// ShareUpload - web servers are read-only.
+func (c *httpClient) ShareUpload(ctx context.Context, recursive bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "ShareUpload", APIType: "http"})
+}
+
+// Remove - web servers are read-only.
+func (c *httpClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "Remove", APIType: "http"})
+}
+
+// RemoveBatch - web servers are read-only, every content is sent back with an error.
+func (c *httpClient) RemoveBatch(ctx context.Context, contentCh <-chan *client.Content) <-chan *client.Content {
+	resultCh := make(chan *client.Content)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			content.Err = probe.NewError(client.APINotImplemented{API: "RemoveBatch", APIType: "http"})
+			resultCh <- content
+		}
+	}()
+	return client.ForwardContents(ctx, resultCh)
+}
+
+// GetVersioning - versioning not implemented for web servers.
+func (c *httpClient) GetVersioning(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetVersioning", APIType: "http"})
+}
+
+// SetVersioning - versioning not implemented for web servers.
+func (c *httpClient) SetVersioning(ctx context.Context, status string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetVersioning", APIType: "http"})
+}
+
+// ListVersions - versioning not implemented for web servers.
+func (c *httpClient) ListVersions(ctx context.Context, recursive bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content, 1)
+	contentCh <- &client.Content{Err: probe.NewError(client.APINotImplemented{API: "ListVersions", APIType: "http"})}
+	close(contentCh)
+	return contentCh
+}
+
+// StatVersion - versioning not implemented for web servers.
+func (c *httpClient) StatVersion(ctx context.Context, versionID string) (*client.Content, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "StatVersion", APIType: "http"})
+}
+
+// GetVersion - versioning not implemented for web servers.
+func (c *httpClient) GetVersion(ctx context.Context, versionID string, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetVersion", APIType: "http"})
+}
+
+// RemoveVersion - versioning not implemented for web servers.
+func (c *httpClient) RemoveVersion(ctx context.Context, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveVersion", APIType: "http"})
+}
+
+// GetTags - tagging not implemented for web servers.
+func (c *httpClient) GetTags(ctx context.Context) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetTags", APIType: "http"})
+}
+
+// SetTags - tagging not implemented for web servers.
+func (c *httpClient) SetTags(ctx context.Context, tags map[string]string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "http"})
+}
+
+// DeleteTags - tagging not implemented for web servers.
+func (c *httpClient) DeleteTags(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteTags", APIType: "http"})
+}
+
+// MakeBucketWithLock - object lock not implemented for web servers.
+func (c *httpClient) MakeBucketWithLock(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "MakeBucketWithLock", APIType: "http"})
+}
+
+// GetRetention - object lock not implemented for web servers.
+func (c *httpClient) GetRetention(ctx context.Context) (client.Retention, *probe.Error) {
+	return client.Retention{}, probe.NewError(client.APINotImplemented{API: "GetRetention", APIType: "http"})
+}
+
+// SetRetention - object lock not implemented for web servers.
+func (c *httpClient) SetRetention(ctx context.Context, retention client.Retention) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetRetention", APIType: "http"})
+}
+
+// GetLegalHold - object lock not implemented for web servers.
+func (c *httpClient) GetLegalHold(ctx context.Context) (bool, *probe.Error) {
+	return false, probe.NewError(client.APINotImplemented{API: "GetLegalHold", APIType: "http"})
+}
+
+// SetLegalHold - object lock not implemented for web servers.
+func (c *httpClient) SetLegalHold(ctx context.Context, on bool) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetLegalHold", APIType: "http"})
+}
+
+// GetNotifications - notifications not implemented for web servers.
+func (c *httpClient) GetNotifications(ctx context.Context) ([]client.NotificationConfig, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetNotifications", APIType: "http"})
+}
+
+// AddNotification - notifications not implemented for web servers.
+func (c *httpClient) AddNotification(ctx context.Context, config client.NotificationConfig) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "AddNotification", APIType: "http"})
+}
+
+// RemoveNotification - notifications not implemented for web servers.
+func (c *httpClient) RemoveNotification(ctx context.Context, arn string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveNotification", APIType: "http"})
+}
+
+// Watch - notifications not implemented for web servers.
+func (c *httpClient) Watch(ctx context.Context, events []string, recursive bool) (<-chan *client.Event, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Watch", APIType: "http"})
+}
+
+// GetCORS - CORS configuration not implemented for web servers.
+func (c *httpClient) GetCORS(ctx context.Context) ([]client.CORSRule, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetCORS", APIType: "http"})
+}
+
+// SetCORS - CORS configuration not implemented for web servers.
+func (c *httpClient) SetCORS(ctx context.Context, rules []client.CORSRule) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetCORS", APIType: "http"})
+}
+
+// DeleteCORS - CORS configuration not implemented for web servers.
+func (c *httpClient) DeleteCORS(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteCORS", APIType: "http"})
+}
+
+// GetWebsite - static website configuration not implemented for web servers.
+func (c *httpClient) GetWebsite(ctx context.Context) (client.Website, *probe.Error) {
+	return client.Website{}, probe.NewError(client.APINotImplemented{API: "GetWebsite", APIType: "http"})
+}
+
+// SetWebsite - static website configuration not implemented for web servers.
+func (c *httpClient) SetWebsite(ctx context.Context, website client.Website) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetWebsite", APIType: "http"})
+}
+
+// DeleteWebsite - static website configuration not implemented for web servers.
+func (c *httpClient) DeleteWebsite(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteWebsite", APIType: "http"})
+}
+
+// Select - queries are not implemented for web servers, the caller evaluates them.
+func (c *httpClient) Select(ctx context.Context, query client.SelectQuery) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Select", APIType: "http"})
+}
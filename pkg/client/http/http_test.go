@@ -0,0 +1,134 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// newTestClient - client of a path on the web server at serverURL.
+func newTestClient(c *C, serverURL, path string) client.Client {
+	config := new(client.Config)
+	config.HostURL = client.HTTPSchemePrefix + serverURL + path
+	clnt, err := New(config)
+	c.Assert(err, IsNil)
+	return clnt
+}
+
+// newTestTree - folder served by a test server with a file and a sub-folder.
+func newTestTree(c *C) string {
+	root, e := ioutil.TempDir(os.TempDir(), "http-")
+	c.Assert(e, IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(root, "dataset", "parts"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "dataset", "README"), []byte("hello world"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "dataset", "parts", "part.1"), []byte("part"), 0600), IsNil)
+	return root
+}
+
+func (s *MySuite) TestStat(c *C) {
+	root := newTestTree(c)
+	defer os.RemoveAll(root)
+	server := httptest.NewServer(http.FileServer(http.Dir(root)))
+	defer server.Close()
+
+	content, err := newTestClient(c, server.URL, "/dataset/README").Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(len("hello world")))
+	c.Assert(content.Type.IsRegular(), Equals, true)
+	c.Assert(content.URL.String(), Equals, client.HTTPSchemePrefix+server.URL+"/dataset/README")
+
+	content, err = newTestClient(c, server.URL, "/dataset/").Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+
+	_, err = newTestClient(c, server.URL, "/dataset/missing").Stat(context.Background())
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.PathNotFound)
+	c.Assert(ok, Equals, true)
+}
+
+func (s *MySuite) TestGetRange(c *C) {
+	root := newTestTree(c)
+	defer os.RemoveAll(root)
+	server := httptest.NewServer(http.FileServer(http.Dir(root)))
+	defer server.Close()
+
+	reader, err := newTestClient(c, server.URL, "/dataset/README").Get(context.Background(), 6, 5)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "world")
+
+	// Servers without range support send the whole file.
+	noRanges := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+	defer noRanges.Close()
+	reader, err = newTestClient(c, noRanges.URL, "/README").Get(context.Background(), 2, 3)
+	c.Assert(err, IsNil)
+	data, e = ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "llo")
+}
+
+func (s *MySuite) TestList(c *C) {
+	root := newTestTree(c)
+	defer os.RemoveAll(root)
+	server := httptest.NewServer(http.FileServer(http.Dir(root)))
+	defer server.Close()
+
+	var urls []string
+	for content := range newTestClient(c, server.URL, "/dataset/").List(context.Background(), true, false) {
+		c.Assert(content.Err, IsNil)
+		urls = append(urls, content.URL.Path)
+	}
+	c.Assert(urls, DeepEquals, []string{"/dataset/README", "/dataset/parts/", "/dataset/parts/part.1"})
+
+	urls = nil
+	for content := range newTestClient(c, server.URL, "/dataset/").List(context.Background(), false, false) {
+		c.Assert(content.Err, IsNil)
+		urls = append(urls, content.URL.Path)
+	}
+	c.Assert(urls, DeepEquals, []string{"/dataset/README", "/dataset/parts/"})
+}
+
+func (s *MySuite) TestReadOnly(c *C) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	err := newTestClient(c, server.URL, "/README").Put(context.Background(), bytes.NewReader([]byte("hello")), 5)
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.APINotImplemented)
+	c.Assert(ok, Equals, true)
+}
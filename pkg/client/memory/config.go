@@ -0,0 +1,527 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// GetVersioning - get bucket versioning status, empty if never enabled.
+func (c *memoryClient) GetVersioning(ctx context.Context) (string, *probe.Error) {
+	bucketName, _ := c.url2BucketAndObject()
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return "", err.Trace(bucketName)
+	}
+	return b.versioning, nil
+}
+
+// SetVersioning - enable or suspend versioning on a bucket.
+func (c *memoryClient) SetVersioning(ctx context.Context, status string) *probe.Error {
+	bucketName, _ := c.url2BucketAndObject()
+	if status != client.VersioningEnabled && status != client.VersioningSuspended {
+		return probe.NewError(errors.New("Unrecognized versioning status ‘" + status + "’."))
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return err.Trace(bucketName)
+	}
+	if b.locked && status != client.VersioningEnabled {
+		return probe.NewError(errors.New("Versioning cannot be suspended on bucket ‘" + bucketName + "’ with object lock."))
+	}
+	b.versioning = status
+	return nil
+}
+
+// ListVersions - list all versions and delete markers at a delimited path, if not recursive.
+// Keys are listed in lexical order, versions of a key newest first.
+func (c *memoryClient) ListVersions(ctx context.Context, recursive bool) <-chan *client.Content {
+	bucketName, objectName := c.url2BucketAndObject()
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return sendContents(ctx, []*client.Content{{Err: err.Trace(bucketName)}})
+	}
+	var contents []*client.Content
+	var lastFolder string
+	for _, key := range b.sortedKeys(objectName) {
+		if !recursive {
+			if i := strings.Index(key[len(objectName):], "/"); i >= 0 {
+				folder := key[:len(objectName)+i+1]
+				if folder != lastFolder {
+					lastFolder = folder
+					contents = append(contents, &client.Content{URL: c.objectURL(bucketName, folder), Time: time.Now(), Type: os.ModeDir})
+				}
+				continue
+			}
+		}
+		versions := b.objects[key]
+		for i := len(versions) - 1; i >= 0; i-- {
+			content := versions[i].content(c.objectURL(bucketName, key), false)
+			content.VersionID = versions[i].versionID
+			content.IsLatest = i == len(versions)-1
+			contents = append(contents, content)
+		}
+	}
+	return sendContents(ctx, contents)
+}
+
+// getVersion - a version of the object of this URL which is not a delete
+// marker, store lock must be held.
+func (c *memoryClient) getVersion(versionID string) (*object, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	if objectName == "" {
+		return nil, probe.NewError(client.ObjectMissing{})
+	}
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return nil, err.Trace(bucketName)
+	}
+	for _, o := range b.objects[objectName] {
+		if o.versionID == versionID && !o.deleteMarker {
+			return o, nil
+		}
+	}
+	return nil, probe.NewError(client.VersionNotFound{Path: c.hostURL.String(), VersionID: versionID})
+}
+
+// StatVersion - get metadata of a specific version of an object.
+func (c *memoryClient) StatVersion(ctx context.Context, versionID string) (*client.Content, *probe.Error) {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	o, err := c.getVersion(versionID)
+	if err != nil {
+		return nil, err.Trace(versionID)
+	}
+	content := o.content(*c.hostURL, true)
+	content.VersionID = o.versionID
+	return content, nil
+}
+
+// GetVersion - get a specific version of an object.
+func (c *memoryClient) GetVersion(ctx context.Context, versionID string, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	if offset < 0 || length < 0 {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	o, err := c.getVersion(versionID)
+	if err != nil {
+		return nil, err.Trace(versionID)
+	}
+	return readRange(o.data, offset, length)
+}
+
+// RemoveVersion - permanently remove a specific version or delete marker of an object.
+func (c *memoryClient) RemoveVersion(ctx context.Context, versionID string) *probe.Error {
+	bucketName, objectName := c.url2BucketAndObject()
+	if objectName == "" {
+		return probe.NewError(client.ObjectMissing{})
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return err.Trace(bucketName)
+	}
+	versions := b.objects[objectName]
+	for i, o := range versions {
+		if o.versionID != versionID {
+			continue
+		}
+		if o.isLocked(time.Now()) {
+			return probe.NewError(client.ObjectLocked{Path: c.hostURL.String()})
+		}
+		versions = append(versions[:i:i], versions[i+1:]...)
+		if len(versions) == 0 {
+			delete(b.objects, objectName)
+		} else {
+			b.objects[objectName] = versions
+		}
+		return nil
+	}
+	return probe.NewError(client.VersionNotFound{Path: c.hostURL.String(), VersionID: versionID})
+}
+
+// tagsOf - tags of the current version of the object of this URL, or of
+// the bucket if the URL has no object. Store lock must be held.
+func (c *memoryClient) tagsOf() (*map[string]string, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return nil, err.Trace(bucketName)
+	}
+	if objectName == "" {
+		return &b.tags, nil
+	}
+	o := b.latest(objectName)
+	if o == nil {
+		return nil, probe.NewError(client.PathNotFound{Path: c.hostURL.Path})
+	}
+	return &o.tags, nil
+}
+
+// GetTags - get tags of an object, or of a bucket if the URL has no object.
+func (c *memoryClient) GetTags(ctx context.Context) (map[string]string, *probe.Error) {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	tags, err := c.tagsOf()
+	if err != nil {
+		return nil, err.Trace(c.hostURL.String())
+	}
+	copied := make(map[string]string)
+	for key, value := range *tags {
+		copied[key] = value
+	}
+	return copied, nil
+}
+
+// SetTags - replace all tags of an object, or of a bucket if the URL has no object.
+func (c *memoryClient) SetTags(ctx context.Context, tags map[string]string) *probe.Error {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	current, err := c.tagsOf()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	*current = make(map[string]string)
+	for key, value := range tags {
+		(*current)[key] = value
+	}
+	return nil
+}
+
+// DeleteTags - remove all tags of an object, or of a bucket if the URL has no object.
+func (c *memoryClient) DeleteTags(ctx context.Context) *probe.Error {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	current, err := c.tagsOf()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	*current = nil
+	return nil
+}
+
+// MakeBucketWithLock - make a new bucket with object lock enabled, which implies versioning.
+func (c *memoryClient) MakeBucketWithLock(ctx context.Context) *probe.Error {
+	return c.makeBucket(true)
+}
+
+// lockedObject - current version of the object of this URL in a bucket
+// with object lock, store lock must be held.
+func (c *memoryClient) lockedObject() (*object, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	if objectName == "" {
+		return nil, probe.NewError(client.ObjectMissing{})
+	}
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return nil, err.Trace(bucketName)
+	}
+	if !b.locked {
+		return nil, probe.NewError(errors.New("Object lock is not enabled on bucket ‘" + bucketName + "’."))
+	}
+	o := b.latest(objectName)
+	if o == nil {
+		return nil, probe.NewError(client.PathNotFound{Path: c.hostURL.Path})
+	}
+	return o, nil
+}
+
+// GetRetention - get retention of an object, or default retention of a bucket if the URL has no object.
+func (c *memoryClient) GetRetention(ctx context.Context) (client.Retention, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	if objectName == "" {
+		b, err := c.getBucket(bucketName)
+		if err != nil {
+			return client.Retention{}, err.Trace(bucketName)
+		}
+		return b.retention, nil
+	}
+	o, err := c.lockedObject()
+	if err != nil {
+		return client.Retention{}, err.Trace(bucketName, objectName)
+	}
+	return o.retention, nil
+}
+
+// SetRetention - set retention of an object, or default retention of a bucket
+// if the URL has no object. Compliance retention can only be extended.
+func (c *memoryClient) SetRetention(ctx context.Context, retention client.Retention) *probe.Error {
+	bucketName, objectName := c.url2BucketAndObject()
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	if objectName == "" {
+		b, err := c.getBucket(bucketName)
+		if err != nil {
+			return err.Trace(bucketName)
+		}
+		if !b.locked {
+			return probe.NewError(errors.New("Object lock is not enabled on bucket ‘" + bucketName + "’."))
+		}
+		b.retention = client.Retention{Mode: retention.Mode, Days: retention.Days}
+		return nil
+	}
+	o, err := c.lockedObject()
+	if err != nil {
+		return err.Trace(bucketName, objectName)
+	}
+	if o.retention.Mode == client.RetentionCompliance && o.retention.Until.After(time.Now()) &&
+		(retention.Mode != client.RetentionCompliance || retention.Until.Before(o.retention.Until)) {
+		return probe.NewError(client.ObjectLocked{Path: c.hostURL.String()})
+	}
+	o.retention = client.Retention{Mode: retention.Mode, Until: retention.Until.UTC()}
+	return nil
+}
+
+// GetLegalHold - get legal hold status of an object.
+func (c *memoryClient) GetLegalHold(ctx context.Context) (bool, *probe.Error) {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	o, err := c.lockedObject()
+	if err != nil {
+		return false, err.Trace(c.hostURL.String())
+	}
+	return o.legalHold, nil
+}
+
+// SetLegalHold - set or clear legal hold of an object.
+func (c *memoryClient) SetLegalHold(ctx context.Context, on bool) *probe.Error {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	o, err := c.lockedObject()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	o.legalHold = on
+	return nil
+}
+
+// configBucket - bucket of a URL which has no object, store lock must be held.
+func (c *memoryClient) configBucket() (*bucket, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	if objectName != "" {
+		return nil, probe.NewError(client.InvalidBucketName{Bucket: bucketName + "/" + objectName})
+	}
+	return c.getBucket(bucketName)
+}
+
+// GetNotifications - list all notification targets of a bucket.
+func (c *memoryClient) GetNotifications(ctx context.Context) ([]client.NotificationConfig, *probe.Error) {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.configBucket()
+	if err != nil {
+		return nil, err.Trace(c.hostURL.String())
+	}
+	return append([]client.NotificationConfig(nil), b.notifications...), nil
+}
+
+// AddNotification - add a notification target to the existing configuration
+// of a bucket. Targets are only recorded, nothing is delivered to them.
+func (c *memoryClient) AddNotification(ctx context.Context, config client.NotificationConfig) *probe.Error {
+	// arn:partition:service:region:account-id:resource
+	fields := strings.SplitN(config.ARN, ":", 6)
+	if len(fields) != 6 || fields[0] != "arn" || (fields[2] != "sqs" && fields[2] != "sns" && fields[2] != "lambda") {
+		return probe.NewError(client.InvalidARN{ARN: config.ARN})
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.configBucket()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	b.notifications = append(b.notifications, config)
+	return nil
+}
+
+// RemoveNotification - remove all notification targets of a bucket pointing to this ARN.
+func (c *memoryClient) RemoveNotification(ctx context.Context, arn string) *probe.Error {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.configBucket()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	var kept []client.NotificationConfig
+	for _, config := range b.notifications {
+		if config.ARN != arn {
+			kept = append(kept, config)
+		}
+	}
+	b.notifications = kept
+	return nil
+}
+
+// GetCORS - get CORS rules of a bucket, empty if not configured.
+func (c *memoryClient) GetCORS(ctx context.Context) ([]client.CORSRule, *probe.Error) {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.configBucket()
+	if err != nil {
+		return nil, err.Trace(c.hostURL.String())
+	}
+	return append([]client.CORSRule(nil), b.cors...), nil
+}
+
+// SetCORS - replace CORS rules of a bucket.
+func (c *memoryClient) SetCORS(ctx context.Context, rules []client.CORSRule) *probe.Error {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.configBucket()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	b.cors = append([]client.CORSRule(nil), rules...)
+	return nil
+}
+
+// DeleteCORS - remove all CORS rules of a bucket.
+func (c *memoryClient) DeleteCORS(ctx context.Context) *probe.Error {
+	return c.SetCORS(ctx, nil)
+}
+
+// GetWebsite - get static website configuration of a bucket, empty if not configured.
+func (c *memoryClient) GetWebsite(ctx context.Context) (client.Website, *probe.Error) {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.configBucket()
+	if err != nil {
+		return client.Website{}, err.Trace(c.hostURL.String())
+	}
+	return b.website, nil
+}
+
+// SetWebsite - replace static website configuration of a bucket.
+func (c *memoryClient) SetWebsite(ctx context.Context, website client.Website) *probe.Error {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.configBucket()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	b.website = website
+	return nil
+}
+
+// DeleteWebsite - remove static website configuration of a bucket.
+func (c *memoryClient) DeleteWebsite(ctx context.Context) *probe.Error {
+	return c.SetWebsite(ctx, client.Website{})
+}
+
+// watcher - a Watch in progress, events are queued so that changes of
+// objects never wait for watchers.
+type watcher struct {
+	mutex     sync.Mutex
+	bucket    string
+	prefix    string
+	recursive bool
+	events    map[string]bool
+	pending   []*client.Event
+	wakeCh    chan struct{}
+	url       client.URL
+}
+
+// Watch - stream events of objects under this prefix.
+func (c *memoryClient) Watch(ctx context.Context, events []string, recursive bool) (<-chan *client.Event, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	memStore.mutex.Lock()
+	if _, err := c.getBucket(bucketName); err != nil {
+		memStore.mutex.Unlock()
+		return nil, err.Trace(bucketName)
+	}
+	w := &watcher{
+		bucket:    bucketName,
+		prefix:    objectName,
+		recursive: recursive,
+		events:    make(map[string]bool),
+		wakeCh:    make(chan struct{}, 1),
+		url:       *c.hostURL,
+	}
+	for _, event := range events {
+		w.events[event] = true
+	}
+	memStore.watchers[w] = true
+	memStore.mutex.Unlock()
+
+	eventCh := make(chan *client.Event)
+	go func() {
+		defer close(eventCh)
+		defer func() {
+			memStore.mutex.Lock()
+			delete(memStore.watchers, w)
+			memStore.mutex.Unlock()
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.wakeCh:
+			}
+			w.mutex.Lock()
+			pending := w.pending
+			w.pending = nil
+			w.mutex.Unlock()
+			for _, event := range pending {
+				select {
+				case eventCh <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
+// notify - queue an event for all watchers interested in it, store lock must be held.
+func notify(bucketName, key, eventType string, size int64) {
+	for w := range memStore.watchers {
+		if w.bucket != bucketName || !w.events[eventType] || !strings.HasPrefix(key, w.prefix) {
+			continue
+		}
+		if !w.recursive && strings.Contains(key[len(w.prefix):], "/") {
+			continue
+		}
+		url := w.url
+		url.Path = "/" + bucketName + "/" + key
+		w.mutex.Lock()
+		w.pending = append(w.pending, &client.Event{URL: url, Time: time.Now().UTC(), Size: size, Type: eventType})
+		w.mutex.Unlock()
+		select {
+		case w.wakeCh <- struct{}{}:
+		default:
+		}
+	}
+}
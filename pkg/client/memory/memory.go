@@ -0,0 +1,747 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package memory implements object storage kept in memory by this process,
+// addressed by mem://bucket/object URLs. It is meant for tests and for
+// pipelines with short lived intermediate data.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// object - a single version of an object, or a delete marker.
+type object struct {
+	versionID    string
+	deleteMarker bool
+	data         []byte
+	modTime      time.Time
+	etag         string
+	contentType  string
+	metadata     map[string]string
+	tags         map[string]string
+	retention    client.Retention
+	legalHold    bool
+}
+
+// upload - an upload which failed before all of its data was received.
+type upload struct {
+	initiated time.Time
+	size      int64
+}
+
+// bucket - objects and configuration of a bucket.
+type bucket struct {
+	created       time.Time
+	access        string
+	versioning    string
+	locked        bool
+	retention     client.Retention
+	tags          map[string]string
+	notifications []client.NotificationConfig
+	cors          []client.CORSRule
+	website       client.Website
+	// Versions of each key, oldest first. Unless versioning is enabled
+	// only a single version is kept.
+	objects map[string][]*object
+	uploads map[string][]*upload
+}
+
+// store - all buckets, shared by all clients so that a URL names the same
+// object for the life of the process.
+type store struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	watchers  map[*watcher]bool
+	versionID int64
+}
+
+var memStore = &store{
+	buckets:  make(map[string]*bucket),
+	watchers: make(map[*watcher]bool),
+}
+
+// Reset - remove all buckets, watchers are left running.
+func Reset() {
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	memStore.buckets = make(map[string]*bucket)
+}
+
+// nextVersionID - a new unique version id, stores lock must be held.
+func (s *store) nextVersionID() string {
+	s.versionID++
+	return strconv.FormatInt(s.versionID, 10)
+}
+
+// memoryClient - client of a bucket or object of the in-memory storage.
+type memoryClient struct {
+	hostURL *client.URL
+}
+
+// New - instantiate a new memory client.
+func New(urlStr string) (client.Client, *probe.Error) {
+	hostURL := client.NewURL(urlStr)
+	if hostURL.Scheme != client.MemoryScheme {
+		return nil, probe.NewError(errors.New("Invalid memory URL ‘" + urlStr + "’."))
+	}
+	return &memoryClient{hostURL: hostURL}, nil
+}
+
+// GetURL get url.
+func (c *memoryClient) GetURL() client.URL {
+	return *c.hostURL
+}
+
+// url2BucketAndObject - bucket and object names of this URL.
+func (c *memoryClient) url2BucketAndObject() (bucketName, objectName string) {
+	return splitPath(c.hostURL.Path)
+}
+
+// splitPath - bucket and object names of a URL path.
+func splitPath(path string) (bucketName, objectName string) {
+	splits := strings.SplitN(path, "/", 3)
+	switch len(splits) {
+	case 0, 1:
+		return "", ""
+	case 2:
+		return splits[1], ""
+	}
+	return splits[1], splits[2]
+}
+
+// objectURL - URL of a key of a bucket, folders keep their trailing separator.
+func (c *memoryClient) objectURL(bucketName, key string) client.URL {
+	url := *c.hostURL
+	url.Path = "/" + bucketName + "/" + key
+	return url
+}
+
+// getBucket - a bucket by its name, store lock must be held.
+func (c *memoryClient) getBucket(bucketName string) (*bucket, *probe.Error) {
+	if bucketName == "" {
+		return nil, probe.NewError(client.BucketNameEmpty{})
+	}
+	b, ok := memStore.buckets[bucketName]
+	if !ok {
+		return nil, probe.NewError(client.PathNotFound{Path: c.objectURL(bucketName, "").String()})
+	}
+	return b, nil
+}
+
+// latest - current version of a key, nil if removed or never written.
+func (b *bucket) latest(key string) *object {
+	versions := b.objects[key]
+	if len(versions) == 0 || versions[len(versions)-1].deleteMarker {
+		return nil
+	}
+	return versions[len(versions)-1]
+}
+
+// sortedKeys - keys of all objects starting with prefix in lexical order.
+func (b *bucket) sortedKeys(prefix string) []string {
+	var keys []string
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isLocked - true if a version may not be removed.
+func (o *object) isLocked(now time.Time) bool {
+	return o.legalHold || o.retention.Until.After(now)
+}
+
+// content - listing of a version at url, metadata is only set on stats.
+func (o *object) content(url client.URL, withMetadata bool) *client.Content {
+	content := &client.Content{
+		URL:            url,
+		Time:           o.modTime,
+		Size:           int64(len(o.data)),
+		Type:           os.FileMode(0664),
+		ETag:           o.etag,
+		IsDeleteMarker: o.deleteMarker,
+	}
+	if !o.deleteMarker {
+		content.StorageClass = "STANDARD"
+	}
+	if withMetadata {
+		content.ContentType = o.contentType
+		content.MD5 = o.etag
+		content.Metadata = make(map[string]string)
+		for key, value := range o.metadata {
+			content.Metadata[key] = value
+		}
+	}
+	return content
+}
+
+// Stat - get metadata of a bucket or object, a prefix of other objects is a folder.
+func (c *memoryClient) Stat(ctx context.Context) (*client.Content, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	if bucketName == "" && objectName == "" {
+		return &client.Content{URL: *c.hostURL, Type: os.ModeDir}, nil
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return nil, err.Trace(bucketName)
+	}
+	if objectName == "" {
+		return &client.Content{URL: *c.hostURL, Time: b.created, Type: os.ModeDir}, nil
+	}
+	if o := b.latest(objectName); o != nil {
+		return o.content(*c.hostURL, true), nil
+	}
+	prefix := strings.TrimSuffix(objectName, "/") + "/"
+	for _, key := range b.sortedKeys(prefix) {
+		if b.latest(key) != nil {
+			return &client.Content{URL: *c.hostURL, Type: os.ModeDir}, nil
+		}
+	}
+	return nil, probe.NewError(client.PathNotFound{Path: c.hostURL.Path})
+}
+
+// List - list at delimited path, if not recursive.
+func (c *memoryClient) List(ctx context.Context, recursive, incomplete bool) <-chan *client.Content {
+	// Contents are collected at once, the store is not locked while they are received.
+	memStore.mutex.Lock()
+	var contents []*client.Content
+	if incomplete {
+		contents = c.listIncomplete(recursive)
+	} else {
+		contents = c.list(recursive)
+	}
+	memStore.mutex.Unlock()
+	return sendContents(ctx, contents)
+}
+
+// sendContents - send all contents on a channel closed afterwards, or
+// once ctx is cancelled.
+func sendContents(ctx context.Context, contents []*client.Content) <-chan *client.Content {
+	contentCh := make(chan *client.Content)
+	go func() {
+		defer close(contentCh)
+		for _, content := range contents {
+			select {
+			case contentCh <- content:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return contentCh
+}
+
+// sortedBuckets - names of all buckets in lexical order, store lock must be held.
+func sortedBuckets() []string {
+	var names []string
+	for name := range memStore.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// list - contents of a listing, store lock must be held.
+func (c *memoryClient) list(recursive bool) []*client.Content {
+	bucketName, objectName := c.url2BucketAndObject()
+	var contents []*client.Content
+	if bucketName == "" {
+		for _, name := range sortedBuckets() {
+			bucketURL := c.objectURL(name, "")
+			bucketURL.Path = strings.TrimSuffix(bucketURL.Path, "/")
+			contents = append(contents, &client.Content{URL: bucketURL, Time: memStore.buckets[name].created, Type: os.ModeDir})
+			if recursive {
+				contents = append(contents, c.listObjects(name, "", true)...)
+			}
+		}
+		return contents
+	}
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return []*client.Content{{Err: err.Trace(bucketName)}}
+	}
+	if recursive {
+		return c.listObjects(bucketName, objectName, true)
+	}
+	if objectName == "" && !strings.HasSuffix(c.hostURL.Path, "/") {
+		return []*client.Content{{URL: *c.hostURL, Time: b.created, Type: os.ModeDir}}
+	}
+	if o := b.latest(objectName); o != nil && objectName != "" {
+		return []*client.Content{o.content(*c.hostURL, false)}
+	}
+	return c.listObjects(bucketName, objectName, false)
+}
+
+// listObjects - current versions of all objects below prefix, those below
+// a further separator are rolled up into a single folder unless recursive.
+func (c *memoryClient) listObjects(bucketName, prefix string, recursive bool) []*client.Content {
+	b := memStore.buckets[bucketName]
+	var contents []*client.Content
+	var lastFolder string
+	for _, key := range b.sortedKeys(prefix) {
+		o := b.latest(key)
+		if o == nil {
+			continue
+		}
+		if !recursive {
+			if i := strings.Index(key[len(prefix):], "/"); i >= 0 {
+				folder := key[:len(prefix)+i+1]
+				if folder != lastFolder {
+					lastFolder = folder
+					contents = append(contents, &client.Content{URL: c.objectURL(bucketName, folder), Time: time.Now(), Type: os.ModeDir})
+				}
+				continue
+			}
+		}
+		contents = append(contents, o.content(c.objectURL(bucketName, key), false))
+	}
+	return contents
+}
+
+// listIncomplete - contents of a listing of incomplete uploads, store lock must be held.
+func (c *memoryClient) listIncomplete(recursive bool) []*client.Content {
+	bucketName, objectName := c.url2BucketAndObject()
+	bucketNames := []string{bucketName}
+	if bucketName == "" {
+		bucketNames = sortedBuckets()
+	} else if _, err := c.getBucket(bucketName); err != nil {
+		return []*client.Content{{Err: err.Trace(bucketName)}}
+	}
+	var contents []*client.Content
+	for _, name := range bucketNames {
+		b := memStore.buckets[name]
+		var keys []string
+		for key := range b.uploads {
+			if strings.HasPrefix(key, objectName) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		var lastFolder string
+		for _, key := range keys {
+			if !recursive {
+				if i := strings.Index(key[len(objectName):], "/"); i >= 0 {
+					folder := key[:len(objectName)+i+1]
+					if folder != lastFolder {
+						lastFolder = folder
+						contents = append(contents, &client.Content{URL: c.objectURL(name, folder), Time: time.Now(), Type: os.ModeDir})
+					}
+					continue
+				}
+			}
+			for _, u := range b.uploads[key] {
+				contents = append(contents, &client.Content{
+					URL:  c.objectURL(name, key),
+					Time: u.initiated,
+					Size: u.size,
+					Type: os.ModeTemporary,
+				})
+			}
+		}
+	}
+	return contents
+}
+
+// Get - get object, a range is read if offset or length are set.
+func (c *memoryClient) Get(ctx context.Context, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	if offset < 0 || length < 0 {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return nil, err.Trace(bucketName)
+	}
+	o := b.latest(objectName)
+	if o == nil {
+		return nil, probe.NewError(client.PathNotFound{Path: c.hostURL.Path})
+	}
+	reader, err := readRange(o.data, offset, length)
+	if err != nil {
+		return nil, err.Trace(bucketName, objectName)
+	}
+	notify(bucketName, objectName, client.EventAccess, int64(len(o.data)))
+	return reader, nil
+}
+
+// readRange - reader of [offset, offset+length) of data, length '0' reads till the end.
+func readRange(data []byte, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	if offset > int64(len(data)) {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	end := int64(len(data))
+	if length > 0 && offset+length < end {
+		end = offset + length
+	}
+	// Objects are never modified in place, the reader shares the data.
+	return bytes.NewReader(data[offset:end]), nil
+}
+
+// Put - put object.
+func (c *memoryClient) Put(ctx context.Context, data io.ReadSeeker, size int64) *probe.Error {
+	return c.PutWithMetadata(ctx, data, size, nil)
+}
+
+// PutWithMetadata - store an object with user metadata. An upload failing
+// before size bytes are read is kept as an incomplete upload, data is
+// verified against client.MetadataMD5 before the object is replaced.
+func (c *memoryClient) PutWithMetadata(ctx context.Context, data io.ReadSeeker, size int64, metadata map[string]string) *probe.Error {
+	bucketName, objectName := c.url2BucketAndObject()
+	if bucketName == "" {
+		return probe.NewError(client.BucketNameEmpty{})
+	}
+	if objectName == "" {
+		return probe.NewError(client.ObjectMissing{})
+	}
+	memStore.mutex.Lock()
+	_, err := c.getBucket(bucketName)
+	memStore.mutex.Unlock()
+	if err != nil {
+		return err.Trace(bucketName)
+	}
+
+	initiated := time.Now().UTC()
+	var buffer []byte
+	var e error
+	reader := client.ContextReader(ctx, data)
+	if size < 0 {
+		buffer, e = ioutil.ReadAll(reader)
+	} else {
+		buffer = make([]byte, size)
+		var n int
+		n, e = io.ReadFull(reader, buffer)
+		buffer = buffer[:n]
+	}
+
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	// The bucket may be gone by now.
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return err.Trace(bucketName)
+	}
+	if e != nil {
+		b.uploads[objectName] = append(b.uploads[objectName], &upload{initiated: initiated, size: int64(len(buffer))})
+		return probe.NewError(e)
+	}
+	sum := md5.Sum(buffer)
+	etag := hex.EncodeToString(sum[:])
+	o := &object{
+		versionID:   "null",
+		data:        buffer,
+		modTime:     time.Now().UTC(),
+		etag:        etag,
+		contentType: "application/octet-stream",
+		metadata:    make(map[string]string),
+	}
+	for key, value := range metadata {
+		if key == client.MetadataMD5 {
+			if value != etag {
+				return probe.NewError(client.ChecksumMismatch{Path: c.hostURL.String(), Expected: value, Computed: etag})
+			}
+			continue
+		}
+		if key == client.MetadataTags {
+			tags, e := url.ParseQuery(value)
+			if e != nil {
+				return probe.NewError(e)
+			}
+			o.tags = make(map[string]string)
+			for tag := range tags {
+				o.tags[tag] = tags.Get(tag)
+			}
+			continue
+		}
+		o.metadata[key] = value
+	}
+	if b.locked && b.retention.Mode != "" {
+		o.retention = client.Retention{Mode: b.retention.Mode, Until: o.modTime.AddDate(0, 0, b.retention.Days)}
+	}
+	b.addVersion(objectName, o)
+	notify(bucketName, objectName, client.EventCreate, o.size())
+	return nil
+}
+
+// Copy - copy an object, a version of it if versionID is set, to this URL
+// along with its metadata, content type and tags.
+func (c *memoryClient) Copy(ctx context.Context, source client.URL, versionID string) *probe.Error {
+	bucketName, objectName := c.url2BucketAndObject()
+	if objectName == "" {
+		return probe.NewError(client.ObjectMissing{})
+	}
+	if source.Scheme != client.MemoryScheme {
+		return probe.NewError(client.APINotImplemented{API: "Copy", APIType: "different hosts"})
+	}
+	sourceClnt := &memoryClient{hostURL: &source}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	var o *object
+	if versionID != "" {
+		var err *probe.Error
+		if o, err = sourceClnt.getVersion(versionID); err != nil {
+			return err.Trace(source.String(), versionID)
+		}
+	} else {
+		sourceBucketName, sourceObjectName := sourceClnt.url2BucketAndObject()
+		sourceBucket, err := c.getBucket(sourceBucketName)
+		if err != nil {
+			return err.Trace(source.String())
+		}
+		if o = sourceBucket.latest(sourceObjectName); o == nil {
+			return probe.NewError(client.PathNotFound{Path: source.String()})
+		}
+	}
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return err.Trace(bucketName)
+	}
+	copied := &object{
+		versionID:   "null",
+		data:        o.data,
+		modTime:     time.Now().UTC(),
+		etag:        o.etag,
+		contentType: o.contentType,
+		metadata:    make(map[string]string),
+	}
+	for key, value := range o.metadata {
+		copied.metadata[key] = value
+	}
+	if o.tags != nil {
+		copied.tags = make(map[string]string)
+		for key, value := range o.tags {
+			copied.tags[key] = value
+		}
+	}
+	if b.locked && b.retention.Mode != "" {
+		copied.retention = client.Retention{Mode: b.retention.Mode, Until: copied.modTime.AddDate(0, 0, b.retention.Days)}
+	}
+	b.addVersion(objectName, copied)
+	notify(bucketName, objectName, client.EventCreate, copied.size())
+	return nil
+}
+
+// size - size of the data of a version.
+func (o *object) size() int64 {
+	return int64(len(o.data))
+}
+
+// addVersion - make o the current version of key, a previous version is only
+// kept if versioning is enabled and replaced otherwise.
+func (b *bucket) addVersion(key string, o *object) {
+	if b.versioning == client.VersioningEnabled {
+		o.versionID = memStore.nextVersionID()
+	}
+	versions := b.objects[key]
+	if b.versioning == "" {
+		versions = nil
+	}
+	// There is at most one version without an id.
+	for i, version := range versions {
+		if version.versionID == "null" {
+			versions = append(versions[:i:i], versions[i+1:]...)
+			break
+		}
+	}
+	b.objects[key] = append(versions, o)
+}
+
+// validBucketName - bucket names as accepted by object storage.
+var validBucketName = regexp.MustCompile("^[a-z0-9][a-z0-9\\.\\-]{1,61}[a-z0-9]$")
+
+// MakeBucket - make a new bucket.
+func (c *memoryClient) MakeBucket(ctx context.Context) *probe.Error {
+	return c.makeBucket(false)
+}
+
+// makeBucket - make a new bucket, with object lock if locked.
+func (c *memoryClient) makeBucket(locked bool) *probe.Error {
+	bucketName, objectName := c.url2BucketAndObject()
+	if objectName != "" {
+		return probe.NewError(client.BucketNameTopLevel{})
+	}
+	if bucketName == "" {
+		return probe.NewError(client.BucketNameEmpty{})
+	}
+	if !validBucketName.MatchString(bucketName) {
+		return probe.NewError(client.InvalidBucketName{Bucket: bucketName})
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	if _, ok := memStore.buckets[bucketName]; ok {
+		return probe.NewError(client.BucketExists{Bucket: bucketName})
+	}
+	b := &bucket{
+		created: time.Now().UTC(),
+		access:  "private",
+		locked:  locked,
+		objects: make(map[string][]*object),
+		uploads: make(map[string][]*upload),
+	}
+	// Object lock implies versioning.
+	if locked {
+		b.versioning = client.VersioningEnabled
+	}
+	memStore.buckets[bucketName] = b
+	return nil
+}
+
+// validAccess - canned ACLs of a bucket.
+var validAccess = map[string]bool{
+	"private":            true,
+	"public-read":        true,
+	"public-read-write":  true,
+	"authenticated-read": true,
+}
+
+// GetBucketAccess get acl on a bucket.
+func (c *memoryClient) GetBucketAccess(ctx context.Context) (string, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	if objectName != "" {
+		return "", probe.NewError(client.InvalidBucketName{Bucket: bucketName + "/" + objectName})
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return "", err.Trace(bucketName)
+	}
+	return b.access, nil
+}
+
+// SetBucketAccess set acl on a bucket
+func (c *memoryClient) SetBucketAccess(ctx context.Context, access string) *probe.Error {
+	bucketName, objectName := c.url2BucketAndObject()
+	if objectName != "" {
+		return probe.NewError(client.InvalidBucketName{Bucket: bucketName + "/" + objectName})
+	}
+	if !validAccess[access] {
+		return probe.NewError(errors.New("Unrecognized access ‘" + access + "’."))
+	}
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return err.Trace(bucketName)
+	}
+	b.access = access
+	return nil
+}
+
+// ShareDownload - objects in memory are not reachable by other processes.
+func (c *memoryClient) ShareDownload(ctx context.Context, expires time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "ShareDownload", APIType: "memory"})
+}
+
+// ShareUpload - objects in memory are not reachable by other processes.
+func (c *memoryClient) ShareUpload(ctx context.Context, recursive bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "ShareUpload", APIType: "memory"})
+}
+
+// Remove - remove object, incomplete uploads of an object or an empty bucket.
+func (c *memoryClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	bucketName, objectName := c.url2BucketAndObject()
+	memStore.mutex.Lock()
+	defer memStore.mutex.Unlock()
+	b, err := c.getBucket(bucketName)
+	if err != nil {
+		return err.Trace(bucketName)
+	}
+	if incomplete {
+		delete(b.uploads, objectName)
+		return nil
+	}
+	if objectName == "" {
+		if len(b.objects) > 0 || len(b.uploads) > 0 {
+			return probe.NewError(errors.New("Bucket ‘" + bucketName + "’ is not empty."))
+		}
+		delete(memStore.buckets, bucketName)
+		return nil
+	}
+	b.removeObject(bucketName, objectName)
+	return nil
+}
+
+// removeObject - remove the current version of key, a delete marker
+// takes its place if versioning was ever enabled.
+func (b *bucket) removeObject(bucketName, key string) {
+	o := b.latest(key)
+	if o == nil {
+		return
+	}
+	if b.versioning == "" {
+		delete(b.objects, key)
+	} else {
+		b.addVersion(key, &object{versionID: "null", deleteMarker: true, modTime: time.Now().UTC()})
+	}
+	notify(bucketName, key, client.EventRemove, o.size())
+}
+
+// RemoveBatch - remove all objects received on contentCh, each object is
+// sent back with Err set if it could not be removed.
+func (c *memoryClient) RemoveBatch(ctx context.Context, contentCh <-chan *client.Content) <-chan *client.Content {
+	resultCh := make(chan *client.Content)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			if e := ctx.Err(); e != nil {
+				content.Err = probe.NewError(e)
+				resultCh <- content
+				continue
+			}
+			bucketName, objectName := splitPath(content.URL.Path)
+			memStore.mutex.Lock()
+			b, err := c.getBucket(bucketName)
+			if err != nil {
+				content.Err = err.Trace(bucketName)
+			} else {
+				b.removeObject(bucketName, objectName)
+			}
+			memStore.mutex.Unlock()
+			resultCh <- content
+		}
+	}()
+	return client.ForwardContents(ctx, resultCh)
+}
+
+// Select - queries are not implemented for memory, the caller evaluates them.
+func (c *memoryClient) Select(ctx context.Context, query client.SelectQuery) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Select", APIType: "memory"})
+}
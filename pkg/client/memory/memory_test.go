@@ -0,0 +1,202 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func (s *MySuite) SetUpTest(c *C) {
+	Reset()
+}
+
+// newTestClient - client of a memory URL.
+func newTestClient(c *C, url string) client.Client {
+	clnt, err := New(url)
+	c.Assert(err, IsNil)
+	return clnt
+}
+
+// listURLs - URLs of a listing.
+func listURLs(c *C, url string, recursive, incomplete bool) []string {
+	var urls []string
+	for content := range newTestClient(c, url).List(context.Background(), recursive, incomplete) {
+		c.Assert(content.Err, IsNil)
+		urls = append(urls, content.URL.String())
+	}
+	return urls
+}
+
+func (s *MySuite) TestList(c *C) {
+	c.Assert(newTestClient(c, "mem://bucket").MakeBucket(context.Background()), IsNil)
+	for _, key := range []string{"b/2", "a", "b/1", "c/d/e", "b.txt"} {
+		c.Assert(newTestClient(c, "mem://bucket/"+key).Put(context.Background(), bytes.NewReader([]byte(key)), int64(len(key))), IsNil)
+	}
+
+	c.Assert(listURLs(c, "mem://bucket/", false, false), DeepEquals, []string{
+		"mem://bucket/a", "mem://bucket/b.txt", "mem://bucket/b/", "mem://bucket/c/",
+	})
+	c.Assert(listURLs(c, "mem://bucket/", true, false), DeepEquals, []string{
+		"mem://bucket/a", "mem://bucket/b.txt", "mem://bucket/b/1", "mem://bucket/b/2", "mem://bucket/c/d/e",
+	})
+	c.Assert(listURLs(c, "mem://bucket/b", false, false), DeepEquals, []string{
+		"mem://bucket/b.txt", "mem://bucket/b/",
+	})
+	c.Assert(listURLs(c, "mem://bucket/b/", false, false), DeepEquals, []string{
+		"mem://bucket/b/1", "mem://bucket/b/2",
+	})
+	c.Assert(listURLs(c, "mem://", false, false), DeepEquals, []string{"mem://bucket"})
+
+	content, err := newTestClient(c, "mem://bucket/c/d").Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+	_, err = newTestClient(c, "mem://bucket/missing").Stat(context.Background())
+	_, ok := err.ToGoError().(client.PathNotFound)
+	c.Assert(ok, Equals, true)
+}
+
+// failingReader - returns an error after its data.
+type failingReader struct {
+	*bytes.Reader
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	n, e := r.Reader.Read(p)
+	if e == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, e
+}
+
+func (s *MySuite) TestIncomplete(c *C) {
+	c.Assert(newTestClient(c, "mem://bucket").MakeBucket(context.Background()), IsNil)
+	clnt := newTestClient(c, "mem://bucket/folder/object")
+	err := clnt.PutWithMetadata(context.Background(), failingReader{bytes.NewReader([]byte("hel"))}, 5, nil)
+	c.Assert(err, Not(IsNil))
+	c.Assert(listURLs(c, "mem://bucket/", false, false), HasLen, 0)
+	c.Assert(listURLs(c, "mem://bucket/", false, true), DeepEquals, []string{"mem://bucket/folder/"})
+	c.Assert(listURLs(c, "mem://bucket/", true, true), DeepEquals, []string{"mem://bucket/folder/object"})
+
+	c.Assert(clnt.Remove(context.Background(), true), IsNil)
+	c.Assert(listURLs(c, "mem://bucket/", true, true), HasLen, 0)
+}
+
+func (s *MySuite) TestPutGet(c *C) {
+	c.Assert(newTestClient(c, "mem://bucket").MakeBucket(context.Background()), IsNil)
+	clnt := newTestClient(c, "mem://bucket/object")
+	c.Assert(clnt.PutWithMetadata(context.Background(), bytes.NewReader([]byte("hello world")), 11, map[string]string{
+		client.MetadataMD5: "5eb63bbbe01eeed093cb22bb8f5acdc3",
+		"Color":            "blue",
+	}), IsNil)
+	content, err := clnt.Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.ETag, Equals, "5eb63bbbe01eeed093cb22bb8f5acdc3")
+	c.Assert(content.Metadata, DeepEquals, map[string]string{"Color": "blue"})
+
+	c.Assert(clnt.PutWithMetadata(context.Background(), bytes.NewReader([]byte("hello world")), 11, map[string]string{
+		client.MetadataTags: "project=mc&cost=42",
+	}), IsNil)
+	tags, err := clnt.GetTags(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(tags, DeepEquals, map[string]string{"project": "mc", "cost": "42"})
+
+	reader, err := clnt.Get(context.Background(), 6, 3)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "wor")
+
+	err = clnt.PutWithMetadata(context.Background(), bytes.NewReader([]byte("corrupted")), 9, map[string]string{
+		client.MetadataMD5: "5eb63bbbe01eeed093cb22bb8f5acdc3",
+	})
+	_, ok := err.ToGoError().(client.ChecksumMismatch)
+	c.Assert(ok, Equals, true)
+}
+
+func (s *MySuite) TestAccess(c *C) {
+	clnt := newTestClient(c, "mem://bucket")
+	c.Assert(clnt.MakeBucket(context.Background()), IsNil)
+	_, ok := clnt.MakeBucket(context.Background()).ToGoError().(client.BucketExists)
+	c.Assert(ok, Equals, true)
+
+	access, err := clnt.GetBucketAccess(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(access, Equals, "private")
+	c.Assert(clnt.SetBucketAccess(context.Background(), "public-read"), IsNil)
+	access, err = clnt.GetBucketAccess(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(access, Equals, "public-read")
+	c.Assert(clnt.SetBucketAccess(context.Background(), "everyone"), Not(IsNil))
+}
+
+func (s *MySuite) TestVersions(c *C) {
+	c.Assert(newTestClient(c, "mem://bucket").MakeBucket(context.Background()), IsNil)
+	c.Assert(newTestClient(c, "mem://bucket").SetVersioning(context.Background(), client.VersioningEnabled), IsNil)
+	clnt := newTestClient(c, "mem://bucket/object")
+	c.Assert(clnt.Put(context.Background(), bytes.NewReader([]byte("v1")), 2), IsNil)
+	c.Assert(clnt.Put(context.Background(), bytes.NewReader([]byte("v2")), 2), IsNil)
+	c.Assert(clnt.Remove(context.Background(), false), IsNil)
+
+	var versions []*client.Content
+	for content := range clnt.ListVersions(context.Background(), false) {
+		c.Assert(content.Err, IsNil)
+		versions = append(versions, content)
+	}
+	c.Assert(versions, HasLen, 3)
+	c.Assert(versions[0].IsDeleteMarker, Equals, true)
+	c.Assert(versions[0].IsLatest, Equals, true)
+
+	reader, err := clnt.GetVersion(context.Background(), versions[2].VersionID, 0, 0)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "v1")
+
+	// Removing the delete marker brings back the latest version.
+	c.Assert(clnt.RemoveVersion(context.Background(), versions[0].VersionID), IsNil)
+	content, err := clnt.Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(2))
+}
+
+func (s *MySuite) TestWatch(c *C) {
+	c.Assert(newTestClient(c, "mem://bucket").MakeBucket(context.Background()), IsNil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eventCh, err := newTestClient(c, "mem://bucket/").Watch(ctx, []string{client.EventCreate}, true)
+	c.Assert(err, IsNil)
+	c.Assert(newTestClient(c, "mem://bucket/object").Put(context.Background(), bytes.NewReader([]byte("hello")), 5), IsNil)
+	event := <-eventCh
+	c.Assert(event.Type, Equals, client.EventCreate)
+	c.Assert(event.URL.String(), Equals, "mem://bucket/object")
+	c.Assert(event.Size, Equals, int64(5))
+}
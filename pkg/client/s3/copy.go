@@ -0,0 +1,159 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// Parts of objects too large for a single copy are copied this large.
+const copyPartSize = 512 * 1024 * 1024
+
+// copyPartResult - reply of a request copying a part of a multipart upload.
+type copyPartResult struct {
+	ETag string
+}
+
+// Copy - copy an object of this host, a version of it if versionID is set,
+// to this URL without transferring its data. Metadata, content type and
+// tags are copied along.
+func (c *s3Client) Copy(ctx context.Context, source client.URL, versionID string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(client.ObjectMissing{})
+	}
+	if source.Scheme != c.hostURL.Scheme || source.Host != c.hostURL.Host {
+		return probe.NewError(client.APINotImplemented{API: "Copy", APIType: "different hosts"})
+	}
+	sourceBucket, sourceObject := c.splitURL(&source)
+	if sourceObject == "" {
+		return probe.NewError(client.ObjectMissing{})
+	}
+	copySource := encodePath("/" + sourceBucket + "/" + sourceObject)
+	var queryValues url.Values
+	if versionID != "" {
+		copySource += "?versionId=" + url.QueryEscape(versionID)
+		queryValues = url.Values{"versionId": {versionID}}
+	}
+	content, err := c.headObject(ctx, sourceBucket, sourceObject, queryValues)
+	if err != nil {
+		return err.Trace(sourceBucket, sourceObject, versionID)
+	}
+	if content.Size > maxSinglePutSize {
+		return c.copyMultipart(ctx, bucket, object, copySource, content)
+	}
+	resp, err := c.executeMethod(ctx, "PUT", requestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		customHeader: http.Header{"X-Amz-Copy-Source": {copySource}},
+	})
+	if err != nil {
+		return err.Trace(bucket, object, copySource)
+	}
+	_, err = readReply(resp)
+	return err.Trace(bucket, object, copySource)
+}
+
+// copyMultipart - copy an object too large for a single copy part by part,
+// metadata is not copied along by multipart uploads and set from content.
+func (c *s3Client) copyMultipart(ctx context.Context, bucket, object, copySource string, content *client.Content) *probe.Error {
+	header := make(http.Header)
+	header.Set("Content-Type", content.ContentType)
+	for key, value := range content.Metadata {
+		header.Set(metadataPrefix+key, value)
+	}
+	resp, err := c.executeMethod(ctx, "POST", requestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  url.Values{"uploads": {""}},
+		customHeader: header,
+	})
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	initiate := initiateMultipartUploadResult{}
+	if err = decodeXMLResponse(resp, &initiate); err != nil {
+		return err.Trace(bucket, object)
+	}
+	uploadID := initiate.UploadID
+
+	partSize := int64(copyPartSize)
+	if content.Size > partSize*maxParts {
+		partSize = (content.Size + maxParts - 1) / maxParts
+	}
+	complete := completeMultipartUpload{}
+	for offset := int64(0); offset < content.Size && err == nil; offset += partSize {
+		end := offset + partSize
+		if end > content.Size {
+			end = content.Size
+		}
+		partNumber := len(complete.Parts) + 1
+		var etag string
+		etag, err = c.copyPart(ctx, bucket, object, uploadID, partNumber, copySource, offset, end-1)
+		complete.Parts = append(complete.Parts, completePart{PartNumber: partNumber, ETag: etag})
+	}
+	if err == nil {
+		err = c.completeMultipart(ctx, bucket, object, uploadID, complete)
+	}
+	if err != nil {
+		// Aborted even when cancelled, so that no parts are left behind.
+		resp, abortErr := c.executeMethod(context.Background(), "DELETE", requestMetadata{
+			bucketName:  bucket,
+			objectName:  object,
+			queryValues: url.Values{"uploadId": {uploadID}},
+		})
+		if abortErr == nil {
+			closeResponse(resp)
+		}
+		return err.Trace(bucket, object, uploadID)
+	}
+	return nil
+}
+
+// copyPart - copy the bytes first to last of copySource as a part of a multipart upload.
+func (c *s3Client) copyPart(ctx context.Context, bucket, object, uploadID string, partNumber int, copySource string, first, last int64) (string, *probe.Error) {
+	resp, err := c.executeMethod(ctx, "PUT", requestMetadata{
+		bucketName: bucket,
+		objectName: object,
+		queryValues: url.Values{
+			"partNumber": {strconv.Itoa(partNumber)},
+			"uploadId":   {uploadID},
+		},
+		customHeader: http.Header{
+			"X-Amz-Copy-Source":       {copySource},
+			"X-Amz-Copy-Source-Range": {"bytes=" + strconv.FormatInt(first, 10) + "-" + strconv.FormatInt(last, 10)},
+		},
+	})
+	if err != nil {
+		return "", err.Trace(bucket, object, strconv.Itoa(partNumber))
+	}
+	body, err := readReply(resp)
+	if err != nil {
+		return "", err.Trace(bucket, object, strconv.Itoa(partNumber))
+	}
+	result := copyPartResult{}
+	if e := xml.Unmarshal(body, &result); e != nil {
+		return "", probe.NewError(e)
+	}
+	return result.ETag, nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package s3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestCopy(c *C) {
+	var copySource string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "HEAD" && r.URL.Path == "/bucket/old name":
+			w.Header().Set("Content-Length", "12")
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		case r.Method == "PUT" && r.URL.Path == "/bucket/object":
+			copySource = r.Header.Get("X-Amz-Copy-Source")
+			if r.ContentLength > 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			// Copies of the latest version fail after the reply was started.
+			if copySource == "/bucket/old%20name" {
+				w.Write([]byte("<Error><Code>InternalError</Code><Message>We encountered an internal error. Please try again.</Message></Error>"))
+				return
+			}
+			w.Write([]byte("<CopyObjectResult><ETag>\"object\"</ETag></CopyObjectResult>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	source := *client.NewURL(server.URL + "/bucket/old name")
+	c.Assert(s3c.Copy(context.Background(), source, "v1"), IsNil)
+	c.Assert(copySource, Equals, "/bucket/old%20name?versionId=v1")
+
+	// Errors in the body of a successful reply are reported.
+	err = s3c.Copy(context.Background(), source, "")
+	c.Assert(err, Not(IsNil))
+	c.Assert(err.ToGoError().Error(), Equals, "We encountered an internal error. Please try again.")
+
+	// Objects are only copied within a host.
+	err = s3c.Copy(context.Background(), *client.NewURL("https://example.com/bucket/object"), "")
+	_, ok := err.ToGoError().(client.APINotImplemented)
+	c.Assert(ok, Equals, true)
+}
@@ -0,0 +1,221 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// createBucketConfiguration - location constraint of a new bucket.
+type createBucketConfiguration struct {
+	XMLName  xml.Name `xml:"CreateBucketConfiguration"`
+	Xmlns    string   `xml:"xmlns,attr,omitempty"`
+	Location string   `xml:"LocationConstraint"`
+}
+
+// objectLockRule - default retention applied to new objects of a bucket.
+type objectLockRule struct {
+	DefaultRetention struct {
+		Mode  string `xml:"Mode,omitempty"`
+		Days  int    `xml:"Days,omitempty"`
+		Years int    `xml:"Years,omitempty"`
+	}
+}
+
+// objectLockConfiguration - object lock state and default retention of a bucket.
+type objectLockConfiguration struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	Xmlns             string          `xml:"xmlns,attr,omitempty"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled,omitempty"`
+	Rule              *objectLockRule `xml:"Rule,omitempty"`
+}
+
+// objectRetention - retention of a single object.
+type objectRetention struct {
+	XMLName         xml.Name   `xml:"Retention"`
+	Xmlns           string     `xml:"xmlns,attr,omitempty"`
+	Mode            string     `xml:"Mode,omitempty"`
+	RetainUntilDate *time.Time `xml:"RetainUntilDate,omitempty"`
+}
+
+// objectLegalHold - legal hold of a single object.
+type objectLegalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Status  string   `xml:"Status"`
+}
+
+// MakeBucketWithLock - make a new bucket with object lock enabled, which implies versioning.
+func (c *s3Client) MakeBucketWithLock(ctx context.Context) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if err := checkMakeBucket(bucket, object); err != nil {
+		return err.Trace(bucket, object)
+	}
+	metadata := requestMetadata{bucketName: bucket}
+	if region := c.getRegion(); region != "us-east-1" && region != "google" {
+		var err *probe.Error
+		metadata, err = newXMLRequestMetadata(bucket, "", nil, createBucketConfiguration{Xmlns: s3Namespace, Location: region})
+		if err != nil {
+			return err.Trace(bucket)
+		}
+	}
+	metadata.customHeader = http.Header{}
+	metadata.customHeader.Set("x-amz-acl", "private")
+	metadata.customHeader.Set("x-amz-bucket-object-lock-enabled", "true")
+	resp, err := c.executeMethod(ctx, "PUT", metadata)
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// GetRetention - get retention of an object, or default retention of a bucket if the URL has no object.
+func (c *s3Client) GetRetention(ctx context.Context) (client.Retention, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return client.Retention{}, probe.NewError(client.BucketNameEmpty{})
+	}
+	if object == "" {
+		config := objectLockConfiguration{}
+		if err := c.getLockDocument(ctx, bucket, "", "object-lock", &config); err != nil {
+			return client.Retention{}, err.Trace(bucket)
+		}
+		if config.Rule == nil {
+			return client.Retention{}, nil
+		}
+		return client.Retention{
+			Mode: config.Rule.DefaultRetention.Mode,
+			Days: config.Rule.DefaultRetention.Days + 365*config.Rule.DefaultRetention.Years,
+		}, nil
+	}
+	retention := objectRetention{}
+	if err := c.getLockDocument(ctx, bucket, object, "retention", &retention); err != nil {
+		return client.Retention{}, err.Trace(bucket, object)
+	}
+	if retention.RetainUntilDate == nil {
+		return client.Retention{Mode: retention.Mode}, nil
+	}
+	return client.Retention{Mode: retention.Mode, Until: *retention.RetainUntilDate}, nil
+}
+
+// SetRetention - set retention of an object, or default retention of a bucket if the URL has no object.
+func (c *s3Client) SetRetention(ctx context.Context, retention client.Retention) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(client.BucketNameEmpty{})
+	}
+	var v interface{}
+	var resource string
+	if object == "" {
+		config := objectLockConfiguration{Xmlns: s3Namespace, ObjectLockEnabled: "Enabled"}
+		if retention.Mode != "" {
+			config.Rule = &objectLockRule{}
+			config.Rule.DefaultRetention.Mode = retention.Mode
+			config.Rule.DefaultRetention.Days = retention.Days
+		}
+		v, resource = config, "object-lock"
+	} else {
+		until := retention.Until.UTC()
+		v, resource = objectRetention{Xmlns: s3Namespace, Mode: retention.Mode, RetainUntilDate: &until}, "retention"
+	}
+	metadata, err := newXMLRequestMetadata(bucket, object, url.Values{resource: {""}}, v)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	resp, err := c.executeMethod(ctx, "PUT", metadata)
+	if err != nil {
+		return c.toLockError(err).Trace(bucket, object)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// GetLegalHold - get legal hold status of an object.
+func (c *s3Client) GetLegalHold(ctx context.Context) (bool, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return false, probe.NewError(client.ObjectMissing{})
+	}
+	legalHold := objectLegalHold{}
+	if err := c.getLockDocument(ctx, bucket, object, "legal-hold", &legalHold); err != nil {
+		return false, err.Trace(bucket, object)
+	}
+	return legalHold.Status == "ON", nil
+}
+
+// SetLegalHold - set or clear legal hold of an object.
+func (c *s3Client) SetLegalHold(ctx context.Context, on bool) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(client.ObjectMissing{})
+	}
+	legalHold := objectLegalHold{Xmlns: s3Namespace, Status: "OFF"}
+	if on {
+		legalHold.Status = "ON"
+	}
+	metadata, err := newXMLRequestMetadata(bucket, object, url.Values{"legal-hold": {""}}, legalHold)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	resp, err := c.executeMethod(ctx, "PUT", metadata)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// getLockDocument - fetch an object lock sub-resource, a missing configuration leaves v untouched.
+func (c *s3Client) getLockDocument(ctx context.Context, bucket, object, resource string, v interface{}) *probe.Error {
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{resource: {""}},
+	})
+	if err != nil {
+		if errResponse := minio.ToErrorResponse(err.ToGoError()); errResponse != nil {
+			switch errResponse.Code {
+			case "ObjectLockConfigurationNotFoundError", "NoSuchObjectLockConfiguration":
+				return nil
+			}
+		}
+		return err.Trace(bucket, object, resource)
+	}
+	return decodeXMLResponse(resp, v).Trace(bucket, object, resource)
+}
+
+// toLockError - translate errors about retention periods and legal holds into client.ObjectLocked.
+func (c *s3Client) toLockError(err *probe.Error) *probe.Error {
+	errResponse := minio.ToErrorResponse(err.ToGoError())
+	if errResponse == nil {
+		return err
+	}
+	// Messages differ between servers, only the code is reliable.
+	if errResponse.Code == "ObjectLocked" {
+		return probe.NewError(client.ObjectLocked{Path: c.hostURL.String()})
+	}
+	return err
+}
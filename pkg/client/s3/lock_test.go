@@ -0,0 +1,142 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+
+	. "gopkg.in/check.v1"
+)
+
+// lockHandler is an http.Handler serving a bucket with object lock enabled.
+type lockHandler struct {
+	documents map[string][]byte
+}
+
+func (h lockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if r.Method == "PUT" && r.URL.Path == "/bucket" && len(query) == 0 {
+		if r.Header.Get("x-amz-bucket-object-lock-enabled") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+	if r.Method == "DELETE" {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>ObjectLocked</Code><Message>Object is WORM protected and cannot be overwritten</Message></Error>"))
+		return
+	}
+	var resource string
+	for _, name := range []string{"object-lock", "retention", "legal-hold"} {
+		if _, ok := query[name]; ok {
+			resource = r.URL.Path + "?" + name
+		}
+	}
+	switch r.Method {
+	case "PUT":
+		if r.Header.Get("Content-MD5") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.documents[resource], _ = ioutil.ReadAll(r.Body)
+	case "GET":
+		document, ok := h.documents[resource]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<Error><Code>NoSuchObjectLockConfiguration</Code></Error>"))
+			return
+		}
+		w.Write(document)
+	}
+}
+
+func (s *MySuite) TestLockOperations(c *C) {
+	handler := lockHandler{documents: make(map[string][]byte)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	err = s3c.MakeBucketWithLock(context.Background())
+	c.Assert(err, IsNil)
+
+	// Bucket default retention.
+	retention, err := s3c.GetRetention(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(retention.Mode, Equals, "")
+	err = s3c.SetRetention(context.Background(), client.Retention{Mode: client.RetentionCompliance, Days: 90})
+	c.Assert(err, IsNil)
+	retention, err = s3c.GetRetention(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(retention, DeepEquals, client.Retention{Mode: client.RetentionCompliance, Days: 90})
+
+	// Cleared by a configuration without a rule.
+	err = s3c.SetRetention(context.Background(), client.Retention{})
+	c.Assert(err, IsNil)
+	retention, err = s3c.GetRetention(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(retention, DeepEquals, client.Retention{})
+
+	conf.HostURL = server.URL + "/bucket/object"
+	s3c, err = New(conf)
+	c.Assert(err, IsNil)
+
+	// Object retention.
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = s3c.SetRetention(context.Background(), client.Retention{Mode: client.RetentionGovernance, Until: until})
+	c.Assert(err, IsNil)
+	retention, err = s3c.GetRetention(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(retention.Mode, Equals, client.RetentionGovernance)
+	c.Assert(retention.Until.Equal(until), Equals, true)
+
+	// Legal hold.
+	on, err := s3c.GetLegalHold(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(on, Equals, false)
+	err = s3c.SetLegalHold(context.Background(), true)
+	c.Assert(err, IsNil)
+	on, err = s3c.GetLegalHold(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(on, Equals, true)
+
+	// Locked objects cannot be removed.
+	err = s3c.Remove(context.Background(), false)
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.ObjectLocked)
+	c.Assert(ok, Equals, true)
+	err = s3c.RemoveVersion(context.Background(), "v1")
+	c.Assert(err, Not(IsNil))
+	_, ok = err.ToGoError().(client.ObjectLocked)
+	c.Assert(ok, Equals, true)
+
+	// Other errors are kept, whatever their message.
+	err = s3c.(*s3Client).toLockError(probe.NewError(minio.ErrorResponse{Code: "AccessDenied", Message: "Object is WORM protected and cannot be overwritten"}))
+	_, ok = err.ToGoError().(client.ObjectLocked)
+	c.Assert(ok, Equals, false)
+}
@@ -0,0 +1,337 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// filterRule - a prefix or suffix rule of a notification filter.
+type filterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+// notificationFilter - object key filter of a notification target.
+type notificationFilter struct {
+	FilterRules []filterRule `xml:"S3Key>FilterRule"`
+}
+
+// notificationTarget - a <QueueConfiguration>, <TopicConfiguration> or <CloudFunctionConfiguration>.
+type notificationTarget struct {
+	ID            string              `xml:"Id,omitempty"`
+	Queue         string              `xml:"Queue,omitempty"`
+	Topic         string              `xml:"Topic,omitempty"`
+	CloudFunction string              `xml:"CloudFunction,omitempty"`
+	Events        []string            `xml:"Event"`
+	Filter        *notificationFilter `xml:"Filter,omitempty"`
+}
+
+// notificationConfiguration - bucket notification configuration.
+type notificationConfiguration struct {
+	XMLName        xml.Name             `xml:"NotificationConfiguration"`
+	Xmlns          string               `xml:"xmlns,attr,omitempty"`
+	Queues         []notificationTarget `xml:"QueueConfiguration"`
+	Topics         []notificationTarget `xml:"TopicConfiguration"`
+	CloudFunctions []notificationTarget `xml:"CloudFunctionConfiguration"`
+}
+
+// notificationRecord - a single record of a ListenBucketNotification reply.
+type notificationRecord struct {
+	EventName string    `json:"eventName"`
+	EventTime time.Time `json:"eventTime"`
+	S3        struct {
+		Object struct {
+			Key  string `json:"key"`
+			Size int64  `json:"size"`
+		} `json:"object"`
+	} `json:"s3"`
+}
+
+// s3 event name prefixes of client event types.
+var eventPrefixes = map[string]string{
+	client.EventCreate: "s3:ObjectCreated:",
+	client.EventRemove: "s3:ObjectRemoved:",
+	client.EventAccess: "s3:ObjectAccessed:",
+}
+
+// toS3Events - translate client event types into s3 event names, unknown names are passed as is.
+func toS3Events(events []string) []string {
+	var s3Events []string
+	for _, event := range events {
+		if prefix, ok := eventPrefixes[event]; ok {
+			s3Events = append(s3Events, prefix+"*")
+			continue
+		}
+		s3Events = append(s3Events, event)
+	}
+	return s3Events
+}
+
+// fromS3Event - translate an s3 event name into a client event type.
+func fromS3Event(s3Event string) string {
+	for event, prefix := range eventPrefixes {
+		if strings.HasPrefix(s3Event, prefix) {
+			return event
+		}
+	}
+	return s3Event
+}
+
+// GetNotifications - list all notification targets of a bucket.
+func (c *s3Client) GetNotifications(ctx context.Context) ([]client.NotificationConfig, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object != "" {
+		return nil, probe.NewError(client.InvalidBucketName{Bucket: filepath.Join(bucket, object)})
+	}
+	notification, err := c.getNotificationConfiguration(ctx, bucket)
+	if err != nil {
+		return nil, err.Trace(bucket)
+	}
+	var configs []client.NotificationConfig
+	targets := append(append(notification.Queues, notification.Topics...), notification.CloudFunctions...)
+	for _, target := range targets {
+		config := client.NotificationConfig{
+			ID:  target.ID,
+			ARN: target.Queue + target.Topic + target.CloudFunction,
+		}
+		for _, event := range target.Events {
+			config.Events = append(config.Events, fromS3Event(event))
+		}
+		if target.Filter != nil {
+			for _, rule := range target.Filter.FilterRules {
+				switch strings.ToLower(rule.Name) {
+				case "prefix":
+					config.Prefix = rule.Value
+				case "suffix":
+					config.Suffix = rule.Value
+				}
+			}
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// AddNotification - add a notification target to the existing configuration of a bucket.
+func (c *s3Client) AddNotification(ctx context.Context, config client.NotificationConfig) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object != "" {
+		return probe.NewError(client.InvalidBucketName{Bucket: filepath.Join(bucket, object)})
+	}
+	notification, err := c.getNotificationConfiguration(ctx, bucket)
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	target := notificationTarget{
+		ID:     config.ID,
+		Events: toS3Events(config.Events),
+	}
+	if config.Prefix != "" || config.Suffix != "" {
+		target.Filter = new(notificationFilter)
+		if config.Prefix != "" {
+			target.Filter.FilterRules = append(target.Filter.FilterRules, filterRule{Name: "prefix", Value: config.Prefix})
+		}
+		if config.Suffix != "" {
+			target.Filter.FilterRules = append(target.Filter.FilterRules, filterRule{Name: "suffix", Value: config.Suffix})
+		}
+	}
+	// arn:partition:service:region:account-id:resource
+	fields := strings.SplitN(config.ARN, ":", 6)
+	if len(fields) != 6 || fields[0] != "arn" {
+		return probe.NewError(client.InvalidARN{ARN: config.ARN})
+	}
+	switch fields[2] {
+	case "sqs":
+		target.Queue = config.ARN
+		notification.Queues = append(notification.Queues, target)
+	case "sns":
+		target.Topic = config.ARN
+		notification.Topics = append(notification.Topics, target)
+	case "lambda":
+		target.CloudFunction = config.ARN
+		notification.CloudFunctions = append(notification.CloudFunctions, target)
+	default:
+		return probe.NewError(client.InvalidARN{ARN: config.ARN})
+	}
+	return c.setNotificationConfiguration(ctx, bucket, notification).Trace(bucket, config.ARN)
+}
+
+// RemoveNotification - remove all notification targets of a bucket pointing to this ARN.
+func (c *s3Client) RemoveNotification(ctx context.Context, arn string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object != "" {
+		return probe.NewError(client.InvalidBucketName{Bucket: filepath.Join(bucket, object)})
+	}
+	notification, err := c.getNotificationConfiguration(ctx, bucket)
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	filterTargets := func(targets []notificationTarget) []notificationTarget {
+		var kept []notificationTarget
+		for _, target := range targets {
+			if target.Queue+target.Topic+target.CloudFunction != arn {
+				kept = append(kept, target)
+			}
+		}
+		return kept
+	}
+	notification.Queues = filterTargets(notification.Queues)
+	notification.Topics = filterTargets(notification.Topics)
+	notification.CloudFunctions = filterTargets(notification.CloudFunctions)
+	return c.setNotificationConfiguration(ctx, bucket, notification).Trace(bucket, arn)
+}
+
+// getNotificationConfiguration - fetch the ?notification document of a bucket.
+func (c *s3Client) getNotificationConfiguration(ctx context.Context, bucket string) (notificationConfiguration, *probe.Error) {
+	notification := notificationConfiguration{}
+	if bucket == "" {
+		return notification, probe.NewError(client.BucketNameEmpty{})
+	}
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"notification": {""}},
+	})
+	if err != nil {
+		return notification, err.Trace(bucket)
+	}
+	if err = decodeXMLResponse(resp, &notification); err != nil {
+		return notification, err.Trace(bucket)
+	}
+	return notification, nil
+}
+
+// setNotificationConfiguration - replace the ?notification document of a bucket.
+func (c *s3Client) setNotificationConfiguration(ctx context.Context, bucket string, notification notificationConfiguration) *probe.Error {
+	notification.Xmlns = s3Namespace
+	metadata, err := newXMLRequestMetadata(bucket, "", url.Values{"notification": {""}}, notification)
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	resp, err := c.executeMethod(ctx, "PUT", metadata)
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// Watch - stream events of objects under this prefix. This relies on the
+// ListenBucketNotification extension of Minio servers, a long lived request
+// replying with one JSON document per line and blank lines as keep-alives.
+func (c *s3Client) Watch(ctx context.Context, events []string, recursive bool) (<-chan *client.Event, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return nil, probe.NewError(client.BucketNameEmpty{})
+	}
+	queryValues := url.Values{}
+	queryValues.Set("prefix", object)
+	queryValues.Set("suffix", "")
+	for _, event := range toS3Events(events) {
+		queryValues.Add("events", event)
+	}
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+		bucketName:  bucket,
+		queryValues: queryValues,
+	})
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+
+	eventCh := make(chan *client.Event)
+	// Closing the body unblocks the reader below.
+	stopCh := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopCh:
+		}
+		resp.Body.Close()
+	}()
+	go func() {
+		defer close(eventCh)
+		defer close(stopCh)
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var info struct {
+				Records []notificationRecord
+			}
+			if e := json.Unmarshal([]byte(line), &info); e != nil {
+				c.sendEvent(ctx, eventCh, &client.Event{Err: probe.NewError(e)})
+				return
+			}
+			for _, record := range info.Records {
+				key, e := url.QueryUnescape(record.S3.Object.Key)
+				if e != nil {
+					key = record.S3.Object.Key
+				}
+				// Listening is always recursive, drop events below sub-folders if asked to.
+				if !recursive && strings.Contains(strings.TrimPrefix(key, object), string(c.hostURL.Separator)) {
+					continue
+				}
+				url := *c.hostURL
+				url.Path = filepath.Join(string(url.Separator), bucket, key)
+				if c.virtualStyle {
+					url.Path = filepath.Join(string(url.Separator), key)
+				}
+				event := &client.Event{
+					URL:  url,
+					Time: record.EventTime,
+					Size: record.S3.Object.Size,
+					Type: fromS3Event(record.EventName),
+				}
+				if !c.sendEvent(ctx, eventCh, event) {
+					return
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			// Body closed on purpose, not an error.
+		default:
+			if e := scanner.Err(); e != nil {
+				c.sendEvent(ctx, eventCh, &client.Event{Err: probe.NewError(e)})
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
+// sendEvent - deliver an event unless the watcher is done, returns false if done.
+func (c *s3Client) sendEvent(ctx context.Context, eventCh chan<- *client.Event, event *client.Event) bool {
+	select {
+	case eventCh <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
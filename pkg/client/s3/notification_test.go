@@ -0,0 +1,117 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+// notificationHandler is an http.Handler serving bucket notification requests.
+type notificationHandler struct {
+	document *[]byte
+}
+
+func (h notificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if _, ok := query["notification"]; ok {
+		switch r.Method {
+		case "PUT":
+			*h.document, _ = ioutil.ReadAll(r.Body)
+		case "GET":
+			if len(*h.document) == 0 {
+				w.Write([]byte("<NotificationConfiguration></NotificationConfiguration>"))
+				return
+			}
+			w.Write(*h.document)
+		}
+		return
+	}
+	// ListenBucketNotification, a keep-alive followed by two records.
+	if query.Get("prefix") != "photos/" || len(query["events"]) != 2 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Write([]byte(" \n"))
+	w.Write([]byte(`{"Records":[{"eventName":"s3:ObjectCreated:Put","eventTime":"2016-01-02T15:04:05Z","s3":{"object":{"key":"photos%2Fa+b.jpg","size":42}}}]}` + "\n"))
+	w.Write([]byte(`{"Records":[{"eventName":"s3:ObjectRemoved:Delete","eventTime":"2016-01-02T15:04:06Z","s3":{"object":{"key":"photos%2F2016%2Fc.jpg"}}}]}` + "\n"))
+}
+
+func (s *MySuite) TestNotificationOperations(c *C) {
+	document := []byte{}
+	server := httptest.NewServer(notificationHandler{document: &document})
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	configs, err := s3c.GetNotifications(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(len(configs), Equals, 0)
+
+	queue := client.NotificationConfig{
+		ARN:    "arn:aws:sqs:us-east-1:444455556666:photos",
+		Events: []string{client.EventCreate, client.EventRemove},
+		Suffix: ".jpg",
+	}
+	c.Assert(s3c.AddNotification(context.Background(), queue), IsNil)
+	topic := client.NotificationConfig{
+		ARN:    "arn:aws:sns:us-east-1:444455556666:photos",
+		Events: []string{client.EventAccess},
+		Prefix: "thumbnails/",
+	}
+	c.Assert(s3c.AddNotification(context.Background(), topic), IsNil)
+	err = s3c.AddNotification(context.Background(), client.NotificationConfig{ARN: "arn:aws:s3:::bucket"})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.InvalidARN)
+	c.Assert(ok, Equals, true)
+
+	configs, err = s3c.GetNotifications(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(configs, DeepEquals, []client.NotificationConfig{queue, topic})
+
+	c.Assert(s3c.RemoveNotification(context.Background(), queue.ARN), IsNil)
+	configs, err = s3c.GetNotifications(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(configs, DeepEquals, []client.NotificationConfig{topic})
+
+	// Watch events, only the first one is not below a sub-folder.
+	conf.HostURL = server.URL + "/bucket/photos/"
+	s3c, err = New(conf)
+	c.Assert(err, IsNil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eventCh, err := s3c.Watch(ctx, []string{client.EventCreate, client.EventRemove}, false)
+	c.Assert(err, IsNil)
+	var events []*client.Event
+	for event := range eventCh {
+		c.Assert(event.Err, IsNil)
+		events = append(events, event)
+	}
+	c.Assert(len(events), Equals, 1)
+	c.Assert(events[0].Type, Equals, client.EventCreate)
+	c.Assert(events[0].URL.Path, Equals, "/bucket/photos/a b.jpg")
+	c.Assert(events[0].Size, Equals, int64(42))
+}
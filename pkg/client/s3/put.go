@@ -0,0 +1,299 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// Limits of S3 uploads.
+const (
+	maxSinglePutSize = 5 * 1024 * 1024 * 1024
+	minPartSize      = 5 * 1024 * 1024
+	maxParts         = 10000
+)
+
+// initiateMultipartUploadResult - reply of a request initiating a multipart upload.
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+// completePart - a single uploaded part of a multipart upload.
+type completePart struct {
+	PartNumber int
+	ETag       string
+}
+
+// completeMultipartUpload - body of a request completing a multipart upload.
+type completeMultipartUpload struct {
+	XMLName xml.Name       `xml:"CompleteMultipartUpload"`
+	Parts   []completePart `xml:"Part"`
+}
+
+// putObjectWithMetadata - upload an object with user metadata, which the
+// vendored minio-go does not send. Objects larger than a single PUT allows,
+// or of unknown size, are streamed as a multipart upload. Data is verified
+// against the MD5 checksum in metadata if any, which is stored along.
+func (c *s3Client) putObjectWithMetadata(ctx context.Context, bucket, object string, data io.Reader, size int64, metadata map[string]string) *probe.Error {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/octet-stream")
+	var expectedMD5 string
+	for key, value := range metadata {
+		if key == client.MetadataMD5 {
+			expectedMD5 = value
+		}
+		if key == client.MetadataTags {
+			header.Set("X-Amz-Tagging", value)
+			continue
+		}
+		header.Set(metadataPrefix+key, value)
+	}
+	if size < 0 || size > maxSinglePutSize {
+		return c.putMultipartWithMetadata(ctx, bucket, object, data, size, header, expectedMD5)
+	}
+	if _, stored, err := c.putVerified(ctx, bucket, object, nil, header, data, size, expectedMD5); err != nil {
+		if stored != nil {
+			// Streamed uploads are only verified once stored, do not leave corrupted data behind.
+			resp, removeErr := c.executeMethod(ctx, "DELETE", requestMetadata{
+				bucketName:  bucket,
+				objectName:  object,
+				queryValues: stored,
+			})
+			if removeErr == nil {
+				closeResponse(resp)
+			}
+		}
+		return err.Trace(bucket, object)
+	}
+	return nil
+}
+
+// putVerified - upload size bytes of data with a PUT request and return the
+// ETag of the reply. Bodies of up to a part are buffered and sent with their
+// Content-MD5, larger ones are hashed while streamed and verified against the
+// ETag. Data is also verified against expectedMD5 if set, buffered bodies
+// before they are sent. Data failing verification once stored is reported
+// along with the query values addressing the stored version, nil otherwise.
+func (c *s3Client) putVerified(ctx context.Context, bucket, object string, queryValues url.Values, header http.Header, data io.Reader, size int64, expectedMD5 string) (string, url.Values, *probe.Error) {
+	resource := "/" + bucket + "/" + object
+	hasher := md5.New()
+	var metadata requestMetadata
+	if size >= 0 && size <= minPartSize {
+		buffer := make([]byte, size)
+		if _, e := io.ReadFull(data, buffer); e != nil {
+			return "", nil, probe.NewError(e)
+		}
+		hasher.Write(buffer)
+		if sum := hex.EncodeToString(hasher.Sum(nil)); expectedMD5 != "" && sum != expectedMD5 {
+			return "", nil, probe.NewError(client.ChecksumMismatch{Path: resource, Expected: expectedMD5, Computed: sum})
+		}
+		metadata = newBytesRequestMetadata(bucket, object, queryValues, buffer)
+		metadata.customHeader = header
+		// An empty body is left out, it would otherwise be sent chunked.
+		if size == 0 {
+			metadata.contentBody = nil
+		}
+	} else {
+		metadata = newPutRequestMetadata(bucket, object, queryValues, header, io.TeeReader(data, hasher), size)
+	}
+	resp, err := c.executeMethod(ctx, "PUT", metadata)
+	if err != nil {
+		return "", nil, err.Trace(bucket, object)
+	}
+	closeResponse(resp)
+	etag := resp.Header.Get("ETag")
+	// A plain DELETE of a versioned object would only hide it behind a delete marker.
+	stored := url.Values{}
+	if versionID := resp.Header.Get("X-Amz-Version-Id"); versionID != "" && versionID != "null" {
+		stored.Set("versionId", versionID)
+	}
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if expectedMD5 != "" && sum != expectedMD5 {
+		return "", stored, probe.NewError(client.ChecksumMismatch{Path: resource, Expected: expectedMD5, Computed: sum})
+	}
+	if trimmed := strings.Trim(etag, "\""); isMD5ETag(resp.Header) && trimmed != sum {
+		return "", stored, probe.NewError(client.ChecksumMismatch{Path: resource, Expected: sum, Computed: trimmed})
+	}
+	return etag, nil, nil
+}
+
+// isMD5ETag - the ETag of a reply is the MD5 checksum of the data. ETags of
+// multipart uploads and of objects encrypted with SSE-KMS or SSE-C are not,
+// though the latter look alike.
+func isMD5ETag(header http.Header) bool {
+	if strings.HasPrefix(header.Get("X-Amz-Server-Side-Encryption"), "aws:kms") {
+		return false
+	}
+	if header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "" {
+		return false
+	}
+	return client.IsMD5(strings.Trim(header.Get("ETag"), "\""))
+}
+
+// newPutRequestMetadata - request metadata of an upload of size bytes read from data.
+func newPutRequestMetadata(bucket, object string, queryValues url.Values, header http.Header, data io.Reader, size int64) requestMetadata {
+	metadata := requestMetadata{
+		bucketName:    bucket,
+		objectName:    object,
+		queryValues:   queryValues,
+		customHeader:  header,
+		contentLength: size,
+	}
+	// An empty body is left out, it would otherwise be sent chunked.
+	if size > 0 {
+		metadata.contentBody = io.LimitReader(data, size)
+	}
+	return metadata
+}
+
+// putMultipartWithMetadata - stream an object part by part, the upload is
+// aborted if any part fails or the object does not match expectedMD5.
+func (c *s3Client) putMultipartWithMetadata(ctx context.Context, bucket, object string, data io.Reader, size int64, header http.Header, expectedMD5 string) *probe.Error {
+	resp, err := c.executeMethod(ctx, "POST", requestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  url.Values{"uploads": {""}},
+		customHeader: header,
+	})
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	initiate := initiateMultipartUploadResult{}
+	if err = decodeXMLResponse(resp, &initiate); err != nil {
+		return err.Trace(bucket, object)
+	}
+	uploadID := initiate.UploadID
+
+	hasher := md5.New()
+	complete, err := c.putParts(ctx, bucket, object, uploadID, io.TeeReader(data, hasher), size)
+	if sum := hex.EncodeToString(hasher.Sum(nil)); err == nil && expectedMD5 != "" && sum != expectedMD5 {
+		err = probe.NewError(client.ChecksumMismatch{Path: "/" + bucket + "/" + object, Expected: expectedMD5, Computed: sum})
+	}
+	if err == nil {
+		err = c.completeMultipart(ctx, bucket, object, uploadID, complete)
+	}
+	if err != nil {
+		// Aborted even when cancelled, so that no parts are left behind.
+		resp, abortErr := c.executeMethod(context.Background(), "DELETE", requestMetadata{
+			bucketName:  bucket,
+			objectName:  object,
+			queryValues: url.Values{"uploadId": {uploadID}},
+		})
+		if abortErr == nil {
+			closeResponse(resp)
+		}
+		return err.Trace(bucket, object, uploadID)
+	}
+	return nil
+}
+
+// putParts - upload all parts of a multipart upload. Parts of an object of
+// known size are streamed, otherwise each part is buffered to learn its length.
+func (c *s3Client) putParts(ctx context.Context, bucket, object, uploadID string, data io.Reader, size int64) (completeMultipartUpload, *probe.Error) {
+	complete := completeMultipartUpload{}
+	partSize := int64(minPartSize)
+	if size > minPartSize*maxParts {
+		partSize = (size + maxParts - 1) / maxParts
+	}
+	var buffer []byte
+	var uploaded int64
+	for partNumber := 1; partNumber <= maxParts; partNumber++ {
+		partData, partLength := data, partSize
+		isLast := false
+		if size >= 0 {
+			if remaining := size - uploaded; remaining <= partSize {
+				partLength, isLast = remaining, true
+			}
+		} else {
+			if buffer == nil {
+				buffer = make([]byte, partSize)
+			}
+			n, e := io.ReadFull(data, buffer)
+			if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+				return complete, probe.NewError(e)
+			}
+			partData, partLength, isLast = bytes.NewReader(buffer[:n]), int64(n), e != nil
+		}
+		queryValues := url.Values{
+			"partNumber": {strconv.Itoa(partNumber)},
+			"uploadId":   {uploadID},
+		}
+		etag, _, err := c.putVerified(ctx, bucket, object, queryValues, nil, partData, partLength, "")
+		if err != nil {
+			return complete, err.Trace(bucket, object, strconv.Itoa(partNumber))
+		}
+		complete.Parts = append(complete.Parts, completePart{
+			PartNumber: partNumber,
+			ETag:       etag,
+		})
+		uploaded += partLength
+		if isLast {
+			return complete, nil
+		}
+	}
+	return complete, probe.NewError(minio.ErrorResponse{
+		Code:     "EntityTooLarge",
+		Message:  "Object is larger than " + strconv.Itoa(maxParts) + " parts.",
+		Resource: "/" + bucket + "/" + object,
+	})
+}
+
+// completeMultipart - complete a multipart upload. Errors may be reported
+// in the body of a successful reply.
+func (c *s3Client) completeMultipart(ctx context.Context, bucket, object, uploadID string, complete completeMultipartUpload) *probe.Error {
+	metadata, err := newXMLRequestMetadata(bucket, object, url.Values{"uploadId": {uploadID}}, complete)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	resp, err := c.executeMethod(ctx, "POST", metadata)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	_, err = readReply(resp)
+	return err.Trace(bucket, object)
+}
+
+// readReply - read the body of a successful reply, which may still carry an
+// error for requests processed after the reply was started.
+func readReply(resp *http.Response) ([]byte, *probe.Error) {
+	defer closeResponse(resp)
+	body, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if strings.Contains(string(body), "<Error>") {
+		errResp := minio.ErrorResponse{}
+		if e = xml.Unmarshal(body, &errResp); e != nil {
+			return nil, probe.NewError(e)
+		}
+		return nil, probe.NewError(errResp)
+	}
+	return body, nil
+}
@@ -0,0 +1,204 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+// metadataHandler is an http.Handler storing a single object along with
+// its user metadata, uploaded with a PUT or a multipart upload.
+type metadataHandler struct {
+	mu       sync.Mutex
+	data     []byte
+	header   http.Header
+	parts    [][]byte
+	uploadID string
+	removed  int
+}
+
+func (h *metadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	query := r.URL.Query()
+	switch {
+	case r.Method == "POST" && len(query["uploads"]) == 1:
+		h.header, h.parts, h.uploadID = r.Header, nil, "upload-1"
+		w.Write([]byte("<InitiateMultipartUploadResult><UploadId>" + h.uploadID + "</UploadId></InitiateMultipartUploadResult>"))
+	case r.Method == "PUT" && query.Get("uploadId") != "":
+		if query.Get("uploadId") != h.uploadID || query.Get("partNumber") != strconv.Itoa(len(h.parts)+1) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		h.parts = append(h.parts, body)
+		w.Header().Set("ETag", "\"part\"")
+	case r.Method == "POST" && query.Get("uploadId") != "":
+		h.data = bytes.Join(h.parts, nil)
+		w.Write([]byte("<CompleteMultipartUploadResult><ETag>\"object\"</ETag></CompleteMultipartUploadResult>"))
+	case r.Method == "PUT":
+		if r.ContentLength < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.data, _ = ioutil.ReadAll(r.Body)
+		h.header = r.Header
+	case r.Method == "HEAD":
+		for key, values := range h.header {
+			if strings.HasPrefix(key, "X-Amz-Meta-") {
+				w.Header()[key] = values
+			}
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(h.data)))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	case r.Method == "DELETE":
+		h.data, h.header = nil, nil
+		h.removed++
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (s *MySuite) TestPutWithMetadata(c *C) {
+	handler := &metadataHandler{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	data := []byte("Hello, World")
+	metadata := map[string]string{client.MetadataMode: "640", client.MetadataUID: "1000"}
+	err = s3c.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), metadata)
+	c.Assert(err, IsNil)
+	c.Assert(handler.data, DeepEquals, data)
+
+	content, err := s3c.Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(len(data)))
+	c.Assert(content.Metadata, DeepEquals, metadata)
+
+	// Uploads of unknown size are multipart.
+	metadata[client.MetadataMode] = "600"
+	err = s3c.PutWithMetadata(context.Background(), bytes.NewReader(data), -1, metadata)
+	c.Assert(err, IsNil)
+	c.Assert(handler.parts, HasLen, 1)
+	c.Assert(handler.data, DeepEquals, data)
+
+	content, err = s3c.Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Metadata, DeepEquals, metadata)
+
+	// Tags are set along with the upload, not stored as metadata.
+	tagged := map[string]string{client.MetadataTags: "project=mc&cost=42"}
+	err = s3c.PutWithMetadata(context.Background(), bytes.NewReader(data), -1, tagged)
+	c.Assert(err, IsNil)
+	c.Assert(handler.header.Get("X-Amz-Tagging"), Equals, "project=mc&cost=42")
+	c.Assert(handler.header.Get("X-Amz-Meta-Mc-Tags"), Equals, "")
+}
+
+func (s *MySuite) TestPutVerified(c *C) {
+	handler := &metadataHandler{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	data := []byte("Hello, World")
+	sum := md5.Sum(data)
+	metadata := map[string]string{client.MetadataMD5: hex.EncodeToString(sum[:])}
+	err = s3c.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), metadata)
+	c.Assert(err, IsNil)
+	c.Assert(handler.data, DeepEquals, data)
+	// Checksums are sent and stored.
+	c.Assert(handler.header.Get("Content-Md5"), Equals, base64.StdEncoding.EncodeToString(sum[:]))
+	c.Assert(handler.header.Get("X-Amz-Meta-Mc-Md5"), Equals, hex.EncodeToString(sum[:]))
+
+	// Objects uploaded in parts keep the checksum of the whole object.
+	err = s3c.PutWithMetadata(context.Background(), bytes.NewReader(data), -1, metadata)
+	c.Assert(err, IsNil)
+	c.Assert(handler.parts, HasLen, 1)
+	content, err := s3c.Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Metadata[client.MetadataMD5], Equals, hex.EncodeToString(sum[:]))
+
+	// Data not matching its source is never sent.
+	handler.data = nil
+	metadata[client.MetadataMD5] = hex.EncodeToString(make([]byte, md5.Size))
+	err = s3c.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), metadata)
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.ChecksumMismatch)
+	c.Assert(ok, Equals, true)
+	c.Assert(handler.data, IsNil)
+	// Nor is an object of that name removed.
+	c.Assert(handler.removed, Equals, 0)
+}
+
+func (s *MySuite) TestIsMD5ETag(c *C) {
+	header := http.Header{"Etag": {"\"5eb63bbbe01eeed093cb22bb8f5acdc3\""}}
+	c.Assert(isMD5ETag(header), Equals, true)
+	header.Set("X-Amz-Server-Side-Encryption", "AES256")
+	c.Assert(isMD5ETag(header), Equals, true)
+
+	// ETags of SSE-KMS and SSE-C objects look alike but are not checksums.
+	header.Set("X-Amz-Server-Side-Encryption", "aws:kms")
+	c.Assert(isMD5ETag(header), Equals, false)
+	header.Del("X-Amz-Server-Side-Encryption")
+	header.Set("X-Amz-Server-Side-Encryption-Customer-Algorithm", "AES256")
+	c.Assert(isMD5ETag(header), Equals, false)
+
+	c.Assert(isMD5ETag(http.Header{"Etag": {"\"5eb63bbbe01eeed093cb22bb8f5acdc3-2\""}}), Equals, false)
+}
+
+func (s *MySuite) TestPutBadDigest(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("<Error><Code>BadDigest</Code><Message>The Content-MD5 you specified did not match what we received.</Message></Error>"))
+	}))
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	data := []byte("Hello, World")
+	err = s3c.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), map[string]string{client.MetadataMode: "640"})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.ChecksumMismatch)
+	c.Assert(ok, Equals, true)
+}
@@ -0,0 +1,144 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// objectReader is an io.ReadSeeker over a ranged GET request. The
+// request is sent lazily upon first Read() and re-sent after every Seek().
+type objectReader struct {
+	mutex *sync.Mutex
+
+	ctx    context.Context
+	c      *s3Client
+	bucket string
+	object string
+	query  url.Values
+	header http.Header
+	offset int64
+	length int64
+	start  int64
+	body   io.ReadCloser
+}
+
+// newObjectReader - reader for [offset, offset+length) of an object, length '0' reads till the end.
+func newObjectReader(ctx context.Context, c *s3Client, bucket, object string, query url.Values, offset, length int64) *objectReader {
+	return &objectReader{
+		mutex:  new(sync.Mutex),
+		ctx:    ctx,
+		c:      c,
+		bucket: bucket,
+		object: object,
+		query:  query,
+		offset: offset,
+		start:  offset,
+		length: length,
+	}
+}
+
+// open - send the ranged GET request starting at the current offset.
+func (r *objectReader) open() error {
+	header := make(http.Header)
+	for k, v := range r.header {
+		header[k] = v
+	}
+	switch {
+	case r.length > 0:
+		end := r.start + r.length - 1
+		if r.offset > end {
+			return io.EOF
+		}
+		header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-"+strconv.FormatInt(end, 10))
+	case r.offset > 0:
+		header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-")
+	}
+	resp, err := r.c.executeMethod(r.ctx, "GET", requestMetadata{
+		bucketName:   r.bucket,
+		objectName:   r.object,
+		queryValues:  r.query,
+		customHeader: header,
+	})
+	if err != nil {
+		return err.ToGoError()
+	}
+	r.body = resp.Body
+	return nil
+}
+
+// Read reads up to len(p) bytes into p.
+func (r *objectReader) Read(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.body == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if err != nil {
+		r.body.Close()
+		r.body = nil
+		if err != io.EOF {
+			return n, err
+		}
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek sets the offset for the next Read, only whence '0' and '1' are supported.
+func (r *objectReader) Seek(offset int64, whence int) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	switch whence {
+	case 0:
+		offset = r.start + offset
+	case 1:
+		offset = r.offset + offset
+	default:
+		return 0, errors.New("objectReader: seeking relative to the end is not supported")
+	}
+	if offset < r.start {
+		return 0, errors.New("objectReader: negative position")
+	}
+	if offset != r.offset && r.body != nil {
+		closeResponse(&http.Response{Body: r.body})
+		r.body = nil
+	}
+	r.offset = offset
+	return offset - r.start, nil
+}
+
+// Close - release the underlying connection if any.
+func (r *objectReader) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.body != nil {
+		closeResponse(&http.Response{Body: r.body})
+		r.body = nil
+	}
+	return nil
+}
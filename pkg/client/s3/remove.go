@@ -0,0 +1,152 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"net/url"
+	"strings"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// maxDeleteKeys - maximum number of keys of a single Multi-Object Delete request.
+const maxDeleteKeys = 1000
+
+// deleteObject - a single <Object> of a Multi-Object Delete request.
+type deleteObject struct {
+	Key string `xml:"Key"`
+}
+
+// deleteRequest - Multi-Object Delete request, in quiet mode only failures are replied.
+type deleteRequest struct {
+	XMLName xml.Name       `xml:"Delete"`
+	Xmlns   string         `xml:"xmlns,attr,omitempty"`
+	Quiet   bool           `xml:"Quiet"`
+	Objects []deleteObject `xml:"Object"`
+}
+
+// deleteError - a single <Error> of a Multi-Object Delete reply.
+type deleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+// deleteResult - Multi-Object Delete reply.
+type deleteResult struct {
+	XMLName xml.Name      `xml:"DeleteResult"`
+	Errors  []deleteError `xml:"Error"`
+}
+
+// RemoveBatch - remove all objects received on contentCh with Multi-Object
+// Delete requests, each object is sent back with Err set if it could not be
+// removed. Batches are sent while the next one is being filled.
+func (c *s3Client) RemoveBatch(ctx context.Context, contentCh <-chan *client.Content) <-chan *client.Content {
+	resultCh := make(chan *client.Content)
+	batchCh := make(chan []*client.Content, 1)
+	go func() {
+		defer close(batchCh)
+		batch := make([]*client.Content, 0, maxDeleteKeys)
+		for content := range contentCh {
+			batch = append(batch, content)
+			if len(batch) == maxDeleteKeys {
+				batchCh <- batch
+				batch = make([]*client.Content, 0, maxDeleteKeys)
+			}
+		}
+		if len(batch) > 0 {
+			batchCh <- batch
+		}
+	}()
+	go func() {
+		defer close(resultCh)
+		for batch := range batchCh {
+			for _, content := range c.removeBatch(ctx, batch) {
+				resultCh <- content
+			}
+		}
+	}()
+	return client.ForwardContents(ctx, resultCh)
+}
+
+// removeBatch - remove a batch of objects, setting Err of those which failed.
+func (c *s3Client) removeBatch(ctx context.Context, batch []*client.Content) []*client.Content {
+	bucket, _ := c.url2BucketAndObject()
+	keys := make(map[string]*client.Content)
+	for _, content := range batch {
+		keys[c.objectKey(content.URL)] = content
+	}
+	deleteErrors, err := c.deleteObjects(ctx, bucket, keys)
+	if err != nil {
+		// The whole request failed, so did every key.
+		for _, content := range batch {
+			content.Err = err
+		}
+		return batch
+	}
+	for _, deleteErr := range deleteErrors {
+		content, ok := keys[deleteErr.Key]
+		if !ok {
+			continue
+		}
+		errResponse := minio.ErrorResponse{
+			Code:     deleteErr.Code,
+			Message:  deleteErr.Message,
+			Resource: "/" + bucket + "/" + deleteErr.Key,
+		}
+		content.Err = c.toLockError(probe.NewError(errResponse)).Trace(bucket, deleteErr.Key)
+	}
+	return batch
+}
+
+// deleteObjects - send a single Multi-Object Delete request, returns per-key failures.
+func (c *s3Client) deleteObjects(ctx context.Context, bucket string, keys map[string]*client.Content) ([]deleteError, *probe.Error) {
+	request := deleteRequest{Xmlns: s3Namespace, Quiet: true}
+	for key := range keys {
+		request.Objects = append(request.Objects, deleteObject{Key: key})
+	}
+	metadata, err := newXMLRequestMetadata(bucket, "", url.Values{"delete": {""}}, request)
+	if err != nil {
+		return nil, err.Trace(bucket)
+	}
+	resp, err := c.executeMethod(ctx, "POST", metadata)
+	if err != nil {
+		return nil, err.Trace(bucket)
+	}
+	result := deleteResult{}
+	if err = decodeXMLResponse(resp, &result); err != nil {
+		return nil, err.Trace(bucket)
+	}
+	return result.Errors, nil
+}
+
+// objectKey - object name of a URL on the same bucket as this client.
+func (c *s3Client) objectKey(u client.URL) string {
+	path := strings.TrimPrefix(u.Path, string(u.Separator))
+	if c.virtualStyle {
+		return path
+	}
+	// Strip the bucket name.
+	if i := strings.Index(path, string(u.Separator)); i >= 0 {
+		return path[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+// deleteHandler is an http.Handler serving Multi-Object Delete requests,
+// objects named ‘locked’ cannot be removed.
+type deleteHandler struct {
+	mutex    *sync.Mutex
+	requests *int
+	deleted  map[string]bool
+}
+
+func (h deleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["delete"]; !ok || r.Method != "POST" || r.Header.Get("Content-MD5") == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	request := deleteRequest{}
+	if e := xml.NewDecoder(r.Body).Decode(&request); e != nil || !request.Quiet || len(request.Objects) > maxDeleteKeys {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	*h.requests++
+	result := deleteResult{}
+	for _, object := range request.Objects {
+		if object.Key == "locked" {
+			result.Errors = append(result.Errors, deleteError{Key: object.Key, Code: "ObjectLocked", Message: "Object is WORM protected and cannot be overwritten"})
+			continue
+		}
+		h.deleted[object.Key] = true
+	}
+	xml.NewEncoder(w).Encode(result)
+}
+
+func (s *MySuite) TestRemoveBatch(c *C) {
+	handler := deleteHandler{mutex: new(sync.Mutex), requests: new(int), deleted: make(map[string]bool)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket/"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	count := 2500
+	contentCh := make(chan *client.Content)
+	go func() {
+		defer close(contentCh)
+		for i := 0; i < count; i++ {
+			contentCh <- &client.Content{URL: *client.NewURL(server.URL + "/bucket/dir/object" + strconv.Itoa(i))}
+		}
+		contentCh <- &client.Content{URL: *client.NewURL(server.URL + "/bucket/locked")}
+	}()
+	var failed []*client.Content
+	removed := 0
+	for content := range s3c.RemoveBatch(context.Background(), contentCh) {
+		if content.Err != nil {
+			failed = append(failed, content)
+			continue
+		}
+		removed++
+	}
+	c.Assert(removed, Equals, count)
+	c.Assert(len(handler.deleted), Equals, count)
+	c.Assert(handler.deleted["dir/object42"], Equals, true)
+	c.Assert(*handler.requests, Equals, 3)
+	c.Assert(len(failed), Equals, 1)
+	c.Assert(failed[0].URL.Path, Equals, "/bucket/locked")
+	_, ok := failed[0].Err.ToGoError().(client.ObjectLocked)
+	c.Assert(ok, Equals, true)
+}
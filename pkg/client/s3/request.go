@@ -0,0 +1,322 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// The vendored minio-go library only covers the basic object and bucket
+// operations. Sub-resource APIs such as ?versioning, ?tagging and friends
+// are sent through the small request layer below, which reuses the
+// transport and credentials of the s3Client.
+
+// s3Namespace - XML namespace of S3 request documents.
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// requestMetadata - everything needed to build a single S3 request.
+type requestMetadata struct {
+	bucketName   string
+	objectName   string
+	queryValues  url.Values
+	customHeader http.Header
+
+	// Request body, its length and optional checksums.
+	contentBody        io.Reader
+	contentLength      int64
+	contentMD5Bytes    []byte
+	contentSHA256Bytes []byte
+}
+
+// newXMLRequestMetadata - request metadata carrying a marshalled XML document as body.
+func newXMLRequestMetadata(bucket, object string, queryValues url.Values, v interface{}) (requestMetadata, *probe.Error) {
+	xmlBytes, e := xml.Marshal(v)
+	if e != nil {
+		return requestMetadata{}, probe.NewError(e)
+	}
+	return newBytesRequestMetadata(bucket, object, queryValues, xmlBytes), nil
+}
+
+// newBytesRequestMetadata - request metadata carrying an in-memory body.
+func newBytesRequestMetadata(bucket, object string, queryValues url.Values, body []byte) requestMetadata {
+	md5Sum := md5.Sum(body)
+	sha256Sum := sha256.Sum256(body)
+	return requestMetadata{
+		bucketName:         bucket,
+		objectName:         object,
+		queryValues:        queryValues,
+		contentBody:        bytes.NewReader(body),
+		contentLength:      int64(len(body)),
+		contentMD5Bytes:    md5Sum[:],
+		contentSHA256Bytes: sha256Sum[:],
+	}
+}
+
+// isAnonymous - true if no credentials are configured for this host.
+func (c *s3Client) isAnonymous() bool {
+	return c.config.AccessKeyID == "" || c.config.SecretAccessKey == ""
+}
+
+// isSignatureV2 - true if the host is configured for AWS signature version '2'.
+func (c *s3Client) isSignatureV2() bool {
+	return c.config.Signature == "S3v2" || c.getRegion() == "google"
+}
+
+// getRegion - region used for signing requests, looked up only once.
+func (c *s3Client) getRegion() string {
+	c.regionOnce.Do(func() {
+		host := c.hostURL.Host
+		if !c.virtualStyle {
+			c.region = getRegion(host)
+			return
+		}
+		// Virtual style requests carry the bucket name in the host.
+		bucket, _ := c.url2BucketAndObject()
+		host = strings.TrimPrefix(host, bucket+".")
+		c.region = getRegion(host)
+		if host != "s3.amazonaws.com" {
+			return
+		}
+		// Generic Amazon S3 endpoint, ask for the actual bucket location.
+		if region, err := c.getBucketLocation(bucket); err == nil && region != "" {
+			c.region = region
+		}
+	})
+	return c.region
+}
+
+// getBucketLocation - fetch the location constraint of a bucket.
+func (c *s3Client) getBucketLocation(bucket string) (string, *probe.Error) {
+	// Looked up once for all requests, whichever asks first.
+	req, err := c.newRequest(context.Background(), "GET", requestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"location": {""}},
+	})
+	if err != nil {
+		return "", err.Trace(bucket)
+	}
+	// Location queries are always answered by the 'us-east-1' region.
+	if !c.isAnonymous() {
+		signV4(req, c.config.AccessKeyID, c.config.SecretAccessKey, "us-east-1")
+	}
+	resp, e := c.transport.RoundTrip(req)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	defer closeResponse(resp)
+	if resp.StatusCode != http.StatusOK {
+		return "", probe.NewError(httpRespToErrorResponse(resp, bucket, ""))
+	}
+	var location string
+	if e := xml.NewDecoder(resp.Body).Decode(&location); e != nil {
+		return "", probe.NewError(e)
+	}
+	if location == "EU" {
+		location = "eu-west-1"
+	}
+	return location, nil
+}
+
+// getRequestURL - properly encoded URL for a bucket and object.
+func (c *s3Client) getRequestURL(bucket, object string, queryValues url.Values) (*url.URL, *probe.Error) {
+	u, e := url.Parse(c.hostURL.Scheme + c.hostURL.SchemeSeparator + c.hostURL.Host)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	path := "/"
+	if !c.virtualStyle && bucket != "" {
+		path = path + bucket
+		if object != "" {
+			path = path + "/"
+		}
+	}
+	path = path + object
+	u.Path = path
+	u.RawPath = encodePath(path)
+	if len(queryValues) > 0 {
+		u.RawQuery = strings.Replace(queryValues.Encode(), "+", "%20", -1)
+	}
+	return u, nil
+}
+
+// newRequest - instantiate a new signed HTTP request.
+func (c *s3Client) newRequest(ctx context.Context, method string, metadata requestMetadata) (*http.Request, *probe.Error) {
+	u, err := c.getRequestURL(metadata.bucketName, metadata.objectName, metadata.queryValues)
+	if err != nil {
+		return nil, err.Trace(metadata.bucketName, metadata.objectName)
+	}
+	req, e := http.NewRequest(method, u.String(), nil)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	req = req.WithContext(ctx)
+	req.URL = u
+	req.Header.Set("User-Agent", c.userAgent)
+	for k, v := range metadata.customHeader {
+		req.Header[k] = v
+	}
+	if metadata.contentBody != nil {
+		req.Body = ioutil.NopCloser(metadata.contentBody)
+		req.ContentLength = metadata.contentLength
+	}
+	if metadata.contentMD5Bytes != nil {
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(metadata.contentMD5Bytes))
+	}
+	if c.isAnonymous() {
+		return req, nil
+	}
+	if c.isSignatureV2() {
+		signV2(req, c.config.AccessKeyID, c.config.SecretAccessKey, c.virtualStyle)
+		return req, nil
+	}
+	switch {
+	case metadata.contentSHA256Bytes != nil:
+		req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(metadata.contentSHA256Bytes))
+	case metadata.contentBody != nil:
+		req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	default:
+		req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+	}
+	signV4(req, c.config.AccessKeyID, c.config.SecretAccessKey, c.getRegion())
+	return req, nil
+}
+
+// executeMethod - send a request and translate non 2xx replies into minio.ErrorResponse.
+func (c *s3Client) executeMethod(ctx context.Context, method string, metadata requestMetadata) (*http.Response, *probe.Error) {
+	req, err := c.newRequest(ctx, method, metadata)
+	if err != nil {
+		return nil, err.Trace(method)
+	}
+	// RoundTrip is used directly instead of http.Client{}, to avoid
+	// following redirects automatically, just like minio-go does.
+	resp, e := c.transport.RoundTrip(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer closeResponse(resp)
+		return nil, probe.NewError(httpRespToErrorResponse(resp, metadata.bucketName, metadata.objectName))
+	}
+	return resp, nil
+}
+
+// closeResponse - drain and close the body, so that the connection can be re-used.
+func closeResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// httpRespToErrorResponse - S3 error replies carry an XML body, except for HEAD requests.
+func httpRespToErrorResponse(resp *http.Response, bucket, object string) error {
+	errResp := minio.ErrorResponse{}
+	if e := xml.NewDecoder(resp.Body).Decode(&errResp); e != nil || errResp.Code == "" {
+		errResp = minio.ErrorResponse{Message: resp.Status}
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			errResp.Code = "NoSuchKey"
+			if object == "" {
+				errResp.Code = "NoSuchBucket"
+			}
+		case http.StatusForbidden:
+			errResp.Code = "AccessDenied"
+		case http.StatusMethodNotAllowed:
+			errResp.Code = "MethodNotAllowed"
+		case http.StatusNotImplemented:
+			errResp.Code = "NotImplemented"
+		default:
+			errResp.Code = resp.Status
+		}
+	}
+	errResp.Resource = "/" + bucket
+	if object != "" {
+		errResp.Resource = errResp.Resource + "/" + object
+	}
+	errResp.RequestID = resp.Header.Get("x-amz-request-id")
+	errResp.HostID = resp.Header.Get("x-amz-id-2")
+	errResp.AmzBucketRegion = resp.Header.Get("x-amz-bucket-region")
+	return errResp
+}
+
+// decodeXMLResponse - decode the XML body of a successful reply into v.
+func decodeXMLResponse(resp *http.Response, v interface{}) *probe.Error {
+	defer closeResponse(resp)
+	if e := xml.NewDecoder(resp.Body).Decode(v); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// encodePath - encode an object path, leaving only the unreserved characters as is.
+func encodePath(path string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(path); i++ {
+		ch := path[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9':
+			buf.WriteByte(ch)
+		case ch == '-', ch == '_', ch == '.', ch == '~', ch == '/':
+			buf.WriteByte(ch)
+		default:
+			buf.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{ch})))
+		}
+	}
+	return buf.String()
+}
+
+// s3 region map used for signing requests.
+var regions = map[string]string{
+	"s3-fips-us-gov-west-1.amazonaws.com": "us-gov-west-1",
+	"s3.amazonaws.com":                    "us-east-1",
+	"s3-external-1.amazonaws.com":         "us-east-1",
+	"s3-us-west-1.amazonaws.com":          "us-west-1",
+	"s3-us-west-2.amazonaws.com":          "us-west-2",
+	"s3-eu-west-1.amazonaws.com":          "eu-west-1",
+	"s3-eu-central-1.amazonaws.com":       "eu-central-1",
+	"s3-ap-southeast-1.amazonaws.com":     "ap-southeast-1",
+	"s3-ap-southeast-2.amazonaws.com":     "ap-southeast-2",
+	"s3-ap-northeast-1.amazonaws.com":     "ap-northeast-1",
+	"s3-sa-east-1.amazonaws.com":          "sa-east-1",
+	"s3.cn-north-1.amazonaws.com.cn":      "cn-north-1",
+
+	// Add google cloud storage as one of the regions
+	"storage.googleapis.com": "google",
+}
+
+// getRegion returns a region based on its endpoint mapping.
+func getRegion(host string) string {
+	if region, ok := regions[host]; ok {
+		return region
+	}
+	// Region cannot be empty according to Amazon S3 for AWS Signature Version 4.
+	return "us-east-1"
+}
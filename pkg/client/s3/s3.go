@@ -17,12 +17,16 @@
 package s3
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -36,9 +40,16 @@ import (
 // S3 client
 type s3Client struct {
 	mu           *sync.Mutex
-	api          minio.CloudStorageAPI
+	apiConfig    minio.Config
 	hostURL      *client.URL
 	virtualStyle bool
+
+	// Used by requests which are not covered by minio-go.
+	config     *client.Config
+	transport  http.RoundTripper
+	userAgent  string
+	region     string
+	regionOnce sync.Once
 }
 
 // New returns an initialized s3Client structure. if debug use a internal trace transport.
@@ -66,28 +77,59 @@ func New(config *client.Config) (client.Client, *probe.Error) {
 		}(),
 	}
 	s3Conf.SetUserAgent(config.AppName, config.AppVersion, config.AppComments...)
-	api, err := minio.New(s3Conf)
-	if err != nil {
-		return nil, probe.NewError(err)
+	if _, e := url.Parse(s3Conf.Endpoint); e != nil {
+		return nil, probe.NewError(e)
 	}
 	s3Clnt := &s3Client{
 		mu:           new(sync.Mutex),
-		api:          api,
+		apiConfig:    s3Conf,
 		hostURL:      u,
 		virtualStyle: isVirtualHostStyle(u.Host),
+		config:       config,
+		transport:    transport,
+		userAgent:    "Minio (" + runtime.GOOS + "; " + runtime.GOARCH + ") " + config.AppName + "/" + config.AppVersion,
 	}
 	return s3Clnt, nil
 }
 
+// contextTransport - transport sending all requests with ctx, minio-go
+// requests are not given a context otherwise.
+type contextTransport struct {
+	ctx       context.Context
+	transport http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transport.RoundTrip(req.WithContext(t.ctx))
+}
+
+// api - minio-go client sending requests with ctx. The region is the one
+// used by all other requests, so that it is looked up only once. It never
+// fails as the endpoint was already parsed by New.
+func (c *s3Client) api(ctx context.Context) minio.CloudStorageAPI {
+	s3Conf := c.apiConfig
+	s3Conf.Region = c.getRegion()
+	s3Conf.Transport = contextTransport{ctx: ctx, transport: c.transport}
+	api, _ := minio.New(s3Conf)
+	return api
+}
+
 // GetURL get url.
 func (c *s3Client) GetURL() client.URL {
 	return *c.hostURL
 }
 
 // Get - get object.
-func (c *s3Client) Get(offset, length int64) (io.ReadSeeker, *probe.Error) {
+func (c *s3Client) Get(ctx context.Context, offset, length int64) (io.ReadSeeker, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
-	reader, err := c.api.GetPartialObject(bucket, object, offset, length)
+	if offset < 0 || length < 0 {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	if offset > 0 || length > 0 {
+		// Ranged GET, fetches only the requested bytes.
+		return newObjectReader(ctx, c, bucket, object, nil, offset, length), nil
+	}
+	reader, err := c.api(ctx).GetPartialObject(bucket, object, offset, length)
 	if err != nil {
 		errResponse := minio.ToErrorResponse(err)
 		if errResponse != nil {
@@ -101,25 +143,31 @@ func (c *s3Client) Get(offset, length int64) (io.ReadSeeker, *probe.Error) {
 }
 
 // Remove - remove object or bucket.
-func (c *s3Client) Remove(incomplete bool) *probe.Error {
+func (c *s3Client) Remove(ctx context.Context, incomplete bool) *probe.Error {
 	bucket, object := c.url2BucketAndObject()
 	if incomplete {
-		errCh := c.api.RemoveIncompleteUpload(bucket, object)
+		errCh := c.api(ctx).RemoveIncompleteUpload(bucket, object)
 		return probe.NewError(<-errCh)
 	}
-	var err error
 	if object == "" {
-		err = c.api.RemoveBucket(bucket)
-	} else {
-		err = c.api.RemoveObject(bucket, object)
+		return probe.NewError(c.api(ctx).RemoveBucket(bucket))
 	}
-	return probe.NewError(err)
+	// minio-go ignores all errors of DeleteObject, locked objects must be reported.
+	resp, err := c.executeMethod(ctx, "DELETE", requestMetadata{
+		bucketName: bucket,
+		objectName: object,
+	})
+	if err != nil {
+		return c.toLockError(err).Trace(bucket, object)
+	}
+	closeResponse(resp)
+	return nil
 }
 
 // ShareDownload - get a usable presigned object url to share.
-func (c *s3Client) ShareDownload(expires time.Duration) (string, *probe.Error) {
+func (c *s3Client) ShareDownload(ctx context.Context, expires time.Duration) (string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
-	presignedURL, err := c.api.PresignedGetObject(bucket, object, expires)
+	presignedURL, err := c.api(ctx).PresignedGetObject(bucket, object, expires)
 	if err != nil {
 		return "", probe.NewError(err)
 	}
@@ -127,7 +175,7 @@ func (c *s3Client) ShareDownload(expires time.Duration) (string, *probe.Error) {
 }
 
 // ShareUpload - get data for presigned post http form upload.
-func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+func (c *s3Client) ShareUpload(ctx context.Context, isRecursive bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
 	p := minio.NewPostPolicy()
 	if err := p.SetExpires(time.Now().UTC().Add(expires)); err != nil {
@@ -149,18 +197,26 @@ func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentT
 			return nil, probe.NewError(err)
 		}
 	}
-	m, err := c.api.PresignedPostPolicy(p)
+	m, err := c.api(ctx).PresignedPostPolicy(p)
 	return m, probe.NewError(err)
 }
 
 // Put - put object.
-func (c *s3Client) Put(data io.ReadSeeker, size int64) *probe.Error {
-	// md5 is purposefully ignored since AmazonS3 does not return proper md5sum
-	// for a multipart upload and there is no need to cross verify,
-	// invidual parts are properly verified fully in transit and also upon completion
-	// of the multipart request.
+func (c *s3Client) Put(ctx context.Context, data io.ReadSeeker, size int64) *probe.Error {
+	return c.PutWithMetadata(ctx, data, size, nil)
+}
+
+// PutWithMetadata - upload an object with user metadata.
+func (c *s3Client) PutWithMetadata(ctx context.Context, data io.ReadSeeker, size int64, metadata map[string]string) *probe.Error {
+	// Uploads are verified in transit by minio-go and putObjectWithMetadata,
+	// the latter also verifies them against the checksum of their source.
 	bucket, object := c.url2BucketAndObject()
-	err := c.api.PutObject(bucket, object, data, size, "application/octet-stream")
+	var err error
+	if len(metadata) == 0 {
+		err = c.api(ctx).PutObject(bucket, object, data, size, "application/octet-stream")
+	} else if perr := c.putObjectWithMetadata(ctx, bucket, object, data, size, metadata); perr != nil {
+		err = perr.ToGoError()
+	}
 	if err != nil {
 		errResponse := minio.ToErrorResponse(err)
 		if errResponse != nil {
@@ -177,15 +233,19 @@ func (c *s3Client) Put(data io.ReadSeeker, size int64) *probe.Error {
 			if errResponse.Code == "InvalidArgument" {
 				return probe.NewError(client.ObjectMissing{})
 			}
+			if errResponse.Code == "BadDigest" {
+				return probe.NewError(client.ChecksumMismatch{
+					Path: c.hostURL.String(),
+				})
+			}
 		}
 		return probe.NewError(err)
 	}
 	return nil
 }
 
-// MakeBucket - make a new bucket.
-func (c *s3Client) MakeBucket() *probe.Error {
-	bucket, object := c.url2BucketAndObject()
+// checkMakeBucket - validate bucket name of a bucket to be created.
+func checkMakeBucket(bucket, object string) *probe.Error {
 	if object != "" {
 		return probe.NewError(client.BucketNameTopLevel{})
 	}
@@ -196,8 +256,17 @@ func (c *s3Client) MakeBucket() *probe.Error {
 	if !match {
 		return probe.NewError(errors.New("Bucket name can contain alphabet, '-' and numbers, but first character should be an alphabet"))
 	}
+	return nil
+}
+
+// MakeBucket - make a new bucket.
+func (c *s3Client) MakeBucket(ctx context.Context) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if err := checkMakeBucket(bucket, object); err != nil {
+		return err.Trace(bucket, object)
+	}
 
-	err := c.api.MakeBucket(bucket, minio.BucketACL("private"))
+	err := c.api(ctx).MakeBucket(bucket, minio.BucketACL("private"))
 	if err != nil {
 		return probe.NewError(err)
 	}
@@ -205,7 +274,7 @@ func (c *s3Client) MakeBucket() *probe.Error {
 }
 
 // GetBucketAccess get acl on a bucket.
-func (c *s3Client) GetBucketAccess() (acl string, error *probe.Error) {
+func (c *s3Client) GetBucketAccess(ctx context.Context) (acl string, error *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
 	if object != "" {
 		return "", probe.NewError(client.InvalidBucketName{Bucket: filepath.Join(bucket, object)})
@@ -213,7 +282,7 @@ func (c *s3Client) GetBucketAccess() (acl string, error *probe.Error) {
 	if bucket == "" {
 		return "", probe.NewError(client.BucketNameEmpty{})
 	}
-	bucketACL, err := c.api.GetBucketACL(bucket)
+	bucketACL, err := c.api(ctx).GetBucketACL(bucket)
 	if err != nil {
 		return "", probe.NewError(err)
 	}
@@ -221,7 +290,7 @@ func (c *s3Client) GetBucketAccess() (acl string, error *probe.Error) {
 }
 
 // SetBucketAccess set acl on a bucket
-func (c *s3Client) SetBucketAccess(acl string) *probe.Error {
+func (c *s3Client) SetBucketAccess(ctx context.Context, acl string) *probe.Error {
 	bucket, object := c.url2BucketAndObject()
 	if object != "" {
 		return probe.NewError(client.InvalidBucketName{Bucket: filepath.Join(bucket, object)})
@@ -229,7 +298,7 @@ func (c *s3Client) SetBucketAccess(acl string) *probe.Error {
 	if bucket == "" {
 		return probe.NewError(client.BucketNameEmpty{})
 	}
-	err := c.api.SetBucketACL(bucket, minio.BucketACL(acl))
+	err := c.api(ctx).SetBucketACL(bucket, minio.BucketACL(acl))
 	if err != nil {
 		return probe.NewError(err)
 	}
@@ -237,14 +306,13 @@ func (c *s3Client) SetBucketAccess(acl string) *probe.Error {
 }
 
 // Stat - send a 'HEAD' on a bucket or object to fetch its metadata.
-func (c *s3Client) Stat() (*client.Content, *probe.Error) {
+func (c *s3Client) Stat(ctx context.Context) (*client.Content, *probe.Error) {
 	c.mu.Lock()
-	objectMetadata := new(client.Content)
 	bucket, object := c.url2BucketAndObject()
 	switch {
 	// valid case for '-r s3/'
 	case bucket == "" && object == "":
-		for bucket := range c.api.ListBuckets() {
+		for bucket := range c.api(ctx).ListBuckets() {
 			if bucket.Err != nil {
 				c.mu.Unlock()
 				return nil, probe.NewError(bucket.Err)
@@ -254,10 +322,10 @@ func (c *s3Client) Stat() (*client.Content, *probe.Error) {
 		return &client.Content{URL: *c.hostURL, Type: os.ModeDir}, nil
 	}
 	if object != "" {
-		metadata, err := c.api.StatObject(bucket, object)
+		content, err := c.headObject(ctx, bucket, object, nil)
 		if err != nil {
 			c.mu.Unlock()
-			errResponse := minio.ToErrorResponse(err)
+			errResponse := minio.ToErrorResponse(err.ToGoError())
 			if errResponse != nil {
 				if errResponse.Code == "NoSuchKey" {
 					// Append "/" to the object name proactively and see if the Listing
@@ -265,7 +333,7 @@ func (c *s3Client) Stat() (*client.Content, *probe.Error) {
 					prefixName := object
 					// Trim any trailing separators and add it.
 					prefixName = strings.TrimSuffix(prefixName, string(c.hostURL.Separator)) + string(c.hostURL.Separator)
-					for objectStat := range c.api.ListObjects(bucket, prefixName, false) {
+					for objectStat := range c.api(ctx).ListObjects(bucket, prefixName, false) {
 						if objectStat.Err != nil {
 							return nil, probe.NewError(objectStat.Err)
 						}
@@ -277,16 +345,12 @@ func (c *s3Client) Stat() (*client.Content, *probe.Error) {
 					return nil, probe.NewError(client.PathNotFound{Path: c.hostURL.Path})
 				}
 			}
-			return nil, probe.NewError(err)
+			return nil, err.Trace(bucket, object)
 		}
-		objectMetadata.URL = *c.hostURL
-		objectMetadata.Time = metadata.LastModified
-		objectMetadata.Size = metadata.Size
-		objectMetadata.Type = os.FileMode(0664)
 		c.mu.Unlock()
-		return objectMetadata, nil
+		return content, nil
 	}
-	err := c.api.BucketExists(bucket)
+	err := c.api(ctx).BucketExists(bucket)
 	if err != nil {
 		c.mu.Unlock()
 		return nil, probe.NewError(err)
@@ -298,6 +362,53 @@ func (c *s3Client) Stat() (*client.Content, *probe.Error) {
 	return bucketMetadata, nil
 }
 
+// metadataPrefix - prefix of user metadata headers.
+const metadataPrefix = "X-Amz-Meta-"
+
+// headObject - stat an object, or a specific version of it, with a HEAD
+// request. Unlike minio-go it also returns the user metadata.
+func (c *s3Client) headObject(ctx context.Context, bucket, object string, queryValues url.Values) (*client.Content, *probe.Error) {
+	resp, err := c.executeMethod(ctx, "HEAD", requestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: queryValues,
+	})
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	defer closeResponse(resp)
+	size, e := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	date, e := time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified"))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	content := new(client.Content)
+	content.URL = *c.hostURL
+	content.Time = date
+	content.Size = size
+	content.Type = os.FileMode(0664)
+	content.ETag = strings.Trim(resp.Header.Get("ETag"), "\"")
+	if isMD5ETag(resp.Header) {
+		content.MD5 = content.ETag
+	}
+	content.ContentType = resp.Header.Get("Content-Type")
+	content.StorageClass = resp.Header.Get("X-Amz-Storage-Class")
+	content.VersionID = resp.Header.Get("X-Amz-Version-Id")
+	content.IsDeleteMarker = resp.Header.Get("X-Amz-Delete-Marker") == "true"
+	for key := range resp.Header {
+		if strings.HasPrefix(key, metadataPrefix) {
+			if content.Metadata == nil {
+				content.Metadata = make(map[string]string)
+			}
+			content.Metadata[strings.TrimPrefix(key, metadataPrefix)] = resp.Header.Get(key)
+		}
+	}
+	return content, nil
+}
+
 // Figure out if the URL is of 'virtual host' style.
 // Currently only supported hosts with virtual style are Amazon S3 and Google Cloud Storage.
 func isVirtualHostStyle(hostURL string) bool {
@@ -308,7 +419,12 @@ func isVirtualHostStyle(hostURL string) bool {
 
 // url2BucketAndObject gives bucketName and objectName from URL path.
 func (c *s3Client) url2BucketAndObject() (bucketName, objectName string) {
-	path := c.hostURL.Path
+	return c.splitURL(c.hostURL)
+}
+
+// splitURL - bucket and object names of a URL of this host.
+func (c *s3Client) splitURL(u *client.URL) (bucketName, objectName string) {
+	path := u.Path
 	// Convert any virtual host styled requests.
 	//
 	// For the time being this check is introduced for S3,
@@ -316,16 +432,16 @@ func (c *s3Client) url2BucketAndObject() (bucketName, objectName string) {
 	// List them below.
 	if c.virtualStyle {
 		var bucket string
-		hostIndex := strings.Index(c.hostURL.Host, "s3")
+		hostIndex := strings.Index(u.Host, "s3")
 		if hostIndex == -1 {
-			hostIndex = strings.Index(c.hostURL.Host, "storage.googleapis")
+			hostIndex = strings.Index(u.Host, "storage.googleapis")
 		}
 		if hostIndex > 0 {
-			bucket = c.hostURL.Host[:hostIndex-1]
-			path = string(c.hostURL.Separator) + bucket + c.hostURL.Path
+			bucket = u.Host[:hostIndex-1]
+			path = string(u.Separator) + bucket + u.Path
 		}
 	}
-	splits := strings.SplitN(path, string(c.hostURL.Separator), 3)
+	splits := strings.SplitN(path, string(u.Separator), 3)
 	switch len(splits) {
 	case 0, 1:
 		bucketName = ""
@@ -343,41 +459,41 @@ func (c *s3Client) url2BucketAndObject() (bucketName, objectName string) {
 /// Bucket API operations.
 
 // List - list at delimited path, if not recursive.
-func (c *s3Client) List(recursive, incomplete bool) <-chan *client.Content {
+func (c *s3Client) List(ctx context.Context, recursive, incomplete bool) <-chan *client.Content {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	contentCh := make(chan *client.Content)
 	if incomplete {
 		if recursive {
-			go c.listIncompleteRecursiveInRoutine(contentCh)
+			go c.listIncompleteRecursiveInRoutine(ctx, contentCh)
 		} else {
-			go c.listIncompleteInRoutine(contentCh)
+			go c.listIncompleteInRoutine(ctx, contentCh)
 		}
 	} else {
 		if recursive {
-			go c.listRecursiveInRoutine(contentCh)
+			go c.listRecursiveInRoutine(ctx, contentCh)
 		} else {
-			go c.listInRoutine(contentCh)
+			go c.listInRoutine(ctx, contentCh)
 		}
 	}
-	return contentCh
+	return client.ForwardContents(ctx, contentCh)
 }
 
-func (c *s3Client) listIncompleteInRoutine(contentCh chan *client.Content) {
+func (c *s3Client) listIncompleteInRoutine(ctx context.Context, contentCh chan *client.Content) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		for bucket := range c.api.ListBuckets() {
+		for bucket := range c.api(ctx).ListBuckets() {
 			if bucket.Err != nil {
 				contentCh <- &client.Content{
 					Err: probe.NewError(bucket.Err),
 				}
 				return
 			}
-			for object := range c.api.ListIncompleteUploads(bucket.Name, o, false) {
+			for object := range c.api(ctx).ListIncompleteUploads(bucket.Name, o, false) {
 				if object.Err != nil {
 					contentCh <- &client.Content{
 						Err: probe.NewError(object.Err),
@@ -407,7 +523,7 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *client.Content) {
 			}
 		}
 	default:
-		for object := range c.api.ListIncompleteUploads(b, o, false) {
+		for object := range c.api(ctx).ListIncompleteUploads(b, o, false) {
 			if object.Err != nil {
 				contentCh <- &client.Content{
 					Err: probe.NewError(object.Err),
@@ -438,20 +554,20 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *client.Content) {
 	}
 }
 
-func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *client.Content) {
+func (c *s3Client) listIncompleteRecursiveInRoutine(ctx context.Context, contentCh chan *client.Content) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		for bucket := range c.api.ListBuckets() {
+		for bucket := range c.api(ctx).ListBuckets() {
 			if bucket.Err != nil {
 				contentCh <- &client.Content{
 					Err: probe.NewError(bucket.Err),
 				}
 				return
 			}
-			for object := range c.api.ListIncompleteUploads(bucket.Name, o, true) {
+			for object := range c.api(ctx).ListIncompleteUploads(bucket.Name, o, true) {
 				if object.Err != nil {
 					contentCh <- &client.Content{
 						Err: probe.NewError(object.Err),
@@ -469,7 +585,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *client.Conte
 			}
 		}
 	default:
-		for object := range c.api.ListIncompleteUploads(b, o, true) {
+		for object := range c.api(ctx).ListIncompleteUploads(b, o, true) {
 			if object.Err != nil {
 				contentCh <- &client.Content{
 					Err: probe.NewError(object.Err),
@@ -492,13 +608,13 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *client.Conte
 	}
 }
 
-func (c *s3Client) listInRoutine(contentCh chan *client.Content) {
+func (c *s3Client) listInRoutine(ctx context.Context, contentCh chan *client.Content) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		for bucket := range c.api.ListBuckets() {
+		for bucket := range c.api(ctx).ListBuckets() {
 			if bucket.Err != nil {
 				contentCh <- &client.Content{
 					Err: probe.NewError(bucket.Err),
@@ -515,7 +631,7 @@ func (c *s3Client) listInRoutine(contentCh chan *client.Content) {
 			contentCh <- content
 		}
 	case b != "" && !strings.HasSuffix(c.hostURL.Path, string(c.hostURL.Separator)) && o == "":
-		err := c.api.BucketExists(b)
+		err := c.api(ctx).BucketExists(b)
 		if err != nil {
 			contentCh <- &client.Content{
 				Err: probe.NewError(err),
@@ -526,7 +642,7 @@ func (c *s3Client) listInRoutine(contentCh chan *client.Content) {
 		content.Type = os.ModeDir
 		contentCh <- content
 	default:
-		metadata, err := c.api.StatObject(b, o)
+		metadata, err := c.api(ctx).StatObject(b, o)
 		switch err.(type) {
 		case nil:
 			content := new(client.Content)
@@ -534,9 +650,12 @@ func (c *s3Client) listInRoutine(contentCh chan *client.Content) {
 			content.Time = metadata.LastModified
 			content.Size = metadata.Size
 			content.Type = os.FileMode(0664)
+			content.ETag = strings.Trim(metadata.ETag, "\"")
+			content.ContentType = metadata.ContentType
+			content.StorageClass = metadata.StorageClass
 			contentCh <- content
 		default:
-			for object := range c.api.ListObjects(b, o, false) {
+			for object := range c.api(ctx).ListObjects(b, o, false) {
 				if object.Err != nil {
 					contentCh <- &client.Content{
 						Err: probe.NewError(object.Err),
@@ -561,6 +680,8 @@ func (c *s3Client) listInRoutine(contentCh chan *client.Content) {
 					content.Size = object.Size
 					content.Time = object.LastModified
 					content.Type = os.FileMode(0664)
+					content.ETag = strings.Trim(object.ETag, "\"")
+					content.StorageClass = object.StorageClass
 				}
 				contentCh <- content
 			}
@@ -568,13 +689,13 @@ func (c *s3Client) listInRoutine(contentCh chan *client.Content) {
 	}
 }
 
-func (c *s3Client) listRecursiveInRoutine(contentCh chan *client.Content) {
+func (c *s3Client) listRecursiveInRoutine(ctx context.Context, contentCh chan *client.Content) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		for bucket := range c.api.ListBuckets() {
+		for bucket := range c.api(ctx).ListBuckets() {
 			if bucket.Err != nil {
 				contentCh <- &client.Content{
 					Err: probe.NewError(bucket.Err),
@@ -588,7 +709,7 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *client.Content) {
 				Type: os.ModeDir,
 				Time: bucket.CreationDate,
 			}
-			for object := range c.api.ListObjects(bucket.Name, o, true) {
+			for object := range c.api(ctx).ListObjects(bucket.Name, o, true) {
 				if object.Err != nil {
 					contentCh <- &client.Content{
 						Err: probe.NewError(object.Err),
@@ -602,11 +723,13 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *client.Content) {
 				content.Size = object.Size
 				content.Time = object.LastModified
 				content.Type = os.FileMode(0664)
+				content.ETag = strings.Trim(object.ETag, "\"")
+				content.StorageClass = object.StorageClass
 				contentCh <- content
 			}
 		}
 	default:
-		for object := range c.api.ListObjects(b, o, true) {
+		for object := range c.api(ctx).ListObjects(b, o, true) {
 			if object.Err != nil {
 				contentCh <- &client.Content{
 					Err: probe.NewError(object.Err),
@@ -625,6 +748,8 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *client.Content) {
 			content.Size = object.Size
 			content.Time = object.LastModified
 			content.Type = os.FileMode(0664)
+			content.ETag = strings.Trim(object.ETag, "\"")
+			content.StorageClass = object.StorageClass
 			contentCh <- content
 		}
 	}
@@ -19,6 +19,7 @@ package s3
 // bucketHandler is an http.Handler that verifies bucket responses and validates incoming requests
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -137,17 +138,17 @@ func (s *MySuite) TestBucketOperations(c *C) {
 	s3c, err := New(conf)
 	c.Assert(err, IsNil)
 
-	err = s3c.MakeBucket()
+	err = s3c.MakeBucket(context.Background())
 	c.Assert(err, IsNil)
 
-	err = s3c.SetBucketAccess("public-read-write")
+	err = s3c.SetBucketAccess(context.Background(), "public-read-write")
 	c.Assert(err, IsNil)
 
 	conf.HostURL = server.URL + string(s3c.GetURL().Separator)
 	s3c, err = New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false) {
+	for content := range s3c.List(context.Background(), false, false) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsDir(), Equals, true)
 	}
@@ -156,7 +157,7 @@ func (s *MySuite) TestBucketOperations(c *C) {
 	s3c, err = New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false) {
+	for content := range s3c.List(context.Background(), false, false) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsDir(), Equals, true)
 	}
@@ -165,7 +166,7 @@ func (s *MySuite) TestBucketOperations(c *C) {
 	s3c, err = New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false) {
+	for content := range s3c.List(context.Background(), false, false) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsRegular(), Equals, true)
 	}
@@ -184,15 +185,15 @@ func (s *MySuite) TestObjectOperations(c *C) {
 	s3c, err := New(conf)
 	c.Assert(err, IsNil)
 
-	err = s3c.Put(bytes.NewReader(object.data), int64(len(object.data)))
+	err = s3c.Put(context.Background(), bytes.NewReader(object.data), int64(len(object.data)))
 	c.Assert(err, IsNil)
 
-	content, err := s3c.Stat()
+	content, err := s3c.Stat(context.Background())
 	c.Assert(err, IsNil)
 	c.Assert(content.Size, Equals, int64(len(object.data)))
 	c.Assert(content.Type.IsRegular(), Equals, true)
 
-	reader, err := s3c.Get(0, 0)
+	reader, err := s3c.Get(context.Background(), 0, 0)
 	var buffer bytes.Buffer
 	{
 		_, err := io.Copy(&buffer, reader)
@@ -200,3 +201,34 @@ func (s *MySuite) TestObjectOperations(c *C) {
 		c.Assert(buffer.Bytes(), DeepEquals, object.data)
 	}
 }
+
+// stallHandler - replies only once requests are given up.
+type stallHandler struct{}
+
+func (stallHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	<-r.Context().Done()
+}
+
+func (s *MySuite) TestCancel(c *C) {
+	server := httptest.NewServer(stallHandler{})
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket/"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	// Listings are sent by minio-go, their channel is closed once cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	contentCh := s3c.List(ctx, false, false)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	for range contentCh {
+	}
+
+	conf.HostURL = server.URL + "/bucket/object"
+	s3c, err = New(conf)
+	c.Assert(err, IsNil)
+	_, err = s3c.Stat(ctx)
+	c.Assert(err, Not(IsNil))
+	c.Assert(err.ToGoError(), ErrorMatches, ".*context canceled")
+}
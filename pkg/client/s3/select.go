@@ -0,0 +1,219 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net/url"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// selectCSVInput - <CSV> element of <InputSerialization>.
+type selectCSVInput struct {
+	FileHeaderInfo string `xml:"FileHeaderInfo,omitempty"`
+	FieldDelimiter string `xml:"FieldDelimiter,omitempty"`
+}
+
+// selectJSONInput - <JSON> element of <InputSerialization>.
+type selectJSONInput struct {
+	Type string `xml:"Type"`
+}
+
+// selectInputSerialization - format of the queried object.
+type selectInputSerialization struct {
+	CompressionType string           `xml:"CompressionType"`
+	CSV             *selectCSVInput  `xml:"CSV,omitempty"`
+	JSON            *selectJSONInput `xml:"JSON,omitempty"`
+}
+
+// selectOutputFormat - <CSV> or <JSON> element of <OutputSerialization>, defaults only.
+type selectOutputFormat struct{}
+
+// selectOutputSerialization - format of the returned records.
+type selectOutputSerialization struct {
+	CSV  *selectOutputFormat `xml:"CSV,omitempty"`
+	JSON *selectOutputFormat `xml:"JSON,omitempty"`
+}
+
+// selectObjectContentRequest - body of a SelectObjectContent request.
+type selectObjectContentRequest struct {
+	XMLName             xml.Name                  `xml:"SelectObjectContentRequest"`
+	Xmlns               string                    `xml:"xmlns,attr,omitempty"`
+	Expression          string                    `xml:"Expression"`
+	ExpressionType      string                    `xml:"ExpressionType"`
+	InputSerialization  selectInputSerialization  `xml:"InputSerialization"`
+	OutputSerialization selectOutputSerialization `xml:"OutputSerialization"`
+}
+
+// Select - run an SQL expression on the server with S3 Select. Servers
+// without S3 Select report client.APINotImplemented.
+func (c *s3Client) Select(ctx context.Context, query client.SelectQuery) (io.ReadCloser, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	request := selectObjectContentRequest{
+		Xmlns:          s3Namespace,
+		Expression:     query.Expression,
+		ExpressionType: "SQL",
+	}
+	request.InputSerialization.CompressionType = "NONE"
+	if query.Gzip {
+		request.InputSerialization.CompressionType = "GZIP"
+	}
+	switch query.InputFormat {
+	case client.SelectJSON:
+		request.InputSerialization.JSON = &selectJSONInput{Type: query.JSONType}
+	default:
+		request.InputSerialization.CSV = &selectCSVInput{
+			FileHeaderInfo: query.CSVHeader,
+			FieldDelimiter: query.CSVDelimiter,
+		}
+	}
+	switch query.OutputFormat {
+	case client.SelectJSON:
+		request.OutputSerialization.JSON = &selectOutputFormat{}
+	default:
+		request.OutputSerialization.CSV = &selectOutputFormat{}
+	}
+
+	metadata, err := newXMLRequestMetadata(bucket, object, url.Values{"select": {""}, "select-type": {"2"}}, request)
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	resp, err := c.executeMethod(ctx, "POST", metadata)
+	if err != nil {
+		if errResponse := minio.ToErrorResponse(err.ToGoError()); errResponse != nil {
+			switch errResponse.Code {
+			case "NotImplemented", "MethodNotAllowed":
+				return nil, probe.NewError(client.APINotImplemented{API: "Select", APIType: c.hostURL.Host})
+			}
+		}
+		return nil, err.Trace(bucket, object)
+	}
+	return &selectReader{body: resp.Body, resource: "/" + bucket + "/" + object}, nil
+}
+
+// selectReader - payload of the Records messages of an event stream reply,
+// see http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectSELECTContent.html
+type selectReader struct {
+	body     io.ReadCloser
+	resource string
+	records  []byte
+	isEnd    bool
+}
+
+// Read - read records, io.EOF only once the End message was received since
+// a reply cut short would otherwise look like a complete one.
+func (r *selectReader) Read(p []byte) (int, error) {
+	for len(r.records) == 0 {
+		if r.isEnd {
+			return 0, io.EOF
+		}
+		if e := r.readMessage(); e != nil {
+			if e == io.EOF {
+				e = io.ErrUnexpectedEOF
+			}
+			return 0, e
+		}
+	}
+	n := copy(p, r.records)
+	r.records = r.records[n:]
+	return n, nil
+}
+
+// Close - close the reply, the query is aborted if it did not end yet.
+func (r *selectReader) Close() error {
+	return r.body.Close()
+}
+
+// readMessage - read one message of the event stream, keeping the payload of Records.
+func (r *selectReader) readMessage() error {
+	// Prelude is total length, headers length and their checksum.
+	prelude := make([]byte, 12)
+	if _, e := io.ReadFull(r.body, prelude); e != nil {
+		return e
+	}
+	totalLength := binary.BigEndian.Uint32(prelude[0:4])
+	headersLength := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return errors.New("Select response is corrupted, prelude checksum mismatch.")
+	}
+	if totalLength < 16+headersLength {
+		return errors.New("Select response is corrupted, invalid message length.")
+	}
+	message := make([]byte, totalLength-12)
+	if _, e := io.ReadFull(r.body, message); e != nil {
+		return e
+	}
+	checksum := crc32.Update(crc32.ChecksumIEEE(prelude), crc32.IEEETable, message[:len(message)-4])
+	if checksum != binary.BigEndian.Uint32(message[len(message)-4:]) {
+		return errors.New("Select response is corrupted, message checksum mismatch.")
+	}
+	headers, e := parseSelectHeaders(message[:headersLength])
+	if e != nil {
+		return e
+	}
+	payload := message[headersLength : len(message)-4]
+
+	switch headers[":message-type"] {
+	case "error":
+		return minio.ErrorResponse{
+			Code:     headers[":error-code"],
+			Message:  headers[":error-message"],
+			Resource: r.resource,
+		}
+	case "event":
+		switch headers[":event-type"] {
+		case "Records":
+			r.records = payload
+		case "End":
+			r.isEnd = true
+		}
+		// Stats, Progress and Cont carry nothing to return.
+	}
+	return nil
+}
+
+// parseSelectHeaders - decode message headers, only string values are sent.
+func parseSelectHeaders(data []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(data) > 0 {
+		nameLength := int(data[0])
+		if len(data) < 1+nameLength+3 {
+			return nil, errors.New("Select response is corrupted, invalid header.")
+		}
+		name := string(data[1 : 1+nameLength])
+		data = data[1+nameLength:]
+		// Value type 7 is a string.
+		if data[0] != 7 {
+			return nil, errors.New("Select response is corrupted, unexpected header type.")
+		}
+		valueLength := int(binary.BigEndian.Uint16(data[1:3]))
+		if len(data) < 3+valueLength {
+			return nil, errors.New("Select response is corrupted, invalid header.")
+		}
+		headers[name] = string(data[3 : 3+valueLength])
+		data = data[3+valueLength:]
+	}
+	return headers, nil
+}
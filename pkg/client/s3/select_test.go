@@ -0,0 +1,133 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/xml"
+	"hash/crc32"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+// selectMessage - encode a message of an event stream.
+func selectMessage(headers map[string]string, payload []byte) []byte {
+	var headerBytes bytes.Buffer
+	for _, name := range []string{":message-type", ":event-type", ":error-code", ":error-message"} {
+		value, ok := headers[name]
+		if !ok {
+			continue
+		}
+		headerBytes.WriteByte(byte(len(name)))
+		headerBytes.WriteString(name)
+		headerBytes.WriteByte(7)
+		binary.Write(&headerBytes, binary.BigEndian, uint16(len(value)))
+		headerBytes.WriteString(value)
+	}
+	var message bytes.Buffer
+	binary.Write(&message, binary.BigEndian, uint32(16+headerBytes.Len()+len(payload)))
+	binary.Write(&message, binary.BigEndian, uint32(headerBytes.Len()))
+	binary.Write(&message, binary.BigEndian, crc32.ChecksumIEEE(message.Bytes()))
+	message.Write(headerBytes.Bytes())
+	message.Write(payload)
+	binary.Write(&message, binary.BigEndian, crc32.ChecksumIEEE(message.Bytes()))
+	return message.Bytes()
+}
+
+// selectHandler is an http.Handler answering S3 Select requests of object
+// "people.csv", and rejecting them as not implemented for any other object.
+type selectHandler struct{}
+
+func (h selectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || r.URL.Query().Get("select-type") != "2" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/bucket/people.csv" {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	request := selectObjectContentRequest{}
+	if e := xml.Unmarshal(body, &request); e != nil || request.InputSerialization.CSV == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if request.Expression == "bad" {
+		w.Write(selectMessage(map[string]string{":message-type": "error", ":error-code": "ParseUnexpectedToken", ":error-message": "Unexpected token"}, nil))
+		return
+	}
+	w.Write(selectMessage(map[string]string{":message-type": "event", ":event-type": "Records"}, []byte("alice\n")))
+	w.Write(selectMessage(map[string]string{":message-type": "event", ":event-type": "Stats"}, []byte("<Stats/>")))
+	w.Write(selectMessage(map[string]string{":message-type": "event", ":event-type": "Records"}, []byte("bob\n")))
+	if request.Expression != "cut" {
+		w.Write(selectMessage(map[string]string{":message-type": "event", ":event-type": "End"}, nil))
+	}
+}
+
+func (s *MySuite) TestSelect(c *C) {
+	server := httptest.NewServer(selectHandler{})
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket/people.csv"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	query := client.SelectQuery{
+		Expression:   "select s.name from S3Object s",
+		InputFormat:  client.SelectCSV,
+		OutputFormat: client.SelectCSV,
+		CSVHeader:    "USE",
+	}
+	reader, err := s3c.Select(context.Background(), query)
+	c.Assert(err, IsNil)
+	records, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(records), Equals, "alice\nbob\n")
+	c.Assert(reader.Close(), IsNil)
+
+	// Errors are sent in the stream.
+	query.Expression = "bad"
+	reader, err = s3c.Select(context.Background(), query)
+	c.Assert(err, IsNil)
+	_, e = ioutil.ReadAll(reader)
+	c.Assert(e, Not(IsNil))
+
+	// A stream without End is incomplete.
+	query.Expression = "cut"
+	reader, err = s3c.Select(context.Background(), query)
+	c.Assert(err, IsNil)
+	_, e = ioutil.ReadAll(reader)
+	c.Assert(e, Not(IsNil))
+
+	// Servers without S3 Select.
+	conf.HostURL = server.URL + "/bucket/people.json"
+	s3c, err = New(conf)
+	c.Assert(err, IsNil)
+	_, err = s3c.Select(context.Background(), query)
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.APINotImplemented)
+	c.Assert(ok, Equals, true)
+}
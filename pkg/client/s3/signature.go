@@ -0,0 +1,232 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// signature and API related constants.
+const (
+	signV4Algorithm   = "AWS4-HMAC-SHA256"
+	iso8601DateFormat = "20060102T150405Z"
+	yyyymmdd          = "20060102"
+	unsignedPayload   = "UNSIGNED-PAYLOAD"
+	emptySHA256       = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// Headers never included in a V4 signature, see minio-go for the rationale.
+var ignoredHeaders = map[string]bool{
+	"Authorization":  true,
+	"Content-Type":   true,
+	"Content-Length": true,
+	"User-Agent":     true,
+}
+
+// sumHMAC - calculate HMAC-SHA256 of data with key.
+func sumHMAC(key []byte, data []byte) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// getSigningKey hmac seed to calculate final signature
+func getSigningKey(secret, region string, t time.Time) []byte {
+	date := sumHMAC([]byte("AWS4"+secret), []byte(t.Format(yyyymmdd)))
+	regionBytes := sumHMAC(date, []byte(region))
+	service := sumHMAC(regionBytes, []byte("s3"))
+	return sumHMAC(service, []byte("aws4_request"))
+}
+
+// getScope generate a string of a specific date, an AWS region, and a service
+func getScope(region string, t time.Time) string {
+	return strings.Join([]string{t.Format(yyyymmdd), region, "s3", "aws4_request"}, "/")
+}
+
+// getSignedHeaders - sorted list of lower case header names covered by the signature.
+func getSignedHeaders(req *http.Request) []string {
+	var headers []string
+	for k := range req.Header {
+		if ignoredHeaders[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		headers = append(headers, strings.ToLower(k))
+	}
+	headers = append(headers, "host")
+	sort.Strings(headers)
+	return headers
+}
+
+// getCanonicalRequest generate a canonical request of style.
+//
+// canonicalRequest =
+//
+//	<HTTPMethod>\n
+//	<CanonicalURI>\n
+//	<CanonicalQueryString>\n
+//	<CanonicalHeaders>\n
+//	<SignedHeaders>\n
+//	<HashedPayload>
+func getCanonicalRequest(req *http.Request, signedHeaders []string) string {
+	req.URL.RawQuery = strings.Replace(req.URL.Query().Encode(), "+", "%20", -1)
+	var headers bytes.Buffer
+	for _, k := range signedHeaders {
+		headers.WriteString(k)
+		headers.WriteByte(':')
+		if k == "host" {
+			headers.WriteString(req.URL.Host)
+		} else {
+			headers.WriteString(strings.Join(req.Header[http.CanonicalHeaderKey(k)], ","))
+		}
+		headers.WriteByte('\n')
+	}
+	return strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		req.URL.RawQuery,
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+}
+
+// signV4 sign the request in accordance with
+// http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html.
+func signV4(req *http.Request, accessKeyID, secretAccessKey, region string) {
+	t := time.Now().UTC()
+	req.Header.Set("X-Amz-Date", t.Format(iso8601DateFormat))
+	if req.Header.Get("X-Amz-Content-Sha256") == "" {
+		req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+	}
+
+	signedHeaders := getSignedHeaders(req)
+	canonicalRequest := getCanonicalRequest(req, signedHeaders)
+	canonicalRequestSum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := signV4Algorithm + "\n" + t.Format(iso8601DateFormat) + "\n" +
+		getScope(region, t) + "\n" + hex.EncodeToString(canonicalRequestSum[:])
+
+	signingKey := getSigningKey(secretAccessKey, region, t)
+	signature := hex.EncodeToString(sumHMAC(signingKey, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", strings.Join([]string{
+		signV4Algorithm + " Credential=" + accessKeyID + "/" + getScope(region, t),
+		"SignedHeaders=" + strings.Join(signedHeaders, ";"),
+		"Signature=" + signature,
+	}, ", "))
+}
+
+// Sub-resources which are part of the V2 canonicalized resource. Must be sorted.
+var resourceList = []string{
+	"acl",
+	"cors",
+	"delete",
+	"legal-hold",
+	"lifecycle",
+	"location",
+	"logging",
+	"notification",
+	"object-lock",
+	"partNumber",
+	"policy",
+	"requestPayment",
+	"response-cache-control",
+	"response-content-disposition",
+	"response-content-encoding",
+	"response-content-language",
+	"response-content-type",
+	"response-expires",
+	"retention",
+	"select",
+	"select-type",
+	"tagging",
+	"torrent",
+	"uploadId",
+	"uploads",
+	"versionId",
+	"versioning",
+	"versions",
+	"website",
+}
+
+// signV2 sign the request (AWS Signature Version 2).
+//
+// StringToSign = HTTP-Verb + "\n" +
+//
+//	Content-MD5 + "\n" +
+//	Content-Type + "\n" +
+//	Date + "\n" +
+//	CanonicalizedProtocolHeaders +
+//	CanonicalizedResource;
+func signV2(req *http.Request, accessKeyID, secretAccessKey string, virtualStyle bool) {
+	if req.Header.Get("Date") == "" {
+		req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(req.Method + "\n")
+	buf.WriteString(req.Header.Get("Content-MD5") + "\n")
+	buf.WriteString(req.Header.Get("Content-Type") + "\n")
+	buf.WriteString(req.Header.Get("Date") + "\n")
+
+	// Canonicalized protocol headers.
+	var protoHeaders []string
+	for k := range req.Header {
+		if lk := strings.ToLower(k); strings.HasPrefix(lk, "x-amz") {
+			protoHeaders = append(protoHeaders, lk)
+		}
+	}
+	sort.Strings(protoHeaders)
+	for _, k := range protoHeaders {
+		buf.WriteString(k + ":" + strings.Join(req.Header[http.CanonicalHeaderKey(k)], ",") + "\n")
+	}
+
+	// Canonicalized resource, virtual style requests carry the bucket in the host.
+	if virtualStyle {
+		if i := strings.Index(req.URL.Host, ".s3"); i > 0 {
+			buf.WriteString("/" + req.URL.Host[:i])
+		} else if i := strings.Index(req.URL.Host, ".storage.googleapis"); i > 0 {
+			buf.WriteString("/" + req.URL.Host[:i])
+		}
+	}
+	buf.WriteString(encodePath(req.URL.Path))
+	query := req.URL.Query()
+	separator := "?"
+	for _, resource := range resourceList {
+		values, ok := query[resource]
+		if !ok {
+			continue
+		}
+		buf.WriteString(separator + resource)
+		if len(values) > 0 && values[0] != "" {
+			buf.WriteString("=" + values[0])
+		}
+		separator = "&"
+	}
+
+	hm := hmac.New(sha1.New, []byte(secretAccessKey))
+	hm.Write(buf.Bytes())
+	req.Header.Set("Authorization", "AWS "+accessKeyID+":"+base64.StdEncoding.EncodeToString(hm.Sum(nil)))
+}
@@ -0,0 +1,118 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"net/url"
+	"sort"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// tag - a single key value pair.
+type tag struct {
+	Key   string
+	Value string
+}
+
+// tagging - tag set of a bucket or an object.
+type tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	TagSet  struct {
+		Tags []tag `xml:"Tag"`
+	}
+}
+
+// GetTags - get tags of an object, or of a bucket if the URL has no object.
+func (c *s3Client) GetTags(ctx context.Context) (map[string]string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return nil, probe.NewError(client.BucketNameEmpty{})
+	}
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"tagging": {""}},
+	})
+	if err != nil {
+		// Buckets without any tags reply with 'NoSuchTagSet'.
+		if errResponse := minio.ToErrorResponse(err.ToGoError()); errResponse != nil && errResponse.Code == "NoSuchTagSet" {
+			return map[string]string{}, nil
+		}
+		return nil, err.Trace(bucket, object)
+	}
+	t := tagging{}
+	if err = decodeXMLResponse(resp, &t); err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	tags := make(map[string]string)
+	for _, tag := range t.TagSet.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
+
+// SetTags - replace all tags of an object, or of a bucket if the URL has no object.
+func (c *s3Client) SetTags(ctx context.Context, tags map[string]string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(client.BucketNameEmpty{})
+	}
+	t := tagging{Xmlns: s3Namespace}
+	// Keep the document stable, map iteration order is random.
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		t.TagSet.Tags = append(t.TagSet.Tags, tag{Key: key, Value: tags[key]})
+	}
+	metadata, err := newXMLRequestMetadata(bucket, object, url.Values{"tagging": {""}}, t)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	resp, err := c.executeMethod(ctx, "PUT", metadata)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// DeleteTags - remove all tags of an object, or of a bucket if the URL has no object.
+func (c *s3Client) DeleteTags(ctx context.Context) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(client.BucketNameEmpty{})
+	}
+	resp, err := c.executeMethod(ctx, "DELETE", requestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"tagging": {""}},
+	})
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	closeResponse(resp)
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+// taggingHandler is an http.Handler storing the tag set of a single object.
+type taggingHandler struct {
+	resource string
+	document *[]byte
+}
+
+func (h taggingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["tagging"]; !ok || r.URL.Path != h.resource {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case "PUT":
+		document, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		*h.document = document
+	case "GET":
+		if len(*h.document) == 0 {
+			w.Write([]byte("<Tagging><TagSet></TagSet></Tagging>"))
+			return
+		}
+		w.Write(*h.document)
+	case "DELETE":
+		*h.document = nil
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *MySuite) TestTaggingOperations(c *C) {
+	var document []byte
+	handler := taggingHandler{
+		resource: "/bucket/object",
+		document: &document,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + handler.resource
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	tags, err := s3c.GetTags(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(len(tags), Equals, 0)
+
+	err = s3c.SetTags(context.Background(), map[string]string{"project": "mc", "costcenter": "42"})
+	c.Assert(err, IsNil)
+	c.Assert(string(document), Equals, `<Tagging xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><TagSet><Tag><Key>costcenter</Key><Value>42</Value></Tag><Tag><Key>project</Key><Value>mc</Value></Tag></TagSet></Tagging>`)
+
+	tags, err = s3c.GetTags(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(tags, DeepEquals, map[string]string{"project": "mc", "costcenter": "42"})
+
+	err = s3c.DeleteTags(context.Background())
+	c.Assert(err, IsNil)
+	tags, err = s3c.GetTags(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(len(tags), Equals, 0)
+}
@@ -0,0 +1,239 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// versioningConfiguration - bucket versioning state.
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Status  string   `xml:"Status,omitempty"`
+}
+
+// listVersionsEntry - a <Version>, <DeleteMarker> or <CommonPrefixes> element of a versions listing.
+type listVersionsEntry struct {
+	XMLName      xml.Name
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified time.Time
+	Size         int64
+	Prefix       string
+}
+
+// listVersionsResult - ListObjectVersions reply. Versions and delete markers
+// are decoded into a single slice to preserve their order.
+type listVersionsResult struct {
+	Name                string
+	Prefix              string
+	KeyMarker           string
+	VersionIDMarker     string `xml:"VersionIdMarker"`
+	MaxKeys             int
+	Delimiter           string
+	IsTruncated         bool
+	NextKeyMarker       string
+	NextVersionIDMarker string              `xml:"NextVersionIdMarker"`
+	Entries             []listVersionsEntry `xml:",any"`
+}
+
+// GetVersioning - get bucket versioning status, empty if never enabled.
+func (c *s3Client) GetVersioning(ctx context.Context) (string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object != "" {
+		return "", probe.NewError(client.InvalidBucketName{Bucket: filepath.Join(bucket, object)})
+	}
+	if bucket == "" {
+		return "", probe.NewError(client.BucketNameEmpty{})
+	}
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"versioning": {""}},
+	})
+	if err != nil {
+		return "", err.Trace(bucket)
+	}
+	config := versioningConfiguration{}
+	if err = decodeXMLResponse(resp, &config); err != nil {
+		return "", err.Trace(bucket)
+	}
+	return config.Status, nil
+}
+
+// SetVersioning - enable or suspend versioning on a bucket.
+func (c *s3Client) SetVersioning(ctx context.Context, status string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object != "" {
+		return probe.NewError(client.InvalidBucketName{Bucket: filepath.Join(bucket, object)})
+	}
+	if bucket == "" {
+		return probe.NewError(client.BucketNameEmpty{})
+	}
+	metadata, err := newXMLRequestMetadata(bucket, "", url.Values{"versioning": {""}}, versioningConfiguration{Xmlns: s3Namespace, Status: status})
+	if err != nil {
+		return err.Trace(bucket, status)
+	}
+	resp, err := c.executeMethod(ctx, "PUT", metadata)
+	if err != nil {
+		return err.Trace(bucket, status)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// ListVersions - list all versions and delete markers at a delimited path, if not recursive.
+func (c *s3Client) ListVersions(ctx context.Context, recursive bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content)
+	go c.listVersionsInRoutine(ctx, recursive, contentCh)
+	return client.ForwardContents(ctx, contentCh)
+}
+
+func (c *s3Client) listVersionsInRoutine(ctx context.Context, recursive bool, contentCh chan *client.Content) {
+	defer close(contentCh)
+	// get bucket and object from URL.
+	b, o := c.url2BucketAndObject()
+	if b == "" {
+		contentCh <- &client.Content{Err: probe.NewError(client.BucketNameEmpty{})}
+		return
+	}
+	queryValues := url.Values{}
+	queryValues.Set("versions", "")
+	queryValues.Set("prefix", o)
+	if !recursive {
+		queryValues.Set("delimiter", string(c.hostURL.Separator))
+	}
+	for {
+		resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+			bucketName:  b,
+			queryValues: queryValues,
+		})
+		if err != nil {
+			contentCh <- &client.Content{Err: err.Trace(b, o)}
+			return
+		}
+		result := listVersionsResult{}
+		if err = decodeXMLResponse(resp, &result); err != nil {
+			contentCh <- &client.Content{Err: err.Trace(b, o)}
+			return
+		}
+		for _, entry := range result.Entries {
+			url := *c.hostURL
+			content := new(client.Content)
+			switch entry.XMLName.Local {
+			case "CommonPrefixes":
+				// We need to keep the trailing Separator, do not use filepath.Join().
+				url.Path = string(url.Separator) + b + string(url.Separator) + entry.Prefix
+				if c.virtualStyle {
+					url.Path = string(url.Separator) + entry.Prefix
+				}
+				content.URL = url
+				content.Time = time.Now()
+				content.Type = os.ModeDir
+			case "Version", "DeleteMarker":
+				// Join bucket and incoming object key.
+				url.Path = filepath.Join(string(url.Separator), b, entry.Key)
+				if c.virtualStyle {
+					url.Path = filepath.Join(string(url.Separator), entry.Key)
+				}
+				content.URL = url
+				content.Size = entry.Size
+				content.Time = entry.LastModified
+				content.Type = os.FileMode(0664)
+				content.VersionID = entry.VersionID
+				content.IsLatest = entry.IsLatest
+				content.IsDeleteMarker = entry.XMLName.Local == "DeleteMarker"
+			default:
+				continue
+			}
+			contentCh <- content
+		}
+		if !result.IsTruncated {
+			return
+		}
+		queryValues.Set("key-marker", result.NextKeyMarker)
+		queryValues.Set("version-id-marker", result.NextVersionIDMarker)
+	}
+}
+
+// StatVersion - send a 'HEAD' on a specific version of an object.
+func (c *s3Client) StatVersion(ctx context.Context, versionID string) (*client.Content, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return nil, probe.NewError(client.ObjectMissing{})
+	}
+	content, err := c.headObject(ctx, bucket, object, url.Values{"versionId": {versionID}})
+	if err != nil {
+		return nil, c.toVersionError(err, versionID).Trace(bucket, object, versionID)
+	}
+	return content, nil
+}
+
+// GetVersion - get a specific version of an object.
+func (c *s3Client) GetVersion(ctx context.Context, versionID string, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return nil, probe.NewError(client.ObjectMissing{})
+	}
+	// Verify upfront that the version exists, errors are otherwise only seen upon Read().
+	if _, err := c.StatVersion(ctx, versionID); err != nil {
+		return nil, err.Trace(bucket, object, versionID)
+	}
+	return newObjectReader(ctx, c, bucket, object, url.Values{"versionId": {versionID}}, offset, length), nil
+}
+
+// RemoveVersion - permanently remove a specific version of an object.
+func (c *s3Client) RemoveVersion(ctx context.Context, versionID string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(client.ObjectMissing{})
+	}
+	resp, err := c.executeMethod(ctx, "DELETE", requestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"versionId": {versionID}},
+	})
+	if err != nil {
+		return c.toLockError(c.toVersionError(err, versionID)).Trace(bucket, object, versionID)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// toVersionError - translate 'NoSuchVersion' and friends into typed client errors.
+func (c *s3Client) toVersionError(err *probe.Error, versionID string) *probe.Error {
+	errResponse := minio.ToErrorResponse(err.ToGoError())
+	if errResponse == nil {
+		return err
+	}
+	switch errResponse.Code {
+	case "NoSuchKey", "NoSuchVersion", "InvalidArgument":
+		return probe.NewError(client.VersionNotFound{Path: c.hostURL.String(), VersionID: versionID})
+	}
+	return err
+}
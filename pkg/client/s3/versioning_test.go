@@ -0,0 +1,142 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+// versioningHandler is an http.Handler serving a versioned bucket with a single object.
+type versioningHandler struct {
+	status   *string
+	versions map[string][]byte
+}
+
+func (h versioningHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == "PUT" && r.URL.Path == "/bucket":
+		if _, ok := query["versioning"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		config := versioningConfiguration{}
+		if err := xml.NewDecoder(r.Body).Decode(&config); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*h.status = config.Status
+	case r.Method == "GET" && r.URL.Path == "/bucket":
+		if _, ok := query["versioning"]; ok {
+			w.Write([]byte("<VersioningConfiguration><Status>" + *h.status + "</Status></VersioningConfiguration>"))
+			return
+		}
+		w.Write([]byte(`<ListVersionsResult>
+<Name>bucket</Name><Prefix>object</Prefix><IsTruncated>false</IsTruncated>
+<DeleteMarker><Key>object</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest><LastModified>2015-05-23T00:00:00.000Z</LastModified></DeleteMarker>
+<Version><Key>object</Key><VersionId>v2</VersionId><IsLatest>false</IsLatest><LastModified>2015-05-22T00:00:00.000Z</LastModified><Size>5</Size></Version>
+<Version><Key>object</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><LastModified>2015-05-21T00:00:00.000Z</LastModified><Size>12</Size></Version>
+</ListVersionsResult>`))
+	case r.URL.Path == "/bucket/object":
+		data, ok := h.versions[query.Get("versionId")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case "HEAD":
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			w.Header().Set("x-amz-version-id", query.Get("versionId"))
+		case "GET":
+			w.Write(data)
+		case "DELETE":
+			delete(h.versions, query.Get("versionId"))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (s *MySuite) TestVersioningOperations(c *C) {
+	status := ""
+	handler := versioningHandler{
+		status: &status,
+		versions: map[string][]byte{
+			"v1": []byte("Hello, World"),
+			"v2": []byte("Hello"),
+		},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	err = s3c.SetVersioning(context.Background(), client.VersioningEnabled)
+	c.Assert(err, IsNil)
+	versioning, err := s3c.GetVersioning(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(versioning, Equals, client.VersioningEnabled)
+
+	conf.HostURL = server.URL + "/bucket/object"
+	s3c, err = New(conf)
+	c.Assert(err, IsNil)
+
+	var versionIDs []string
+	for content := range s3c.ListVersions(context.Background(), false) {
+		c.Assert(content.Err, IsNil)
+		c.Assert(content.URL.Path, Equals, "/bucket/object")
+		versionIDs = append(versionIDs, content.VersionID)
+		if content.VersionID == "v3" {
+			c.Assert(content.IsDeleteMarker, Equals, true)
+			c.Assert(content.IsLatest, Equals, true)
+		}
+	}
+	c.Assert(versionIDs, DeepEquals, []string{"v3", "v2", "v1"})
+
+	content, err := s3c.StatVersion(context.Background(), "v1")
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(12))
+	c.Assert(content.VersionID, Equals, "v1")
+
+	reader, err := s3c.GetVersion(context.Background(), "v1", 0, 0)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(data, DeepEquals, []byte("Hello, World"))
+
+	err = s3c.RemoveVersion(context.Background(), "v1")
+	c.Assert(err, IsNil)
+	_, err = s3c.StatVersion(context.Background(), "v1")
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.VersionNotFound)
+	c.Assert(ok, Equals, true)
+}
@@ -0,0 +1,202 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"net/url"
+	"path/filepath"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-go"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// corsRule - a single <CORSRule> element.
+type corsRule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedHeaders []string `xml:"AllowedHeader"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader"`
+}
+
+// corsConfiguration - bucket CORS configuration.
+type corsConfiguration struct {
+	XMLName xml.Name   `xml:"CORSConfiguration"`
+	Xmlns   string     `xml:"xmlns,attr,omitempty"`
+	Rules   []corsRule `xml:"CORSRule"`
+}
+
+// websiteIndexDocument - document served for requests ending with a '/'.
+type websiteIndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+// websiteErrorDocument - document served upon 4XX errors.
+type websiteErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+// websiteConfiguration - bucket static website configuration.
+type websiteConfiguration struct {
+	XMLName       xml.Name              `xml:"WebsiteConfiguration"`
+	Xmlns         string                `xml:"xmlns,attr,omitempty"`
+	IndexDocument *websiteIndexDocument `xml:"IndexDocument,omitempty"`
+	ErrorDocument *websiteErrorDocument `xml:"ErrorDocument,omitempty"`
+}
+
+// GetCORS - get CORS rules of a bucket, empty if not configured.
+func (c *s3Client) GetCORS(ctx context.Context) ([]client.CORSRule, *probe.Error) {
+	config := corsConfiguration{}
+	if err := c.getBucketConfig(ctx, "cors", "NoSuchCORSConfiguration", &config); err != nil {
+		return nil, err.Trace()
+	}
+	var rules []client.CORSRule
+	for _, rule := range config.Rules {
+		rules = append(rules, client.CORSRule{
+			ID:             rule.ID,
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+			MaxAgeSeconds:  rule.MaxAgeSeconds,
+		})
+	}
+	return rules, nil
+}
+
+// SetCORS - replace CORS rules of a bucket.
+func (c *s3Client) SetCORS(ctx context.Context, rules []client.CORSRule) *probe.Error {
+	config := corsConfiguration{Xmlns: s3Namespace}
+	for _, rule := range rules {
+		config.Rules = append(config.Rules, corsRule{
+			ID:             rule.ID,
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+			MaxAgeSeconds:  rule.MaxAgeSeconds,
+		})
+	}
+	return c.setBucketConfig(ctx, "cors", config).Trace()
+}
+
+// DeleteCORS - remove all CORS rules of a bucket.
+func (c *s3Client) DeleteCORS(ctx context.Context) *probe.Error {
+	return c.deleteBucketConfig(ctx, "cors").Trace()
+}
+
+// GetWebsite - get static website configuration of a bucket, empty if not configured.
+func (c *s3Client) GetWebsite(ctx context.Context) (client.Website, *probe.Error) {
+	config := websiteConfiguration{}
+	if err := c.getBucketConfig(ctx, "website", "NoSuchWebsiteConfiguration", &config); err != nil {
+		return client.Website{}, err.Trace()
+	}
+	website := client.Website{}
+	if config.IndexDocument != nil {
+		website.IndexDocument = config.IndexDocument.Suffix
+	}
+	if config.ErrorDocument != nil {
+		website.ErrorDocument = config.ErrorDocument.Key
+	}
+	return website, nil
+}
+
+// SetWebsite - serve a bucket as a static website.
+func (c *s3Client) SetWebsite(ctx context.Context, website client.Website) *probe.Error {
+	config := websiteConfiguration{Xmlns: s3Namespace}
+	config.IndexDocument = &websiteIndexDocument{Suffix: website.IndexDocument}
+	if website.ErrorDocument != "" {
+		config.ErrorDocument = &websiteErrorDocument{Key: website.ErrorDocument}
+	}
+	return c.setBucketConfig(ctx, "website", config).Trace(website.IndexDocument, website.ErrorDocument)
+}
+
+// DeleteWebsite - stop serving a bucket as a static website.
+func (c *s3Client) DeleteWebsite(ctx context.Context) *probe.Error {
+	return c.deleteBucketConfig(ctx, "website").Trace()
+}
+
+// bucketOnly - bucket name of this client, which must not point to an object.
+func (c *s3Client) bucketOnly() (string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object != "" {
+		return "", probe.NewError(client.InvalidBucketName{Bucket: filepath.Join(bucket, object)})
+	}
+	if bucket == "" {
+		return "", probe.NewError(client.BucketNameEmpty{})
+	}
+	return bucket, nil
+}
+
+// getBucketConfig - decode a bucket sub-resource, leaves v untouched if the
+// server replies with notFoundCode.
+func (c *s3Client) getBucketConfig(ctx context.Context, resource, notFoundCode string, v interface{}) *probe.Error {
+	bucket, err := c.bucketOnly()
+	if err != nil {
+		return err.Trace(resource)
+	}
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{resource: {""}},
+	})
+	if err != nil {
+		if errResponse := minio.ToErrorResponse(err.ToGoError()); errResponse != nil && errResponse.Code == notFoundCode {
+			return nil
+		}
+		return err.Trace(bucket, resource)
+	}
+	return decodeXMLResponse(resp, v).Trace(bucket, resource)
+}
+
+// setBucketConfig - replace a bucket sub-resource.
+func (c *s3Client) setBucketConfig(ctx context.Context, resource string, v interface{}) *probe.Error {
+	bucket, err := c.bucketOnly()
+	if err != nil {
+		return err.Trace(resource)
+	}
+	metadata, err := newXMLRequestMetadata(bucket, "", url.Values{resource: {""}}, v)
+	if err != nil {
+		return err.Trace(bucket, resource)
+	}
+	resp, err := c.executeMethod(ctx, "PUT", metadata)
+	if err != nil {
+		return err.Trace(bucket, resource)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// deleteBucketConfig - remove a bucket sub-resource.
+func (c *s3Client) deleteBucketConfig(ctx context.Context, resource string) *probe.Error {
+	bucket, err := c.bucketOnly()
+	if err != nil {
+		return err.Trace(resource)
+	}
+	resp, err := c.executeMethod(ctx, "DELETE", requestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{resource: {""}},
+	})
+	if err != nil {
+		return err.Trace(bucket, resource)
+	}
+	closeResponse(resp)
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+// bucketConfigHandler is an http.Handler serving ?cors and ?website sub-resources.
+type bucketConfigHandler struct {
+	documents map[string][]byte
+}
+
+func (h bucketConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resource := strings.TrimSuffix(r.URL.RawQuery, "=")
+	switch r.Method {
+	case "PUT":
+		if r.Header.Get("Content-MD5") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.documents[resource], _ = ioutil.ReadAll(r.Body)
+	case "DELETE":
+		delete(h.documents, resource)
+		w.WriteHeader(http.StatusNoContent)
+	case "GET":
+		document, ok := h.documents[resource]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			if resource == "cors" {
+				w.Write([]byte("<Error><Code>NoSuchCORSConfiguration</Code></Error>"))
+			} else {
+				w.Write([]byte("<Error><Code>NoSuchWebsiteConfiguration</Code></Error>"))
+			}
+			return
+		}
+		w.Write(document)
+	}
+}
+
+func (s *MySuite) TestBucketConfigOperations(c *C) {
+	server := httptest.NewServer(bucketConfigHandler{documents: make(map[string][]byte)})
+	defer server.Close()
+
+	conf := new(client.Config)
+	conf.HostURL = server.URL + "/bucket"
+	s3c, err := New(conf)
+	c.Assert(err, IsNil)
+
+	// CORS rules.
+	rules, err := s3c.GetCORS(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(len(rules), Equals, 0)
+	expectedRules := []client.CORSRule{
+		{ID: "read", AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET", "HEAD"}, MaxAgeSeconds: 3000},
+		{AllowedOrigins: []string{"https://example.com"}, AllowedMethods: []string{"PUT"}, AllowedHeaders: []string{"*"}, ExposeHeaders: []string{"ETag"}},
+	}
+	c.Assert(s3c.SetCORS(context.Background(), expectedRules), IsNil)
+	rules, err = s3c.GetCORS(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(rules, DeepEquals, expectedRules)
+	c.Assert(s3c.DeleteCORS(context.Background()), IsNil)
+	rules, err = s3c.GetCORS(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(len(rules), Equals, 0)
+
+	// Static website.
+	website, err := s3c.GetWebsite(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(website, Equals, client.Website{})
+	c.Assert(s3c.SetWebsite(context.Background(), client.Website{IndexDocument: "index.html", ErrorDocument: "404.html"}), IsNil)
+	website, err = s3c.GetWebsite(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(website, Equals, client.Website{IndexDocument: "index.html", ErrorDocument: "404.html"})
+	c.Assert(s3c.DeleteWebsite(context.Background()), IsNil)
+	website, err = s3c.GetWebsite(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(website, Equals, client.Website{})
+
+	// Objects do not carry bucket configurations.
+	conf.HostURL = server.URL + "/bucket/object"
+	s3c, err = New(conf)
+	c.Assert(err, IsNil)
+	_, err = s3c.GetCORS(context.Background())
+	c.Assert(err, Not(IsNil))
+}
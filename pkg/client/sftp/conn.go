@@ -0,0 +1,166 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sftp
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+	pkgsftp "github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// connections - SFTP sessions shared by all clients of a user on a host,
+// keyed by ‘user@host:port’.
+var connections = struct {
+	sync.Mutex
+	m map[string]*pkgsftp.Client
+}{m: make(map[string]*pkgsftp.Client)}
+
+// dialTimeout - time allowed to connect and authenticate.
+const dialTimeout = 30 * time.Second
+
+// defaultKeys - private keys of the user tried without a password or key file.
+var defaultKeys = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// connect - the session of the user on the host, established on first use.
+func (c *sftpClient) connect() (*pkgsftp.Client, *probe.Error) {
+	key := c.user + "@" + c.address
+	connections.Lock()
+	defer connections.Unlock()
+	if conn, ok := connections.m[key]; ok {
+		return conn, nil
+	}
+	auths, err := authMethods(c.config)
+	if err != nil {
+		return nil, err.Trace(key)
+	}
+	hostKeyCallback, err := knownHostsCallback()
+	if err != nil {
+		return nil, err.Trace(key)
+	}
+	sshClient, e := ssh.Dial("tcp", c.address, &ssh.ClientConfig{
+		User:            c.user,
+		Auth:            auths,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	})
+	if e != nil {
+		return nil, probe.NewError(e).Trace(key)
+	}
+	conn, e := pkgsftp.NewClient(sshClient)
+	if e != nil {
+		sshClient.Close()
+		return nil, probe.NewError(e).Trace(key)
+	}
+	connections.m[key] = conn
+	return conn, nil
+}
+
+// homeDir - home folder of the current user, where keys are kept.
+func homeDir() string {
+	if u, e := user.Current(); e == nil && u.HomeDir != "" {
+		return u.HomeDir
+	}
+	return os.Getenv("HOME")
+}
+
+// currentUser - name of the current user, the default user on hosts.
+func currentUser() string {
+	if u, e := user.Current(); e == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// authMethods - a password, a private key file, or default keys of the user
+// when neither is configured.
+func authMethods(config *client.Config) ([]ssh.AuthMethod, *probe.Error) {
+	var auths []ssh.AuthMethod
+	if config.SecretAccessKey != "" {
+		password := config.SecretAccessKey
+		auths = append(auths, ssh.Password(password),
+			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}))
+	}
+	if config.PrivateKey != "" {
+		signer, err := readPrivateKey(config.PrivateKey)
+		if err != nil {
+			return nil, err.Trace(config.PrivateKey)
+		}
+		auths = append(auths, ssh.PublicKeys(signer))
+	}
+	if len(auths) > 0 {
+		return auths, nil
+	}
+	var signers []ssh.Signer
+	for _, name := range defaultKeys {
+		// Missing keys and keys protected by a passphrase are skipped.
+		if signer, err := readPrivateKey(filepath.Join(homeDir(), ".ssh", name)); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) == 0 {
+		return nil, probe.NewError(errors.New("No password, private key file or default private key found."))
+	}
+	return []ssh.AuthMethod{ssh.PublicKeys(signers...)}, nil
+}
+
+// readPrivateKey - parse an unencrypted private key file.
+func readPrivateKey(keyFile string) (ssh.Signer, *probe.Error) {
+	data, e := ioutil.ReadFile(keyFile)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	signer, e := ssh.ParsePrivateKey(data)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return signer, nil
+}
+
+// knownHostsCallback - verify host keys against known hosts of the user,
+// hosts are added to them by connecting with ssh once.
+func knownHostsCallback() (ssh.HostKeyCallback, *probe.Error) {
+	callback, e := knownhosts.New(filepath.Join(homeDir(), ".ssh", "known_hosts"))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return callback, nil
+}
+
+// hostAddress - host with the SSH port if none is given.
+func hostAddress(host string) string {
+	if _, _, e := net.SplitHostPort(host); e == nil {
+		return host
+	}
+	return net.JoinHostPort(host, "22")
+}
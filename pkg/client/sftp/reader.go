@@ -0,0 +1,149 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sftp
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	pkgsftp "github.com/pkg/sftp"
+)
+
+// fileReader is an io.ReadSeeker over a remote file. The file is opened
+// lazily upon first Read() and closed once the end is reached.
+type fileReader struct {
+	mutex *sync.Mutex
+
+	conn   *pkgsftp.Client
+	path   string
+	offset int64
+	length int64
+	start  int64
+	file   *pkgsftp.File
+	// Set once the end was reached, until the next Seek.
+	eof bool
+}
+
+// newFileReader - reader for [offset, offset+length) of a file, length '0' reads till the end.
+func newFileReader(conn *pkgsftp.Client, path string, offset, length int64) *fileReader {
+	return &fileReader{
+		mutex:  new(sync.Mutex),
+		conn:   conn,
+		path:   path,
+		offset: offset,
+		start:  offset,
+		length: length,
+	}
+}
+
+// open - open the file at the current offset.
+func (r *fileReader) open() error {
+	file, e := r.conn.Open(r.path)
+	if e != nil {
+		return e
+	}
+	if _, e = file.Seek(r.offset, 0); e != nil {
+		file.Close()
+		return e
+	}
+	r.file = file
+	return nil
+}
+
+// Read reads up to len(p) bytes into p.
+func (r *fileReader) Read(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.eof {
+		return 0, io.EOF
+	}
+	if r.length > 0 {
+		remaining := r.start + r.length - r.offset
+		if remaining <= 0 {
+			r.closeFile()
+			r.eof = true
+			return 0, io.EOF
+		}
+		if int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Read(p)
+	r.offset += int64(n)
+	if err != nil {
+		r.closeFile()
+		if err == io.EOF {
+			r.eof = true
+			// Data is handed over first, the next Read reports the end.
+			if n > 0 {
+				return n, nil
+			}
+		}
+		return n, err
+	}
+	return n, nil
+}
+
+// Seek sets the offset for the next Read, only whence '0' and '1' are supported.
+func (r *fileReader) Seek(offset int64, whence int) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	switch whence {
+	case 0:
+		offset = r.start + offset
+	case 1:
+		offset = r.offset + offset
+	default:
+		return 0, errors.New("fileReader: seeking relative to the end is not supported")
+	}
+	if offset < r.start {
+		return 0, errors.New("fileReader: negative position")
+	}
+	if offset != r.offset {
+		// Open files follow the new offset, closed ones are re-opened at it.
+		if r.file != nil {
+			if _, e := r.file.Seek(offset, 0); e != nil {
+				return 0, e
+			}
+		}
+		r.eof = false
+	}
+	r.offset = offset
+	return offset - r.start, nil
+}
+
+// Close - close the remote file if open.
+func (r *fileReader) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.closeFile()
+	return nil
+}
+
+// closeFile - release the handle of the remote file.
+func (r *fileReader) closeFile() {
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+}
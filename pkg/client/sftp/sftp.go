@@ -0,0 +1,666 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package sftp implements a client for files on SSH file transfer servers.
+package sftp
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+	pkgsftp "github.com/pkg/sftp"
+)
+
+// partSuffix - files are uploaded under this suffix and renamed in place
+// once complete, so that readers never see partial files.
+const partSuffix = ".part.mc"
+
+// sftpClient - client of an SFTP server, keys are used as user name and
+// password, or a private key file of config is used.
+type sftpClient struct {
+	hostURL *client.URL
+	config  *client.Config
+	address string
+	user    string
+}
+
+// New - instantiate a new sftp client.
+func New(config *client.Config) (client.Client, *probe.Error) {
+	hostURL := client.NewURL(config.HostURL)
+	if hostURL.Type != client.SFTP {
+		return nil, probe.NewError(errors.New("Not an SFTP URL ‘" + config.HostURL + "’."))
+	}
+	// Users of the URL take precedence over configured ones.
+	user := hostURL.User
+	if user == "" {
+		user = config.AccessKeyID
+	}
+	if user == "" {
+		user = currentUser()
+	}
+	return &sftpClient{
+		hostURL: hostURL,
+		config:  config,
+		address: hostAddress(hostURL.Host),
+		user:    user,
+	}, nil
+}
+
+// GetURL get url.
+func (c *sftpClient) GetURL() client.URL {
+	return *c.hostURL
+}
+
+// remotePath - path on the server of a URL path, paths under ‘/~/’ are
+// sent relative, servers resolve them in the home folder of the user.
+func remotePath(urlPath string) string {
+	if urlPath == "/~" || strings.HasPrefix(urlPath, "/~/") {
+		if p := strings.TrimPrefix(strings.TrimPrefix(urlPath, "/~"), "/"); p != "" {
+			return p
+		}
+		return "."
+	}
+	return urlPath
+}
+
+// contentURL - URL of a path on the server as reported in contents.
+func (c *sftpClient) contentURL(urlPath string) client.URL {
+	contentURL := *c.hostURL
+	contentURL.Path = urlPath
+	return contentURL
+}
+
+// toClientError - translate errors of the server into client errors.
+func (c *sftpClient) toClientError(e error, urlPath string) *probe.Error {
+	if os.IsNotExist(e) {
+		return probe.NewError(client.PathNotFound{Path: c.contentURL(urlPath).String()})
+	}
+	if os.IsPermission(e) {
+		return probe.NewError(client.PathInsufficientPermission{Path: c.contentURL(urlPath).String()})
+	}
+	if status, ok := e.(*pkgsftp.StatusError); ok && status.Code == statusPermissionDenied {
+		return probe.NewError(client.PathInsufficientPermission{Path: c.contentURL(urlPath).String()})
+	}
+	return probe.NewError(e)
+}
+
+// statusPermissionDenied - SSH_FX_PERMISSION_DENIED status code of replies.
+const statusPermissionDenied = 3
+
+// isNotFound - err is a missing path.
+func isNotFound(err *probe.Error) bool {
+	_, ok := err.ToGoError().(client.PathNotFound)
+	return ok
+}
+
+// stat - content of a URL path, symbolic links are followed.
+func (c *sftpClient) stat(conn *pkgsftp.Client, urlPath string) (*client.Content, *probe.Error) {
+	st, e := conn.Stat(remotePath(urlPath))
+	if e != nil {
+		return nil, c.toClientError(e, urlPath)
+	}
+	return c.fileContent(urlPath, st), nil
+}
+
+// fileContent - content of a file or folder, folders end with a separator.
+func (c *sftpClient) fileContent(urlPath string, st os.FileInfo) *client.Content {
+	if st.IsDir() && !strings.HasSuffix(urlPath, "/") {
+		urlPath = urlPath + "/"
+	}
+	return &client.Content{
+		URL:  c.contentURL(urlPath),
+		Time: st.ModTime(),
+		Size: st.Size(),
+		Type: st.Mode(),
+	}
+}
+
+// Stat - get metadata of a file or folder, file attributes are reported
+// as metadata.
+func (c *sftpClient) Stat(ctx context.Context) (*client.Content, *probe.Error) {
+	conn, err := c.connect()
+	if err != nil {
+		return nil, err.Trace(c.hostURL.String())
+	}
+	st, e := conn.Stat(remotePath(c.hostURL.Path))
+	if e != nil {
+		err := c.toClientError(e, c.hostURL.Path)
+		return nil, err.Trace(c.hostURL.String())
+	}
+	content := c.fileContent(c.hostURL.Path, st)
+	content.URL = *c.hostURL
+	content.Metadata = fileAttributes(st)
+	return content, nil
+}
+
+// fileAttributes - mode, ownership and times of a file as metadata.
+func fileAttributes(st os.FileInfo) map[string]string {
+	metadata := map[string]string{
+		client.MetadataMode:  strconv.FormatUint(uint64(st.Mode().Perm()), 8),
+		client.MetadataMtime: st.ModTime().UTC().Format(time.RFC3339Nano),
+	}
+	if sys, ok := st.Sys().(*pkgsftp.FileStat); ok {
+		metadata[client.MetadataUID] = strconv.FormatUint(uint64(sys.UID), 10)
+		metadata[client.MetadataGID] = strconv.FormatUint(uint64(sys.GID), 10)
+		atime := time.Unix(int64(sys.Atime), 0)
+		metadata[client.MetadataAtime] = atime.UTC().Format(time.RFC3339Nano)
+	}
+	return metadata
+}
+
+// List - list a file or folder as itself, entries of a folder given with a
+// trailing separator, or entries of the parent folder matching the name
+// otherwise. Recursive listings contain files only. Incomplete listings
+// contain files still being uploaded, under the name they are uploaded to.
+func (c *sftpClient) List(ctx context.Context, recursive, incomplete bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content)
+	go func() {
+		defer close(contentCh)
+		conn, err := c.connect()
+		if err != nil {
+			contentCh <- &client.Content{Err: err.Trace(c.hostURL.String())}
+			return
+		}
+		folderPath := c.hostURL.Path
+		if strings.HasSuffix(folderPath, "/") {
+			// Missing folders are empty, as prefixes of object storage are.
+			if _, err := c.stat(conn, folderPath); err != nil {
+				if !isNotFound(err) {
+					contentCh <- &client.Content{Err: err.Trace(c.hostURL.String())}
+				}
+				return
+			}
+			c.listFolder(ctx, conn, folderPath, "", recursive, incomplete, contentCh)
+			return
+		}
+		content, err := c.stat(conn, folderPath)
+		if err == nil && !incomplete {
+			if recursive && content.Type.IsDir() {
+				c.listFolder(ctx, conn, folderPath+"/", "", recursive, incomplete, contentCh)
+				return
+			}
+			contentCh <- content
+			return
+		}
+		if err != nil && !isNotFound(err) {
+			contentCh <- &client.Content{Err: err.Trace(c.hostURL.String())}
+			return
+		}
+		// Names are matched as prefixes within their folder.
+		if !c.listFolder(ctx, conn, path.Dir(folderPath)+"/", folderPath, recursive, incomplete, contentCh) && !incomplete {
+			contentCh <- &client.Content{Err: probe.NewError(client.PathNotFound{Path: c.hostURL.String()})}
+		}
+	}()
+	return client.ForwardContents(ctx, contentCh)
+}
+
+// listFolder - send entries of a folder starting with prefix, and entries
+// of sub-folders instead of the folders themselves if recursive. Reports
+// whether anything was sent.
+func (c *sftpClient) listFolder(ctx context.Context, conn *pkgsftp.Client, folderPath, prefix string, recursive, incomplete bool, contentCh chan<- *client.Content) bool {
+	entries, e := conn.ReadDir(remotePath(folderPath))
+	if e != nil {
+		err := c.toClientError(e, folderPath)
+		contentCh <- &client.Content{Err: err.Trace(c.contentURL(folderPath).String())}
+		return true
+	}
+	sent := false
+	for _, entry := range entries {
+		// Folders are not walked any further once cancelled.
+		if ctx.Err() != nil {
+			break
+		}
+		entryPath := folderPath + entry.Name()
+		if !strings.HasPrefix(entryPath, prefix) {
+			continue
+		}
+		// Links are listed as what they point to, broken ones as links.
+		if entry.Mode()&os.ModeSymlink != 0 {
+			if st, e := conn.Stat(remotePath(entryPath)); e == nil {
+				entry = st
+			}
+		}
+		if entry.IsDir() {
+			if recursive {
+				if c.listFolder(ctx, conn, entryPath+"/", "", recursive, incomplete, contentCh) {
+					sent = true
+				}
+				continue
+			}
+			if incomplete {
+				continue
+			}
+		} else if strings.HasSuffix(entryPath, partSuffix) != incomplete {
+			continue
+		}
+		content := c.fileContent(strings.TrimSuffix(entryPath, partSuffix), entry)
+		contentCh <- content
+		sent = true
+	}
+	return sent
+}
+
+// Get - read a file, ranges are read by seeking the remote file.
+func (c *sftpClient) Get(ctx context.Context, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	if offset < 0 || length < 0 {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	conn, err := c.connect()
+	if err != nil {
+		return nil, err.Trace(c.hostURL.String())
+	}
+	content, err := c.stat(conn, c.hostURL.Path)
+	if err != nil {
+		return nil, err.Trace(c.hostURL.String())
+	}
+	if content.Type.IsDir() {
+		return nil, probe.NewError(client.PathIsDir{Path: c.hostURL.String()})
+	}
+	if offset > content.Size {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	return newFileReader(conn, remotePath(c.hostURL.Path), offset, length), nil
+}
+
+// Put - upload a file.
+func (c *sftpClient) Put(ctx context.Context, data io.ReadSeeker, size int64) *probe.Error {
+	return c.PutWithMetadata(ctx, data, size, nil)
+}
+
+// PutWithMetadata - upload a file under a temporary name and rename it in
+// place once complete and verified, folders leading to it are created as
+// needed. Mode and times found in metadata are restored, ownership is not,
+// users on the server are unrelated to local ones.
+func (c *sftpClient) PutWithMetadata(ctx context.Context, data io.ReadSeeker, size int64, metadata map[string]string) *probe.Error {
+	if _, ok := metadata[client.MetadataTags]; ok {
+		return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "sftp"})
+	}
+	filePath := c.hostURL.Path
+	if strings.HasSuffix(filePath, "/") {
+		return probe.NewError(client.PathIsDir{Path: c.hostURL.String()})
+	}
+	conn, err := c.connect()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	if content, err := c.stat(conn, filePath); err == nil && content.Type.IsDir() {
+		return probe.NewError(client.PathIsDir{Path: c.hostURL.String()})
+	}
+	if err := c.mkdirAll(conn, path.Dir(filePath)); err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	partPath := filePath + partSuffix
+	// Leftovers of interrupted uploads are replaced.
+	if e := conn.Remove(remotePath(partPath)); e != nil && !os.IsNotExist(e) {
+		err := c.toClientError(e, partPath)
+		return err.Trace(c.hostURL.String())
+	}
+	// Preserved links carry no data, only their target.
+	if target, ok := metadata[client.MetadataSymlink]; ok {
+		if e := conn.Symlink(target, remotePath(partPath)); e != nil {
+			err := c.toClientError(e, partPath)
+			return err.Trace(c.hostURL.String(), target)
+		}
+		return c.rename(conn, partPath, filePath)
+	}
+	if err := c.putFile(conn, partPath, client.ContextReader(ctx, data), size, metadata); err != nil {
+		conn.Remove(remotePath(partPath))
+		return err.Trace(c.hostURL.String())
+	}
+	if err := applyAttributes(conn, remotePath(partPath), metadata); err != nil {
+		conn.Remove(remotePath(partPath))
+		return err.Trace(c.hostURL.String())
+	}
+	return c.rename(conn, partPath, filePath)
+}
+
+// Copy - files are copied by reading them.
+func (c *sftpClient) Copy(ctx context.Context, source client.URL, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "Copy", APIType: "sftp"})
+}
+
+// putFile - write data to a new file, verified against the checksum of metadata if any.
+func (c *sftpClient) putFile(conn *pkgsftp.Client, partPath string, data io.Reader, size int64, metadata map[string]string) *probe.Error {
+	file, e := conn.OpenFile(remotePath(partPath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if e != nil {
+		return c.toClientError(e, partPath)
+	}
+	hasher := md5.New()
+	data = io.TeeReader(data, hasher)
+	if size < 0 { // Read till EOF.
+		_, e = io.Copy(file, data)
+	} else { // Read till N bytes.
+		_, e = io.CopyN(file, data, size)
+	}
+	if e != nil {
+		file.Close()
+		return c.toClientError(e, partPath)
+	}
+	if e = file.Close(); e != nil {
+		return c.toClientError(e, partPath)
+	}
+	expected, ok := metadata[client.MetadataMD5]
+	if !ok {
+		return nil
+	}
+	if computed := hex.EncodeToString(hasher.Sum(nil)); computed != expected {
+		return probe.NewError(client.ChecksumMismatch{Path: c.hostURL.String(), Expected: expected, Computed: computed})
+	}
+	return nil
+}
+
+// applyAttributes - set mode and times of a file from metadata,
+// attributes missing from metadata are left as is.
+func applyAttributes(conn *pkgsftp.Client, remote string, metadata map[string]string) *probe.Error {
+	if value, ok := metadata[client.MetadataMode]; ok {
+		mode, e := strconv.ParseUint(value, 8, 32)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		if e = conn.Chmod(remote, os.FileMode(mode).Perm()); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	mtimeValue, ok := metadata[client.MetadataMtime]
+	if !ok {
+		return nil
+	}
+	mtime, e := time.Parse(time.RFC3339Nano, mtimeValue)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	// Without a recorded access time, it is set to the modification time.
+	atime := mtime
+	if atimeValue, ok := metadata[client.MetadataAtime]; ok {
+		if atime, e = time.Parse(time.RFC3339Nano, atimeValue); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	if e = conn.Chtimes(remote, atime, mtime); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// rename - move a complete upload in place. Servers refuse to rename over
+// existing files, those are removed first.
+func (c *sftpClient) rename(conn *pkgsftp.Client, partPath, filePath string) *probe.Error {
+	e := conn.Rename(remotePath(partPath), remotePath(filePath))
+	if e == nil {
+		return nil
+	}
+	if _, se := conn.Lstat(remotePath(filePath)); se == nil {
+		if e = conn.Remove(remotePath(filePath)); e == nil {
+			e = conn.Rename(remotePath(partPath), remotePath(filePath))
+		}
+	}
+	if e != nil {
+		conn.Remove(remotePath(partPath))
+		err := c.toClientError(e, filePath)
+		return err.Trace(c.hostURL.String())
+	}
+	return nil
+}
+
+// mkdirAll - create a folder along with folders leading to it, existing
+// ones are left as is.
+func (c *sftpClient) mkdirAll(conn *pkgsftp.Client, folderPath string) *probe.Error {
+	if folderPath == "/" || folderPath == "." || folderPath == "/~" {
+		return nil
+	}
+	st, e := conn.Stat(remotePath(folderPath))
+	if e == nil {
+		if !st.IsDir() {
+			return probe.NewError(errors.New("‘" + c.contentURL(folderPath).String() + "’ is not a folder."))
+		}
+		return nil
+	}
+	if !os.IsNotExist(e) {
+		return c.toClientError(e, folderPath)
+	}
+	if err := c.mkdirAll(conn, path.Dir(folderPath)); err != nil {
+		return err.Trace(folderPath)
+	}
+	if e = conn.Mkdir(remotePath(folderPath)); e != nil {
+		// Folders created by concurrent uploads are fine.
+		if st, se := conn.Stat(remotePath(folderPath)); se == nil && st.IsDir() {
+			return nil
+		}
+		return c.toClientError(e, folderPath)
+	}
+	return nil
+}
+
+// MakeBucket - create the folder, along with folders leading to it.
+func (c *sftpClient) MakeBucket(ctx context.Context) *probe.Error {
+	folderPath := strings.TrimSuffix(c.hostURL.Path, "/")
+	if folderPath == "" || folderPath == "/~" {
+		return probe.NewError(client.BucketNameEmpty{})
+	}
+	conn, err := c.connect()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	if _, e := conn.Stat(remotePath(folderPath)); e == nil {
+		return probe.NewError(client.BucketExists{Bucket: c.hostURL.String()})
+	}
+	if err := c.mkdirAll(conn, folderPath); err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	return nil
+}
+
+// Remove - remove a file or an empty folder, or the upload in progress to
+// a file if incomplete.
+func (c *sftpClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	conn, err := c.connect()
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	filePath := strings.TrimSuffix(c.hostURL.Path, "/")
+	if incomplete {
+		filePath = filePath + partSuffix
+	}
+	if e := conn.Remove(remotePath(filePath)); e != nil {
+		err := c.toClientError(e, filePath)
+		return err.Trace(c.hostURL.String())
+	}
+	return nil
+}
+
+// RemoveBatch - remove all files received on contentCh, each file is sent
+// back with Err set if it could not be removed.
+func (c *sftpClient) RemoveBatch(ctx context.Context, contentCh <-chan *client.Content) <-chan *client.Content {
+	resultCh := make(chan *client.Content)
+	go func() {
+		defer close(resultCh)
+		conn, err := c.connect()
+		for content := range contentCh {
+			switch {
+			case err != nil:
+				content.Err = err.Trace(content.URL.String())
+			case ctx.Err() != nil:
+				content.Err = probe.NewError(ctx.Err())
+			case content.Type.IsDir():
+				content.Err = probe.NewError(client.PathIsDir{Path: content.URL.String()})
+			default:
+				if e := conn.Remove(remotePath(content.URL.Path)); e != nil {
+					content.Err = c.toClientError(e, content.URL.Path).Trace(content.URL.String())
+				}
+			}
+			resultCh <- content
+		}
+	}()
+	return client.ForwardContents(ctx, resultCh)
+}
+
+// GetBucketAccess - access policies not implemented for SFTP servers.
+func (c *sftpClient) GetBucketAccess(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetBucketAccess", APIType: "sftp"})
+}
+
+// SetBucketAccess - access policies not implemented for SFTP servers.
+func (c *sftpClient) SetBucketAccess(ctx context.Context, access string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetBucketAccess", APIType: "sftp"})
+}
+
+// ShareDownload - sharing not implemented for SFTP servers.
+func (c *sftpClient) ShareDownload(ctx context.Context, expires time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "ShareDownload", APIType: "sftp"})
+}
+
+// ShareUpload - sharing not implemented for SFTP servers.
+func (c *sftpClient) ShareUpload(ctx context.Context, recursive bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "ShareUpload", APIType: "sftp"})
+}
+
+// GetVersioning - versioning not implemented for SFTP servers.
+func (c *sftpClient) GetVersioning(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetVersioning", APIType: "sftp"})
+}
+
+// SetVersioning - versioning not implemented for SFTP servers.
+func (c *sftpClient) SetVersioning(ctx context.Context, status string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetVersioning", APIType: "sftp"})
+}
+
+// ListVersions - versioning not implemented for SFTP servers.
+func (c *sftpClient) ListVersions(ctx context.Context, recursive bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content, 1)
+	contentCh <- &client.Content{Err: probe.NewError(client.APINotImplemented{API: "ListVersions", APIType: "sftp"})}
+	close(contentCh)
+	return contentCh
+}
+
+// StatVersion - versioning not implemented for SFTP servers.
+func (c *sftpClient) StatVersion(ctx context.Context, versionID string) (*client.Content, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "StatVersion", APIType: "sftp"})
+}
+
+// GetVersion - versioning not implemented for SFTP servers.
+func (c *sftpClient) GetVersion(ctx context.Context, versionID string, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetVersion", APIType: "sftp"})
+}
+
+// RemoveVersion - versioning not implemented for SFTP servers.
+func (c *sftpClient) RemoveVersion(ctx context.Context, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveVersion", APIType: "sftp"})
+}
+
+// GetTags - tagging not implemented for SFTP servers.
+func (c *sftpClient) GetTags(ctx context.Context) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetTags", APIType: "sftp"})
+}
+
+// SetTags - tagging not implemented for SFTP servers.
+func (c *sftpClient) SetTags(ctx context.Context, tags map[string]string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "sftp"})
+}
+
+// DeleteTags - tagging not implemented for SFTP servers.
+func (c *sftpClient) DeleteTags(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteTags", APIType: "sftp"})
+}
+
+// MakeBucketWithLock - object lock not implemented for SFTP servers.
+func (c *sftpClient) MakeBucketWithLock(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "MakeBucketWithLock", APIType: "sftp"})
+}
+
+// GetRetention - object lock not implemented for SFTP servers.
+func (c *sftpClient) GetRetention(ctx context.Context) (client.Retention, *probe.Error) {
+	return client.Retention{}, probe.NewError(client.APINotImplemented{API: "GetRetention", APIType: "sftp"})
+}
+
+// SetRetention - object lock not implemented for SFTP servers.
+func (c *sftpClient) SetRetention(ctx context.Context, retention client.Retention) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetRetention", APIType: "sftp"})
+}
+
+// GetLegalHold - object lock not implemented for SFTP servers.
+func (c *sftpClient) GetLegalHold(ctx context.Context) (bool, *probe.Error) {
+	return false, probe.NewError(client.APINotImplemented{API: "GetLegalHold", APIType: "sftp"})
+}
+
+// SetLegalHold - object lock not implemented for SFTP servers.
+func (c *sftpClient) SetLegalHold(ctx context.Context, on bool) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetLegalHold", APIType: "sftp"})
+}
+
+// GetNotifications - notifications not implemented for SFTP servers.
+func (c *sftpClient) GetNotifications(ctx context.Context) ([]client.NotificationConfig, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetNotifications", APIType: "sftp"})
+}
+
+// AddNotification - notifications not implemented for SFTP servers.
+func (c *sftpClient) AddNotification(ctx context.Context, config client.NotificationConfig) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "AddNotification", APIType: "sftp"})
+}
+
+// RemoveNotification - notifications not implemented for SFTP servers.
+func (c *sftpClient) RemoveNotification(ctx context.Context, arn string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveNotification", APIType: "sftp"})
+}
+
+// Watch - notifications not implemented for SFTP servers.
+func (c *sftpClient) Watch(ctx context.Context, events []string, recursive bool) (<-chan *client.Event, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Watch", APIType: "sftp"})
+}
+
+// GetCORS - CORS configuration not implemented for SFTP servers.
+func (c *sftpClient) GetCORS(ctx context.Context) ([]client.CORSRule, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetCORS", APIType: "sftp"})
+}
+
+// SetCORS - CORS configuration not implemented for SFTP servers.
+func (c *sftpClient) SetCORS(ctx context.Context, rules []client.CORSRule) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetCORS", APIType: "sftp"})
+}
+
+// DeleteCORS - CORS configuration not implemented for SFTP servers.
+func (c *sftpClient) DeleteCORS(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteCORS", APIType: "sftp"})
+}
+
+// GetWebsite - static website configuration not implemented for SFTP servers.
+func (c *sftpClient) GetWebsite(ctx context.Context) (client.Website, *probe.Error) {
+	return client.Website{}, probe.NewError(client.APINotImplemented{API: "GetWebsite", APIType: "sftp"})
+}
+
+// SetWebsite - static website configuration not implemented for SFTP servers.
+func (c *sftpClient) SetWebsite(ctx context.Context, website client.Website) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetWebsite", APIType: "sftp"})
+}
+
+// DeleteWebsite - static website configuration not implemented for SFTP servers.
+func (c *sftpClient) DeleteWebsite(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteWebsite", APIType: "sftp"})
+}
+
+// Select - queries are not implemented for SFTP servers, the caller evaluates them.
+func (c *sftpClient) Select(ctx context.Context, query client.SelectQuery) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Select", APIType: "sftp"})
+}
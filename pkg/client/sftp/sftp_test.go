@@ -0,0 +1,223 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sftp
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/client"
+	pkgsftp "github.com/pkg/sftp"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// newTestServer - an SFTP server of the local file system, connected over
+// pipes as the session of user on host, with a folder holding a file and
+// a sub-folder.
+func newTestServer(c *C, host string) (root string, done func()) {
+	root, e := ioutil.TempDir(os.TempDir(), "sftp-")
+	c.Assert(e, IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(root, "share", "parts"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "share", "README"), []byte("hello world"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "share", "parts", "part.1"), []byte("part"), 0600), IsNil)
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	server, e := pkgsftp.NewServer(struct {
+		io.Reader
+		io.WriteCloser
+	}{serverReader, serverWriter})
+	c.Assert(e, IsNil)
+	go server.Serve()
+	conn, e := pkgsftp.NewClientPipe(clientReader, clientWriter)
+	c.Assert(e, IsNil)
+
+	key := "user@" + hostAddress(host)
+	connections.Lock()
+	connections.m[key] = conn
+	connections.Unlock()
+	return root, func() {
+		connections.Lock()
+		delete(connections.m, key)
+		connections.Unlock()
+		// The client waits for replies until the server hangs up.
+		serverWriter.Close()
+		conn.Close()
+		os.RemoveAll(root)
+	}
+}
+
+// newTestClient - client of a path on host.
+func newTestClient(c *C, host, path string) client.Client {
+	clnt, err := New(&client.Config{HostURL: "sftp://user@" + host + filepath.ToSlash(path)})
+	c.Assert(err, IsNil)
+	return clnt
+}
+
+// listPaths - paths listed by a client.
+func listPaths(c *C, clnt client.Client, recursive, incomplete bool) []string {
+	var paths []string
+	for content := range clnt.List(context.Background(), recursive, incomplete) {
+		c.Assert(content.Err, IsNil)
+		paths = append(paths, content.URL.Path)
+	}
+	return paths
+}
+
+func (s *MySuite) TestRemotePath(c *C) {
+	c.Assert(remotePath("/srv/drop/file"), Equals, "/srv/drop/file")
+	c.Assert(remotePath("/~/drop/file"), Equals, "drop/file")
+	c.Assert(remotePath("/~/"), Equals, ".")
+	c.Assert(remotePath("/~"), Equals, ".")
+	c.Assert(remotePath("/~user/file"), Equals, "/~user/file")
+}
+
+func (s *MySuite) TestStatList(c *C) {
+	root, done := newTestServer(c, "list.example.com")
+	defer done()
+	share := filepath.ToSlash(filepath.Join(root, "share"))
+
+	content, err := newTestClient(c, "list.example.com", share+"/README").Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(len("hello world")))
+	c.Assert(content.Type.IsRegular(), Equals, true)
+	c.Assert(content.Metadata[client.MetadataMode], Equals, "600")
+
+	content, err = newTestClient(c, "list.example.com", share+"/parts").Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+
+	_, err = newTestClient(c, "list.example.com", share+"/missing").Stat(context.Background())
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.PathNotFound)
+	c.Assert(ok, Equals, true)
+
+	c.Assert(listPaths(c, newTestClient(c, "list.example.com", share+"/"), false, false), DeepEquals, []string{share + "/README", share + "/parts/"})
+	c.Assert(listPaths(c, newTestClient(c, "list.example.com", share+"/"), true, false), DeepEquals, []string{share + "/README", share + "/parts/part.1"})
+	c.Assert(listPaths(c, newTestClient(c, "list.example.com", share+"/RE"), false, false), DeepEquals, []string{share + "/README"})
+	c.Assert(listPaths(c, newTestClient(c, "list.example.com", share+"/missing/"), true, false), IsNil)
+
+	// Uploads in progress are only listed as incomplete.
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "share", "upload"+partSuffix), []byte("up"), 0600), IsNil)
+	c.Assert(listPaths(c, newTestClient(c, "list.example.com", share+"/"), false, false), DeepEquals, []string{share + "/README", share + "/parts/"})
+	c.Assert(listPaths(c, newTestClient(c, "list.example.com", share+"/"), true, true), DeepEquals, []string{share + "/upload"})
+	c.Assert(newTestClient(c, "list.example.com", share+"/upload").Remove(context.Background(), true), IsNil)
+	c.Assert(listPaths(c, newTestClient(c, "list.example.com", share+"/"), true, true), IsNil)
+}
+
+func (s *MySuite) TestGetRange(c *C) {
+	root, done := newTestServer(c, "get.example.com")
+	defer done()
+	file := filepath.Join(root, "share", "README")
+
+	reader, err := newTestClient(c, "get.example.com", file).Get(context.Background(), 6, 5)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "world")
+
+	// Reading past the end keeps reporting the end, seeking back reads again.
+	reader, err = newTestClient(c, "get.example.com", file).Get(context.Background(), 0, 0)
+	c.Assert(err, IsNil)
+	data, e = ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "hello world")
+	n, e := reader.Read(make([]byte, 1))
+	c.Assert(n, Equals, 0)
+	c.Assert(e, Equals, io.EOF)
+	_, e = reader.Seek(6, 0)
+	c.Assert(e, IsNil)
+	data, e = ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "world")
+
+	_, err = newTestClient(c, "get.example.com", filepath.Join(root, "share", "parts")).Get(context.Background(), 0, 0)
+	_, ok := err.ToGoError().(client.PathIsDir)
+	c.Assert(ok, Equals, true)
+}
+
+func (s *MySuite) TestPutMakeBucketRemove(c *C) {
+	root, done := newTestServer(c, "put.example.com")
+	defer done()
+
+	// Folders leading to a file are created along with it, attributes are restored.
+	data := []byte("hello")
+	sum := md5.Sum(data)
+	file := filepath.Join(root, "share", "new", "folder", "object")
+	clnt := newTestClient(c, "put.example.com", file)
+	metadata := map[string]string{
+		client.MetadataMD5:   hex.EncodeToString(sum[:]),
+		client.MetadataMode:  "640",
+		client.MetadataMtime: "2016-01-02T03:04:05Z",
+	}
+	c.Assert(clnt.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), metadata), IsNil)
+	written, e := ioutil.ReadFile(file)
+	c.Assert(e, IsNil)
+	c.Assert(string(written), Equals, "hello")
+	st, e := os.Stat(file)
+	c.Assert(e, IsNil)
+	c.Assert(st.Mode().Perm(), Equals, os.FileMode(0640))
+	c.Assert(st.ModTime().UTC().Format("2006-01-02T15:04:05Z"), Equals, "2016-01-02T03:04:05Z")
+
+	// Existing files are replaced, no upload is left behind.
+	c.Assert(clnt.Put(context.Background(), bytes.NewReader([]byte("hello again")), -1), IsNil)
+	written, e = ioutil.ReadFile(file)
+	c.Assert(e, IsNil)
+	c.Assert(string(written), Equals, "hello again")
+	_, e = os.Stat(file + partSuffix)
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	// Files failing verification never replace existing ones.
+	err := clnt.PutWithMetadata(context.Background(), bytes.NewReader(data), -1, map[string]string{client.MetadataMD5: hex.EncodeToString(make([]byte, md5.Size))})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.ChecksumMismatch)
+	c.Assert(ok, Equals, true)
+	written, e = ioutil.ReadFile(file)
+	c.Assert(e, IsNil)
+	c.Assert(string(written), Equals, "hello again")
+	_, e = os.Stat(file + partSuffix)
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	bucket := filepath.Join(root, "share", "bucket")
+	c.Assert(newTestClient(c, "put.example.com", bucket).MakeBucket(context.Background()), IsNil)
+	err = newTestClient(c, "put.example.com", bucket).MakeBucket(context.Background())
+	c.Assert(err, Not(IsNil))
+	_, ok = err.ToGoError().(client.BucketExists)
+	c.Assert(ok, Equals, true)
+
+	// Folders are only removed once empty.
+	folder := filepath.Join(root, "share", "new", "folder") + "/"
+	c.Assert(newTestClient(c, "put.example.com", folder).Remove(context.Background(), false), Not(IsNil))
+	c.Assert(clnt.Remove(context.Background(), false), IsNil)
+	c.Assert(newTestClient(c, "put.example.com", folder).Remove(context.Background(), false), IsNil)
+	_, e = os.Stat(folder)
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
@@ -28,6 +28,7 @@ import (
 type URL struct {
 	Type            URLType
 	Scheme          string
+	User            string
 	Host            string
 	Path            string
 	SchemeSeparator string
@@ -41,8 +42,23 @@ type URLType int
 const (
 	Object     = iota // Minio and S3 compatible cloud storage
 	Filesystem        // POSIX compatible file systems
+	HTTP              // Read-only web servers
+	SFTP              // SSH file transfer servers
 )
 
+// HTTPSchemePrefix - prefix of the scheme of URLs read from web servers
+// instead of object storage, as in web+https://example.com/file.tar.gz
+const HTTPSchemePrefix = "web+"
+
+// MemoryScheme - scheme of object storage URLs kept in memory by this
+// process, as in mem://bucket/object
+const MemoryScheme = "mem"
+
+// SFTPScheme - scheme of URLs of SSH file transfer servers, as in
+// sftp://user@example.com/path, paths starting with ‘/~/’ are relative to
+// the home folder of the user.
+const SFTPScheme = "sftp"
+
 // Maybe rawurl is of the form scheme:path. (Scheme must be [a-zA-Z][a-zA-Z0-9+-.]*)
 // If so, return scheme, path; else return "", rawurl.
 func getScheme(rawurl string) (scheme, path string) {
@@ -50,7 +66,7 @@ func getScheme(rawurl string) (scheme, path string) {
 	if len(urlSplits) == 2 {
 		scheme, uri := urlSplits[0], "//"+urlSplits[1]
 		// ignore numbers in scheme
-		validScheme := regexp.MustCompile("^[a-zA-Z]+$")
+		validScheme := regexp.MustCompile(`^(web\+)?[a-zA-Z]+$`)
 		if uri != "" {
 			if validScheme.MatchString(scheme) {
 				return scheme, uri
@@ -77,20 +93,31 @@ func splitSpecial(s string, delimiter string, cutdelimiter bool) (string, string
 	return s[0:i], s[i:]
 }
 
-// getHost - extract host from authority string, we do not support ftp style username@ yet.
-func getHost(authority string) (host string) {
+// getHost - extract user and host from authority string, passwords of
+// username:password style userinfo are dropped.
+func getHost(authority string) (user, host string) {
 	i := strings.LastIndex(authority, "@")
 	if i >= 0 {
-		// TODO support, username@password style userinfo, useful for ftp support.
-		return
+		user, _ = splitSpecial(authority[:i], ":", true)
+		return user, authority[i+1:]
 	}
-	return authority
+	return "", authority
 }
 
 // NewURL returns an abstracted URL for filesystems and object storage.
 func NewURL(urlStr string) *URL {
 	scheme, rest := getScheme(urlStr)
 	rest, _ = splitSpecial(rest, "?", true)
+	// In-memory storage has no host, buckets follow the scheme separator.
+	if scheme == MemoryScheme && strings.HasPrefix(rest, "//") {
+		return &URL{
+			Scheme:          scheme,
+			Type:            Object,
+			Path:            "/" + rest[2:],
+			SchemeSeparator: "://",
+			Separator:       '/',
+		}
+	}
 	if strings.HasPrefix(rest, "//") {
 		// if rest has '//' prefix, skip them
 		var authority string
@@ -98,7 +125,32 @@ func NewURL(urlStr string) *URL {
 		if rest == "" {
 			rest = "/"
 		}
-		host := getHost(authority)
+		user, host := getHost(authority)
+		if host != "" && scheme == SFTPScheme {
+			return &URL{
+				Scheme:          scheme,
+				Type:            SFTP,
+				User:            user,
+				Host:            host,
+				Path:            rest,
+				SchemeSeparator: "://",
+				Separator:       '/',
+			}
+		}
+		// Only SFTP takes userinfo.
+		if user != "" {
+			host = ""
+		}
+		if host != "" && (scheme == HTTPSchemePrefix+"http" || scheme == HTTPSchemePrefix+"https") {
+			return &URL{
+				Scheme:          strings.TrimPrefix(scheme, HTTPSchemePrefix),
+				Type:            HTTP,
+				Host:            host,
+				Path:            rest,
+				SchemeSeparator: "://",
+				Separator:       '/',
+			}
+		}
 		if host != "" && (scheme == "http" || scheme == "https") {
 			return &URL{
 				Scheme:          scheme,
@@ -126,7 +178,7 @@ func JoinURLs(url1, url2 *URL) *URL {
 		url1Path = strings.Replace(url1.Path, "\\", "/", -1)
 		url2Path = strings.Replace(url2.Path, "\\", "/", -1)
 	}
-	if url1.Type == Object {
+	if url1.Type == Object || url1.Type == HTTP || url1.Type == SFTP {
 		if strings.HasSuffix(url1Path, "/") {
 			url1.Path = url1Path + strings.TrimPrefix(url2Path, "/")
 		} else {
@@ -154,10 +206,21 @@ func (u URL) String() string {
 		return u.Path
 	}
 	// if Object convert from any non standard paths to a supported URL path style.
-	if u.Type == Object {
+	if u.Type == Object || u.Type == HTTP || u.Type == SFTP {
+		if u.Type == HTTP {
+			buf.WriteString(HTTPSchemePrefix)
+		}
 		buf.WriteString(u.Scheme)
 		buf.WriteByte(':')
 		buf.WriteString("//")
+		if u.Scheme == MemoryScheme {
+			buf.WriteString(strings.TrimPrefix(u.Path, "/"))
+			return buf.String()
+		}
+		if u.User != "" {
+			buf.WriteString(u.User)
+			buf.WriteByte('@')
+		}
 		if h := u.Host; h != "" {
 			buf.WriteString(h)
 		}
@@ -0,0 +1,125 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webdav
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// propfindBody - properties asked for by PROPFIND requests.
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:">
+  <D:prop>
+    <D:resourcetype/>
+    <D:getcontentlength/>
+    <D:getlastmodified/>
+    <D:getetag/>
+    <D:getcontenttype/>
+  </D:prop>
+</D:propfind>`
+
+// multistatus - reply to a PROPFIND request, one response per file or folder.
+type multistatus struct {
+	Responses []struct {
+		Href      string `xml:"DAV: href"`
+		Propstats []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"DAV: collection"`
+				} `xml:"DAV: resourcetype"`
+				ContentLength string `xml:"DAV: getcontentlength"`
+				LastModified  string `xml:"DAV: getlastmodified"`
+				ETag          string `xml:"DAV: getetag"`
+				ContentType   string `xml:"DAV: getcontenttype"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// propfind - contents of a path and, for depth "1", of the entries of a
+// folder, in lexical order. Paths of folders end with a separator.
+func (c *webdavClient) propfind(ctx context.Context, fpath, depth string) ([]*client.Content, *probe.Error) {
+	header := make(http.Header)
+	header.Set("Depth", depth)
+	header.Set("Content-Type", "application/xml; charset=utf-8")
+	body := strings.NewReader(propfindBody)
+	resp, err := c.executeMethod(ctx, "PROPFIND", fpath, header, body, int64(body.Len()))
+	if err != nil {
+		return nil, err.Trace(fpath)
+	}
+	defer closeResponse(resp)
+	ms := new(multistatus)
+	if e := xml.NewDecoder(resp.Body).Decode(ms); e != nil {
+		return nil, probe.NewError(e)
+	}
+	var contents []*client.Content
+	for _, response := range ms.Responses {
+		href, e := url.Parse(response.Href)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		for _, propstat := range response.Propstats {
+			// Properties a server does not know are listed with status 404.
+			if !strings.Contains(propstat.Status, " 200 ") {
+				continue
+			}
+			prop := propstat.Prop
+			content := &client.Content{
+				URL:         c.contentURL(href.Path),
+				Type:        os.FileMode(0664),
+				ContentType: prop.ContentType,
+			}
+			if prop.ResourceType.Collection != nil {
+				content.Type = os.ModeDir
+				content.URL.Path = strings.TrimSuffix(href.Path, "/") + "/"
+				content.ContentType = ""
+			} else if size, e := strconv.ParseInt(prop.ContentLength, 10, 64); e == nil {
+				content.Size = size
+			}
+			if date, e := time.Parse(http.TimeFormat, prop.LastModified); e == nil {
+				content.Time = date
+			}
+			// ETags of shares are rarely checksums, only those which are reported.
+			if etag := strings.Trim(prop.ETag, `"`); client.IsMD5(etag) {
+				content.ETag = etag
+			}
+			contents = append(contents, content)
+			break
+		}
+	}
+	sort.Sort(byPath(contents))
+	return contents, nil
+}
+
+// byPath - sort contents by path, so that listings are in lexical order.
+type byPath []*client.Content
+
+func (b byPath) Len() int           { return len(b) }
+func (b byPath) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byPath) Less(i, j int) bool { return b[i].URL.Path < b[j].URL.Path }
@@ -0,0 +1,159 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webdav
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// fileReader is an io.ReadSeeker over a ranged GET request. The request
+// is sent lazily upon first Read() and re-sent after every Seek().
+type fileReader struct {
+	mutex *sync.Mutex
+
+	ctx    context.Context
+	c      *webdavClient
+	path   string
+	offset int64
+	length int64
+	start  int64
+	body   io.ReadCloser
+	// Set once the end was reached, until the next Seek.
+	eof bool
+}
+
+// newFileReader - reader for [offset, offset+length) of a file, length '0' reads till the end.
+func newFileReader(ctx context.Context, c *webdavClient, path string, offset, length int64) *fileReader {
+	return &fileReader{
+		mutex:  new(sync.Mutex),
+		ctx:    ctx,
+		c:      c,
+		path:   path,
+		offset: offset,
+		start:  offset,
+		length: length,
+	}
+}
+
+// open - send the ranged GET request starting at the current offset.
+func (r *fileReader) open() error {
+	header := make(http.Header)
+	end := int64(-1)
+	switch {
+	case r.length > 0:
+		end = r.start + r.length - 1
+		if r.offset > end {
+			return io.EOF
+		}
+		header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-"+strconv.FormatInt(end, 10))
+	case r.offset > 0:
+		header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-")
+	}
+	resp, err := r.c.executeMethod(r.ctx, "GET", r.path, header, nil, 0)
+	if err != nil {
+		return err.ToGoError()
+	}
+	var body io.Reader = resp.Body
+	// Servers without range support reply with the whole file, skip
+	// what was not asked for.
+	if resp.StatusCode != http.StatusPartialContent && header.Get("Range") != "" {
+		if _, e := io.CopyN(ioutil.Discard, resp.Body, r.offset); e != nil {
+			closeResponse(resp)
+			return e
+		}
+		if end >= 0 {
+			body = io.LimitReader(resp.Body, end-r.offset+1)
+		}
+	}
+	r.body = struct {
+		io.Reader
+		io.Closer
+	}{body, resp.Body}
+	return nil
+}
+
+// Read reads up to len(p) bytes into p.
+func (r *fileReader) Read(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.eof {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if err != nil {
+		closeResponse(&http.Response{Body: r.body})
+		r.body = nil
+		if err == io.EOF {
+			r.eof = true
+			// Data is handed over first, the next Read reports the end.
+			if n > 0 {
+				return n, nil
+			}
+		}
+		return n, err
+	}
+	return n, nil
+}
+
+// Seek sets the offset for the next Read, only whence '0' and '1' are supported.
+func (r *fileReader) Seek(offset int64, whence int) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	switch whence {
+	case 0:
+		offset = r.start + offset
+	case 1:
+		offset = r.offset + offset
+	default:
+		return 0, errors.New("fileReader: seeking relative to the end is not supported")
+	}
+	if offset < r.start {
+		return 0, errors.New("fileReader: negative position")
+	}
+	if offset != r.offset && r.body != nil {
+		closeResponse(&http.Response{Body: r.body})
+		r.body = nil
+	}
+	if offset != r.offset {
+		r.eof = false
+	}
+	r.offset = offset
+	return offset - r.start, nil
+}
+
+// Close - release the underlying connection if any.
+func (r *fileReader) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.body != nil {
+		closeResponse(&http.Response{Body: r.body})
+		r.body = nil
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webdav
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"strings"
+
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/httptracer"
+)
+
+// Trace - tracing structure
+type Trace struct{}
+
+// NewTrace - initialize Trace structure
+func NewTrace() httptracer.HTTPTracer {
+	return Trace{}
+}
+
+// Request - Trace HTTP Request, credentials of basic authentication are redacted.
+func (t Trace) Request(req *http.Request) (err error) {
+	origAuth := req.Header.Get("Authorization")
+	if strings.TrimSpace(origAuth) != "" {
+		// Set a temporary redacted auth
+		req.Header.Set("Authorization", "Basic **REDACTED**")
+	}
+
+	var reqTrace []byte
+	reqTrace, err = httputil.DumpRequestOut(req, false) // Only display header
+	if err == nil {
+		console.Debug(string(reqTrace))
+	}
+
+	// Undo
+	if strings.TrimSpace(origAuth) != "" {
+		req.Header.Set("Authorization", origAuth)
+	}
+	return err
+}
+
+// Response - Trace HTTP Response
+func (t Trace) Response(res *http.Response) (err error) {
+	resTrace, err := httputil.DumpResponse(res, false) // Only display header
+	if err == nil {
+		console.Debug(string(resTrace))
+	}
+	return err
+}
@@ -0,0 +1,540 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package webdav implements a client for files on WebDAV shares.
+package webdav
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/httptracer"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// webdavClient - client of a WebDAV share, keys are sent as user name and
+// password with basic authentication.
+type webdavClient struct {
+	hostURL   *client.URL
+	transport http.RoundTripper
+	userAgent string
+	username  string
+	password  string
+}
+
+// New - instantiate a new webdav client.
+func New(config *client.Config) (client.Client, *probe.Error) {
+	transport := http.DefaultTransport
+	if config.Debug == true {
+		transport = httptracer.GetNewTraceTransport(NewTrace(), http.DefaultTransport)
+	}
+	return &webdavClient{
+		hostURL:   client.NewURL(config.HostURL),
+		transport: transport,
+		userAgent: "Minio (" + runtime.GOOS + "; " + runtime.GOARCH + ") " + config.AppName + "/" + config.AppVersion,
+		username:  config.AccessKeyID,
+		password:  config.SecretAccessKey,
+	}, nil
+}
+
+// GetURL get url.
+func (c *webdavClient) GetURL() client.URL {
+	return *c.hostURL
+}
+
+// requestURL - URL of a path on the share.
+func (c *webdavClient) requestURL(path string) string {
+	u := url.URL{
+		Scheme: c.hostURL.Scheme,
+		Host:   c.hostURL.Host,
+		Path:   path,
+	}
+	return u.String()
+}
+
+// contentURL - URL of a path on the share as reported in contents.
+func (c *webdavClient) contentURL(path string) client.URL {
+	contentURL := *c.hostURL
+	contentURL.Path = path
+	return contentURL
+}
+
+// executeMethod - send a request and translate non 2xx replies into client errors.
+func (c *webdavClient) executeMethod(ctx context.Context, method, path string, header http.Header, body io.Reader, length int64) (*http.Response, *probe.Error) {
+	req, e := http.NewRequest(method, c.requestURL(path), body)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		// Unknown lengths are sent chunked.
+		req.ContentLength = length
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, e := c.transport.RoundTrip(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	closeResponse(resp)
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, probe.NewError(client.PathNotFound{Path: c.requestURL(path)})
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, probe.NewError(client.PathInsufficientPermission{Path: c.requestURL(path)})
+	case http.StatusRequestedRangeNotSatisfiable:
+		return nil, probe.NewError(client.InvalidRange{})
+	}
+	return nil, probe.NewError(statusError{method: method, url: c.requestURL(path), status: resp.Status, code: resp.StatusCode})
+}
+
+// statusError - a reply without a matching client error.
+type statusError struct {
+	method string
+	url    string
+	status string
+	code   int
+}
+
+func (e statusError) Error() string {
+	return "Unable to " + e.method + " ‘" + e.url + "’: " + e.status + "."
+}
+
+// isStatus - err is a reply with the given status code.
+func isStatus(err *probe.Error, code int) bool {
+	e, ok := err.ToGoError().(statusError)
+	return ok && e.code == code
+}
+
+// closeResponse - drain and close the body, so that the connection can be re-used.
+func closeResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// Stat - get metadata of a file or folder with a PROPFIND request.
+func (c *webdavClient) Stat(ctx context.Context) (*client.Content, *probe.Error) {
+	contents, err := c.propfind(ctx, c.hostURL.Path, "0")
+	if err != nil {
+		return nil, err.Trace(c.hostURL.String())
+	}
+	if len(contents) == 0 {
+		return nil, probe.NewError(client.PathNotFound{Path: c.hostURL.String()})
+	}
+	content := contents[0]
+	content.URL = *c.hostURL
+	return content, nil
+}
+
+// List - list a file or folder as itself, entries of a folder given with a
+// trailing separator, or entries of the parent folder matching the name
+// otherwise. Recursive listings contain files only.
+func (c *webdavClient) List(ctx context.Context, recursive, incomplete bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content)
+	go func() {
+		defer close(contentCh)
+		// Files are written at once, there are no incomplete uploads on a share.
+		if incomplete {
+			return
+		}
+		folderPath := c.hostURL.Path
+		if strings.HasSuffix(folderPath, "/") {
+			// Missing folders are empty, as prefixes of object storage are.
+			if _, err := c.propfind(ctx, folderPath, "0"); err != nil {
+				if !isNotFound(err) {
+					contentCh <- &client.Content{Err: err.Trace(c.hostURL.String())}
+				}
+				return
+			}
+			c.listFolder(ctx, folderPath, "", recursive, contentCh)
+			return
+		}
+		contents, err := c.propfind(ctx, folderPath, "0")
+		if err == nil && len(contents) > 0 {
+			if recursive && contents[0].Type.IsDir() {
+				c.listFolder(ctx, folderPath+"/", "", recursive, contentCh)
+				return
+			}
+			contentCh <- contents[0]
+			return
+		}
+		if err != nil && !isNotFound(err) {
+			contentCh <- &client.Content{Err: err.Trace(c.hostURL.String())}
+			return
+		}
+		// Names are matched as prefixes within their folder.
+		if !c.listFolder(ctx, path.Dir(folderPath)+"/", folderPath, recursive, contentCh) {
+			contentCh <- &client.Content{Err: probe.NewError(client.PathNotFound{Path: c.hostURL.String()})}
+		}
+	}()
+	return client.ForwardContents(ctx, contentCh)
+}
+
+// isNotFound - err is a missing path.
+func isNotFound(err *probe.Error) bool {
+	_, ok := err.ToGoError().(client.PathNotFound)
+	return ok
+}
+
+// listFolder - send entries of a folder starting with prefix, and entries
+// of sub-folders instead of the folders themselves if recursive. Reports
+// whether anything was sent.
+func (c *webdavClient) listFolder(ctx context.Context, folderPath, prefix string, recursive bool, contentCh chan<- *client.Content) bool {
+	contents, err := c.propfind(ctx, folderPath, "1")
+	if err != nil {
+		contentCh <- &client.Content{Err: err.Trace(c.requestURL(folderPath))}
+		return true
+	}
+	sent := false
+	for _, content := range contents {
+		// The folder itself is part of the reply.
+		if content.URL.Path == folderPath || !strings.HasPrefix(content.URL.Path, prefix) {
+			continue
+		}
+		if recursive && content.Type.IsDir() {
+			if c.listFolder(ctx, content.URL.Path, "", recursive, contentCh) {
+				sent = true
+			}
+			continue
+		}
+		contentCh <- content
+		sent = true
+	}
+	return sent
+}
+
+// Get - read a file, ranges are fetched with a Range request.
+func (c *webdavClient) Get(ctx context.Context, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	if offset < 0 || length < 0 {
+		return nil, probe.NewError(client.InvalidRange{Offset: offset})
+	}
+	return newFileReader(ctx, c, c.hostURL.Path, offset, length), nil
+}
+
+// Put - upload a file.
+func (c *webdavClient) Put(ctx context.Context, data io.ReadSeeker, size int64) *probe.Error {
+	return c.PutWithMetadata(ctx, data, size, nil)
+}
+
+// PutWithMetadata - upload a file, folders leading to it are created as
+// needed. Only a checksum is taken from metadata, shares keep no file
+// attributes. Files failing verification are removed again.
+func (c *webdavClient) PutWithMetadata(ctx context.Context, data io.ReadSeeker, size int64, metadata map[string]string) *probe.Error {
+	if _, ok := metadata[client.MetadataTags]; ok {
+		return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "webdav"})
+	}
+	filePath := c.hostURL.Path
+	if strings.HasSuffix(filePath, "/") {
+		return probe.NewError(client.PathIsDir{Path: c.hostURL.String()})
+	}
+	if err := c.makeParents(ctx, filePath); err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	hasher := md5.New()
+	resp, err := c.executeMethod(ctx, "PUT", filePath, nil, io.TeeReader(data, hasher), size)
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	closeResponse(resp)
+	expected, ok := metadata[client.MetadataMD5]
+	if !ok {
+		return nil
+	}
+	if computed := hex.EncodeToString(hasher.Sum(nil)); computed != expected {
+		if resp, err = c.executeMethod(ctx, "DELETE", filePath, nil, nil, 0); err == nil {
+			closeResponse(resp)
+		}
+		return probe.NewError(client.ChecksumMismatch{Path: c.hostURL.String(), Expected: expected, Computed: computed})
+	}
+	return nil
+}
+
+// Copy - files are copied by reading them.
+func (c *webdavClient) Copy(ctx context.Context, source client.URL, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "Copy", APIType: "webdav"})
+}
+
+// collections - folders known to exist, parents are created once per process.
+var collections = struct {
+	sync.Mutex
+	m map[string]bool
+}{m: make(map[string]bool)}
+
+// makeParents - create the folders leading to a path top down, shares only
+// create files in existing folders.
+func (c *webdavClient) makeParents(ctx context.Context, filePath string) *probe.Error {
+	var parents []string
+	for dir := path.Dir(filePath); dir != "/" && dir != "."; dir = path.Dir(dir) {
+		parents = append([]string{dir + "/"}, parents...)
+	}
+	collections.Lock()
+	defer collections.Unlock()
+	for _, dir := range parents {
+		if collections.m[c.requestURL(dir)] {
+			continue
+		}
+		if err := c.mkcol(ctx, dir); err != nil && !isStatus(err, http.StatusMethodNotAllowed) {
+			return err.Trace(dir)
+		}
+		collections.m[c.requestURL(dir)] = true
+	}
+	return nil
+}
+
+// mkcol - create a folder, shares reply with 405 to existing ones.
+func (c *webdavClient) mkcol(ctx context.Context, folderPath string) *probe.Error {
+	resp, err := c.executeMethod(ctx, "MKCOL", folderPath, nil, nil, 0)
+	if err != nil {
+		return err.Trace(folderPath)
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// MakeBucket - create the folder, along with folders leading to it.
+func (c *webdavClient) MakeBucket(ctx context.Context) *probe.Error {
+	folderPath := strings.TrimSuffix(c.hostURL.Path, "/") + "/"
+	if folderPath == "/" {
+		return probe.NewError(client.BucketNameEmpty{})
+	}
+	if err := c.makeParents(ctx, strings.TrimSuffix(folderPath, "/")); err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	if err := c.mkcol(ctx, folderPath); err != nil {
+		if isStatus(err, http.StatusMethodNotAllowed) {
+			return probe.NewError(client.BucketExists{Bucket: c.hostURL.String()})
+		}
+		return err.Trace(c.hostURL.String())
+	}
+	return nil
+}
+
+// GetBucketAccess - access policies not implemented for WebDAV shares.
+func (c *webdavClient) GetBucketAccess(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetBucketAccess", APIType: "webdav"})
+}
+
+// SetBucketAccess - access policies not implemented for WebDAV shares.
+func (c *webdavClient) SetBucketAccess(ctx context.Context, access string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetBucketAccess", APIType: "webdav"})
+}
+
+// ShareDownload - sharing not implemented for WebDAV shares.
+func (c *webdavClient) ShareDownload(ctx context.Context, expires time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "ShareDownload", APIType: "webdav"})
+}
+
+// ShareUpload - sharing not implemented for WebDAV shares.
+func (c *webdavClient) ShareUpload(ctx context.Context, recursive bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "ShareUpload", APIType: "webdav"})
+}
+
+// Remove - remove a file or an empty folder. Shares remove folders along
+// with their content, so non-empty ones are refused here.
+func (c *webdavClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	if incomplete {
+		return nil
+	}
+	contents, err := c.propfind(ctx, c.hostURL.Path, "1")
+	if err != nil {
+		return err.Trace(c.hostURL.String())
+	}
+	if len(contents) > 1 {
+		return probe.NewError(errors.New("Folder ‘" + c.hostURL.String() + "’ is not empty."))
+	}
+	return c.remove(ctx, c.hostURL.Path)
+}
+
+// remove - send a DELETE request for a path.
+func (c *webdavClient) remove(ctx context.Context, filePath string) *probe.Error {
+	resp, err := c.executeMethod(ctx, "DELETE", filePath, nil, nil, 0)
+	if err != nil {
+		return err.Trace(c.requestURL(filePath))
+	}
+	closeResponse(resp)
+	collections.Lock()
+	delete(collections.m, c.requestURL(strings.TrimSuffix(filePath, "/")+"/"))
+	collections.Unlock()
+	return nil
+}
+
+// RemoveBatch - remove all files received on contentCh, each file is sent
+// back with Err set if it could not be removed.
+func (c *webdavClient) RemoveBatch(ctx context.Context, contentCh <-chan *client.Content) <-chan *client.Content {
+	resultCh := make(chan *client.Content)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			if content.Type.IsDir() {
+				content.Err = probe.NewError(client.PathIsDir{Path: content.URL.String()})
+			} else if err := c.remove(ctx, content.URL.Path); err != nil {
+				content.Err = err.Trace(content.URL.String())
+			}
+			resultCh <- content
+		}
+	}()
+	return client.ForwardContents(ctx, resultCh)
+}
+
+// GetVersioning - versioning not implemented for WebDAV shares.
+func (c *webdavClient) GetVersioning(ctx context.Context) (string, *probe.Error) {
+	return "", probe.NewError(client.APINotImplemented{API: "GetVersioning", APIType: "webdav"})
+}
+
+// SetVersioning - versioning not implemented for WebDAV shares.
+func (c *webdavClient) SetVersioning(ctx context.Context, status string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetVersioning", APIType: "webdav"})
+}
+
+// ListVersions - versioning not implemented for WebDAV shares.
+func (c *webdavClient) ListVersions(ctx context.Context, recursive bool) <-chan *client.Content {
+	contentCh := make(chan *client.Content, 1)
+	contentCh <- &client.Content{Err: probe.NewError(client.APINotImplemented{API: "ListVersions", APIType: "webdav"})}
+	close(contentCh)
+	return contentCh
+}
+
+// StatVersion - versioning not implemented for WebDAV shares.
+func (c *webdavClient) StatVersion(ctx context.Context, versionID string) (*client.Content, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "StatVersion", APIType: "webdav"})
+}
+
+// GetVersion - versioning not implemented for WebDAV shares.
+func (c *webdavClient) GetVersion(ctx context.Context, versionID string, offset, length int64) (io.ReadSeeker, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetVersion", APIType: "webdav"})
+}
+
+// RemoveVersion - versioning not implemented for WebDAV shares.
+func (c *webdavClient) RemoveVersion(ctx context.Context, versionID string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveVersion", APIType: "webdav"})
+}
+
+// GetTags - tagging not implemented for WebDAV shares.
+func (c *webdavClient) GetTags(ctx context.Context) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetTags", APIType: "webdav"})
+}
+
+// SetTags - tagging not implemented for WebDAV shares.
+func (c *webdavClient) SetTags(ctx context.Context, tags map[string]string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetTags", APIType: "webdav"})
+}
+
+// DeleteTags - tagging not implemented for WebDAV shares.
+func (c *webdavClient) DeleteTags(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteTags", APIType: "webdav"})
+}
+
+// MakeBucketWithLock - object lock not implemented for WebDAV shares.
+func (c *webdavClient) MakeBucketWithLock(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "MakeBucketWithLock", APIType: "webdav"})
+}
+
+// GetRetention - object lock not implemented for WebDAV shares.
+func (c *webdavClient) GetRetention(ctx context.Context) (client.Retention, *probe.Error) {
+	return client.Retention{}, probe.NewError(client.APINotImplemented{API: "GetRetention", APIType: "webdav"})
+}
+
+// SetRetention - object lock not implemented for WebDAV shares.
+func (c *webdavClient) SetRetention(ctx context.Context, retention client.Retention) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetRetention", APIType: "webdav"})
+}
+
+// GetLegalHold - object lock not implemented for WebDAV shares.
+func (c *webdavClient) GetLegalHold(ctx context.Context) (bool, *probe.Error) {
+	return false, probe.NewError(client.APINotImplemented{API: "GetLegalHold", APIType: "webdav"})
+}
+
+// SetLegalHold - object lock not implemented for WebDAV shares.
+func (c *webdavClient) SetLegalHold(ctx context.Context, on bool) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetLegalHold", APIType: "webdav"})
+}
+
+// GetNotifications - notifications not implemented for WebDAV shares.
+func (c *webdavClient) GetNotifications(ctx context.Context) ([]client.NotificationConfig, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetNotifications", APIType: "webdav"})
+}
+
+// AddNotification - notifications not implemented for WebDAV shares.
+func (c *webdavClient) AddNotification(ctx context.Context, config client.NotificationConfig) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "AddNotification", APIType: "webdav"})
+}
+
+// RemoveNotification - notifications not implemented for WebDAV shares.
+func (c *webdavClient) RemoveNotification(ctx context.Context, arn string) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "RemoveNotification", APIType: "webdav"})
+}
+
+// Watch - notifications not implemented for WebDAV shares.
+func (c *webdavClient) Watch(ctx context.Context, events []string, recursive bool) (<-chan *client.Event, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Watch", APIType: "webdav"})
+}
+
+// GetCORS - CORS configuration not implemented for WebDAV shares.
+func (c *webdavClient) GetCORS(ctx context.Context) ([]client.CORSRule, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "GetCORS", APIType: "webdav"})
+}
+
+// SetCORS - CORS configuration not implemented for WebDAV shares.
+func (c *webdavClient) SetCORS(ctx context.Context, rules []client.CORSRule) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetCORS", APIType: "webdav"})
+}
+
+// DeleteCORS - CORS configuration not implemented for WebDAV shares.
+func (c *webdavClient) DeleteCORS(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteCORS", APIType: "webdav"})
+}
+
+// GetWebsite - static website configuration not implemented for WebDAV shares.
+func (c *webdavClient) GetWebsite(ctx context.Context) (client.Website, *probe.Error) {
+	return client.Website{}, probe.NewError(client.APINotImplemented{API: "GetWebsite", APIType: "webdav"})
+}
+
+// SetWebsite - static website configuration not implemented for WebDAV shares.
+func (c *webdavClient) SetWebsite(ctx context.Context, website client.Website) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "SetWebsite", APIType: "webdav"})
+}
+
+// DeleteWebsite - static website configuration not implemented for WebDAV shares.
+func (c *webdavClient) DeleteWebsite(ctx context.Context) *probe.Error {
+	return probe.NewError(client.APINotImplemented{API: "DeleteWebsite", APIType: "webdav"})
+}
+
+// Select - queries are not implemented for WebDAV shares, the caller evaluates them.
+func (c *webdavClient) Select(ctx context.Context, query client.SelectQuery) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(client.APINotImplemented{API: "Select", APIType: "webdav"})
+}
@@ -0,0 +1,244 @@
+/*
+ * Minio Client (C) 2014, 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// davHandler - a WebDAV share of a folder, as much of it as the client uses.
+type davHandler struct {
+	root string
+}
+
+func (h davHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, password, ok := r.BasicAuth(); !ok || user != "user" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	fpath := filepath.Join(h.root, filepath.FromSlash(path.Clean(r.URL.Path)))
+	st, e := os.Stat(fpath)
+	switch r.Method {
+	case "GET":
+		if e != nil {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, fpath)
+	case "PUT":
+		if _, pe := os.Stat(filepath.Dir(fpath)); pe != nil {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		ioutil.WriteFile(fpath, data, 0600)
+		w.WriteHeader(http.StatusCreated)
+	case "MKCOL":
+		if e == nil {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if os.Mkdir(fpath, 0700) != nil {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	case "DELETE":
+		if e != nil {
+			http.NotFound(w, r)
+			return
+		}
+		os.RemoveAll(fpath)
+		w.WriteHeader(http.StatusNoContent)
+	case "PROPFIND":
+		if e != nil {
+			http.NotFound(w, r)
+			return
+		}
+		infos := []os.FileInfo{st}
+		hrefs := []string{r.URL.Path}
+		if st.IsDir() && r.Header.Get("Depth") == "1" {
+			entries, _ := ioutil.ReadDir(fpath)
+			for _, entry := range entries {
+				infos = append(infos, entry)
+				hrefs = append(hrefs, strings.TrimSuffix(r.URL.Path, "/")+"/"+entry.Name())
+			}
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(207)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><D:multistatus xmlns:D="DAV:">`)
+		for i, info := range infos {
+			resourceType := ""
+			if info.IsDir() {
+				resourceType = "<D:collection/>"
+			}
+			fmt.Fprintf(w, `<D:response><D:href>%s</D:href><D:propstat><D:prop><D:resourcetype>%s</D:resourcetype>`+
+				`<D:getcontentlength>%d</D:getcontentlength><D:getlastmodified>%s</D:getlastmodified></D:prop>`+
+				`<D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`,
+				hrefs[i], resourceType, info.Size(), info.ModTime().UTC().Format(http.TimeFormat))
+		}
+		fmt.Fprint(w, `</D:multistatus>`)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// newTestShare - folder shared by a test server with a file and a sub-folder.
+func newTestShare(c *C) (string, *httptest.Server) {
+	root, e := ioutil.TempDir(os.TempDir(), "webdav-")
+	c.Assert(e, IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(root, "share", "parts"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "share", "README"), []byte("hello world"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "share", "parts", "part.1"), []byte("part"), 0600), IsNil)
+	return root, httptest.NewServer(davHandler{root: root})
+}
+
+// newTestClient - client of a path on the share at serverURL.
+func newTestClient(c *C, serverURL, path string) client.Client {
+	config := new(client.Config)
+	config.HostURL = serverURL + path
+	config.AccessKeyID = "user"
+	config.SecretAccessKey = "secret"
+	clnt, err := New(config)
+	c.Assert(err, IsNil)
+	return clnt
+}
+
+// listPaths - paths listed by a client.
+func listPaths(c *C, clnt client.Client, recursive bool) []string {
+	var paths []string
+	for content := range clnt.List(context.Background(), recursive, false) {
+		c.Assert(content.Err, IsNil)
+		paths = append(paths, content.URL.Path)
+	}
+	return paths
+}
+
+func (s *MySuite) TestStatList(c *C) {
+	root, server := newTestShare(c)
+	defer os.RemoveAll(root)
+	defer server.Close()
+
+	content, err := newTestClient(c, server.URL, "/share/README").Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(len("hello world")))
+	c.Assert(content.Type.IsRegular(), Equals, true)
+
+	content, err = newTestClient(c, server.URL, "/share/parts").Stat(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+
+	_, err = newTestClient(c, server.URL, "/share/missing").Stat(context.Background())
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.PathNotFound)
+	c.Assert(ok, Equals, true)
+
+	c.Assert(listPaths(c, newTestClient(c, server.URL, "/share/"), false), DeepEquals, []string{"/share/README", "/share/parts/"})
+	c.Assert(listPaths(c, newTestClient(c, server.URL, "/share/"), true), DeepEquals, []string{"/share/README", "/share/parts/part.1"})
+	c.Assert(listPaths(c, newTestClient(c, server.URL, "/share/RE"), false), DeepEquals, []string{"/share/README"})
+
+	// Keys are sent as user name and password.
+	config := &client.Config{HostURL: server.URL + "/share/README"}
+	clnt, err := New(config)
+	c.Assert(err, IsNil)
+	_, err = clnt.Stat(context.Background())
+	c.Assert(err, Not(IsNil))
+	_, ok = err.ToGoError().(client.PathInsufficientPermission)
+	c.Assert(ok, Equals, true)
+}
+
+func (s *MySuite) TestGetRange(c *C) {
+	root, server := newTestShare(c)
+	defer os.RemoveAll(root)
+	defer server.Close()
+
+	reader, err := newTestClient(c, server.URL, "/share/README").Get(context.Background(), 6, 5)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "world")
+
+	// Reading past the end keeps reporting the end without asking again.
+	reader, err = newTestClient(c, server.URL, "/share/README").Get(context.Background(), 0, 0)
+	c.Assert(err, IsNil)
+	data, e = ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "hello world")
+	n, e := reader.Read(make([]byte, 1))
+	c.Assert(n, Equals, 0)
+	c.Assert(e, Equals, io.EOF)
+}
+
+func (s *MySuite) TestPutMakeBucketRemove(c *C) {
+	root, server := newTestShare(c)
+	defer os.RemoveAll(root)
+	defer server.Close()
+
+	// Folders leading to a file are created along with it.
+	data := []byte("hello")
+	sum := md5.Sum(data)
+	clnt := newTestClient(c, server.URL, "/share/new/folder/object")
+	c.Assert(clnt.PutWithMetadata(context.Background(), bytes.NewReader(data), int64(len(data)), map[string]string{client.MetadataMD5: hex.EncodeToString(sum[:])}), IsNil)
+	written, e := ioutil.ReadFile(filepath.Join(root, "share", "new", "folder", "object"))
+	c.Assert(e, IsNil)
+	c.Assert(string(written), Equals, "hello")
+
+	// Files failing verification are removed again.
+	corrupted := newTestClient(c, server.URL, "/share/corrupted")
+	err := corrupted.PutWithMetadata(context.Background(), bytes.NewReader(data), -1, map[string]string{client.MetadataMD5: hex.EncodeToString(make([]byte, md5.Size))})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(client.ChecksumMismatch)
+	c.Assert(ok, Equals, true)
+	_, e = os.Stat(filepath.Join(root, "share", "corrupted"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	c.Assert(newTestClient(c, server.URL, "/share/bucket").MakeBucket(context.Background()), IsNil)
+	err = newTestClient(c, server.URL, "/share/bucket").MakeBucket(context.Background())
+	c.Assert(err, Not(IsNil))
+	_, ok = err.ToGoError().(client.BucketExists)
+	c.Assert(ok, Equals, true)
+
+	// Folders are only removed once empty.
+	c.Assert(newTestClient(c, server.URL, "/share/new/folder/").Remove(context.Background(), false), Not(IsNil))
+	c.Assert(clnt.Remove(context.Background(), false), IsNil)
+	c.Assert(newTestClient(c, server.URL, "/share/new/folder/").Remove(context.Background(), false), IsNil)
+	_, e = os.Stat(filepath.Join(root, "share", "new", "folder"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
@@ -0,0 +1,247 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+var (
+	rbFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of rb.",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "Remove all objects, versions and incomplete uploads of a bucket before removing it.",
+		},
+		cli.BoolFlag{
+			Name:  "dangerous",
+			Usage: "Allow removing all buckets of a host, requires --force.",
+		},
+	}
+)
+
+// remove a bucket or folder.
+var rbCmd = cli.Command{
+	Name:   "rb",
+	Usage:  "Remove a bucket or folder [WARNING: Use with care].",
+	Action: mainRemoveBucket,
+	Flags:  append(rbFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Remove an empty bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} s3.amazonaws.com/mybucket
+
+   2. Remove a bucket and all its objects and incomplete uploads.
+      $ mc {{.Name}} --force s3.amazonaws.com/mybucket
+
+   3. Remove a local folder and all its contents.
+      $ mc {{.Name}} --force /tmp/scratch
+
+   4. Remove all buckets of a host and all their objects.
+      $ mc {{.Name}} --force --dangerous play.minio.io:9000
+`,
+}
+
+// removeBucketMessage is container for remove bucket success and failure messages.
+type removeBucketMessage struct {
+	Status string `json:"status"`
+	Bucket string `json:"bucket"`
+}
+
+// String colorized remove bucket message.
+func (s removeBucketMessage) String() string {
+	return console.Colorize("RemoveBucket", "Removed ‘"+s.Bucket+"’ successfully.")
+}
+
+// JSON jsonified remove bucket message.
+func (s removeBucketMessage) JSON() string {
+	s.Status = "success"
+	removeBucketJSONBytes, err := json.Marshal(s)
+	fatalIf(probe.NewError(err), "Unable to marshal into JSON.")
+
+	return string(removeBucketJSONBytes)
+}
+
+// isHostURL - true if a URL points to all buckets of a host, or to a filesystem root.
+func isHostURL(url client.URL) bool {
+	path := strings.Trim(url.Path, string(url.Separator))
+	if url.Type == client.Filesystem {
+		return filepath.Dir(filepath.Clean(url.Path)) == filepath.Clean(url.Path)
+	}
+	return path == "" && !isURLVirtualHostStyle(url.Host)
+}
+
+// Validate command line arguments.
+func checkRemoveBucketSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "rb", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(), "Unable to validate empty argument.")
+		}
+	}
+	isForce := ctx.Bool("force")
+	isDangerous := ctx.Bool("dangerous")
+	if isDangerous && !isForce {
+		fatalIf(errInvalidArgument().Trace(), "‘--dangerous’ requires ‘--force’ option.")
+	}
+
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
+	for _, url := range URLs {
+		targetURL := client.NewURL(url)
+		switch {
+		case isHostURL(*targetURL):
+			if targetURL.Type == client.Filesystem {
+				fatalIf(errInvalidArgument().Trace(url), "Refusing to remove filesystem root ‘"+url+"’.")
+			}
+			if !isDangerous {
+				fatalIf(errInvalidArgument().Trace(url),
+					"‘"+url+"’ points to all buckets of a host. Please review carefully and use ‘--force --dangerous’ to perform this *DANGEROUS* operation.")
+			}
+		case targetURL.Type == client.Object && !isBucketURL(*targetURL):
+			fatalIf(errInvalidArgument().Trace(url), "‘"+url+"’ is not a bucket, please use ‘mc rm’ to remove objects.")
+		}
+	}
+}
+
+// emptyBucket - remove all objects and incomplete uploads of a bucket. Buckets
+// which ever had versioning enabled keep versions and delete markers of removed
+// objects, all of them are removed instead.
+func emptyBucket(url string) {
+	url = strings.TrimSuffix(url, "/") + "/"
+	isRecursive := true
+	isFake := false
+	clnt, err := url2Client(url)
+	if err != nil {
+		errorIf(err.Trace(url), "Invalid URL ‘"+url+"’.")
+		return
+	}
+	if status, err := clnt.GetVersioning(globalContext); err == nil && status != "" {
+		removeVersions(clnt)
+	} else {
+		rmAll(url, isRecursive, false, isFake, false, rmFilter{})
+	}
+	rmAll(url, isRecursive, true, isFake, false, rmFilter{})
+}
+
+// removeVersions - remove all versions and delete markers under a URL.
+func removeVersions(clnt client.Client) {
+	url := clnt.GetURL().String()
+	isRecursive := true
+	for content := range clnt.ListVersions(globalContext, isRecursive) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(url), "Unable to list versions of ‘"+url+"’.")
+			return
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		objectURL := content.URL.String()
+		objectClnt, err := url2Client(objectURL)
+		if err != nil {
+			errorIf(err.Trace(objectURL), "Invalid URL ‘"+objectURL+"’.")
+			continue
+		}
+		if err = objectClnt.RemoveVersion(globalContext, content.VersionID); err != nil {
+			errorIf(err.Trace(objectURL, content.VersionID), rmErrorMessage(objectURL, err))
+			continue
+		}
+		printMsg(rmMessage{Status: "success", URL: objectURL})
+	}
+}
+
+// removeBucket - remove a bucket or folder, emptying it first if isForce.
+func removeBucket(url string, isForce bool) *probe.Error {
+	clnt, err := url2Client(url)
+	if err != nil {
+		return err.Trace(url)
+	}
+	if isForce {
+		emptyBucket(url)
+	}
+	if err = clnt.Remove(globalContext, false); err != nil {
+		return err.Trace(url)
+	}
+	return nil
+}
+
+// mainRemoveBucket is entry point for rb command.
+func mainRemoveBucket(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'rb' cli arguments.
+	checkRemoveBucketSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("RemoveBucket", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Remove", color.New(color.FgGreen, color.Bold))
+
+	// Set command flags from context.
+	isForce := ctx.Bool("force")
+
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
+
+	for _, url := range URLs {
+		if !isHostURL(*client.NewURL(url)) {
+			if err := removeBucket(url, isForce); err != nil {
+				errorIf(err.Trace(url), "Unable to remove ‘"+url+"’.")
+				continue
+			}
+			printMsg(removeBucketMessage{Bucket: url})
+			continue
+		}
+
+		// Guarded by ‘--dangerous’ in checkRemoveBucketSyntax.
+		clnt, err := url2Client(url)
+		fatalIf(err.Trace(url), "Unable to initialize target ‘"+url+"’.")
+		for bucket := range clnt.List(globalContext, false, false) {
+			if bucket.Err != nil {
+				errorIf(bucket.Err.Trace(url), "Unable to list buckets of ‘"+url+"’.")
+				break
+			}
+			bucketURL := strings.TrimSuffix(bucket.URL.String(), "/")
+			if err := removeBucket(bucketURL, isForce); err != nil {
+				errorIf(err.Trace(bucketURL), "Unable to remove ‘"+bucketURL+"’.")
+				continue
+			}
+			printMsg(removeBucketMessage{Bucket: bucketURL})
+		}
+	}
+}
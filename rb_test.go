@@ -0,0 +1,130 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/mc/pkg/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestRemoveBucket(c *C) {
+	c.Assert(isHostURL(*client.NewURL("https://s3.amazonaws.com")), Equals, true)
+	c.Assert(isHostURL(*client.NewURL("https://s3.amazonaws.com/")), Equals, true)
+	c.Assert(isHostURL(*client.NewURL("https://s3.amazonaws.com/mybucket")), Equals, false)
+	c.Assert(isHostURL(*client.NewURL("https://mybucket.s3.amazonaws.com")), Equals, false)
+	c.Assert(isHostURL(*client.NewURL("/")), Equals, true)
+	c.Assert(isHostURL(*client.NewURL("/tmp")), Equals, false)
+
+	root, e := ioutil.TempDir(os.TempDir(), "rb-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	bucket := filepath.Join(root, "bucket")
+	c.Assert(os.MkdirAll(filepath.Join(bucket, "folder"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(bucket, "folder", "object"), []byte("hello"), 0600), IsNil)
+
+	// Non empty buckets are only removed with force.
+	c.Assert(removeBucket(bucket, false), Not(IsNil))
+	c.Assert(removeBucket(bucket, true), IsNil)
+	_, e = os.Stat(bucket)
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
+
+// versionedBucketHandler is an http.Handler serving a versioned bucket,
+// which can only be removed once all of its versions are.
+type versionedBucketHandler struct {
+	mu       sync.Mutex
+	versions map[string]bool
+	locked   string
+}
+
+func (h *versionedBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && len(query["versioning"]) == 1:
+		w.Write([]byte("<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>"))
+	case r.Method == "GET" && len(query["versions"]) == 1:
+		var keys []string
+		for key := range h.versions {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var entries bytes.Buffer
+		for _, key := range keys {
+			parts := strings.SplitN(key, "?", 2)
+			entries.WriteString("<Version><Key>" + parts[0] + "</Key><VersionId>" + parts[1] + "</VersionId><LastModified>2016-01-01T00:00:00.000Z</LastModified></Version>")
+		}
+		w.Write([]byte("<ListVersionsResult><IsTruncated>false</IsTruncated>" + entries.String() + "</ListVersionsResult>"))
+	case r.Method == "GET" && len(query["uploads"]) == 1:
+		w.Write([]byte("<ListMultipartUploadsResult><IsTruncated>false</IsTruncated></ListMultipartUploadsResult>"))
+	case r.Method == "DELETE" && r.URL.Path == "/bucket":
+		if len(h.versions) > 0 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("<Error><Code>BucketNotEmpty</Code><Message>The bucket you tried to delete is not empty</Message></Error>"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "DELETE" && query.Get("versionId") != "":
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/") + "?" + query.Get("versionId")
+		if key == h.locked {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error><Code>ObjectLocked</Code><Message>Object is WORM protected and cannot be overwritten</Message></Error>"))
+			return
+		}
+		delete(h.versions, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (s *TestSuite) TestRemoveVersionedBucket(c *C) {
+	handler := &versionedBucketHandler{versions: map[string]bool{
+		"folder/object?v1": true,
+		"folder/object?v2": true,
+		"object?v1":        true,
+		"object?v2":        true,
+	}, locked: "object?v1"}
+	versionedServer := httptest.NewServer(handler)
+	defer versionedServer.Close()
+	config, err := loadMcConfig()
+	c.Assert(err, IsNil)
+	config.Hosts[versionedServer.URL] = config.Hosts[server.URL]
+	c.Assert(saveMcConfig(config), IsNil)
+
+	// Versions held by a lock keep the bucket.
+	c.Assert(removeBucket(versionedServer.URL+"/bucket", true), Not(IsNil))
+	c.Assert(handler.versions, DeepEquals, map[string]bool{"object?v1": true})
+
+	// All others are removed, along with the bucket.
+	handler.locked = ""
+	c.Assert(removeBucket(versionedServer.URL+"/bucket", true), IsNil)
+	c.Assert(handler.versions, HasLen, 0)
+}
@@ -0,0 +1,213 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// restore specific flags.
+var (
+	restoreFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of restore.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Restore all objects under a prefix, requires ‘--rewind’.",
+		},
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Version of the object to restore.",
+		},
+		cli.StringFlag{
+			Name:  "rewind",
+			Usage: "Restore objects as of a date, time or duration ago, e.g. 7d.",
+		},
+	}
+)
+
+// restore old versions of objects.
+var restoreCmd = cli.Command{
+	Name:   "restore",
+	Usage:  "Restore an old version of an object as its current version.",
+	Action: mainRestore,
+	Flags:  append(restoreFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET [TARGET ...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Restore a specific version of an object on Amazon S3 cloud storage.
+      $ mc {{.Name}} --version-id 3HL4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY s3/mybucket/config.json
+
+   2. Restore an object, even a deleted one, as it was two days ago.
+      $ mc {{.Name}} --rewind 2d s3/mybucket/config.json
+
+   3. Restore all objects under a prefix as they were on the first of January 2016.
+      $ mc {{.Name}} --recursive --rewind 2016-01-01 s3/mybucket/photos/
+`,
+}
+
+// restoreMessage container for restore messages.
+type restoreMessage struct {
+	Status    string `json:"status"`
+	URL       string `json:"url"`
+	VersionID string `json:"versionId"`
+	IsLatest  bool   `json:"isLatest"`
+}
+
+// String colorized restore message.
+func (r restoreMessage) String() string {
+	if r.IsLatest {
+		return console.Colorize("Restore", "‘"+r.URL+"’ is already at version ‘"+r.VersionID+"’.")
+	}
+	return console.Colorize("Restore", "Restored ‘"+r.URL+"’ to version ‘"+r.VersionID+"’.")
+}
+
+// JSON jsonified restore message.
+func (r restoreMessage) JSON() string {
+	r.Status = "success"
+	restoreMessageBytes, e := json.Marshal(r)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(restoreMessageBytes)
+}
+
+// checkRestoreSyntax - validate all the passed arguments.
+func checkRestoreSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "restore", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+	versionID := ctx.String("version-id")
+	rewind := ctx.String("rewind")
+	switch {
+	case versionID == "" && rewind == "":
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "One of ‘--version-id’ or ‘--rewind’ is required.")
+	case versionID != "" && rewind != "":
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "‘--version-id’ and ‘--rewind’ cannot be used together.")
+	case versionID != "" && (len(ctx.Args()) > 1 || ctx.Bool("recursive")):
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "‘--version-id’ can only be used with a single object.")
+	}
+	if rewind != "" {
+		_, err := parseRewind(rewind)
+		fatalIf(err.Trace(rewind), "Unable to parse ‘--rewind’ value.")
+	}
+}
+
+// restoreVersion - put the selected version of an object back as its current version.
+func restoreVersion(clnt client.Client, versionID, rewind string) *probe.Error {
+	content, err := resolveVersion(clnt, versionID, rewind)
+	if err != nil {
+		return err.Trace(clnt.GetURL().String())
+	}
+	return restoreContent(clnt, content).Trace(clnt.GetURL().String())
+}
+
+// restoreContent - copy a version of an object over its current version on
+// the server, which keeps its metadata and content type.
+func restoreContent(clnt client.Client, content *client.Content) *probe.Error {
+	if !content.IsLatest {
+		if err := clnt.Copy(globalContext, clnt.GetURL(), content.VersionID); err != nil {
+			return err.Trace(clnt.GetURL().String(), content.VersionID)
+		}
+	}
+	printMsg(restoreMessage{
+		URL:       clnt.GetURL().String(),
+		VersionID: content.VersionID,
+		IsLatest:  content.IsLatest,
+	})
+	return nil
+}
+
+// restoreRecursive - restore every object under a prefix, including deleted
+// ones, as of rewind. Objects created after the rewind time are left untouched.
+func restoreRecursive(clnt client.Client, rewind string) {
+	t, err := parseRewind(rewind)
+	if err != nil {
+		errorIf(err.Trace(rewind), "Unable to parse ‘--rewind’ value.")
+		return
+	}
+	isRecursive := true
+	for content := range versionsAt(clnt, isRecursive, t) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list versions of ‘"+clnt.GetURL().String()+"’.")
+			return
+		}
+		objectURL := content.URL.String()
+		objectClnt, err := url2Client(objectURL)
+		if err != nil {
+			errorIf(err.Trace(objectURL), "Unable to initialize target ‘"+objectURL+"’.")
+			continue
+		}
+		errorIf(restoreContent(objectClnt, content).Trace(objectURL), "Unable to restore ‘"+objectURL+"’.")
+	}
+}
+
+// mainRestore - is a handler for mc restore command
+func mainRestore(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'restore' cli arguments.
+	checkRestoreSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Restore", color.New(color.FgGreen, color.Bold))
+
+	// Set command flags from context.
+	isRecursive := ctx.Bool("recursive")
+	versionID := ctx.String("version-id")
+	rewind := ctx.String("rewind")
+	if rewind != "" {
+		// Rewind relative durations only once, all objects are restored as of the same time.
+		t, _ := parseRewind(rewind)
+		rewind = t.Format(time.RFC3339Nano)
+	}
+
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "One or more unknown URL types passed.")
+	for _, targetURL := range URLs {
+		clnt, err := url2Client(targetURL)
+		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+		if isRecursive {
+			restoreRecursive(clnt, rewind)
+			continue
+		}
+		errorIf(restoreVersion(clnt, versionID, rewind).Trace(targetURL), "Unable to restore ‘"+targetURL+"’.")
+	}
+}
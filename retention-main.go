@@ -0,0 +1,243 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// retention specific flags.
+var (
+	retentionFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of retention.",
+		},
+		cli.StringFlag{
+			Name:  "mode",
+			Usage: "Retention mode, either ‘governance’ or ‘compliance’.",
+		},
+		cli.IntFlag{
+			Name:  "days",
+			Usage: "Retention period in days.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Apply to all objects under a prefix recursively.",
+		},
+	}
+)
+
+// Manage object lock retention.
+var retentionCmd = cli.Command{
+	Name:   "retention",
+	Usage:  "Manage object lock retention of objects and buckets.",
+	Action: mainRetention,
+	Flags:  append(retentionFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] --mode MODE --days N set TARGET [TARGET...]
+   mc {{.Name}} [FLAGS] get TARGET [TARGET...]
+   mc {{.Name}} [FLAGS] clear BUCKET [BUCKET...]
+
+MODE:
+   Allowed modes are: [governance, compliance].
+   Setting a retention on a bucket sets the default retention of its new objects,
+   clearing it leaves new objects unprotected. Retention of objects is not cleared.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Protect an object from deletion for 30 days in governance mode.
+      $ mc {{.Name}} --mode governance --days 30 set s3/backups/2016-01.tgz
+
+   2. Protect all objects under a prefix for 7 years in compliance mode.
+      $ mc {{.Name}} --recursive --mode compliance --days 2555 set s3/backups/ledger/
+
+   3. Set a default retention of 90 days for new objects of a bucket.
+      $ mc {{.Name}} --mode compliance --days 90 set s3/backups
+
+   4. Display retention of an object and default retention of a bucket.
+      $ mc {{.Name}} get s3/backups/2016-01.tgz s3/backups
+
+   5. Clear the default retention of a bucket.
+      $ mc {{.Name}} clear s3/backups
+`,
+}
+
+// retentionMessage container for retention messages.
+type retentionMessage struct {
+	Status    string     `json:"status"`
+	Operation string     `json:"operation"`
+	URL       string     `json:"url"`
+	Mode      string     `json:"mode"`
+	Days      int        `json:"days,omitempty"`
+	Until     *time.Time `json:"until,omitempty"`
+}
+
+// String colorized retention message.
+func (r retentionMessage) String() string {
+	var retention string
+	switch {
+	case r.Mode == "":
+		return console.Colorize("Retention", "‘"+r.URL+"’ has no retention.")
+	case r.Until != nil:
+		retention = strings.ToLower(r.Mode) + " until ‘" + r.Until.Local().Format(printDate) + "’"
+	default:
+		retention = strings.ToLower(r.Mode) + " for " + strconv.Itoa(r.Days) + " days"
+	}
+	if r.Operation == "clear" {
+		return console.Colorize("Retention", "Default retention of ‘"+r.URL+"’ cleared.")
+	}
+	if r.Operation == "set" {
+		return console.Colorize("Retention", "Retention of ‘"+r.URL+"’ set to "+retention+".")
+	}
+	return console.Colorize("Retention", "Retention of ‘"+r.URL+"’ is "+retention+".")
+}
+
+// JSON jsonified retention message.
+func (r retentionMessage) JSON() string {
+	r.Status = "success"
+	retentionMessageBytes, e := json.Marshal(r)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(retentionMessageBytes)
+}
+
+// parseRetentionMode - validate and normalize a retention mode.
+func parseRetentionMode(mode string) (string, bool) {
+	switch strings.ToUpper(mode) {
+	case client.RetentionGovernance:
+		return client.RetentionGovernance, true
+	case client.RetentionCompliance:
+		return client.RetentionCompliance, true
+	}
+	return "", false
+}
+
+// checkRetentionSyntax - validate all the passed arguments.
+func checkRetentionSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "retention", 1) // last argument is exit code.
+	}
+	switch ctx.Args().First() {
+	case "set":
+		if _, ok := parseRetentionMode(ctx.String("mode")); !ok {
+			fatalIf(errInvalidArgument().Trace(ctx.String("mode")),
+				"Unrecognized retention mode ‘"+ctx.String("mode")+"’. Allowed values are [governance, compliance].")
+		}
+		if ctx.Int("days") <= 0 {
+			fatalIf(errInvalidArgument().Trace(strconv.Itoa(ctx.Int("days"))), "Retention period ‘--days’ must be a positive number.")
+		}
+	case "clear":
+		if ctx.Bool("recursive") {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Only default retention of buckets is cleared, ‘--recursive’ is not supported.")
+		}
+	case "get":
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "retention", 1) // last argument is exit code.
+	}
+	for _, arg := range ctx.Args().Tail() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+}
+
+// doRetention - set or get retention of a single object, or set, get or clear default retention of a bucket.
+func doRetention(clnt client.Client, operation string, retention client.Retention) *probe.Error {
+	var err *probe.Error
+	switch operation {
+	case "set":
+		if isBucketURL(clnt.GetURL()) {
+			retention.Until = time.Time{}
+		} else {
+			// Objects are retained until a fixed date.
+			retention.Until = time.Now().Add(time.Duration(retention.Days) * 24 * time.Hour)
+		}
+		err = clnt.SetRetention(globalContext, retention)
+	case "clear":
+		if !isBucketURL(clnt.GetURL()) {
+			return errNotBucket(clnt.GetURL().String()).Trace(clnt.GetURL().String())
+		}
+		retention = client.Retention{}
+		err = clnt.SetRetention(globalContext, retention)
+	case "get":
+		retention, err = clnt.GetRetention(globalContext)
+	}
+	if err != nil {
+		return err.Trace(clnt.GetURL().String(), operation)
+	}
+	msg := retentionMessage{
+		Operation: operation,
+		URL:       clnt.GetURL().String(),
+		Mode:      retention.Mode,
+		Days:      retention.Days,
+	}
+	if !retention.Until.IsZero() {
+		msg.Until = &retention.Until
+		msg.Days = 0
+	}
+	printMsg(msg)
+	return nil
+}
+
+// mainRetention - is a handler for mc retention command
+func mainRetention(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'retention' cli arguments.
+	checkRetentionSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Retention", color.New(color.FgGreen, color.Bold))
+
+	// Set command flags from context.
+	isRecursive := ctx.Bool("recursive")
+	operation := ctx.Args().First()
+	mode, _ := parseRetentionMode(ctx.String("mode"))
+	retention := client.Retention{Mode: mode, Days: ctx.Int("days")}
+
+	URLs, err := args2URLs(ctx.Args().Tail())
+	fatalIf(err.Trace(ctx.Args()...), "One or more unknown URL types passed.")
+	for _, targetURL := range URLs {
+		clnt, err := url2Client(targetURL)
+		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+		if !isRecursive {
+			errorIf(doRetention(clnt, operation, retention).Trace(targetURL), "Unable to "+operation+" retention of ‘"+targetURL+"’.")
+			continue
+		}
+		for objectClnt := range listObjectClients(clnt) {
+			objectURL := objectClnt.GetURL().String()
+			errorIf(doRetention(objectClnt, operation, retention).Trace(objectURL), "Unable to "+operation+" retention of ‘"+objectURL+"’.")
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// rmFilter - conditions an object must meet to be removed, the zero value matches everything.
+type rmFilter struct {
+	tags      map[string]string
+	olderThan time.Time // Zero if not set.
+	newerThan time.Time // Zero if not set.
+	larger    *int64
+	smaller   *int64
+}
+
+// parseSize - parse a human readable size such as 10MB or 1GiB.
+func parseSize(value string) (*int64, *probe.Error) {
+	size, e := humanize.ParseBytes(value)
+	if e != nil {
+		return nil, errInvalidSize(value).Trace(value)
+	}
+	sizeInt := int64(size)
+	return &sizeInt, nil
+}
+
+// parseRmFilter - build a filter from ‘--tag’, ‘--older-than’, ‘--newer-than’, ‘--larger’ and ‘--smaller’.
+func parseRmFilter(ctx *cli.Context) (filter rmFilter, err *probe.Error) {
+	if filter.tags, err = parseTags(ctx.StringSlice("tag")); err != nil {
+		return rmFilter{}, err.Trace(ctx.StringSlice("tag")...)
+	}
+	// Both accept the same values as ‘--rewind’, a date or a duration ago.
+	if value := ctx.String("older-than"); value != "" {
+		if filter.olderThan, err = parseRewind(value); err != nil {
+			return rmFilter{}, err.Trace(value)
+		}
+	}
+	if value := ctx.String("newer-than"); value != "" {
+		if filter.newerThan, err = parseRewind(value); err != nil {
+			return rmFilter{}, err.Trace(value)
+		}
+	}
+	if value := ctx.String("larger"); value != "" {
+		if filter.larger, err = parseSize(value); err != nil {
+			return rmFilter{}, err.Trace(value)
+		}
+	}
+	if value := ctx.String("smaller"); value != "" {
+		if filter.smaller, err = parseSize(value); err != nil {
+			return rmFilter{}, err.Trace(value)
+		}
+	}
+	return filter, nil
+}
+
+// isEmpty - true if the filter matches everything.
+func (f rmFilter) isEmpty() bool {
+	return len(f.tags) == 0 && !f.needsStat()
+}
+
+// needsStat - true if the filter looks at time or size of objects.
+func (f rmFilter) needsStat() bool {
+	return !f.olderThan.IsZero() || !f.newerThan.IsZero() || f.larger != nil || f.smaller != nil
+}
+
+// match - true if an object meets all conditions, tags are only fetched
+// if time and size conditions are met.
+func (f rmFilter) match(content *client.Content) (bool, *probe.Error) {
+	if !f.olderThan.IsZero() && !content.Time.Before(f.olderThan) {
+		return false, nil
+	}
+	if !f.newerThan.IsZero() && !content.Time.After(f.newerThan) {
+		return false, nil
+	}
+	if f.larger != nil && content.Size <= *f.larger {
+		return false, nil
+	}
+	if f.smaller != nil && content.Size >= *f.smaller {
+		return false, nil
+	}
+	return matchURLTags(content.URL.String(), f.tags)
+}
+
+// matchURL - like match, for a URL which was not listed.
+func (f rmFilter) matchURL(url string) (bool, *probe.Error) {
+	if !f.needsStat() {
+		return matchURLTags(url, f.tags)
+	}
+	_, content, err := url2Stat(url)
+	if err != nil {
+		return false, err.Trace(url)
+	}
+	return f.match(content)
+}
@@ -17,12 +17,15 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio-xl/pkg/probe"
 )
@@ -50,6 +53,39 @@ var (
 			Name:  "fake",
 			Usage: "Perform a fake remove operation.",
 		},
+		cli.StringSliceFlag{
+			Name:  "tag",
+			Value: &cli.StringSlice{},
+			Usage: "Remove only objects with this tag, in the form key=value.",
+		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "Remove only objects modified before a date or a duration ago, e.g. 90d.",
+		},
+		cli.StringFlag{
+			Name:  "newer-than",
+			Usage: "Remove only objects modified after a date or a duration ago, e.g. 7d.",
+		},
+		cli.StringFlag{
+			Name:  "larger",
+			Usage: "Remove only objects larger than this size, e.g. 1GiB.",
+		},
+		cli.StringFlag{
+			Name:  "smaller",
+			Usage: "Remove only objects smaller than this size, e.g. 10KiB.",
+		},
+		cli.BoolFlag{
+			Name:  "stdin",
+			Usage: "Read targets from standard input, one per line.",
+		},
+		cli.BoolFlag{
+			Name:  "trash",
+			Usage: "Move objects to trash before removing them, see ‘mc trash’.",
+		},
+		cli.BoolFlag{
+			Name:  "dangerous",
+			Usage: "Allow removing all buckets of a host or a filesystem root, requires --force.",
+		},
 	}
 )
 
@@ -65,6 +101,12 @@ var rmCmd = cli.Command{
 USAGE:
    mc {{.Name}} [FLAGS] TARGET [TARGET ...]
 
+VERSIONING:
+   On buckets with versioning enabled, which object lock implies, removing an object
+   only adds a delete marker as its latest version. Earlier versions are kept along
+   with their retention, so locked objects are removed without error and their data
+   stays until the retention period expires. Use ‘mc restore’ to bring them back.
+
 FLAGS:
   {{range .Flags}}{{.}}
   {{end}}
@@ -86,6 +128,21 @@ EXAMPLES:
 
    6. Drop all incomplete uploads recursively matching this prefix.
       $ mc {{.Name}} --incomplete --force --recursive s3.amazonaws.com/jazz-songs/louis/
+
+   7. Remove all objects tagged as temporary recursively.
+      $ mc {{.Name}} --force --recursive --tag lifecycle=temporary s3.amazonaws.com/jazz-songs/
+
+   8. Preview removal of all objects older than 90 days and larger than 1GiB.
+      $ mc {{.Name}} --fake --force --recursive --older-than 90d --larger 1GiB s3.amazonaws.com/backups/
+
+   9. Remove all objects listed in a file.
+      $ cat expired.txt | mc {{.Name}} --stdin
+
+  10. Remove a folder recursively, keeping a copy of every object in trash.
+      $ mc {{.Name}} --force --recursive --trash s3.amazonaws.com/jazz-songs/louis/
+
+  11. Remove all buckets of a host and all their objects.
+      $ mc {{.Name}} --force --recursive --dangerous play.minio.io:9000
 `,
 }
 
@@ -114,7 +171,10 @@ func checkRmSyntax(ctx *cli.Context) {
 	isRecursive := ctx.Bool("recursive")
 	isIncomplete := ctx.Bool("incomplete")
 
-	if !ctx.Args().Present() {
+	isStdin := ctx.Bool("stdin")
+	isTrash := ctx.Bool("trash")
+
+	if !ctx.Args().Present() && !isStdin {
 		exitCode := 1
 		cli.ShowCommandHelpAndExit(ctx, "rm", exitCode)
 	}
@@ -129,14 +189,39 @@ func checkRmSyntax(ctx *cli.Context) {
 		}
 	}
 
+	_, err := parseRmFilter(ctx)
+	fatalIf(err.Trace(), "Unable to parse filter.")
+
+	if isTrash && isIncomplete {
+		fatalIf(errInvalidArgument().Trace(), "‘--trash’ cannot be used with ‘--incomplete’, incomplete uploads are never moved to trash.")
+	}
+
 	if isRecursive && !isForce {
 		fatalIf(errDummy().Trace(),
 			"Recursive removal requires --force option. Please review carefully before performing this *DANGEROUS* operation.")
 	}
+
+	isDangerous := ctx.Bool("dangerous")
+	if isDangerous && !isForce {
+		fatalIf(errInvalidArgument().Trace(), "‘--dangerous’ requires ‘--force’ option.")
+	}
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
+	for _, url := range URLs {
+		fatalIf(checkRmHost(url, isDangerous).Trace(url), "Unable to remove ‘"+url+"’.")
+	}
 }
 
-// Remove a single object.
-func rm(url string, isIncomplete, isFake bool) *probe.Error {
+// checkRmHost - refuse to remove all buckets of a host, or a filesystem root, without isDangerous.
+func checkRmHost(url string, isDangerous bool) *probe.Error {
+	if isDangerous || !isHostURL(*client.NewURL(url)) {
+		return nil
+	}
+	return errDangerousRemove(url).Trace(url)
+}
+
+// Remove a single object, or move it to trash if isTrash.
+func rm(url string, isIncomplete, isFake, isTrash bool) *probe.Error {
 	clnt, err := url2Client(url)
 	if err != nil {
 		return err.Trace(url)
@@ -146,15 +231,73 @@ func rm(url string, isIncomplete, isFake bool) *probe.Error {
 		return nil
 	}
 
-	if err = clnt.Remove(isIncomplete); err != nil {
+	if isTrash {
+		if err = moveToTrash(clnt); err != nil {
+			return err.Trace(url)
+		}
+		return nil
+	}
+
+	if err = clnt.Remove(globalContext, isIncomplete); err != nil {
 		return err.Trace(url)
 	}
 
 	return nil
 }
 
-// Remove all objects recursively.
-func rmAll(url string, isRecursive, isIncomplete, isFake bool) {
+// rmErrorMessage - explain why a remove failed, locked objects need a specific hint.
+// Removals only fail on locks where no delete marker is added, as on unversioned
+// servers enforcing retention.
+func rmErrorMessage(url string, err *probe.Error) string {
+	if _, ok := err.ToGoError().(client.ObjectLocked); ok {
+		return "Unable to remove locked object ‘" + url + "’, its retention period must expire or its legal hold must be cleared first."
+	}
+	return "Unable to remove ‘" + url + "’."
+}
+
+// rmBatch - remove all objects under a prefix, listing and removing concurrently in batches.
+func rmBatch(clnt client.Client, isFake bool, filter rmFilter) {
+	url := clnt.GetURL().String()
+	contentCh := make(chan *client.Content)
+	go func() {
+		defer close(contentCh)
+		isRecursive := true
+		isIncomplete := false
+		for entry := range clnt.List(globalContext, isRecursive, isIncomplete) {
+			if entry.Err != nil {
+				errorIf(entry.Err.Trace(url), "Unable to list ‘"+url+"’.")
+				return
+			}
+			// Recursive listings of object storage only carry prefixes for empty folders.
+			if entry.Type.IsDir() {
+				continue
+			}
+			matched, err := filter.match(entry)
+			if err != nil {
+				errorIf(err.Trace(entry.URL.String()), "Unable to get tags of ‘"+entry.URL.String()+"’.")
+				continue
+			}
+			if !matched {
+				continue
+			}
+			if isFake { // It is a fake remove.
+				printMsg(rmMessage{Status: "success", URL: entry.URL.String()})
+				continue
+			}
+			contentCh <- entry
+		}
+	}()
+	for content := range clnt.RemoveBatch(globalContext, contentCh) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(content.URL.String()), rmErrorMessage(content.URL.String(), content.Err))
+			continue
+		}
+		printMsg(rmMessage{Status: "success", URL: content.URL.String()})
+	}
+}
+
+// Remove all objects recursively, only those matching filter.
+func rmAll(url string, isRecursive, isIncomplete, isFake, isTrash bool, filter rmFilter) {
 	// Initialize new client.
 	clnt, err := url2Client(url)
 	if err != nil {
@@ -162,33 +305,90 @@ func rmAll(url string, isRecursive, isIncomplete, isFake bool) {
 		return // End of journey.
 	}
 
+	// Object storage removes many objects of a bucket per request, filesystems
+	// and hosts need a depth-first walk to remove folders and buckets after
+	// their contents. Objects moved to trash are copied one by one anyway.
+	if isRecursive && !isIncomplete && !isTrash && clnt.GetURL().Type == client.Object && !isHostURL(clnt.GetURL()) {
+		rmBatch(clnt, isFake, filter)
+		return
+	}
+
+	// Never move the trash into itself.
+	var trashURL string
+	if isTrash {
+		if trashURL, err = getTrashURL(url); err != nil {
+			errorIf(err.Trace(url), "Unable to find trash of ‘"+url+"’.")
+			return
+		}
+	}
+
 	/* Disable recursion and only list this folder's contents. We
 	perform manual depth-first recursion ourself here. */
 	nonRecursive := false
-	for entry := range clnt.List(nonRecursive, isIncomplete) {
+	for entry := range clnt.List(globalContext, nonRecursive, isIncomplete) {
 		if entry.Err != nil {
 			errorIf(entry.Err.Trace(url), "Unable to list ‘"+url+"’.")
 			return // End of journey.
 		}
 
+		if isTrash && isTrashURL(entry.URL.String(), trashURL) {
+			continue
+		}
+
 		if entry.Type.IsDir() && isRecursive {
 			// Add separator at the end to remove all its contents.
 			url := entry.URL
 			url.Path = strings.TrimSuffix(entry.URL.Path, string(entry.URL.Separator)) + string(entry.URL.Separator)
 
 			// Recursively remove contents of this directory.
-			rmAll(url.String(), isRecursive, isIncomplete, isFake)
+			rmAll(url.String(), isRecursive, isIncomplete, isFake, isTrash, filter)
+		}
+
+		if !filter.isEmpty() {
+			// Folders do not carry any tags, nor a meaningful time and size.
+			if entry.Type.IsDir() {
+				continue
+			}
+			matched, err := filter.match(entry)
+			if err != nil {
+				errorIf(err.Trace(entry.URL.String()), "Unable to get tags of ‘"+entry.URL.String()+"’.")
+				continue
+			}
+			if !matched {
+				continue
+			}
 		}
 
 		// Regular type.
-		if err = rm(entry.URL.String(), isIncomplete, isFake); err != nil {
-			errorIf(err.Trace(entry.URL.String()), "Unable to remove ‘"+entry.URL.String()+"’.")
+		if err = rm(entry.URL.String(), isIncomplete, isFake, isTrash); err != nil {
+			errorIf(err.Trace(entry.URL.String()), rmErrorMessage(entry.URL.String(), err))
 			continue
 		}
 		printMsg(rmMessage{Status: "success", URL: entry.URL.String()})
 	}
 }
 
+// rmURL - remove a single target, recursively if asked to.
+func rmURL(url string, isRecursive, isForce, isIncomplete, isFake, isTrash bool, filter rmFilter) {
+	if isRecursive && isForce {
+		rmAll(url, isRecursive, isIncomplete, isFake, isTrash, filter)
+		return
+	}
+	matched, err := filter.matchURL(url)
+	if err != nil {
+		errorIf(err.Trace(url), "Unable to evaluate filter of ‘"+url+"’.")
+		return
+	}
+	if !matched {
+		return
+	}
+	if err := rm(url, isIncomplete, isFake, isTrash); err != nil {
+		errorIf(err.Trace(url), rmErrorMessage(url, err))
+		return
+	}
+	printMsg(rmMessage{Status: "success", URL: url})
+}
+
 // main for rm command.
 func mainRm(ctx *cli.Context) {
 	// Set global flags from context.
@@ -202,6 +402,10 @@ func mainRm(ctx *cli.Context) {
 	isIncomplete := ctx.Bool("incomplete")
 	isRecursive := ctx.Bool("recursive")
 	isFake := ctx.Bool("fake")
+	isStdin := ctx.Bool("stdin")
+	isTrash := ctx.Bool("trash")
+	isDangerous := ctx.Bool("dangerous")
+	filter, _ := parseRmFilter(ctx)
 
 	// Set color.
 	console.SetColor("Remove", color.New(color.FgGreen, color.Bold))
@@ -212,14 +416,30 @@ func mainRm(ctx *cli.Context) {
 
 	// Support multiple targets.
 	for _, url := range URLs {
-		if isRecursive && isForce {
-			rmAll(url, isRecursive, isIncomplete, isFake)
-		} else {
-			if err := rm(url, isIncomplete, isFake); err != nil {
-				errorIf(err.Trace(url), "Unable to remove ‘"+url+"’.")
-				continue
-			}
-			printMsg(rmMessage{Status: "success", URL: url})
+		rmURL(url, isRecursive, isForce, isIncomplete, isFake, isTrash, filter)
+	}
+
+	if !isStdin {
+		return
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		arg := strings.TrimSpace(scanner.Text())
+		if arg == "" {
+			continue
 		}
+		url, err := getAliasURL(arg)
+		if err != nil {
+			errorIf(err.Trace(arg), "Unable to parse argument ‘"+arg+"’.")
+			continue
+		}
+		if err = checkRmHost(url, isDangerous); err != nil {
+			errorIf(err.Trace(url), "Unable to remove ‘"+url+"’.")
+			continue
+		}
+		rmURL(url, isRecursive, isForce, isIncomplete, isFake, isTrash, filter)
+	}
+	if e := scanner.Err(); e != nil {
+		fatalIf(probe.NewError(e), "Unable to read standard input.")
 	}
 }
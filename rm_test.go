@@ -0,0 +1,113 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/mc/pkg/client/memory"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestRmFilter(c *C) {
+	size, err := parseSize("1KiB")
+	c.Assert(err, IsNil)
+	c.Assert(*size, Equals, int64(1024))
+	_, err = parseSize("lots")
+	c.Assert(err, Not(IsNil))
+
+	root, e := ioutil.TempDir(os.TempDir(), "rm-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	// An old large file, an old small file and a recent large file.
+	old := time.Now().Add(-100 * 24 * time.Hour)
+	for name, length := range map[string]int{"old-large": 2048, "old-small": 10, "new-large": 2048} {
+		path := filepath.Join(root, "folder", name)
+		c.Assert(os.MkdirAll(filepath.Dir(path), 0700), IsNil)
+		c.Assert(ioutil.WriteFile(path, make([]byte, length), 0600), IsNil)
+		if name != "new-large" {
+			c.Assert(os.Chtimes(path, old, old), IsNil)
+		}
+	}
+
+	olderThan, err := parseRewind("90d")
+	c.Assert(err, IsNil)
+	filter := rmFilter{olderThan: olderThan, larger: size}
+
+	matched, err := filter.matchURL(filepath.Join(root, "folder", "old-large"))
+	c.Assert(err, IsNil)
+	c.Assert(matched, Equals, true)
+	matched, err = filter.matchURL(filepath.Join(root, "folder", "new-large"))
+	c.Assert(err, IsNil)
+	c.Assert(matched, Equals, false)
+
+	// A fake removal keeps everything.
+	rmAll(root+string(os.PathSeparator), true, false, true, false, filter)
+	_, e = os.Stat(filepath.Join(root, "folder", "old-large"))
+	c.Assert(e, IsNil)
+
+	rmAll(root+string(os.PathSeparator), true, false, false, false, filter)
+	_, e = os.Stat(filepath.Join(root, "folder", "old-large"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+	_, e = os.Stat(filepath.Join(root, "folder", "old-small"))
+	c.Assert(e, IsNil)
+	_, e = os.Stat(filepath.Join(root, "folder", "new-large"))
+	c.Assert(e, IsNil)
+}
+
+func (s *TestSuite) TestRmAllBuckets(c *C) {
+	memory.Reset()
+	defer memory.Reset()
+	for _, bucket := range []string{"mem://bucket1", "mem://bucket2"} {
+		clnt, err := url2Client(bucket)
+		c.Assert(err, IsNil)
+		c.Assert(clnt.MakeBucket(globalContext), IsNil)
+	}
+	putMemory(c, "mem://bucket1/folder/object1", "hello")
+	putMemory(c, "mem://bucket2/object2", "world")
+
+	// Buckets of a host are removed after their contents.
+	rmAll("mem://", true, false, false, false, rmFilter{})
+	for _, url := range []string{"mem://bucket1", "mem://bucket2"} {
+		_, _, err := url2Stat(url)
+		c.Assert(err, Not(IsNil))
+	}
+
+	// Only the contents of a bucket are.
+	clnt, err := url2Client("mem://bucket3")
+	c.Assert(err, IsNil)
+	c.Assert(clnt.MakeBucket(globalContext), IsNil)
+	putMemory(c, "mem://bucket3/folder/object3", "hello")
+	rmAll("mem://bucket3/", true, false, false, false, rmFilter{})
+	_, _, err = url2Stat("mem://bucket3/folder/object3")
+	c.Assert(err, Not(IsNil))
+	_, _, err = url2Stat("mem://bucket3")
+	c.Assert(err, IsNil)
+}
+
+func (s *TestSuite) TestRmHost(c *C) {
+	c.Assert(checkRmHost("https://s3.amazonaws.com", false), Not(IsNil))
+	c.Assert(checkRmHost("https://s3.amazonaws.com", true), IsNil)
+	c.Assert(checkRmHost("https://s3.amazonaws.com/bucket", false), IsNil)
+	c.Assert(checkRmHost("mem://", false), Not(IsNil))
+	c.Assert(checkRmHost("/", false), Not(IsNil))
+}
@@ -58,6 +58,11 @@ OPERATION:
 SESSION-ID:
    SESSION - Session can either be $SESSION-ID or "all".
 
+ARCHIVES:
+   Tar and zip archives are written anew by a resumed session, along with all
+   the entries copied into them before. Sessions moving objects into archives
+   cannot be resumed, entries moved before are only left in the archive.
+
 FLAGS:
   {{range .Flags}}{{.}}
   {{end}}
@@ -150,7 +155,7 @@ func clearSession(sid string) {
 
 func sessionExecute(s *sessionV5) {
 	switch s.Header.CommandType {
-	case "cp":
+	case "cp", "mv":
 		doCopySession(s)
 	case "mirror":
 		//doMirrorSession(s)
@@ -321,6 +321,7 @@ func (s *sessionV5) Delete() *probe.Error {
 // Close a session and exit.
 func (s sessionV5) CloseAndDie() {
 	s.Close()
+	completeArchives()
 	console.Infoln("Session safely terminated. To resume session ‘mc session resume " + s.SessionID + "’")
 	os.Exit(0)
 }
@@ -347,3 +348,43 @@ func isCopiedFactory(lastCopied string) func(string) bool {
 		return false
 	}
 }
+
+// copiedPrefix - tracks which entries of a session were copied. Copies finish
+// out of order, only the last entry of the prefix of copied entries is safe to
+// save as LastCopied, as all entries up to it are skipped once resumed.
+type copiedPrefix struct {
+	mutex   sync.Mutex
+	entries int
+	next    int
+	copied  map[int]string
+}
+
+// add - register the next entry of a session, returns its position.
+func (p *copiedPrefix) add() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.entries++
+	return p.entries - 1
+}
+
+// done - record an entry as copied, returns the last entry of the prefix of
+// copied entries if it grew.
+func (p *copiedPrefix) done(index int, sourceURL string) (string, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.copied == nil {
+		p.copied = make(map[int]string)
+	}
+	p.copied[index] = sourceURL
+	var lastCopied string
+	for {
+		url, ok := p.copied[p.next]
+		if !ok {
+			break
+		}
+		delete(p.copied, p.next)
+		lastCopied = url
+		p.next++
+	}
+	return lastCopied, lastCopied != ""
+}
@@ -117,7 +117,7 @@ func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration
 
 	// Generate share URL for each target.
 	incomplete := false
-	for content := range clnt.List(isRecursive, incomplete) {
+	for content := range clnt.List(globalContext, isRecursive, incomplete) {
 		if content.Err != nil {
 			return content.Err.Trace(clnt.GetURL().String())
 		}
@@ -132,7 +132,7 @@ func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration
 		}
 
 		// Generate share URL.
-		shareURL, err := newClnt.ShareDownload(expiry)
+		shareURL, err := newClnt.ShareDownload(globalContext, expiry)
 		if err != nil {
 			// add objectURL and expiry as part of the trace arguments.
 			return err.Trace(objectURL, "expiry="+expiry.String())
@@ -161,7 +161,7 @@ func doShareUploadURL(objectURL string, isRecursive bool, expiry time.Duration,
 	}
 
 	// Generate pre-signed access info.
-	uploadInfo, err := clnt.ShareUpload(isRecursive, expiry, contentType)
+	uploadInfo, err := clnt.ShareUpload(globalContext, isRecursive, expiry, contentType)
 	if err != nil {
 		return err.Trace(objectURL, "expiry="+expiry.String(), "contentType="+contentType)
 	}
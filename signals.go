@@ -21,7 +21,9 @@ import (
 	"os/signal"
 )
 
-// signalTrap traps the registered signals and notifies the caller.
+// signalTrap traps the registered signals, cancels globalContext and
+// notifies the caller. Only the first signal is trapped, another one is
+// handled by default, so that hanging shutdowns can still be forced.
 func signalTrap(sig ...os.Signal) <-chan bool {
 	// channel to notify the caller.
 	trapCh := make(chan bool, 1)
@@ -37,8 +39,10 @@ func signalTrap(sig ...os.Signal) <-chan bool {
 
 		// Wait for the signal.
 		<-sigCh
+		signal.Stop(sigCh)
 
-		// Notify the caller.
+		// Abort operations in progress and notify the caller.
+		globalCancel()
 		trapCh <- true
 	}(trapCh)
 
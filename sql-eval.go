@@ -0,0 +1,824 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// Values are nil for NULL and MISSING, bool, float64, string, json.Number
+// and, for JSON input, map[string]interface{} and []interface{}.
+
+// sqlRecord - a record of a CSV or JSON object.
+type sqlRecord struct {
+	columns []string // Header of CSV input, nil without one.
+	fields  []string // Fields of CSV input.
+	value   interface{}
+	isJSON  bool
+}
+
+// lookup - value of a column or of a path into a JSON document, nil if missing.
+func (r *sqlRecord) lookup(path []string) interface{} {
+	if r.isJSON {
+		value := r.value
+		for _, name := range path {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			if value, ok = object[name]; !ok {
+				value = nil
+				for key, v := range object {
+					if strings.EqualFold(key, name) {
+						value = v
+						break
+					}
+				}
+			}
+		}
+		return value
+	}
+	if len(path) != 1 {
+		return nil
+	}
+	name := path[0]
+	// Positional names, _1 is the first field.
+	if strings.HasPrefix(name, "_") {
+		if index, e := strconv.Atoi(name[1:]); e == nil {
+			if index < 1 || index > len(r.fields) {
+				return nil
+			}
+			return csvField(r.fields[index-1])
+		}
+	}
+	index := -1
+	for i, column := range r.columns {
+		if column == name {
+			index = i
+			break
+		}
+		if index < 0 && strings.EqualFold(column, name) {
+			index = i
+		}
+	}
+	if index < 0 || index >= len(r.fields) {
+		return nil
+	}
+	return csvField(r.fields[index])
+}
+
+// csvField - value of a CSV field, empty fields are NULL.
+func csvField(field string) interface{} {
+	if field == "" {
+		return nil
+	}
+	return field
+}
+
+// sqlExpr - an expression of a query.
+type sqlExpr interface {
+	eval(record *sqlRecord) (interface{}, *probe.Error)
+}
+
+type sqlLiteral struct {
+	value interface{}
+}
+
+type sqlRef struct {
+	path []string
+}
+
+type sqlUnary struct {
+	op   string
+	expr sqlExpr
+}
+
+type sqlBinary struct {
+	op          string
+	left, right sqlExpr
+}
+
+type sqlIsNull struct {
+	expr  sqlExpr
+	isNot bool
+}
+
+type sqlLike struct {
+	expr, pattern sqlExpr
+	isNot         bool
+	// Last compiled pattern, patterns are mostly literals.
+	lastPattern string
+	lastRegexp  *regexp.Regexp
+}
+
+type sqlBetween struct {
+	expr, low, high sqlExpr
+	isNot           bool
+}
+
+type sqlIn struct {
+	expr  sqlExpr
+	list  []sqlExpr
+	isNot bool
+}
+
+type sqlCast struct {
+	expr     sqlExpr
+	typeName string
+}
+
+type sqlFunction struct {
+	name string
+	args []sqlExpr
+}
+
+// sqlAggregate - an aggregate accumulated over all matching records, arg
+// is nil for COUNT(*).
+type sqlAggregate struct {
+	name  string
+	arg   sqlExpr
+	count int64
+	sum   float64
+	value interface{} // MIN and MAX.
+}
+
+// toNumber - numeric value, strings are numbers if they parse as one.
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		number, e := v.Float64()
+		return number, e == nil
+	case string:
+		number, e := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, e == nil
+	}
+	return 0, false
+}
+
+// toBool - boolean value, nil if it is not a boolean.
+func toBool(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		if b, e := strconv.ParseBool(strings.TrimSpace(v)); e == nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// toText - text of a value, as written to CSV output.
+func toText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// compareValues - order of two values, numerically if both are numbers,
+// which strings of CSV input are if they parse as one. ok is false if the
+// values cannot be compared.
+func compareValues(a, b interface{}) (result int, ok bool) {
+	_, aIsString := a.(string)
+	_, bIsString := b.(string)
+	if !aIsString || !bIsString {
+		x, xOk := toNumber(a)
+		y, yOk := toNumber(b)
+		if xOk && yOk {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	if aIsString && bIsString {
+		return strings.Compare(a.(string), b.(string)), true
+	}
+	x, xIsBool := a.(bool)
+	y, yIsBool := b.(bool)
+	if xIsBool && yIsBool {
+		if x == y {
+			return 0, true
+		}
+		if !x {
+			return -1, true
+		}
+		return 1, true
+	}
+	// Strings compared to booleans, as in CSV input.
+	if xIsBool || yIsBool {
+		if xb, ok := toBool(a).(bool); ok {
+			if yb, ok := toBool(b).(bool); ok {
+				return compareValues(xb, yb)
+			}
+		}
+	}
+	return 0, false
+}
+
+func (e *sqlLiteral) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	return e.value, nil
+}
+
+func (e *sqlRef) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	if record == nil {
+		return nil, errInvalidSQL("‘" + strings.Join(e.path, ".") + "’ cannot be used with aggregates").Trace()
+	}
+	return record.lookup(e.path), nil
+}
+
+func (e *sqlUnary) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	value, err := e.expr.eval(record)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	if e.op == "NOT" {
+		b, ok := toBool(value).(bool)
+		if !ok {
+			return nil, nil
+		}
+		return !b, nil
+	}
+	number, ok := toNumber(value)
+	if !ok {
+		return nil, errInvalidSQL("‘-’ needs a number, not ‘" + toText(value) + "’").Trace()
+	}
+	return -number, nil
+}
+
+func (e *sqlBinary) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	left, err := e.left.eval(record)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	// Three valued logic, unknown is nil.
+	switch e.op {
+	case "AND", "OR":
+		isOr := e.op == "OR"
+		l := toBool(left)
+		if l == isOr {
+			return isOr, nil
+		}
+		right, err := e.right.eval(record)
+		if err != nil {
+			return nil, err.Trace()
+		}
+		r := toBool(right)
+		if r == isOr {
+			return isOr, nil
+		}
+		if l == nil || r == nil {
+			return nil, nil
+		}
+		return !isOr, nil
+	}
+	right, err := e.right.eval(record)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	switch e.op {
+	case "=", "!=", "<>", "<", "<=", ">", ">=":
+		result, ok := compareValues(left, right)
+		if !ok {
+			return nil, nil
+		}
+		switch e.op {
+		case "=":
+			return result == 0, nil
+		case "!=", "<>":
+			return result != 0, nil
+		case "<":
+			return result < 0, nil
+		case "<=":
+			return result <= 0, nil
+		case ">":
+			return result > 0, nil
+		}
+		return result >= 0, nil
+	}
+	x, xOk := toNumber(left)
+	y, yOk := toNumber(right)
+	if !xOk || !yOk {
+		return nil, errInvalidSQL("‘" + e.op + "’ needs numbers, not ‘" + toText(left) + "’ and ‘" + toText(right) + "’").Trace()
+	}
+	switch e.op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return nil, errInvalidSQL("division by zero").Trace()
+		}
+		return x / y, nil
+	}
+	if y == 0 {
+		return nil, errInvalidSQL("division by zero").Trace()
+	}
+	return math.Mod(x, y), nil
+}
+
+func (e *sqlIsNull) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	value, err := e.expr.eval(record)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return (value == nil) != e.isNot, nil
+}
+
+func (e *sqlLike) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	value, err := e.expr.eval(record)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	pattern, err := e.pattern.eval(record)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if value == nil || pattern == nil {
+		return nil, nil
+	}
+	if e.lastRegexp == nil || e.lastPattern != toText(pattern) {
+		re, regexpErr := likeToRegexp(toText(pattern))
+		if regexpErr != nil {
+			return nil, probe.NewError(regexpErr)
+		}
+		e.lastPattern, e.lastRegexp = toText(pattern), re
+	}
+	return e.lastRegexp.MatchString(toText(value)) != e.isNot, nil
+}
+
+func (e *sqlBetween) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	value, err := e.expr.eval(record)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	low, err := e.low.eval(record)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	high, err := e.high.eval(record)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if value == nil || low == nil || high == nil {
+		return nil, nil
+	}
+	lowResult, lowOk := compareValues(value, low)
+	highResult, highOk := compareValues(value, high)
+	if !lowOk || !highOk {
+		return nil, nil
+	}
+	return (lowResult >= 0 && highResult <= 0) != e.isNot, nil
+}
+
+func (e *sqlIn) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	value, err := e.expr.eval(record)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	for _, item := range e.list {
+		candidate, err := item.eval(record)
+		if err != nil {
+			return nil, err.Trace()
+		}
+		if result, ok := compareValues(value, candidate); ok && result == 0 {
+			return !e.isNot, nil
+		}
+	}
+	return e.isNot, nil
+}
+
+func (e *sqlCast) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	value, err := e.expr.eval(record)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	switch e.typeName {
+	case "STRING", "VARCHAR":
+		return toText(value), nil
+	case "BOOL", "BOOLEAN":
+		if b, ok := toBool(value).(bool); ok {
+			return b, nil
+		}
+	default:
+		if number, ok := toNumber(value); ok {
+			if e.typeName == "INT" || e.typeName == "INTEGER" {
+				return math.Trunc(number), nil
+			}
+			return number, nil
+		}
+	}
+	return nil, errInvalidSQL("unable to cast ‘" + toText(value) + "’ to " + e.typeName).Trace()
+}
+
+func (e *sqlFunction) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	var args []interface{}
+	for _, arg := range e.args {
+		value, err := arg.eval(record)
+		if err != nil {
+			return nil, err.Trace()
+		}
+		args = append(args, value)
+	}
+	if e.name == "COALESCE" {
+		for _, value := range args {
+			if value != nil {
+				return value, nil
+			}
+		}
+		return nil, nil
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+	text := toText(args[0])
+	switch e.name {
+	case "LOWER":
+		return strings.ToLower(text), nil
+	case "UPPER":
+		return strings.ToUpper(text), nil
+	case "TRIM":
+		return strings.TrimSpace(text), nil
+	}
+	return float64(utf8.RuneCountInString(text)), nil
+}
+
+// accumulate - add a matching record to an aggregate.
+func (e *sqlAggregate) accumulate(record *sqlRecord) *probe.Error {
+	if e.arg == nil {
+		e.count++
+		return nil
+	}
+	value, err := e.arg.eval(record)
+	if err != nil {
+		return err.Trace()
+	}
+	if value == nil {
+		return nil
+	}
+	switch e.name {
+	case "SUM", "AVG":
+		number, ok := toNumber(value)
+		if !ok {
+			return errInvalidSQL("‘" + e.name + "’ needs numbers, not ‘" + toText(value) + "’").Trace()
+		}
+		e.sum += number
+	case "MIN", "MAX":
+		if e.count == 0 {
+			e.value = value
+			break
+		}
+		result, ok := compareValues(value, e.value)
+		if ok && (e.name == "MIN" && result < 0 || e.name == "MAX" && result > 0) {
+			e.value = value
+		}
+	}
+	e.count++
+	return nil
+}
+
+// reset - forget all accumulated records.
+func (e *sqlAggregate) reset() {
+	e.count, e.sum, e.value = 0, 0, nil
+}
+
+func (e *sqlAggregate) eval(record *sqlRecord) (interface{}, *probe.Error) {
+	switch e.name {
+	case "COUNT":
+		return float64(e.count), nil
+	case "SUM":
+		if e.count == 0 {
+			return nil, nil
+		}
+		return e.sum, nil
+	case "AVG":
+		if e.count == 0 {
+			return nil, nil
+		}
+		return e.sum / float64(e.count), nil
+	}
+	return e.value, nil
+}
+
+// sqlRow - a result of a query. Document is set instead of names and
+// values by SELECT * on JSON input.
+type sqlRow struct {
+	names    []string
+	values   []interface{}
+	document interface{}
+	isJSON   bool
+}
+
+// sqlRecordReader - records of an object, nil at the end.
+type sqlRecordReader interface {
+	Read() (*sqlRecord, *probe.Error)
+}
+
+// sqlRowWriter - writes results of a query.
+type sqlRowWriter interface {
+	Write(row sqlRow) *probe.Error
+	Flush() *probe.Error
+}
+
+// execute - stream all records of reader through the query, writing results
+// as they are found. Aggregates are written once all records were read.
+func (q *sqlQuery) execute(reader sqlRecordReader, writer sqlRowWriter) *probe.Error {
+	for _, aggregate := range q.aggregates {
+		aggregate.reset()
+	}
+	var written int64
+	for q.limit < 0 || written < q.limit || len(q.aggregates) > 0 {
+		record, err := reader.Read()
+		if err != nil {
+			return err.Trace()
+		}
+		if record == nil {
+			break
+		}
+		records := []*sqlRecord{record}
+		if array, ok := record.value.([]interface{}); ok && q.isArray {
+			records = records[:0]
+			for _, value := range array {
+				records = append(records, &sqlRecord{value: value, isJSON: true})
+			}
+		}
+		for _, record := range records {
+			if q.where != nil {
+				match, err := q.where.eval(record)
+				if err != nil {
+					return err.Trace()
+				}
+				if toBool(match) != true {
+					continue
+				}
+			}
+			if len(q.aggregates) > 0 {
+				for _, aggregate := range q.aggregates {
+					if err = aggregate.accumulate(record); err != nil {
+						return err.Trace()
+					}
+				}
+				continue
+			}
+			if q.limit >= 0 && written >= q.limit {
+				break
+			}
+			row, err := q.project(record)
+			if err != nil {
+				return err.Trace()
+			}
+			if err = writer.Write(row); err != nil {
+				return err.Trace()
+			}
+			written++
+		}
+	}
+	if len(q.aggregates) > 0 && q.limit != 0 {
+		row, err := q.project(nil)
+		if err != nil {
+			return err.Trace()
+		}
+		if err = writer.Write(row); err != nil {
+			return err.Trace()
+		}
+	}
+	return writer.Flush().Trace()
+}
+
+// project - result of a record, or of the aggregates if record is nil.
+func (q *sqlQuery) project(record *sqlRecord) (sqlRow, *probe.Error) {
+	if len(q.projections) == 0 {
+		if record.isJSON {
+			return sqlRow{document: record.value, isJSON: true}, nil
+		}
+		row := sqlRow{}
+		for i, field := range record.fields {
+			name := "_" + strconv.Itoa(i+1)
+			if i < len(record.columns) {
+				name = record.columns[i]
+			}
+			row.names = append(row.names, name)
+			row.values = append(row.values, field)
+		}
+		return row, nil
+	}
+	row := sqlRow{}
+	for _, projection := range q.projections {
+		value, err := projection.expr.eval(record)
+		if err != nil {
+			return sqlRow{}, err.Trace()
+		}
+		row.names = append(row.names, projection.name)
+		row.values = append(row.values, value)
+	}
+	return row, nil
+}
+
+// csvRecordReader - records of CSV input.
+type csvRecordReader struct {
+	reader  *csv.Reader
+	header  string // USE, IGNORE or NONE.
+	columns []string
+	isFirst bool
+}
+
+// newCSVRecordReader - read CSV records, header is USE, IGNORE or NONE.
+func newCSVRecordReader(reader io.Reader, header string, delimiter rune) *csvRecordReader {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = delimiter
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	return &csvRecordReader{reader: csvReader, header: header, isFirst: true}
+}
+
+func (r *csvRecordReader) Read() (*sqlRecord, *probe.Error) {
+	for {
+		fields, e := r.reader.Read()
+		if e == io.EOF {
+			return nil, nil
+		}
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		if r.isFirst {
+			r.isFirst = false
+			switch r.header {
+			case "USE":
+				r.columns = fields
+				continue
+			case "IGNORE":
+				continue
+			}
+		}
+		return &sqlRecord{columns: r.columns, fields: fields}, nil
+	}
+}
+
+// jsonRecordReader - records of JSON input, a sequence of JSON values such
+// as JSON lines or a single document.
+type jsonRecordReader struct {
+	decoder *json.Decoder
+}
+
+// newJSONRecordReader - read JSON records, numbers are kept as written.
+func newJSONRecordReader(reader io.Reader) *jsonRecordReader {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	return &jsonRecordReader{decoder: decoder}
+}
+
+func (r *jsonRecordReader) Read() (*sqlRecord, *probe.Error) {
+	var value interface{}
+	if e := r.decoder.Decode(&value); e != nil {
+		if e == io.EOF {
+			return nil, nil
+		}
+		return nil, probe.NewError(e)
+	}
+	return &sqlRecord{value: value, isJSON: true}, nil
+}
+
+// csvRowWriter - results as CSV lines, without header like S3 Select.
+type csvRowWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVRowWriter(writer io.Writer) *csvRowWriter {
+	return &csvRowWriter{writer: csv.NewWriter(writer)}
+}
+
+func (w *csvRowWriter) Write(row sqlRow) *probe.Error {
+	var fields []string
+	switch document := row.document.(type) {
+	case nil:
+		if row.isJSON {
+			fields = append(fields, "")
+		}
+		for _, value := range row.values {
+			fields = append(fields, toText(value))
+		}
+	case map[string]interface{}:
+		// Go maps are not ordered, fields are ordered by name.
+		var names []string
+		for name := range document {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fields = append(fields, toText(document[name]))
+		}
+	default:
+		fields = append(fields, toText(document))
+	}
+	if e := w.writer.Write(fields); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+func (w *csvRowWriter) Flush() *probe.Error {
+	w.writer.Flush()
+	if e := w.writer.Error(); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// jsonRowWriter - results as JSON lines, columns are written in order.
+type jsonRowWriter struct {
+	writer *bufio.Writer
+}
+
+func newJSONRowWriter(writer io.Writer) *jsonRowWriter {
+	return &jsonRowWriter{writer: bufio.NewWriter(writer)}
+}
+
+func (w *jsonRowWriter) Write(row sqlRow) *probe.Error {
+	var buf bytes.Buffer
+	if row.isJSON {
+		data, e := json.Marshal(row.document)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		buf.Write(data)
+	} else {
+		buf.WriteByte('{')
+		for i, name := range row.names {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			nameBytes, e := json.Marshal(name)
+			if e != nil {
+				return probe.NewError(e)
+			}
+			valueBytes, e := json.Marshal(row.values[i])
+			if e != nil {
+				return probe.NewError(e)
+			}
+			buf.Write(nameBytes)
+			buf.WriteByte(':')
+			buf.Write(valueBytes)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte('\n')
+	if _, e := w.writer.Write(buf.Bytes()); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+func (w *jsonRowWriter) Flush() *probe.Error {
+	if e := w.writer.Flush(); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
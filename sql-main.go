@@ -0,0 +1,253 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+var (
+	sqlFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of sql.",
+		},
+		cli.StringFlag{
+			Name:  "query, q",
+			Usage: "SQL expression, in the dialect of S3 Select.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Query all objects under a prefix or folder.",
+		},
+		cli.StringFlag{
+			Name:  "input",
+			Usage: "Format of queried objects, ‘csv’ or ‘json’. Guessed from their names by default.",
+		},
+		cli.StringFlag{
+			Name:  "output",
+			Usage: "Format of results, ‘csv’ or ‘json’. The input format by default, JSON with ‘--json’.",
+		},
+		cli.StringFlag{
+			Name:  "compression",
+			Usage: "Compression of queried objects, ‘none’ or ‘gzip’. Gzip for names ending with ‘.gz’ by default.",
+		},
+		cli.StringFlag{
+			Name:  "csv-header",
+			Value: "use",
+			Usage: "First line of CSV input, ‘use’ as column names, ‘ignore’ or ‘none’ if it is a record.",
+		},
+		cli.StringFlag{
+			Name:  "csv-delimiter",
+			Value: ",",
+			Usage: "Field delimiter of CSV input.",
+		},
+		cli.StringFlag{
+			Name:  "json-type",
+			Value: "lines",
+			Usage: "Layout of JSON input, ‘lines’ for a document per line or ‘document’.",
+		},
+	}
+)
+
+// Query CSV and JSON objects with SQL.
+var sqlCmd = cli.Command{
+	Name:   "sql",
+	Usage:  "Query CSV and JSON objects with SQL.",
+	Action: mainSQL,
+	Flags:  append(sqlFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} --query SQL [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+QUERIES:
+   Queries run on the server where it supports S3 Select, and are evaluated
+   locally on files and on servers which do not. Local evaluation supports
+   SELECT, FROM S3Object, WHERE and LIMIT with comparisons, LIKE, BETWEEN, IN,
+   IS NULL, CAST, LOWER, UPPER, TRIM, CHAR_LENGTH, COALESCE and the aggregates
+   COUNT, SUM, AVG, MIN and MAX.
+
+EXAMPLES:
+   1. Select names of people older than 30 from a CSV object on Amazon S3 cloud storage.
+      $ mc {{.Name}} --query "select s.name from S3Object s where s.age > 30" s3/mybucket/people.csv
+
+   2. Count error lines of all gzip compressed JSON logs under a prefix.
+      $ mc {{.Name}} -r --query "select count(*) from S3Object s where s.level = 'error'" s3/mybucket/logs/2015/
+
+   3. Select the first 10 records of a local CSV file without header as JSON.
+      $ mc {{.Name}} --csv-header none --output json --query "select s._1, s._3 from S3Object s limit 10" data.csv
+`,
+}
+
+// checkSQLSyntax - validate all the passed arguments.
+func checkSQLSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() || ctx.String("query") == "" {
+		cli.ShowCommandHelpAndExit(ctx, "sql", 1) // last argument is exit code.
+	}
+	for _, flag := range []struct {
+		name   string
+		values []string
+	}{
+		{"input", []string{"", "csv", "json"}},
+		{"output", []string{"", "csv", "json"}},
+		{"compression", []string{"", "none", "gzip"}},
+		{"csv-header", []string{"use", "ignore", "none"}},
+		{"json-type", []string{"lines", "document"}},
+	} {
+		value := strings.ToLower(ctx.String(flag.name))
+		isValid := false
+		for _, validValue := range flag.values {
+			isValid = isValid || value == validValue
+		}
+		if !isValid {
+			fatalIf(errInvalidArgument().Trace(value), "Invalid value ‘"+value+"’ of ‘--"+flag.name+"’.")
+		}
+	}
+	if utf8.RuneCountInString(ctx.String("csv-delimiter")) != 1 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("csv-delimiter")), "‘--csv-delimiter’ must be a single character.")
+	}
+}
+
+// newSelectQuery - query of an object, formats which are not set are guessed
+// from its name such as ‘people.csv’ or ‘logs.json.gz’.
+func newSelectQuery(ctx *cli.Context, objectURL string) client.SelectQuery {
+	query := client.SelectQuery{
+		Expression:   ctx.String("query"),
+		InputFormat:  strings.ToUpper(ctx.String("input")),
+		OutputFormat: strings.ToUpper(ctx.String("output")),
+		CSVHeader:    strings.ToUpper(ctx.String("csv-header")),
+		CSVDelimiter: ctx.String("csv-delimiter"),
+		JSONType:     strings.ToUpper(ctx.String("json-type")),
+	}
+	name := strings.ToLower(objectURL)
+	switch strings.ToLower(ctx.String("compression")) {
+	case "gzip":
+		query.Gzip = true
+	case "":
+		query.Gzip = strings.HasSuffix(name, ".gz")
+	}
+	if query.InputFormat == "" {
+		query.InputFormat = client.SelectCSV
+		switch filepath.Ext(strings.TrimSuffix(name, ".gz")) {
+		case ".json", ".jsonl", ".ndjson":
+			query.InputFormat = client.SelectJSON
+		}
+	}
+	if query.OutputFormat == "" {
+		query.OutputFormat = query.InputFormat
+		if globalJSON {
+			query.OutputFormat = client.SelectJSON
+		}
+	}
+	return query
+}
+
+// doSQL - run a query on an object, on the server with S3 Select if it
+// is supported and locally otherwise, and write the results to stdout.
+func doSQL(clnt client.Client, query client.SelectQuery) *probe.Error {
+	objectURL := clnt.GetURL().String()
+	records, err := clnt.Select(globalContext, query)
+	if err == nil {
+		defer records.Close()
+		return catOut(records).Trace(objectURL)
+	}
+	if _, ok := err.ToGoError().(client.APINotImplemented); !ok {
+		return err.Trace(objectURL)
+	}
+	return evalSQL(clnt, query, os.Stdout).Trace(objectURL)
+}
+
+// evalSQL - evaluate a query locally, streaming the object through it.
+func evalSQL(clnt client.Client, query client.SelectQuery, output io.Writer) *probe.Error {
+	parsedQuery, err := parseSQL(query.Expression)
+	if err != nil {
+		return err.Trace(query.Expression)
+	}
+	reader, err := clnt.Get(globalContext, 0, 0)
+	if err != nil {
+		return err.Trace()
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	var input io.Reader = reader
+	if query.Gzip {
+		gzipReader, e := gzip.NewReader(reader)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		defer gzipReader.Close()
+		input = gzipReader
+	}
+
+	var records sqlRecordReader
+	switch query.InputFormat {
+	case client.SelectJSON:
+		records = newJSONRecordReader(input)
+	default:
+		delimiter, _ := utf8.DecodeRuneInString(query.CSVDelimiter)
+		records = newCSVRecordReader(input, query.CSVHeader, delimiter)
+	}
+	var rows sqlRowWriter
+	switch query.OutputFormat {
+	case client.SelectJSON:
+		rows = newJSONRowWriter(output)
+	default:
+		rows = newCSVRowWriter(output)
+	}
+	return parsedQuery.execute(records, rows).Trace(query.Expression)
+}
+
+// mainSQL is the entry point for sql command.
+func mainSQL(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'sql' cli arguments.
+	checkSQLSyntax(ctx)
+
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
+
+	for _, url := range URLs {
+		clnt, err := url2Client(url)
+		fatalIf(err.Trace(url), "Unable to initialize target ‘"+url+"’.")
+
+		if ctx.Bool("recursive") {
+			for objectClnt := range listObjectClients(clnt) {
+				objectURL := objectClnt.GetURL().String()
+				errorIf(doSQL(objectClnt, newSelectQuery(ctx, objectURL)).Trace(objectURL), "Unable to query ‘"+objectURL+"’.")
+			}
+			continue
+		}
+		fatalIf(doSQL(clnt, newSelectQuery(ctx, url)).Trace(url), "Unable to query ‘"+url+"’.")
+	}
+}
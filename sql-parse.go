@@ -0,0 +1,657 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// This is the subset of the S3 Select SQL dialect evaluated locally, for
+// files and for servers without S3 Select:
+//
+//   SELECT * | expr [[AS] name], ... FROM S3Object[[*]] [[AS] alias]
+//     [WHERE expr] [LIMIT n]
+//
+// Expressions are literals, column references such as s.name, s._1 or
+// s.address.city, arithmetic, comparisons, AND, OR, NOT, [NOT] LIKE,
+// [NOT] BETWEEN, [NOT] IN, IS [NOT] NULL, CAST, LOWER, UPPER, TRIM,
+// CHAR_LENGTH, COALESCE and the aggregates COUNT, SUM, AVG, MIN and MAX.
+
+// sqlTokenKind - kind of a token of a query.
+type sqlTokenKind int
+
+const (
+	sqlEOF    sqlTokenKind = iota
+	sqlIdent               // Names and keywords.
+	sqlQuoted              // "Quoted names".
+	sqlNumber
+	sqlString
+	sqlSymbol
+)
+
+// sqlToken - a token of a query.
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+}
+
+// sqlKeywords - words which cannot be used as names without quotes.
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true, "AS": true,
+	"AND": true, "OR": true, "NOT": true, "LIKE": true, "IS": true,
+	"NULL": true, "MISSING": true, "TRUE": true, "FALSE": true,
+	"BETWEEN": true, "IN": true,
+}
+
+// sqlTokenize - split a query into tokens.
+func sqlTokenize(query string) ([]sqlToken, *probe.Error) {
+	var tokens []sqlToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{sqlIdent, string(runes[start:i])})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{sqlNumber, string(runes[start:i])})
+		case r == '\'' || r == '"':
+			// Quotes are escaped by doubling them.
+			var text []rune
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, errInvalidSQL("unterminated " + string(r) + " quote").Trace(query)
+				}
+				if runes[i] == r {
+					if i+1 < len(runes) && runes[i+1] == r {
+						text = append(text, r)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				text = append(text, runes[i])
+				i++
+			}
+			kind := sqlString
+			if r == '"' {
+				kind = sqlQuoted
+			}
+			tokens = append(tokens, sqlToken{kind, string(text)})
+		default:
+			if i+1 < len(runes) {
+				switch pair := string(runes[i : i+2]); pair {
+				case "<=", ">=", "<>", "!=":
+					tokens = append(tokens, sqlToken{sqlSymbol, pair})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("=<>+-*/%(),.[];", r) {
+				return nil, errInvalidSQL("unexpected character ‘" + string(r) + "’").Trace(query)
+			}
+			tokens = append(tokens, sqlToken{sqlSymbol, string(r)})
+			i++
+		}
+	}
+	return append(tokens, sqlToken{kind: sqlEOF}), nil
+}
+
+// sqlProjection - an expression of the select list and its name in results.
+type sqlProjection struct {
+	expr sqlExpr
+	name string
+}
+
+// sqlQuery - a parsed query.
+type sqlQuery struct {
+	projections []sqlProjection // Empty for SELECT *.
+	isArray     bool            // FROM S3Object[*], records are elements of top level arrays.
+	where       sqlExpr
+	limit       int64 // Negative if not limited.
+	aggregates  []*sqlAggregate
+}
+
+// sqlParser - recursive descent parser of a query.
+type sqlParser struct {
+	tokens          []sqlToken
+	pos             int
+	refs            []*sqlRef
+	aggregates      []*sqlAggregate
+	allowAggregates bool
+}
+
+// parseSQL - parse a query.
+func parseSQL(query string) (*sqlQuery, *probe.Error) {
+	tokens, err := sqlTokenize(query)
+	if err != nil {
+		return nil, err.Trace(query)
+	}
+	p := &sqlParser{tokens: tokens}
+	q := &sqlQuery{limit: -1}
+
+	if err = p.expectKeyword("SELECT"); err != nil {
+		return nil, err.Trace(query)
+	}
+	if !p.acceptSymbol("*") {
+		p.allowAggregates = true
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err.Trace(query)
+			}
+			projection := sqlProjection{expr: expr}
+			if p.acceptKeyword("AS") || p.peek().kind == sqlQuoted || p.isName() {
+				name, err := p.parseName()
+				if err != nil {
+					return nil, err.Trace(query)
+				}
+				projection.name = name
+			}
+			q.projections = append(q.projections, projection)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		p.allowAggregates = false
+	}
+
+	if err = p.expectKeyword("FROM"); err != nil {
+		return nil, err.Trace(query)
+	}
+	if token := p.next(); token.kind != sqlIdent || !strings.EqualFold(token.text, "S3Object") {
+		return nil, errInvalidSQL("only ‘S3Object’ can be queried").Trace(query)
+	}
+	if p.acceptSymbol("[") {
+		if !p.acceptSymbol("*") || !p.acceptSymbol("]") {
+			return nil, errInvalidSQL("only ‘S3Object[*]’ paths are supported").Trace(query)
+		}
+		q.isArray = true
+	}
+	alias := ""
+	if p.acceptKeyword("AS") || p.peek().kind == sqlQuoted || p.isName() {
+		if alias, err = p.parseName(); err != nil {
+			return nil, err.Trace(query)
+		}
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if q.where, err = p.parseExpr(); err != nil {
+			return nil, err.Trace(query)
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		token := p.next()
+		limit, e := strconv.ParseInt(token.text, 10, 64)
+		if token.kind != sqlNumber || e != nil {
+			return nil, errInvalidSQL("‘LIMIT’ needs a number").Trace(query)
+		}
+		q.limit = limit
+	}
+	p.acceptSymbol(";")
+	if token := p.peek(); token.kind != sqlEOF {
+		return nil, errInvalidSQL("unexpected ‘" + token.text + "’").Trace(query)
+	}
+
+	// Names may be qualified by the alias, or by S3Object without one.
+	for _, ref := range p.refs {
+		if len(ref.path) > 1 && (strings.EqualFold(ref.path[0], "S3Object") || alias != "" && strings.EqualFold(ref.path[0], alias)) {
+			ref.path = ref.path[1:]
+		}
+	}
+	// Results are named like S3 Select does, by alias, by the last part of a
+	// column reference, or by position.
+	for i := range q.projections {
+		if q.projections[i].name != "" {
+			continue
+		}
+		if ref, ok := q.projections[i].expr.(*sqlRef); ok {
+			q.projections[i].name = ref.path[len(ref.path)-1]
+			continue
+		}
+		q.projections[i].name = "_" + strconv.Itoa(i+1)
+	}
+	q.aggregates = p.aggregates
+	if len(q.aggregates) > 0 {
+		for _, projection := range q.projections {
+			if !hasAggregate(projection.expr) {
+				return nil, errInvalidSQL("aggregates cannot be mixed with other columns").Trace(query)
+			}
+		}
+	}
+	return q, nil
+}
+
+// peek - next token, not consumed.
+func (p *sqlParser) peek() sqlToken {
+	return p.tokens[p.pos]
+}
+
+// next - consume the next token.
+func (p *sqlParser) next() sqlToken {
+	token := p.tokens[p.pos]
+	if token.kind != sqlEOF {
+		p.pos++
+	}
+	return token
+}
+
+// isKeyword - true if the next token is a keyword.
+func (p *sqlParser) isKeyword(word string) bool {
+	token := p.peek()
+	return token.kind == sqlIdent && strings.EqualFold(token.text, word)
+}
+
+// isName - true if the next token is an unquoted name and not a keyword.
+func (p *sqlParser) isName() bool {
+	token := p.peek()
+	return token.kind == sqlIdent && !sqlKeywords[strings.ToUpper(token.text)]
+}
+
+// acceptKeyword - consume the next token if it is a keyword.
+func (p *sqlParser) acceptKeyword(word string) bool {
+	if p.isKeyword(word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// acceptSymbol - consume the next token if it is a symbol.
+func (p *sqlParser) acceptSymbol(symbol string) bool {
+	token := p.peek()
+	if token.kind == sqlSymbol && token.text == symbol {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expectKeyword - consume a keyword which must be next.
+func (p *sqlParser) expectKeyword(word string) *probe.Error {
+	if !p.acceptKeyword(word) {
+		return errInvalidSQL("expected ‘" + word + "’ instead of ‘" + p.peek().text + "’").Trace()
+	}
+	return nil
+}
+
+// expectSymbol - consume a symbol which must be next.
+func (p *sqlParser) expectSymbol(symbol string) *probe.Error {
+	if !p.acceptSymbol(symbol) {
+		return errInvalidSQL("expected ‘" + symbol + "’ instead of ‘" + p.peek().text + "’").Trace()
+	}
+	return nil
+}
+
+// parseName - a quoted or unquoted name.
+func (p *sqlParser) parseName() (string, *probe.Error) {
+	if p.peek().kind == sqlQuoted || p.isName() {
+		return p.next().text, nil
+	}
+	return "", errInvalidSQL("expected a name instead of ‘" + p.peek().text + "’").Trace()
+}
+
+// parseExpr - lowest precedence first: OR, AND, NOT, predicates, + -, * / %, unary -.
+func (p *sqlParser) parseExpr() (sqlExpr, *probe.Error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		left = &sqlBinary{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *sqlParser) parseAnd() (sqlExpr, *probe.Error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		left = &sqlBinary{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *sqlParser) parseNot() (sqlExpr, *probe.Error) {
+	if p.acceptKeyword("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		return &sqlUnary{op: "NOT", expr: expr}, nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *sqlParser) parsePredicate() (sqlExpr, *probe.Error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	token := p.peek()
+	if token.kind == sqlSymbol {
+		switch token.text {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err.Trace()
+			}
+			return &sqlBinary{op: token.text, left: left, right: right}, nil
+		}
+	}
+	if p.acceptKeyword("IS") {
+		isNot := p.acceptKeyword("NOT")
+		if !p.acceptKeyword("NULL") && !p.acceptKeyword("MISSING") {
+			return nil, errInvalidSQL("expected ‘NULL’ after ‘IS’").Trace()
+		}
+		return &sqlIsNull{expr: left, isNot: isNot}, nil
+	}
+	isNot := p.acceptKeyword("NOT")
+	switch {
+	case p.acceptKeyword("LIKE"):
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		return &sqlLike{expr: left, pattern: pattern, isNot: isNot}, nil
+	case p.acceptKeyword("BETWEEN"):
+		low, err := p.parseAdditive()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		if err = p.expectKeyword("AND"); err != nil {
+			return nil, err.Trace()
+		}
+		high, err := p.parseAdditive()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		return &sqlBetween{expr: left, low: low, high: high, isNot: isNot}, nil
+	case p.acceptKeyword("IN"):
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		return &sqlIn{expr: left, list: list, isNot: isNot}, nil
+	case isNot:
+		return nil, errInvalidSQL("expected ‘LIKE’, ‘BETWEEN’ or ‘IN’ after ‘NOT’").Trace()
+	}
+	return left, nil
+}
+
+func (p *sqlParser) parseAdditive() (sqlExpr, *probe.Error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	for {
+		token := p.peek()
+		if token.kind != sqlSymbol || (token.text != "+" && token.text != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		left = &sqlBinary{op: token.text, left: left, right: right}
+	}
+}
+
+func (p *sqlParser) parseMultiplicative() (sqlExpr, *probe.Error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	for {
+		token := p.peek()
+		if token.kind != sqlSymbol || (token.text != "*" && token.text != "/" && token.text != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		left = &sqlBinary{op: token.text, left: left, right: right}
+	}
+}
+
+func (p *sqlParser) parseUnary() (sqlExpr, *probe.Error) {
+	if p.acceptSymbol("-") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		return &sqlUnary{op: "-", expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parseList - parenthesized list of expressions.
+func (p *sqlParser) parseList() ([]sqlExpr, *probe.Error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err.Trace()
+	}
+	var list []sqlExpr
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		list = append(list, expr)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err.Trace()
+	}
+	return list, nil
+}
+
+func (p *sqlParser) parsePrimary() (sqlExpr, *probe.Error) {
+	token := p.peek()
+	switch token.kind {
+	case sqlNumber:
+		p.next()
+		number, e := strconv.ParseFloat(token.text, 64)
+		if e != nil {
+			return nil, errInvalidSQL("invalid number ‘" + token.text + "’").Trace()
+		}
+		return &sqlLiteral{value: number}, nil
+	case sqlString:
+		p.next()
+		return &sqlLiteral{value: token.text}, nil
+	case sqlSymbol:
+		if p.acceptSymbol("(") {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err.Trace()
+			}
+			if err = p.expectSymbol(")"); err != nil {
+				return nil, err.Trace()
+			}
+			return expr, nil
+		}
+		return nil, errInvalidSQL("unexpected ‘" + token.text + "’").Trace()
+	case sqlEOF:
+		return nil, errInvalidSQL("unexpected end of query").Trace()
+	}
+
+	switch {
+	case p.acceptKeyword("NULL"), p.acceptKeyword("MISSING"):
+		return &sqlLiteral{value: nil}, nil
+	case p.acceptKeyword("TRUE"):
+		return &sqlLiteral{value: true}, nil
+	case p.acceptKeyword("FALSE"):
+		return &sqlLiteral{value: false}, nil
+	}
+	if token.kind == sqlIdent && p.tokens[p.pos+1].kind == sqlSymbol && p.tokens[p.pos+1].text == "(" {
+		return p.parseFunction()
+	}
+
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	ref := &sqlRef{path: []string{name}}
+	for p.acceptSymbol(".") {
+		if name, err = p.parseName(); err != nil {
+			return nil, err.Trace()
+		}
+		ref.path = append(ref.path, name)
+	}
+	p.refs = append(p.refs, ref)
+	return ref, nil
+}
+
+// sqlFunctionArgs - number of arguments of functions, -1 for any.
+var sqlFunctionArgs = map[string]int{
+	"LOWER":            1,
+	"UPPER":            1,
+	"TRIM":             1,
+	"CHAR_LENGTH":      1,
+	"CHARACTER_LENGTH": 1,
+	"COALESCE":         -1,
+}
+
+func (p *sqlParser) parseFunction() (sqlExpr, *probe.Error) {
+	name := strings.ToUpper(p.next().text)
+	p.next() // (
+	switch name {
+	case "CAST":
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		if err = p.expectKeyword("AS"); err != nil {
+			return nil, err.Trace()
+		}
+		typeName := strings.ToUpper(p.next().text)
+		switch typeName {
+		case "INT", "INTEGER", "FLOAT", "DECIMAL", "NUMERIC", "STRING", "VARCHAR", "BOOL", "BOOLEAN":
+		default:
+			return nil, errInvalidSQL("unsupported type ‘" + typeName + "’").Trace()
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err.Trace()
+		}
+		return &sqlCast{expr: expr, typeName: typeName}, nil
+	case "COUNT", "SUM", "AVG", "MIN", "MAX":
+		if !p.allowAggregates {
+			return nil, errInvalidSQL("‘" + name + "’ is only allowed in the select list").Trace()
+		}
+		aggregate := &sqlAggregate{name: name}
+		if !(name == "COUNT" && p.acceptSymbol("*")) {
+			p.allowAggregates = false
+			arg, err := p.parseExpr()
+			p.allowAggregates = true
+			if err != nil {
+				return nil, err.Trace()
+			}
+			aggregate.arg = arg
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err.Trace()
+		}
+		p.aggregates = append(p.aggregates, aggregate)
+		return aggregate, nil
+	}
+
+	count, ok := sqlFunctionArgs[name]
+	if !ok {
+		return nil, errInvalidSQL("unsupported function ‘" + name + "’").Trace()
+	}
+	function := &sqlFunction{name: name}
+	if !p.acceptSymbol(")") {
+		p.pos-- // Let parseList consume the opening parenthesis.
+		args, err := p.parseList()
+		if err != nil {
+			return nil, err.Trace()
+		}
+		function.args = args
+	}
+	if count >= 0 && len(function.args) != count || count < 0 && len(function.args) == 0 {
+		return nil, errInvalidSQL("wrong number of arguments for ‘" + name + "’").Trace()
+	}
+	return function, nil
+}
+
+// hasAggregate - true if an expression uses an aggregate.
+func hasAggregate(expr sqlExpr) bool {
+	switch e := expr.(type) {
+	case *sqlAggregate:
+		return true
+	case *sqlUnary:
+		return hasAggregate(e.expr)
+	case *sqlBinary:
+		return hasAggregate(e.left) || hasAggregate(e.right)
+	case *sqlCast:
+		return hasAggregate(e.expr)
+	case *sqlFunction:
+		for _, arg := range e.args {
+			if hasAggregate(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// likeToRegexp - translate a LIKE pattern, % matches any text and _ any character.
+func likeToRegexp(pattern string) (*regexp.Regexp, error) {
+	var expr []string
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			expr = append(expr, ".*")
+		case '_':
+			expr = append(expr, ".")
+		default:
+			expr = append(expr, regexp.QuoteMeta(string(r)))
+		}
+	}
+	return regexp.Compile("^(?s:" + strings.Join(expr, "") + ")$")
+}
@@ -0,0 +1,132 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/mc/pkg/client"
+	. "gopkg.in/check.v1"
+)
+
+// runSQL - evaluate a query on CSV or JSON input.
+func runSQL(c *C, query, input string, isJSON bool, isJSONOutput bool) string {
+	parsedQuery, err := parseSQL(query)
+	c.Assert(err, IsNil)
+	var records sqlRecordReader = newCSVRecordReader(strings.NewReader(input), "USE", ',')
+	if isJSON {
+		records = newJSONRecordReader(strings.NewReader(input))
+	}
+	var output bytes.Buffer
+	var rows sqlRowWriter = newCSVRowWriter(&output)
+	if isJSONOutput {
+		rows = newJSONRowWriter(&output)
+	}
+	c.Assert(parsedQuery.execute(records, rows), IsNil)
+	return output.String()
+}
+
+func (s *TestSuite) TestSQLEval(c *C) {
+	people := "name,age,city\nalice,31,Berlin\nbob,25,Paris\ncarol,42,berlin\ndave,,Oslo\n"
+
+	testCases := []struct {
+		query  string
+		output string
+	}{
+		{"select * from S3Object", "alice,31,Berlin\nbob,25,Paris\ncarol,42,berlin\ndave,,Oslo\n"},
+		{"SELECT s.name FROM S3Object s WHERE s.age > 30", "alice\ncarol\n"},
+		{"select name, age + 1 as next from s3object where lower(city) = 'berlin'", "alice,32\ncarol,43\n"},
+		{"select s._1 from S3Object s where s.name like '_a%' or s.age is null", "carol\ndave\n"},
+		{"select s.name from S3Object s where s.age between 25 and 31 and s.name not in ('bob')", "alice\n"},
+		{"select count(*), sum(s.age), max(s.age), min(s.name) from S3Object s", "4,98,42,alice\n"},
+		{"select avg(cast(s.age as int)) from S3Object s where s.age is not null", "32.666666666666664\n"},
+		{"select s.name from S3Object s limit 2", "alice\nbob\n"},
+		{"select s.name from S3Object s where not (s.age < 40)", "carol\n"},
+	}
+	for _, testCase := range testCases {
+		c.Assert(runSQL(c, testCase.query, people, false, false), Equals, testCase.output, Commentf("%s", testCase.query))
+	}
+
+	// JSON input, nested documents and arrays.
+	logs := `{"level":"error","status":500,"request":{"path":"/a"}}
+{"level":"info","status":200,"request":{"path":"/b"}}
+{"level":"error","status":503,"request":{"path":"/c"}}
+`
+	c.Assert(runSQL(c, "select s.request.path, s.status from S3Object s where s.level = 'error'", logs, true, true), Equals,
+		`{"path":"/a","status":500}`+"\n"+`{"path":"/c","status":503}`+"\n")
+	c.Assert(runSQL(c, "select count(*) from S3Object s where s.status >= 500", logs, true, false), Equals, "2\n")
+	c.Assert(runSQL(c, "select * from S3Object s where s.status = 200", logs, true, true), Equals,
+		`{"level":"info","request":{"path":"/b"},"status":200}`+"\n")
+	c.Assert(runSQL(c, "select s.id from S3Object[*] s where s.id > 1", `[{"id":1},{"id":2},{"id":3}]`, true, false), Equals, "2\n3\n")
+
+	// Invalid queries.
+	for _, query := range []string{
+		"",
+		"select from S3Object",
+		"select s.name from table",
+		"select s.name, count(*) from S3Object s",
+		"select s.name from S3Object s where count(*) > 1",
+		"select s.name from S3Object s where s.name = 'alice",
+		"select foo(s.name) from S3Object s",
+		"select s.name from S3Object s limit x",
+	} {
+		_, err := parseSQL(query)
+		c.Assert(err, Not(IsNil), Commentf("%s", query))
+	}
+}
+
+func (s *TestSuite) TestSQL(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	// Gzip compressed local file.
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	gzipWriter.Write([]byte("name,age\nalice,31\nbob,25\n"))
+	gzipWriter.Close()
+	objectPath := filepath.Join(root, "people.csv.gz")
+	c.Assert(ioutil.WriteFile(objectPath, compressed.Bytes(), 0600), IsNil)
+
+	query := client.SelectQuery{
+		Expression:   "select s.name from S3Object s where s.age > 30",
+		InputFormat:  client.SelectCSV,
+		OutputFormat: client.SelectJSON,
+		CSVHeader:    "USE",
+		CSVDelimiter: ",",
+		Gzip:         true,
+	}
+	clnt, err := url2Client(objectPath)
+	c.Assert(err, IsNil)
+	var output bytes.Buffer
+	c.Assert(evalSQL(clnt, query, &output), IsNil)
+	c.Assert(output.String(), Equals, `{"name":"alice"}`+"\n")
+
+	// Servers without S3 Select fall back to local evaluation.
+	objectURL := server.URL + "/bucket/people.csv"
+	data := "name,age\nalice,31\nbob,25\n"
+	c.Assert(putTarget(objectURL, bytes.NewReader([]byte(data)), int64(len(data))), IsNil)
+	clnt, err = url2Client(objectURL)
+	c.Assert(err, IsNil)
+	query.Gzip = false
+	c.Assert(doSQL(clnt, query), IsNil)
+}
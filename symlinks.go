@@ -0,0 +1,97 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"os"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/client/fs"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// Handling of symbolic links found in filesystem sources, kept in session
+// headers under the ‘symlinks’ string flag.
+const (
+	symlinksFollow   = "follow"
+	symlinksSkip     = "skip"
+	symlinksPreserve = "preserve"
+)
+
+// symlinksFlags - flags selecting the handling of symbolic links.
+var symlinksFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "follow-symlinks",
+		Usage: "Copy files and folders symbolic links point to, the default.",
+	},
+	cli.BoolFlag{
+		Name:  "skip-symlinks",
+		Usage: "Skip symbolic links.",
+	},
+	cli.BoolFlag{
+		Name:  "preserve-symlinks",
+		Usage: "Store symbolic links as empty objects and recreate them on download.",
+	},
+}
+
+// fsSymlinks - handling of symbolic links by filesystem clients, set by
+// commands taking symlinks flags.
+var fsSymlinks = fs.FollowSymlinks
+
+// symlinksFromContext - handling of symbolic links selected by flags,
+// following them unless another handling is requested.
+func symlinksFromContext(ctx *cli.Context) (string, *probe.Error) {
+	symlinks := symlinksFollow
+	count := 0
+	for flag, value := range map[string]string{
+		"follow-symlinks":   symlinksFollow,
+		"skip-symlinks":     symlinksSkip,
+		"preserve-symlinks": symlinksPreserve,
+	} {
+		if ctx.Bool(flag) {
+			symlinks = value
+			count++
+		}
+	}
+	if count > 1 {
+		return "", errInvalidArgument().Trace()
+	}
+	return symlinks, nil
+}
+
+// setSymlinks - set the handling of symbolic links by filesystem clients.
+func setSymlinks(symlinks string) {
+	switch symlinks {
+	case symlinksSkip:
+		fsSymlinks = fs.SkipSymlinks
+	case symlinksPreserve:
+		fsSymlinks = fs.PreserveSymlinks
+	default:
+		fsSymlinks = fs.FollowSymlinks
+	}
+}
+
+// isSymlink - content is a preserved symbolic link.
+func isSymlink(content *client.Content) bool {
+	return content.Type&os.ModeSymlink == os.ModeSymlink
+}
+
+// isCopyable - content can be copied, regular files and preserved links.
+func isCopyable(content *client.Content) bool {
+	return content.Type.IsRegular() || isSymlink(content)
+}
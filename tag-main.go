@@ -0,0 +1,201 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// tag specific flags.
+var (
+	tagFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of tag.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Apply to all objects under a prefix recursively.",
+		},
+	}
+)
+
+// Manage object tags.
+var tagCmd = cli.Command{
+	Name:   "tag",
+	Usage:  "Manage tags of buckets and objects.",
+	Action: mainTag,
+	Flags:  append(tagFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] set TARGET KEY=VALUE [KEY=VALUE...]
+   mc {{.Name}} [FLAGS] get TARGET
+   mc {{.Name}} [FLAGS] remove TARGET
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Tag an object on Amazon S3 cloud storage with a project and a cost center.
+      $ mc {{.Name}} set s3/mybucket/report.pdf project=mc costcenter=42
+
+   2. Tag all objects under a prefix recursively.
+      $ mc {{.Name}} --recursive set s3/mybucket/reports/ project=mc
+
+   3. Display tags of an object.
+      $ mc {{.Name}} get s3/mybucket/report.pdf
+
+   4. Remove tags of all objects under a prefix recursively.
+      $ mc {{.Name}} --recursive remove s3/mybucket/reports/
+`,
+}
+
+// tagMessage container for tag messages.
+type tagMessage struct {
+	Status    string            `json:"status"`
+	Operation string            `json:"operation"`
+	URL       string            `json:"url"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// String colorized tag message.
+func (t tagMessage) String() string {
+	switch t.Operation {
+	case "set":
+		return console.Colorize("Tag", "Tags set for ‘"+t.URL+"’.")
+	case "remove":
+		return console.Colorize("Tag", "Tags removed for ‘"+t.URL+"’.")
+	}
+	if len(t.Tags) == 0 {
+		return console.Colorize("Tag", "‘"+t.URL+"’ has no tags.")
+	}
+	var keys []string
+	for key := range t.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	message := console.Colorize("Tag", "‘"+t.URL+"’:")
+	for _, key := range keys {
+		message = message + "\n   " + console.Colorize("TagKey", key) + "=" + console.Colorize("TagValue", t.Tags[key])
+	}
+	return message
+}
+
+// JSON jsonified tag message.
+func (t tagMessage) JSON() string {
+	t.Status = "success"
+	tagMessageBytes, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(tagMessageBytes)
+}
+
+// checkTagSyntax - validate all the passed arguments.
+func checkTagSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "tag", 1) // last argument is exit code.
+	}
+	switch ctx.Args().First() {
+	case "set":
+		if len(ctx.Args().Tail()) < 2 {
+			cli.ShowCommandHelpAndExit(ctx, "tag", 1) // last argument is exit code.
+		}
+		_, err := parseTags(ctx.Args().Tail().Tail())
+		fatalIf(err.Trace(ctx.Args()...), "Unable to parse tags.")
+	case "get", "remove":
+		if len(ctx.Args().Tail()) != 1 {
+			cli.ShowCommandHelpAndExit(ctx, "tag", 1) // last argument is exit code.
+		}
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "tag", 1) // last argument is exit code.
+	}
+	if strings.TrimSpace(ctx.Args().Tail().First()) == "" {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+	}
+}
+
+// doTag - set, get or remove tags of a single bucket or object.
+func doTag(clnt client.Client, operation string, tags map[string]string) *probe.Error {
+	var err *probe.Error
+	switch operation {
+	case "set":
+		err = clnt.SetTags(globalContext, tags)
+	case "get":
+		tags, err = clnt.GetTags(globalContext)
+	case "remove":
+		err = clnt.DeleteTags(globalContext)
+	}
+	if err != nil {
+		return err.Trace(clnt.GetURL().String(), operation)
+	}
+	printMsg(tagMessage{
+		Operation: operation,
+		URL:       clnt.GetURL().String(),
+		Tags:      tags,
+	})
+	return nil
+}
+
+// mainTag - is a handler for mc tag command
+func mainTag(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'tag' cli arguments.
+	checkTagSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Tag", color.New(color.FgGreen, color.Bold))
+	console.SetColor("TagKey", color.New(color.FgCyan))
+	console.SetColor("TagValue", color.New(color.FgYellow))
+
+	// Set command flags from context.
+	isRecursive := ctx.Bool("recursive")
+
+	operation := ctx.Args().First()
+	var tags map[string]string
+	if operation == "set" {
+		tags, _ = parseTags(ctx.Args().Tail().Tail())
+	}
+
+	URLs, err := args2URLs(ctx.Args().Tail()[:1])
+	fatalIf(err.Trace(ctx.Args()...), "One or more unknown URL types passed.")
+
+	for _, url := range URLs {
+		clnt, err := url2Client(url)
+		fatalIf(err.Trace(url), "Unable to initialize target ‘"+url+"’.")
+
+		if isRecursive {
+			for objectClnt := range listObjectClients(clnt) {
+				objectURL := objectClnt.GetURL().String()
+				errorIf(doTag(objectClnt, operation, tags).Trace(objectURL), "Unable to "+operation+" tags for ‘"+objectURL+"’.")
+			}
+			continue
+		}
+		fatalIf(doTag(clnt, operation, tags).Trace(url), "Unable to "+operation+" tags for ‘"+url+"’.")
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestParseTags(c *C) {
+	tags, err := parseTags([]string{"project=mc", "costcenter=42", "empty="})
+	c.Assert(err, IsNil)
+	c.Assert(tags, DeepEquals, map[string]string{"project": "mc", "costcenter": "42", "empty": ""})
+
+	// Round trip through the query form used by ‘--tags’.
+	tags, err = parseTagsString(tagsToString(map[string]string{"a b": "c&d", "e": "f=g"}))
+	c.Assert(err, IsNil)
+	c.Assert(tags, DeepEquals, map[string]string{"a b": "c&d", "e": "f=g"})
+
+	_, err = parseTags([]string{"novalue"})
+	c.Assert(err, Not(IsNil))
+	_, err = parseTags([]string{"=value"})
+	c.Assert(err, Not(IsNil))
+	_, err = parseTags([]string{"key=1", "key=2"})
+	c.Assert(err, Not(IsNil))
+	_, err = parseTags([]string{strings.Repeat("k", 129) + "=v"})
+	c.Assert(err, Not(IsNil))
+
+	var args []string
+	for i := 0; i < 11; i++ {
+		args = append(args, "key"+strconv.Itoa(i)+"=value")
+	}
+	_, err = parseTags(args)
+	c.Assert(err, Not(IsNil))
+}
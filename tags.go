@@ -0,0 +1,115 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// S3 limits on object tags.
+const (
+	maxTagsPerObject = 10
+	maxTagKeyLength  = 128
+	maxTagValueLen   = 256
+)
+
+// parseTags - parse a list of ‘key=value’ arguments into a tag set.
+func parseTags(args []string) (map[string]string, *probe.Error) {
+	tags := make(map[string]string)
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, errInvalidTag(arg).Trace(arg)
+		}
+		key, value := arg[:i], arg[i+1:]
+		if len(key) > maxTagKeyLength || len(value) > maxTagValueLen {
+			return nil, errInvalidTag(arg).Trace(arg)
+		}
+		if _, ok := tags[key]; ok {
+			return nil, errInvalidTag(arg).Trace(arg)
+		}
+		tags[key] = value
+	}
+	if len(tags) > maxTagsPerObject {
+		return nil, errTooManyTags(len(tags)).Trace(args...)
+	}
+	return tags, nil
+}
+
+// parseTagsString - parse tags in URL query form, for example ‘project=mc&cost=42’.
+func parseTagsString(value string) (map[string]string, *probe.Error) {
+	if value == "" {
+		return nil, nil
+	}
+	var args []string
+	for _, arg := range strings.Split(value, "&") {
+		arg, e := url.QueryUnescape(arg)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		args = append(args, arg)
+	}
+	return parseTags(args)
+}
+
+// tagsToString - format tags in URL query form, keys are sorted.
+func tagsToString(tags map[string]string) string {
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var args []string
+	for _, key := range keys {
+		args = append(args, url.QueryEscape(key)+"="+url.QueryEscape(tags[key]))
+	}
+	return strings.Join(args, "&")
+}
+
+// matchTags - true if the object carries all the tags of filter.
+func matchTags(clnt client.Client, filter map[string]string) (bool, *probe.Error) {
+	if len(filter) == 0 {
+		return true, nil
+	}
+	tags, err := clnt.GetTags(globalContext)
+	if err != nil {
+		return false, err.Trace(clnt.GetURL().String())
+	}
+	for key, value := range filter {
+		if v, ok := tags[key]; !ok || v != value {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchURLTags - same as matchTags, for a URL.
+func matchURLTags(urlStr string, filter map[string]string) (bool, *probe.Error) {
+	if len(filter) == 0 {
+		return true, nil
+	}
+	clnt, err := url2Client(urlStr)
+	if err != nil {
+		return false, err.Trace(urlStr)
+	}
+	return matchTags(clnt, filter)
+}
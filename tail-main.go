@@ -0,0 +1,260 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+var (
+	// tailPollInterval - how often objects are checked for growth with ‘--follow’.
+	tailPollInterval = 2 * time.Second
+
+	// tailFileInterval - how often local files are read for appended bytes with ‘--follow’.
+	tailFileInterval = 250 * time.Millisecond
+)
+
+var (
+	tailFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of tail.",
+		},
+		cli.IntFlag{
+			Name:  "lines, n",
+			Value: 10,
+			Usage: "Number of lines to display.",
+		},
+		cli.BoolFlag{
+			Name:  "follow, f",
+			Usage: "Keep displaying bytes appended to the file.",
+		},
+	}
+)
+
+// Display last lines of a file.
+var tailCmd = cli.Command{
+	Name:   "tail",
+	Usage:  "Display last lines of a file.",
+	Action: mainTail,
+	Flags:  append(tailFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SOURCE [SOURCE...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Display the last 20 lines of a log object on Amazon S3 cloud storage, only these are downloaded.
+      $ mc {{.Name}} -n 20 s3.amazonaws.com/mybucket/logs/access.log
+
+   2. Keep displaying lines appended to a local log file.
+      $ mc {{.Name}} -f /var/log/syslog
+
+   3. Keep displaying lines appended to an object, its size is checked every few seconds.
+      $ mc {{.Name}} --follow s3/mybucket/logs/access.log
+`,
+}
+
+// checkTailSyntax - validate all the passed arguments.
+func checkTailSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "tail", 1) // last argument is exit code.
+	}
+	if ctx.Int("lines") < 0 {
+		fatalIf(errInvalidArgument().Trace(), "‘--lines’ cannot be negative.")
+	}
+	if ctx.Bool("follow") && len(ctx.Args()) > 1 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "‘--follow’ accepts only one file.")
+	}
+}
+
+// tailStart - offset of the last lines of data, a final newline does not
+// start another line. Returns -1 if data holds fewer lines, data may then
+// not start at the beginning of a line.
+func tailStart(data []byte, lines int) int {
+	if lines == 0 {
+		return len(data)
+	}
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	for i := end - 1; i >= 0; i-- {
+		if data[i] != '\n' {
+			continue
+		}
+		if lines--; lines == 0 {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// tailURL - display the last lines of a URL, fetching chunks backwards from
+// its end until enough lines are read. Returns the size that was read up to.
+func tailURL(url string, lines int) (int64, *probe.Error) {
+	clnt, size, err := statFile(url)
+	if err != nil {
+		return 0, err.Trace(url)
+	}
+	var data []byte
+	end := size
+	for {
+		start := end - lineChunkSize
+		if start < 0 {
+			start = 0
+		}
+		chunk, err := readRange(clnt, start, end-start)
+		if err != nil {
+			return 0, err.Trace(url)
+		}
+		data = append(chunk, data...)
+		end = start
+		if offset := tailStart(data, lines); offset >= 0 {
+			data = data[offset:]
+			break
+		}
+		if start == 0 {
+			break
+		}
+	}
+	if err = catOut(bytes.NewReader(data)); err != nil {
+		return 0, err.Trace(url)
+	}
+	return size, nil
+}
+
+// followURL - display bytes appended to a URL after offset, until an error.
+func followURL(url string, offset int64) *probe.Error {
+	if client.NewURL(url).Type == client.Filesystem {
+		return followFile(url, offset).Trace(url)
+	}
+	return followObject(url, offset).Trace(url)
+}
+
+// followObject - object storage has no appends, an object is only ever
+// replaced. Its size is polled and every new tail is fetched with a ranged
+// read, an object which shrank was replaced and is displayed from the start.
+func followObject(url string, offset int64) *probe.Error {
+	for {
+		time.Sleep(tailPollInterval)
+		clnt, size, err := statFile(url)
+		if err != nil {
+			return err.Trace(url)
+		}
+		if size < offset {
+			offset = 0
+		}
+		if size == offset {
+			continue
+		}
+		data, err := readRange(clnt, offset, size-offset)
+		if err != nil {
+			return err.Trace(url)
+		}
+		if err = catOut(bytes.NewReader(data)); err != nil {
+			return err.Trace(url)
+		}
+		offset += int64(len(data))
+	}
+}
+
+// followFile - keep a local file open and display what is appended to it.
+// A truncated file is displayed from the start, and a file replaced by
+// rotation is opened again.
+func followFile(path string, offset int64) *probe.Error {
+	file, e := os.Open(path)
+	if e != nil {
+		return probe.NewError(e).Trace(path)
+	}
+	defer func() { file.Close() }()
+	if _, e = file.Seek(offset, 0); e != nil {
+		return probe.NewError(e).Trace(path)
+	}
+	for {
+		if err := catOut(file); err != nil {
+			return err.Trace(path)
+		}
+		time.Sleep(tailFileInterval)
+
+		openInfo, e := file.Stat()
+		if e != nil {
+			return probe.NewError(e).Trace(path)
+		}
+		pathInfo, e := os.Stat(path)
+		if e == nil && !os.SameFile(openInfo, pathInfo) {
+			// Rotated, read what is left of the old file first.
+			if err := catOut(file); err != nil {
+				return err.Trace(path)
+			}
+			newFile, e := os.Open(path)
+			if e != nil {
+				return probe.NewError(e).Trace(path)
+			}
+			file.Close()
+			file = newFile
+			continue
+		}
+		position, e := file.Seek(0, 1)
+		if e != nil {
+			return probe.NewError(e).Trace(path)
+		}
+		if openInfo.Size() < position {
+			// Truncated.
+			if _, e = file.Seek(0, 0); e != nil {
+				return probe.NewError(e).Trace(path)
+			}
+		}
+	}
+}
+
+// mainTail is the entry point for tail command.
+func mainTail(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'tail' cli arguments.
+	checkTailSyntax(ctx)
+
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse arguments.")
+	if ctx.Bool("follow") && len(URLs) > 1 {
+		fatalIf(errInvalidArgument().Trace(URLs...), "‘--follow’ accepts only one file.")
+	}
+
+	lines := ctx.Int("lines")
+	for i, url := range URLs {
+		if len(URLs) > 1 {
+			printFileHeader(url, i == 0)
+		}
+		size, err := tailURL(url, lines)
+		fatalIf(err.Trace(url), "Unable to read from ‘"+url+"’.")
+		if ctx.Bool("follow") {
+			fatalIf(followURL(url, size).Trace(url), "Unable to follow ‘"+url+"’.")
+		}
+	}
+}
@@ -0,0 +1,199 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+var (
+	trashFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of trash.",
+		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "Empty only objects removed before a date or a duration ago, e.g. 30d.",
+		},
+	}
+)
+
+// manage objects removed with ‘rm --trash’.
+var trashCmd = cli.Command{
+	Name:   "trash",
+	Usage:  "List, restore and empty objects removed with ‘rm --trash’.",
+	Action: mainTrash,
+	Flags:  append(trashFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] OPERATION TARGET [ID...]
+
+OPERATION:
+   list      List objects in trash.
+   restore   Restore objects to where they were removed from.
+   empty     Permanently remove objects from trash.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. List objects removed from a bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} list s3.amazonaws.com/jazz-songs
+
+   2. Restore an object from trash.
+      $ mc {{.Name}} restore s3.amazonaws.com/jazz-songs 20151104T071521.123456789Z-file01.mp3
+
+   3. List local files in trash, any local path selects the local trash.
+      $ mc {{.Name}} list .
+
+   4. Permanently remove objects removed more than 30 days ago.
+      $ mc {{.Name}} --older-than 30d empty s3.amazonaws.com/jazz-songs
+`,
+}
+
+// trashMessage container for trash list, restore and empty messages.
+type trashMessage struct {
+	op      string
+	Status  string    `json:"status"`
+	ID      string    `json:"id"`
+	URL     string    `json:"url"`
+	Size    int64     `json:"size"`
+	Deleted time.Time `json:"deleted"`
+}
+
+// String colorized trash message.
+func (t trashMessage) String() string {
+	switch t.op {
+	case "restore":
+		return console.Colorize("Trash", "Restored ‘"+t.URL+"’ from trash.")
+	case "empty":
+		return console.Colorize("Trash", "Removed ‘"+t.ID+"’ from trash permanently.")
+	}
+	message := console.Colorize("Time", fmt.Sprintf("[%s] ", t.Deleted.Local().Format(printDate)))
+	message += console.Colorize("Size", fmt.Sprintf("%6s ", humanize.IBytes(uint64(t.Size))))
+	message += console.Colorize("ID", t.ID)
+	message += " <- " + console.Colorize("URL", t.URL)
+	return message
+}
+
+// JSON jsonified trash message.
+func (t trashMessage) JSON() string {
+	t.Status = "success"
+	trashJSONBytes, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(trashJSONBytes)
+}
+
+// checkTrashSyntax - validate all the passed arguments.
+func checkTrashSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "trash", 1) // last argument is exit code.
+	}
+	tailArgs := ctx.Args().Tail()
+	switch strings.TrimSpace(ctx.Args().First()) {
+	case "list":
+		if len(tailArgs) != 1 {
+			fatalIf(errInvalidArgument().Trace(tailArgs...), "Incorrect number of arguments for trash list command.")
+		}
+	case "restore":
+		if len(tailArgs) < 2 {
+			fatalIf(errInvalidArgument().Trace(tailArgs...), "Please specify the ids of objects to restore, see ‘mc trash list’.")
+		}
+	case "empty":
+		if len(tailArgs) != 1 {
+			fatalIf(errInvalidArgument().Trace(tailArgs...), "Incorrect number of arguments for trash empty command.")
+		}
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "trash", 1) // last argument is exit code.
+	}
+	if value := ctx.String("older-than"); value != "" {
+		_, err := parseRewind(value)
+		fatalIf(err.Trace(value), "Unable to parse ‘--older-than’.")
+	}
+}
+
+// mainTrash is the entry point for trash command.
+func mainTrash(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'trash' cli arguments.
+	checkTrashSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Trash", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Size", color.New(color.FgYellow))
+	console.SetColor("ID", color.New(color.FgCyan, color.Bold))
+	console.SetColor("URL", color.New(color.FgWhite))
+
+	op := strings.TrimSpace(ctx.Args().First())
+	tailArgs := ctx.Args().Tail()
+
+	URLs, err := args2TargetURLs(tailArgs[:1])
+	fatalIf(err.Trace(tailArgs[0]), "Unable to parse argument ‘"+tailArgs[0]+"’.")
+	trashURL, err := getTrashURL(URLs[0])
+	fatalIf(err.Trace(URLs[0]), "Unable to find trash of ‘"+URLs[0]+"’.")
+
+	switch op {
+	case "list":
+		entries, err := listTrash(trashURL)
+		fatalIf(err.Trace(trashURL), "Unable to list trash ‘"+trashURL+"’.")
+		for _, entry := range entries {
+			printMsg(trashMessage{op: op, ID: entry.ID, URL: entry.URL, Size: entry.Size, Deleted: entry.Deleted})
+		}
+	case "restore":
+		for _, id := range tailArgs[1:] {
+			info, err := restoreTrashEntry(trashURL, id)
+			if err != nil {
+				errorIf(err.Trace(trashURL, id), "Unable to restore ‘"+id+"’.")
+				continue
+			}
+			printMsg(trashMessage{op: op, ID: id, URL: info.URL, Size: info.Size, Deleted: info.Deleted})
+		}
+	case "empty":
+		var olderThan time.Time
+		if value := ctx.String("older-than"); value != "" {
+			olderThan, _ = parseRewind(value)
+		}
+		entries, err := listTrash(trashURL)
+		fatalIf(err.Trace(trashURL), "Unable to list trash ‘"+trashURL+"’.")
+		for _, entry := range entries {
+			if !olderThan.IsZero() && !entry.Deleted.Before(olderThan) {
+				continue
+			}
+			if err := removeTrashEntry(trashURL, entry.ID); err != nil {
+				errorIf(err.Trace(trashURL, entry.ID), "Unable to remove ‘"+entry.ID+"’ from trash.")
+				continue
+			}
+			printMsg(trashMessage{op: op, ID: entry.ID, URL: entry.URL, Size: entry.Size, Deleted: entry.Deleted})
+		}
+	}
+}
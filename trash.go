@@ -0,0 +1,337 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// Every object moved to trash gets its own folder holding its data and
+// a small JSON document describing where it came from.
+const (
+	trashDefaultPrefix = ".trash"
+	trashDataName      = "data"
+	trashInfoName      = "info.json"
+	trashInfoVersion   = "1"
+)
+
+// trashInfo - metadata of an object in trash.
+type trashInfo struct {
+	Version string    `json:"version"`
+	URL     string    `json:"url"`
+	Size    int64     `json:"size"`
+	Deleted time.Time `json:"deleted"`
+}
+
+// trashEntry - an object in trash, identified by the name of its folder.
+type trashEntry struct {
+	ID string
+	trashInfo
+}
+
+// trashEntries - sort trash entries by time of removal.
+type trashEntries []trashEntry
+
+func (t trashEntries) Len() int           { return len(t) }
+func (t trashEntries) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t trashEntries) Less(i, j int) bool { return t[i].Deleted.Before(t[j].Deleted) }
+
+// getTrashURL - trash folder of a URL, always ending with a separator. Local
+// files go to the config folder, objects to the trash configured for their
+// host or to a ‘.trash’ prefix of their bucket.
+func getTrashURL(URL string) (string, *probe.Error) {
+	url := client.NewURL(URL)
+	if url.Type == client.Filesystem {
+		configDir, err := getMcConfigDir()
+		if err != nil {
+			return "", err.Trace(URL)
+		}
+		trashDir, e := filepath.Abs(filepath.Join(configDir, globalTrashDir))
+		if e != nil {
+			return "", probe.NewError(e).Trace(URL)
+		}
+		return trashDir + string(os.PathSeparator), nil
+	}
+
+	hostCfg, err := getHostConfig(URL)
+	if err != nil {
+		return "", err.Trace(URL)
+	}
+	separator := string(url.Separator)
+	trashURL := *url
+	switch {
+	case hostCfg.Trash != "":
+		trashURL.Path = separator + strings.Trim(hostCfg.Trash, separator) + separator
+	case isURLVirtualHostStyle(url.Host):
+		trashURL.Path = separator + trashDefaultPrefix + separator
+	default:
+		bucket := strings.SplitN(strings.TrimPrefix(url.Path, separator), separator, 2)[0]
+		if bucket == "" {
+			return "", errNoTrash(URL).Trace(URL)
+		}
+		trashURL.Path = separator + bucket + separator + trashDefaultPrefix + separator
+	}
+	return trashURL.String(), nil
+}
+
+// isTrashURL - true if a URL is inside a trash folder, or is the folder itself.
+func isTrashURL(URL, trashURL string) bool {
+	url := client.NewURL(URL)
+	if url.Type == client.Filesystem {
+		path, e := filepath.Abs(url.Path)
+		if e != nil {
+			return false
+		}
+		return strings.HasPrefix(path+string(os.PathSeparator), trashURL)
+	}
+	return strings.HasPrefix(strings.TrimSuffix(URL, "/")+"/", trashURL)
+}
+
+// trashEntryURL - URL of a file of a trash entry, or of the entry itself if name is empty.
+func trashEntryURL(trashURL, id, name string) string {
+	separator := string(client.NewURL(trashURL).Separator)
+	if name == "" {
+		return trashURL + id + separator
+	}
+	return trashURL + id + separator + name
+}
+
+// newTrashID - name of a new trash entry, sortable by time and readable.
+func newTrashID(url client.URL) string {
+	name := filepath.Base(strings.TrimSuffix(url.Path, string(url.Separator)))
+	return time.Now().UTC().Format("20060102T150405.000000000Z") + "-" + name
+}
+
+// isValidTrashID - true if an id names a single entry of a trash folder.
+func isValidTrashID(id string) bool {
+	if id == "" || id == "." || id == ".." {
+		return false
+	}
+	return !strings.ContainsAny(id, `/\`)
+}
+
+// moveObject - move an object without transferring its data where possible.
+// Files are renamed and objects copied on the server, other objects are
+// copied through the client and removed afterwards.
+func moveObject(sourceURL, targetURL string, size int64) *probe.Error {
+	source, target := client.NewURL(sourceURL), client.NewURL(targetURL)
+	if source.Type == client.Filesystem && target.Type == client.Filesystem {
+		if e := os.MkdirAll(filepath.Dir(target.Path), 0700); e != nil {
+			return probe.NewError(e).Trace(targetURL)
+		}
+		// Files on another device cannot be renamed and are copied below.
+		if e := os.Rename(source.Path, target.Path); e == nil {
+			return nil
+		}
+	}
+	sourceClnt, err := url2Client(sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	targetClnt, err := url2Client(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	err = targetClnt.Copy(globalContext, *source, "")
+	if _, ok := err.ToGoError().(client.APINotImplemented); ok {
+		var reader io.ReadSeeker
+		if reader, err = getSource(sourceURL); err != nil {
+			return err.Trace(sourceURL)
+		}
+		err = putTarget(targetURL, reader, size)
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	if err != nil {
+		return err.Trace(sourceURL, targetURL)
+	}
+	if err = sourceClnt.Remove(globalContext, false); err != nil {
+		return err.Trace(sourceURL)
+	}
+	return nil
+}
+
+// moveToTrash - move an object to trash along with its original URL.
+// Folders carry no data and are only removed.
+func moveToTrash(clnt client.Client) *probe.Error {
+	url := clnt.GetURL()
+	content, err := clnt.Stat(globalContext)
+	if err != nil {
+		return err.Trace(url.String())
+	}
+	if content.Type.IsDir() {
+		return clnt.Remove(globalContext, false).Trace(url.String())
+	}
+
+	originalURL := url.String()
+	if url.Type == client.Filesystem {
+		// Restore must not depend on the current working folder.
+		path, e := filepath.Abs(url.Path)
+		if e != nil {
+			return probe.NewError(e).Trace(originalURL)
+		}
+		originalURL = path
+	}
+	trashURL, err := getTrashURL(originalURL)
+	if err != nil {
+		return err.Trace(originalURL)
+	}
+	id := newTrashID(url)
+
+	dataURL := trashEntryURL(trashURL, id, trashDataName)
+	if err = moveObject(url.String(), dataURL, content.Size); err != nil {
+		return err.Trace(originalURL, trashURL)
+	}
+
+	info := trashInfo{
+		Version: trashInfoVersion,
+		URL:     originalURL,
+		Size:    content.Size,
+		Deleted: time.Now().UTC(),
+	}
+	infoBytes, e := json.Marshal(info)
+	if e != nil {
+		return probe.NewError(e).Trace(originalURL)
+	}
+	if err = putTarget(trashEntryURL(trashURL, id, trashInfoName), bytes.NewReader(infoBytes), int64(len(infoBytes))); err != nil {
+		// Entries without metadata are hidden, put the object back.
+		moveObject(dataURL, url.String(), content.Size)
+		return err.Trace(originalURL, trashURL)
+	}
+	return nil
+}
+
+// readTrashInfo - read the metadata of a trash entry.
+func readTrashInfo(trashURL, id string) (trashInfo, *probe.Error) {
+	if !isValidTrashID(id) {
+		return trashInfo{}, errInvalidTrashEntry(id).Trace(id)
+	}
+	infoURL := trashEntryURL(trashURL, id, trashInfoName)
+	reader, err := getSource(infoURL)
+	if err != nil {
+		return trashInfo{}, err.Trace(infoURL)
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	infoBytes, e := ioutil.ReadAll(reader)
+	if e != nil {
+		return trashInfo{}, probe.NewError(e).Trace(infoURL)
+	}
+	info := trashInfo{}
+	if e = json.Unmarshal(infoBytes, &info); e != nil || info.URL == "" {
+		return trashInfo{}, errInvalidTrashEntry(id).Trace(infoURL)
+	}
+	return info, nil
+}
+
+// listTrash - list all entries of a trash folder, oldest first. Entries
+// without readable metadata, such as interrupted removals, are reported
+// and skipped.
+func listTrash(trashURL string) ([]trashEntry, *probe.Error) {
+	url := client.NewURL(trashURL)
+	if url.Type == client.Filesystem {
+		// Nothing was ever removed.
+		if _, e := os.Stat(url.Path); os.IsNotExist(e) {
+			return nil, nil
+		}
+	}
+	clnt, err := url2Client(trashURL)
+	if err != nil {
+		return nil, err.Trace(trashURL)
+	}
+
+	var entries trashEntries
+	isRecursive := false
+	isIncomplete := false
+	for content := range clnt.List(globalContext, isRecursive, isIncomplete) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(trashURL)
+		}
+		if !content.Type.IsDir() {
+			continue
+		}
+		id := filepath.Base(strings.TrimSuffix(content.URL.Path, string(content.URL.Separator)))
+		info, err := readTrashInfo(trashURL, id)
+		if err != nil {
+			errorIf(err.Trace(trashURL, id), "Unable to read trash entry ‘"+id+"’.")
+			continue
+		}
+		entries = append(entries, trashEntry{ID: id, trashInfo: info})
+	}
+	sort.Sort(entries)
+	return entries, nil
+}
+
+// removeTrashEntry - permanently remove an entry from trash.
+func removeTrashEntry(trashURL, id string) *probe.Error {
+	if !isValidTrashID(id) {
+		return errInvalidTrashEntry(id).Trace(id)
+	}
+	// Data first, an entry without metadata would be hidden from listings.
+	if err := rm(trashEntryURL(trashURL, id, trashDataName), false, false, false); err != nil {
+		return err.Trace(trashURL, id)
+	}
+	return removeTrashInfo(trashURL, id)
+}
+
+// removeTrashInfo - remove the metadata of an entry whose data is gone.
+func removeTrashInfo(trashURL, id string) *probe.Error {
+	if err := rm(trashEntryURL(trashURL, id, trashInfoName), false, false, false); err != nil {
+		return err.Trace(trashURL, id)
+	}
+	// Object storage has no folders to clean up.
+	if client.NewURL(trashURL).Type == client.Filesystem {
+		if e := os.Remove(filepath.Join(client.NewURL(trashURL).Path, id)); e != nil {
+			return probe.NewError(e).Trace(trashURL, id)
+		}
+	}
+	return nil
+}
+
+// restoreTrashEntry - move an entry back to its original URL and remove it
+// from trash. An object which was created again since is never overwritten.
+func restoreTrashEntry(trashURL, id string) (trashInfo, *probe.Error) {
+	info, err := readTrashInfo(trashURL, id)
+	if err != nil {
+		return trashInfo{}, err.Trace(trashURL, id)
+	}
+	if _, _, err = url2Stat(info.URL); err == nil {
+		return trashInfo{}, errOverWriteNotAllowed(info.URL).Trace(info.URL)
+	}
+
+	dataURL := trashEntryURL(trashURL, id, trashDataName)
+	if err = moveObject(dataURL, info.URL, info.Size); err != nil {
+		return trashInfo{}, err.Trace(dataURL, info.URL)
+	}
+	if err = removeTrashInfo(trashURL, id); err != nil {
+		return trashInfo{}, err.Trace(trashURL, id)
+	}
+	return info, nil
+}
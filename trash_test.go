@@ -0,0 +1,128 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/mc/pkg/client/memory"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestTrashURL(c *C) {
+	trashURL, err := getTrashURL("https://s3.amazonaws.com/bucket/folder/object")
+	c.Assert(err, IsNil)
+	c.Assert(trashURL, Equals, "https://s3.amazonaws.com/bucket/.trash/")
+	c.Assert(isTrashURL("https://s3.amazonaws.com/bucket/.trash/id/data", trashURL), Equals, true)
+	c.Assert(isTrashURL("https://s3.amazonaws.com/bucket/.trash", trashURL), Equals, true)
+	c.Assert(isTrashURL("https://s3.amazonaws.com/bucket/folder/object", trashURL), Equals, false)
+
+	trashURL, err = getTrashURL("https://bucket.s3.amazonaws.com/object")
+	c.Assert(err, IsNil)
+	c.Assert(trashURL, Equals, "https://bucket.s3.amazonaws.com/.trash/")
+
+	_, err = getTrashURL("https://s3.amazonaws.com")
+	c.Assert(err, Not(IsNil))
+
+	c.Assert(isValidTrashID("20151104T071521.000000000Z-object"), Equals, true)
+	c.Assert(isValidTrashID(".."), Equals, false)
+	c.Assert(isValidTrashID("../config.json"), Equals, false)
+}
+
+func (s *TestSuite) TestTrash(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "trash-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	c.Assert(os.MkdirAll(filepath.Join(root, "folder"), 0700), IsNil)
+	object1 := filepath.Join(root, "object1")
+	object2 := filepath.Join(root, "folder", "object2")
+	c.Assert(ioutil.WriteFile(object1, []byte("hello"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(object2, []byte("world"), 0600), IsNil)
+
+	trashURL, err := getTrashURL(root)
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(trashURL)
+
+	rmAll(root+string(os.PathSeparator), true, false, false, true, rmFilter{})
+	_, e = os.Stat(object1)
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	entries, err := listTrash(trashURL)
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 2)
+	urls := map[string]string{}
+	for _, entry := range entries {
+		c.Assert(entry.Size, Equals, int64(5))
+		urls[entry.URL] = entry.ID
+	}
+	c.Assert(urls[object1], Not(Equals), "")
+	c.Assert(urls[object2], Not(Equals), "")
+
+	// Restore recreates the folder and never overwrites.
+	info, err := restoreTrashEntry(trashURL, urls[object2])
+	c.Assert(err, IsNil)
+	c.Assert(info.URL, Equals, object2)
+	data, e := ioutil.ReadFile(object2)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "world")
+
+	c.Assert(ioutil.WriteFile(object1, []byte("new"), 0600), IsNil)
+	_, err = restoreTrashEntry(trashURL, urls[object1])
+	c.Assert(err, Not(IsNil))
+
+	c.Assert(removeTrashEntry(trashURL, urls[object1]), IsNil)
+	entries, err = listTrash(trashURL)
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 0)
+}
+
+func (s *TestSuite) TestTrashMemory(c *C) {
+	memory.Reset()
+	defer memory.Reset()
+
+	clnt, err := url2Client("mem://bucket")
+	c.Assert(err, IsNil)
+	c.Assert(clnt.MakeBucket(globalContext), IsNil)
+	clnt, err = url2Client("mem://bucket/object")
+	c.Assert(err, IsNil)
+	c.Assert(clnt.PutWithMetadata(globalContext, bytes.NewReader([]byte("hello")), 5, map[string]string{"Color": "blue"}), IsNil)
+
+	// Objects are copied on the server, keeping their metadata.
+	c.Assert(rm("mem://bucket/object", false, false, true), IsNil)
+	_, err = clnt.Stat(globalContext)
+	c.Assert(err, Not(IsNil))
+	trashURL, err := getTrashURL("mem://bucket/object")
+	c.Assert(err, IsNil)
+	entries, err := listTrash(trashURL)
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 1)
+
+	_, err = restoreTrashEntry(trashURL, entries[0].ID)
+	c.Assert(err, IsNil)
+	c.Assert(readMemory(c, "mem://bucket/object"), Equals, "hello")
+	content, err := clnt.Stat(globalContext)
+	c.Assert(err, IsNil)
+	c.Assert(content.Metadata, DeepEquals, map[string]string{"Color": "blue"})
+	entries, err = listTrash(trashURL)
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 0)
+}
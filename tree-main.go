@@ -0,0 +1,208 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// tree specific flags.
+var (
+	treeFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of tree.",
+		},
+		cli.IntFlag{
+			Name:  "depth, d",
+			Usage: "Descend at most this many levels of folders, 0 is unlimited.",
+		},
+		cli.BoolFlag{
+			Name:  "files, f",
+			Usage: "Show files and objects, not only folders.",
+		},
+	}
+)
+
+// show folders as a tree.
+var treeCmd = cli.Command{
+	Name:   "tree",
+	Usage:  "List folders and prefixes as a tree.",
+	Action: mainTree,
+	Flags:  append(treeFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Show all prefixes of a bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} s3/mybucket
+
+   2. Show the first two levels of prefixes and their objects.
+      $ mc {{.Name}} --depth 2 --files s3/mybucket/photos/
+
+   3. Show a local folder as nested JSON.
+      $ mc --json {{.Name}} backup/
+`,
+}
+
+// treeNode - a folder or file and, for folders, everything below it.
+type treeNode struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Size     int64       `json:"size,omitempty"`
+	Children []*treeNode `json:"children,omitempty"`
+}
+
+// treeMessage container for a whole tree.
+type treeMessage struct {
+	Status string    `json:"status"`
+	URL    string    `json:"url"`
+	Tree   *treeNode `json:"tree"`
+}
+
+// String colorized tree, drawn with box-drawing characters.
+func (t treeMessage) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(console.Colorize("Dir", t.URL))
+	for i, child := range t.Tree.Children {
+		writeTreeNode(&buf, child, "", i == len(t.Tree.Children)-1)
+	}
+	return buf.String()
+}
+
+// writeTreeNode - write a node and its children, indented by prefix.
+func writeTreeNode(buf *bytes.Buffer, node *treeNode, prefix string, isLast bool) {
+	branch, indent := "├─ ", "│  "
+	if isLast {
+		branch, indent = "└─ ", "   "
+	}
+	buf.WriteString("\n" + prefix + branch)
+	if node.Type == "folder" {
+		buf.WriteString(console.Colorize("Dir", node.Name+"/"))
+	} else {
+		buf.WriteString(console.Colorize("File", node.Name))
+	}
+	for i, child := range node.Children {
+		writeTreeNode(buf, child, prefix+indent, i == len(node.Children)-1)
+	}
+}
+
+// JSON jsonified tree, folders nest their children.
+func (t treeMessage) JSON() string {
+	t.Status = "success"
+	treeJSONBytes, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(treeJSONBytes)
+}
+
+// checkTreeSyntax - validate all the passed arguments.
+func checkTreeSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "tree", 1) // last argument is exit code.
+	}
+	if ctx.Int("depth") < 0 {
+		fatalIf(errInvalidArgument().Trace(), "‘--depth’ cannot be negative.")
+	}
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse argument ‘"+ctx.Args().First()+"’.")
+	if len(URLs) != 1 {
+		fatalIf(errInvalidArgument().Trace(URLs...), "‘"+ctx.Args().First()+"’ matches more than one target.")
+	}
+	_, content, err := url2Stat(URLs[0])
+	fatalIf(err.Trace(URLs[0]), "Unable to stat ‘"+URLs[0]+"’.")
+	if !content.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(URLs[0]), "‘"+URLs[0]+"’ is not a folder.")
+	}
+}
+
+// buildTree - list a folder one level at a time, descending into its
+// sub-folders up to depth levels, all of them if depth is 0. Files are
+// only kept if isFiles. Listing errors are reported and leave a folder empty.
+func buildTree(folderURL string, depth int, isFiles bool) *treeNode {
+	node := &treeNode{Type: "folder"}
+	url := client.NewURL(folderURL)
+	separator := string(url.Separator)
+	if !strings.HasSuffix(url.Path, separator) {
+		// Lists the folder itself otherwise.
+		folderURL += separator
+	}
+	clnt, err := url2Client(folderURL)
+	if err != nil {
+		errorIf(err.Trace(folderURL), "Unable to initialize target ‘"+folderURL+"’.")
+		return node
+	}
+	isRecursive := false
+	isIncomplete := false
+	for content := range clnt.List(globalContext, isRecursive, isIncomplete) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(folderURL), "Unable to list folder ‘"+folderURL+"’.")
+			break
+		}
+		if !content.Type.IsDir() {
+			if isFiles {
+				node.Children = append(node.Children, &treeNode{Name: contentName(content), Type: "file", Size: content.Size})
+			}
+			continue
+		}
+		child := &treeNode{Type: "folder"}
+		switch depth {
+		case 0: // Unlimited.
+			child = buildTree(content.URL.String(), 0, isFiles)
+		case 1: // Deepest level, not listed.
+		default:
+			child = buildTree(content.URL.String(), depth-1, isFiles)
+		}
+		child.Name = contentName(content)
+		node.Children = append(node.Children, child)
+	}
+	return node
+}
+
+// mainTree is the entry point for tree command.
+func mainTree(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'tree' cli arguments.
+	checkTreeSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
+	console.SetColor("File", color.New(color.FgWhite))
+
+	URLs, err := args2URLs(ctx.Args())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse argument ‘"+ctx.Args().First()+"’.")
+
+	tree := buildTree(URLs[0], ctx.Int("depth"), ctx.Bool("files"))
+	tree.Name = contentName(&client.Content{URL: *client.NewURL(URLs[0])})
+	printMsg(treeMessage{URL: URLs[0], Tree: tree})
+}
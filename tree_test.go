@@ -0,0 +1,45 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestTree(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "tree-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	c.Assert(os.MkdirAll(filepath.Join(root, "a", "b", "c"), 0700), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(root, "d"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "a", "object"), []byte("hello"), 0600), IsNil)
+
+	tree := buildTree(root, 0, false)
+	c.Assert(treeMessage{URL: "root", Tree: tree}.String(), Equals, "root\n├─ a/\n│  └─ b/\n│     └─ c/\n└─ d/")
+
+	tree = buildTree(root, 1, true)
+	c.Assert(treeMessage{URL: "root", Tree: tree}.String(), Equals, "root\n├─ a/\n└─ d/")
+
+	tree = buildTree(root, 2, true)
+	c.Assert(treeMessage{URL: "root", Tree: tree}.String(), Equals, "root\n├─ a/\n│  ├─ b/\n│  └─ object\n└─ d/")
+	c.Assert(tree.Children[0].Children[1].Size, Equals, int64(5))
+}
@@ -18,6 +18,8 @@ package main
 
 import (
 	"errors"
+	"strconv"
+	"time"
 
 	"github.com/minio/minio-xl/pkg/probe"
 )
@@ -58,4 +60,64 @@ var (
 	errSourceTargetSame = func(URL string) *probe.Error {
 		return probe.NewError(errors.New("Source and target URL can not be same : " + URL)).Untrace()
 	}
+
+	errInvalidRewind = func(value string) *probe.Error {
+		return probe.NewError(errors.New("Invalid time value ‘" + value + "’, please use a date, an RFC3339 time or a duration such as ‘7d’.")).Untrace()
+	}
+
+	errNoVersionAt = func(URL string, t time.Time) *probe.Error {
+		return probe.NewError(errors.New("No version of ‘" + URL + "’ existed at ‘" + t.Format(printDate) + "’.")).Untrace()
+	}
+
+	errDangerousRemove = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("‘" + URL + "’ points to all buckets of a host or to a filesystem root. Please review carefully and use ‘--force --dangerous’ to perform this *DANGEROUS* operation.")).Untrace()
+	}
+
+	errArchiveMoveResume = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("Moves into archives cannot be resumed, ‘" + URL + "’ would be written anew without the entries moved so far. Please clear the session, the archive holds them.")).Untrace()
+	}
+
+	errNotBucket = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("‘" + URL + "’ is not a bucket.")).Untrace()
+	}
+
+	errInvalidTag = func(tag string) *probe.Error {
+		return probe.NewError(errors.New("Invalid tag ‘" + tag + "’, please use ‘key=value’ with a key of at most 128 and a value of at most 256 characters.")).Untrace()
+	}
+
+	errTooManyTags = func(count int) *probe.Error {
+		return probe.NewError(errors.New("Too many tags ‘" + strconv.Itoa(count) + "’, at most 10 tags are allowed per object.")).Untrace()
+	}
+
+	errInvalidEvent = func(event string) *probe.Error {
+		return probe.NewError(errors.New("Invalid event type ‘" + event + "’, please use ‘put’, ‘delete’ or ‘get’.")).Untrace()
+	}
+
+	errInvalidCORS = func(reason string) *probe.Error {
+		return probe.NewError(errors.New("Invalid CORS configuration, " + reason)).Untrace()
+	}
+
+	errInvalidWebsite = func(reason string) *probe.Error {
+		return probe.NewError(errors.New("Invalid website configuration, " + reason)).Untrace()
+	}
+
+	errInvalidSize = func(value string) *probe.Error {
+		return probe.NewError(errors.New("Invalid size ‘" + value + "’, please use a size such as ‘512KiB’ or ‘10MB’.")).Untrace()
+	}
+
+	errNoTrash = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("No trash location for ‘" + URL + "’, please point to a bucket or configure one with ‘mc config host trash’.")).Untrace()
+	}
+
+	errInvalidTrashEntry = func(id string) *probe.Error {
+		return probe.NewError(errors.New("Invalid trash entry ‘" + id + "’.")).Untrace()
+	}
+
+	errTargetsFailed = func(failed, total int) *probe.Error {
+		return probe.NewError(errors.New(strconv.Itoa(failed) + " of " + strconv.Itoa(total) + " targets failed.")).Untrace()
+	}
+
+	errInvalidSQL = func(reason string) *probe.Error {
+		return probe.NewError(errors.New("Invalid SQL query, " + reason + ".")).Untrace()
+	}
 )
@@ -123,7 +123,7 @@ func getReleaseUpdate(updateURL string) {
 	clnt, err := url2Client(updateURL)
 	fatalIf(err.Trace(updateURL), "Unable to initalize update URL.")
 
-	data, err := clnt.Get(0, 0)
+	data, err := clnt.Get(globalContext, 0, 0)
 	fatalIf(err.Trace(updateURL), "Unable to read from update URL ‘"+updateURL+"’.")
 
 	if mcVersion == "UNOFFICIAL.GOGET" {
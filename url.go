@@ -17,7 +17,9 @@
 package main
 
 import (
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/minio/mc/pkg/client"
 	"github.com/minio/minio-xl/pkg/probe"
@@ -29,6 +31,18 @@ func isURLVirtualHostStyle(hostURL string) bool {
 	return matchS3 || matchGoogle
 }
 
+// isBucketURL - true if an object storage URL points to a bucket and not to an object.
+func isBucketURL(url client.URL) bool {
+	if url.Type != client.Object {
+		return false
+	}
+	path := strings.Trim(url.Path, string(url.Separator))
+	if isURLVirtualHostStyle(url.Host) {
+		return path == ""
+	}
+	return path != "" && !strings.Contains(path, string(url.Separator))
+}
+
 // urlJoinPath Join a path to existing URL.
 func urlJoinPath(url1, url2 string) string {
 	u1 := client.NewURL(url1)
@@ -36,9 +50,29 @@ func urlJoinPath(url1, url2 string) string {
 	return client.JoinURLs(u1, u2).String()
 }
 
-// args2URLs extracts source and target URLs from command-line args.
+// args2URLs extracts source URLs from command-line args, wildcards of
+// object storage URLs are expanded to all matching objects.
 func args2URLs(args []string) ([]string, *probe.Error) {
 	// convert arguments to URLs: expand alias, fix format...
+	URLs := []string{}
+	for _, arg := range args {
+		aliasedURL, err := getAliasURL(arg)
+		if err != nil {
+			return nil, err.Trace(arg)
+		}
+		// Shells only expand wildcards of local paths.
+		expandedURLs, err := expandGlobURL(aliasedURL)
+		if err != nil {
+			return nil, err.Trace(arg)
+		}
+		URLs = append(URLs, expandedURLs...)
+	}
+	return URLs, nil
+}
+
+// args2TargetURLs extracts target URLs from command-line args, they are
+// taken literally so that they never expand to existing objects.
+func args2TargetURLs(args []string) ([]string, *probe.Error) {
 	URLs := []string{}
 	for _, arg := range args {
 		aliasedURL, err := getAliasURL(arg)
@@ -50,6 +84,23 @@ func args2URLs(args []string) ([]string, *probe.Error) {
 	return URLs, nil
 }
 
+// args2CopyURLs extracts URLs from command-line args of a copy, sources
+// are expanded while the target, the last argument, is taken literally.
+func args2CopyURLs(args []string) ([]string, *probe.Error) {
+	if len(args) == 0 {
+		return []string{}, nil
+	}
+	sourceURLs, err := args2URLs(args[:len(args)-1])
+	if err != nil {
+		return nil, err.Trace(args...)
+	}
+	targetURLs, err := args2TargetURLs(args[len(args)-1:])
+	if err != nil {
+		return nil, err.Trace(args...)
+	}
+	return append(sourceURLs, targetURLs...), nil
+}
+
 // url2Client convenience wrapper for getNewClient.
 func url2Client(urlStr string) (client.Client, *probe.Error) {
 	urlConfig, err := getHostConfig(urlStr)
@@ -69,7 +120,7 @@ func url2Stat(urlStr string) (client client.Client, content *client.Content, err
 	if err != nil {
 		return nil, nil, err.Trace(urlStr)
 	}
-	content, err = client.Stat()
+	content, err = client.Stat(globalContext)
 	if err != nil {
 		return nil, nil, err.Trace(urlStr)
 	}
@@ -84,7 +135,7 @@ func isURLPrefixExists(urlPrefix string, incomplete bool) bool {
 	}
 	isRecursive := true
 	isIncomplete := incomplete
-	for entry := range clnt.List(isRecursive, isIncomplete) {
+	for entry := range clnt.List(globalContext, isRecursive, isIncomplete) {
 		if entry.Err != nil {
 			return false
 		}
@@ -92,3 +143,145 @@ func isURLPrefixExists(urlPrefix string, incomplete bool) bool {
 	}
 	return false
 }
+
+// globChars - characters with a special meaning in wildcard patterns.
+const globChars = "*?["
+
+// hasGlob - true if a path contains a wildcard pattern.
+func hasGlob(path string) bool {
+	return strings.ContainsAny(path, globChars)
+}
+
+// expandGlobURL - expand wildcards ‘*’, ‘?’ and ‘[...]’ of an object storage
+// URL to all matching objects and folders, like a shell does for local
+// paths. The URL is returned as it is if an object or folder of that name
+// exists, it is not a valid pattern or nothing matches, object names may
+// contain these characters literally.
+func expandGlobURL(URL string) ([]string, *probe.Error) {
+	url := client.NewURL(URL)
+	if url.Type != client.Object {
+		return []string{URL}, nil
+	}
+	// NewURL drops a query, which is what a ‘?’ wildcard looks like.
+	url.Path = strings.TrimPrefix(URL, url.Scheme+url.SchemeSeparator+url.Host)
+	if !hasGlob(url.Path) {
+		return []string{URL}, nil
+	}
+	// Literal names win over patterns.
+	if _, _, err := url2Stat(URL); err == nil {
+		return []string{URL}, nil
+	}
+	separator := string(url.Separator)
+	segments := strings.Split(strings.Trim(url.Path, separator), separator)
+	for _, segment := range segments {
+		if _, e := path.Match(segment, ""); e != nil {
+			return []string{URL}, nil
+		}
+	}
+	// Like shells, a trailing separator matches folders only.
+	isDir := strings.HasSuffix(url.Path, separator)
+
+	rootURL := *url
+	rootURL.Path = separator
+	matches, err := expandGlobSegments(rootURL, segments, isDir)
+	if err != nil {
+		return nil, err.Trace(URL)
+	}
+	if len(matches) == 0 {
+		return []string{URL}, nil
+	}
+	return matches, nil
+}
+
+// expandGlobSegments - expand the first wildcard segment of a path below
+// baseURL, then the remaining segments below each match.
+func expandGlobSegments(baseURL client.URL, segments []string, isDir bool) ([]string, *probe.Error) {
+	separator := string(baseURL.Separator)
+	// Literal segments are taken as they are.
+	for len(segments) > 0 && !hasGlob(segments[0]) {
+		baseURL.Path = strings.TrimSuffix(baseURL.Path, separator) + separator + segments[0]
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		if isDir {
+			baseURL.Path += separator
+		}
+		return []string{baseURL.String()}, nil
+	}
+
+	pattern := segments[0]
+	isLast := len(segments) == 1
+	names, err := listGlobCandidates(baseURL, pattern[:strings.IndexAny(pattern, globChars)], !isLast || isDir)
+	if err != nil {
+		return nil, err.Trace(baseURL.String())
+	}
+	var matches []string
+	for _, name := range names {
+		if matched, _ := path.Match(pattern, name); !matched {
+			continue
+		}
+		matchURL := baseURL
+		matchURL.Path = strings.TrimSuffix(baseURL.Path, separator) + separator + name
+		expanded, err := expandGlobSegments(matchURL, segments[1:], isDir)
+		if err != nil {
+			return nil, err.Trace(matchURL.String())
+		}
+		matches = append(matches, expanded...)
+	}
+	return matches, nil
+}
+
+// listGlobCandidates - names of all objects and folders directly below
+// baseURL starting with prefix, only folders if isDir.
+func listGlobCandidates(baseURL client.URL, prefix string, isDir bool) ([]string, *probe.Error) {
+	separator := string(baseURL.Separator)
+	listURL := baseURL
+	listURL.Path = strings.TrimSuffix(baseURL.Path, separator) + separator + prefix
+	// Buckets can only be listed all at once.
+	if baseURL.Path == separator && !isURLVirtualHostStyle(baseURL.Host) {
+		listURL.Path = separator
+	}
+	contents, err := listContents(listURL)
+	if err != nil {
+		return nil, err.Trace(listURL.String())
+	}
+	// Listing a prefix which is also the name of an object only returns
+	// that object, list its whole folder instead.
+	if len(contents) == 1 && contentName(contents[0]) == prefix && prefix != "" {
+		listURL.Path = strings.TrimSuffix(baseURL.Path, separator) + separator
+		if contents, err = listContents(listURL); err != nil {
+			return nil, err.Trace(listURL.String())
+		}
+	}
+	var names []string
+	for _, content := range contents {
+		if isDir && !content.Type.IsDir() {
+			continue
+		}
+		names = append(names, contentName(content))
+	}
+	return names, nil
+}
+
+// listContents - all entries of a non recursive listing.
+func listContents(listURL client.URL) ([]*client.Content, *probe.Error) {
+	clnt, err := url2Client(listURL.String())
+	if err != nil {
+		return nil, err.Trace(listURL.String())
+	}
+	var contents []*client.Content
+	isRecursive := false
+	isIncomplete := false
+	for content := range clnt.List(globalContext, isRecursive, isIncomplete) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(listURL.String())
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// contentName - last element of the path of a listed object or folder.
+func contentName(content *client.Content) string {
+	return path.Base(strings.TrimSuffix(filepath.ToSlash(content.URL.Path), "/"))
+}
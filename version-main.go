@@ -19,9 +19,11 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio-xl/pkg/probe"
 )
@@ -38,7 +40,7 @@ var (
 // Print version.
 var versionCmd = cli.Command{
 	Name:   "version",
-	Usage:  "Print version.",
+	Usage:  "Print version, or manage bucket versioning.",
 	Action: mainVersion,
 	Flags:  append(versionFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
@@ -46,10 +48,23 @@ var versionCmd = cli.Command{
 
 USAGE:
    mc {{.Name}} [FLAGS]
+   mc {{.Name}} enable|suspend|info TARGET [TARGET...]
 
 FLAGS:
   {{range .Flags}}{{.}}
   {{end}}
+EXAMPLES:
+   1. Print version of mc.
+      $ mc {{.Name}}
+
+   2. Enable versioning on bucket ‘mybucket’ on Amazon S3 cloud storage.
+      $ mc {{.Name}} enable s3/mybucket
+
+   3. Suspend versioning on bucket ‘mybucket’ on Amazon S3 cloud storage.
+      $ mc {{.Name}} suspend s3/mybucket
+
+   4. Show versioning status of multiple buckets.
+      $ mc {{.Name}} info s3/mybucket play/backups
 `,
 }
 
@@ -79,10 +94,107 @@ func (v versionMessage) JSON() string {
 	return string(msgBytes)
 }
 
+// versioningMessage container for bucket versioning messages.
+type versioningMessage struct {
+	Status     string `json:"status"`
+	Operation  string `json:"operation"`
+	URL        string `json:"url"`
+	Versioning string `json:"versioning"`
+}
+
+// String colorized versioning message.
+func (v versioningMessage) String() string {
+	switch v.Operation {
+	case "enable":
+		return console.Colorize("Versioning", "Versioning is enabled for ‘"+v.URL+"’.")
+	case "suspend":
+		return console.Colorize("Versioning", "Versioning is suspended for ‘"+v.URL+"’.")
+	}
+	switch v.Versioning {
+	case client.VersioningEnabled:
+		return console.Colorize("Versioning", "Versioning is enabled for ‘"+v.URL+"’.")
+	case client.VersioningSuspended:
+		return console.Colorize("Versioning", "Versioning is suspended for ‘"+v.URL+"’.")
+	}
+	return console.Colorize("Versioning", "Versioning was never enabled for ‘"+v.URL+"’.")
+}
+
+// JSON jsonified versioning message.
+func (v versioningMessage) JSON() string {
+	v.Status = "success"
+	versioningMessageBytes, e := json.Marshal(v)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(versioningMessageBytes)
+}
+
+// checkVersionSyntax - validate all the passed arguments.
+func checkVersionSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		return
+	}
+	switch ctx.Args().First() {
+	case "enable", "suspend", "info":
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "version", 1) // last argument is exit code
+	}
+	if len(ctx.Args().Tail()) < 1 {
+		cli.ShowCommandHelpAndExit(ctx, "version", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args().Tail() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+}
+
+// doVersioning - enable, suspend or show versioning of a bucket.
+func doVersioning(operation, targetURL string) *probe.Error {
+	clnt, err := url2Client(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	switch operation {
+	case "enable":
+		err = clnt.SetVersioning(globalContext, client.VersioningEnabled)
+	case "suspend":
+		err = clnt.SetVersioning(globalContext, client.VersioningSuspended)
+	}
+	if err != nil {
+		return err.Trace(targetURL, operation)
+	}
+	status, err := clnt.GetVersioning(globalContext)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	printMsg(versioningMessage{
+		Operation:  operation,
+		URL:        clnt.GetURL().String(),
+		Versioning: status,
+	})
+	return nil
+}
+
 func mainVersion(ctx *cli.Context) {
 	// Set global flags from context.
 	setGlobalsFromContext(ctx)
 
+	// check 'version' cli arguments.
+	checkVersionSyntax(ctx)
+
+	if ctx.Args().Present() {
+		// Additional command speific theme customization.
+		console.SetColor("Versioning", color.New(color.FgGreen, color.Bold))
+
+		operation := ctx.Args().First()
+		URLs, err := args2URLs(ctx.Args().Tail())
+		fatalIf(err.Trace(ctx.Args()...), "One or more unknown URL types passed.")
+		for _, targetURL := range URLs {
+			errorIf(doVersioning(operation, targetURL).Trace(targetURL), "Versioning ‘"+operation+"’ failed for ‘"+targetURL+"’.")
+		}
+		return
+	}
+
 	// Additional command speific theme customization.
 	console.SetColor("Version", color.New(color.FgGreen, color.Bold))
 	console.SetColor("ReleaseTag", color.New(color.FgGreen))
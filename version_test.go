@@ -27,3 +27,51 @@ func (s *TestSuite) TestVersion(c *C) {
 	_, err := time.Parse(mcVersion, http.TimeFormat)
 	c.Assert(err, NotNil)
 }
+
+func (s *TestSuite) TestParseRewind(c *C) {
+	t, err := parseRewind("2016-01-02T15:04:05Z")
+	c.Assert(err, IsNil)
+	c.Assert(t.Equal(time.Date(2016, 1, 2, 15, 4, 5, 0, time.UTC)), Equals, true)
+
+	t, err = parseRewind("2016-01-02")
+	c.Assert(err, IsNil)
+	c.Assert(t.Equal(time.Date(2016, 1, 2, 0, 0, 0, 0, time.Local)), Equals, true)
+
+	t, err = parseRewind("7d")
+	c.Assert(err, IsNil)
+	c.Assert(time.Since(t) >= 7*24*time.Hour, Equals, true)
+
+	t, err = parseRewind("36h")
+	c.Assert(err, IsNil)
+	c.Assert(time.Since(t) >= 36*time.Hour, Equals, true)
+
+	_, err = parseRewind("yesterday")
+	c.Assert(err, Not(IsNil))
+	_, err = parseRewind("-1h")
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *TestSuite) TestRewind(c *C) {
+	// Versions of the test server were modified on 2016-01-01 and 2016-01-03.
+	t := "2016-01-02T00:00:00Z"
+
+	// Removed objects are copied as they were, later ones are not.
+	var urls []string
+	for cpURLs := range prepareCopyURLs([]string{server.URL + "/bucket/folder"}, server.URL+"/bucket/copy/", true, t) {
+		c.Assert(cpURLs.Error, IsNil)
+		c.Assert(cpURLs.SourceContent.VersionID, Equals, "v1")
+		urls = append(urls, cpURLs.SourceContent.URL.String())
+	}
+	c.Assert(urls, DeepEquals, []string{server.URL + "/bucket/folder/object1", server.URL + "/bucket/folder/object2"})
+
+	folderClnt, err := url2Client(server.URL + "/bucket/folder/")
+	c.Assert(err, IsNil)
+	restoreRecursive(folderClnt, t)
+	handler := server.Config.Handler.(objectAPIHandler)
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+	c.Assert(handler.copies, DeepEquals, map[string]string{
+		"/bucket/folder/object1": "/bucket/folder/object1?versionId=v1",
+		"/bucket/folder/object2": "/bucket/folder/object2?versionId=v1",
+	})
+}
@@ -0,0 +1,154 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// parseRewind - parse the value of ‘--rewind’, which is either an absolute
+// time (RFC3339 or 2006-01-02) or a duration into the past such as 7d or 36h.
+func parseRewind(value string) (time.Time, *probe.Error) {
+	value = strings.TrimSpace(value)
+	if t, e := time.Parse(time.RFC3339, value); e == nil {
+		return t, nil
+	}
+	if t, e := time.ParseInLocation("2006-01-02", value, time.Local); e == nil {
+		return t, nil
+	}
+	// time.ParseDuration() does not understand days.
+	if strings.HasSuffix(value, "d") {
+		days, e := strconv.ParseUint(strings.TrimSuffix(value, "d"), 10, 32)
+		if e != nil {
+			return time.Time{}, errInvalidRewind(value).Trace(value)
+		}
+		return time.Now().Add(-time.Duration(days) * 24 * time.Hour), nil
+	}
+	duration, e := time.ParseDuration(value)
+	if e != nil || duration < 0 {
+		return time.Time{}, errInvalidRewind(value).Trace(value)
+	}
+	return time.Now().Add(-duration), nil
+}
+
+// versionAt - find the version of an object which was current at time ‘t’.
+func versionAt(clnt client.Client, t time.Time) (*client.Content, *probe.Error) {
+	objectPath := path.Clean(clnt.GetURL().Path)
+	var found *client.Content
+	for content := range clnt.ListVersions(globalContext, false) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(clnt.GetURL().String())
+		}
+		// Listing is by prefix, skip other objects sharing the same prefix.
+		if path.Clean(content.URL.Path) != objectPath {
+			continue
+		}
+		if content.Time.After(t) {
+			continue
+		}
+		if found == nil || content.Time.After(found.Time) {
+			found = content
+		}
+	}
+	if found == nil || found.IsDeleteMarker {
+		return nil, errNoVersionAt(clnt.GetURL().String(), t).Trace(clnt.GetURL().String())
+	}
+	return found, nil
+}
+
+// versionsAt - the versions of all objects under a URL which were current at
+// time ‘t’, found with a single listing of all versions. Objects removed
+// since are included, those removed by then or created later are not.
+// Folders of listings which are not recursive are passed on as is.
+func versionsAt(clnt client.Client, isRecursive bool, t time.Time) <-chan *client.Content {
+	contentCh := make(chan *client.Content)
+	go func() {
+		defer close(contentCh)
+		url := clnt.GetURL()
+		separator := string(url.Separator)
+		// Listing is by prefix, a URL without a trailing separator names an object or folder.
+		isUnder := func(name string) bool {
+			return strings.HasSuffix(url.Path, separator) || name == url.Path || strings.HasPrefix(name, url.Path+separator)
+		}
+		var found *client.Content
+		flush := func() {
+			if found != nil && !found.IsDeleteMarker {
+				contentCh <- found
+			}
+			found = nil
+		}
+		// Versions of an object are listed together.
+		for content := range clnt.ListVersions(globalContext, isRecursive) {
+			if content.Err != nil {
+				contentCh <- content
+				return
+			}
+			if !isUnder(strings.TrimSuffix(content.URL.Path, separator)) {
+				continue
+			}
+			if content.Type.IsDir() {
+				flush()
+				contentCh <- content
+				continue
+			}
+			if found != nil && found.URL.Path != content.URL.Path {
+				flush()
+			}
+			if content.Time.After(t) {
+				continue
+			}
+			if found == nil || content.Time.After(found.Time) {
+				found = content
+			}
+		}
+		flush()
+	}()
+	return contentCh
+}
+
+// resolveVersion - metadata of the object version selected by either
+// ‘--version-id’ or ‘--rewind’, nil if neither of them is set.
+func resolveVersion(clnt client.Client, versionID, rewind string) (*client.Content, *probe.Error) {
+	switch {
+	case versionID != "":
+		content, err := clnt.StatVersion(globalContext, versionID)
+		if err != nil {
+			return nil, err.Trace(clnt.GetURL().String(), versionID)
+		}
+		if content.IsDeleteMarker {
+			return nil, probe.NewError(client.VersionNotFound{Path: clnt.GetURL().String(), VersionID: versionID})
+		}
+		return content, nil
+	case rewind != "":
+		t, err := parseRewind(rewind)
+		if err != nil {
+			return nil, err.Trace(rewind)
+		}
+		content, err := versionAt(clnt, t)
+		if err != nil {
+			return nil, err.Trace(clnt.GetURL().String(), rewind)
+		}
+		return content, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,159 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// watch specific flags.
+var (
+	watchFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of watch.",
+		},
+		cli.StringFlag{
+			Name:  "events",
+			Value: "put,delete",
+			Usage: "Comma separated list of event types: put, delete, get.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Watch all sub-folders recursively.",
+		},
+	}
+)
+
+// Watch for events on a target.
+var watchCmd = cli.Command{
+	Name:   "watch",
+	Usage:  "Watch for changes of files and objects.",
+	Action: mainWatch,
+	Flags:  append(watchFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Watch uploads and removals of all objects in a bucket.
+      $ mc {{.Name}} --recursive play/photos
+
+   2. Watch downloads of objects under a prefix.
+      $ mc {{.Name}} --events get play/photos/2016/
+
+   3. Watch a local folder and all its sub-folders.
+      $ mc {{.Name}} --recursive ~/Photos
+`,
+}
+
+// watchMessage container for watched events.
+type watchMessage struct {
+	Status string    `json:"status"`
+	Event  string    `json:"event"`
+	Time   time.Time `json:"time"`
+	Size   int64     `json:"size"`
+	URL    string    `json:"url"`
+}
+
+// String colorized watch message.
+func (w watchMessage) String() string {
+	message := console.Colorize("Time", fmt.Sprintf("[%s] ", w.Time.Local().Format(printDate)))
+	message += console.Colorize("Event", fmt.Sprintf("%-6s ", strings.ToUpper(w.Event)))
+	if w.Event != client.EventRemove {
+		message += console.Colorize("Size", fmt.Sprintf("%6s ", humanize.IBytes(uint64(w.Size))))
+	} else {
+		message += fmt.Sprintf("%6s ", "")
+	}
+	return message + console.Colorize("URL", w.URL)
+}
+
+// JSON jsonified watch message.
+func (w watchMessage) JSON() string {
+	w.Status = "success"
+	watchMessageBytes, e := json.Marshal(w)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(watchMessageBytes)
+}
+
+// checkWatchSyntax - validate all the passed arguments.
+func checkWatchSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "watch", 1) // last argument is exit code.
+	}
+	if strings.TrimSpace(ctx.Args().First()) == "" {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+	}
+	_, err := parseEvents(ctx.String("events"))
+	fatalIf(err.Trace(ctx.String("events")), "Unable to parse event types.")
+}
+
+// mainWatch - is a handler for mc watch command
+func mainWatch(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'watch' cli arguments.
+	checkWatchSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Event", color.New(color.FgYellow, color.Bold))
+	console.SetColor("Size", color.New(color.FgYellow))
+	console.SetColor("URL", color.New(color.FgCyan, color.Bold))
+
+	events, _ := parseEvents(ctx.String("events"))
+	isRecursive := ctx.Bool("recursive")
+
+	targetURL, err := getAliasURL(ctx.Args().First())
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse argument ‘"+ctx.Args().First()+"’.")
+	clnt, err := url2Client(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+	// Watch until interrupted.
+	signalTrap(os.Interrupt, syscall.SIGTERM)
+
+	eventCh, err := clnt.Watch(globalContext, events, isRecursive)
+	fatalIf(err.Trace(targetURL), "Unable to watch ‘"+targetURL+"’.")
+	for event := range eventCh {
+		fatalIf(event.Err.Trace(targetURL), "Unable to watch ‘"+targetURL+"’.")
+		printMsg(watchMessage{
+			Event: event.Type,
+			Time:  event.Time,
+			Size:  event.Size,
+			URL:   event.URL.String(),
+		})
+	}
+}
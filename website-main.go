@@ -0,0 +1,184 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/client"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio-xl/pkg/probe"
+)
+
+// website specific flags.
+var (
+	websiteFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of website.",
+		},
+		cli.StringFlag{
+			Name:  "index",
+			Value: "index.html",
+			Usage: "Document served for requests of a folder.",
+		},
+		cli.StringFlag{
+			Name:  "error",
+			Usage: "Document served upon errors.",
+		},
+	}
+)
+
+// Manage bucket static website configuration.
+var websiteCmd = cli.Command{
+	Name:   "website",
+	Usage:  "Manage static website hosting of buckets.",
+	Action: mainWebsite,
+	Flags:  append(websiteFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] set|get|remove TARGET
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Serve a bucket as a static website.
+      $ mc {{.Name}} --index index.html --error 404.html set s3/www.example.com
+
+   2. Display static website configuration of a bucket.
+      $ mc {{.Name}} get s3/www.example.com
+
+   3. Stop serving a bucket as a static website.
+      $ mc {{.Name}} remove s3/www.example.com
+`,
+}
+
+// websiteMessage container for static website messages.
+type websiteMessage struct {
+	Status        string `json:"status"`
+	Operation     string `json:"operation"`
+	URL           string `json:"url"`
+	IndexDocument string `json:"indexDocument,omitempty"`
+	ErrorDocument string `json:"errorDocument,omitempty"`
+}
+
+// String colorized static website message.
+func (w websiteMessage) String() string {
+	switch w.Operation {
+	case "set":
+		return console.Colorize("Website", "‘"+w.URL+"’ is now served as a static website.")
+	case "remove":
+		return console.Colorize("Website", "‘"+w.URL+"’ is no longer served as a static website.")
+	}
+	if w.IndexDocument == "" {
+		return console.Colorize("Website", "‘"+w.URL+"’ is not served as a static website.")
+	}
+	message := console.Colorize("Website", "Index document: ‘"+w.IndexDocument+"’")
+	if w.ErrorDocument != "" {
+		message += "\n" + console.Colorize("Website", "Error document: ‘"+w.ErrorDocument+"’")
+	}
+	return message
+}
+
+// JSON jsonified static website message.
+func (w websiteMessage) JSON() string {
+	w.Status = "success"
+	websiteMessageBytes, e := json.Marshal(w)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(websiteMessageBytes)
+}
+
+// validateWebsite - reject what S3 would reject, before sending anything.
+func validateWebsite(website client.Website) *probe.Error {
+	if website.IndexDocument == "" {
+		return errInvalidWebsite("index document cannot be empty.").Trace()
+	}
+	if strings.Contains(website.IndexDocument, "/") {
+		return errInvalidWebsite("index document ‘" + website.IndexDocument + "’ cannot contain a ‘/’.").Trace(website.IndexDocument)
+	}
+	if strings.HasPrefix(website.ErrorDocument, "/") {
+		return errInvalidWebsite("error document ‘" + website.ErrorDocument + "’ must be an object name relative to the bucket.").Trace(website.ErrorDocument)
+	}
+	return nil
+}
+
+// checkWebsiteSyntax - validate all the passed arguments.
+func checkWebsiteSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "website", 1) // last argument is exit code.
+	}
+	switch args.First() {
+	case "set":
+		website := client.Website{IndexDocument: ctx.String("index"), ErrorDocument: ctx.String("error")}
+		fatalIf(validateWebsite(website).Trace(website.IndexDocument, website.ErrorDocument), "Unable to validate website configuration.")
+	case "get", "remove":
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "website", 1) // last argument is exit code.
+	}
+	if strings.TrimSpace(args.Get(1)) == "" {
+		fatalIf(errInvalidArgument().Trace(args...), "Unable to validate empty argument.")
+	}
+}
+
+// mainWebsite - is a handler for mc website command
+func mainWebsite(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'website' cli arguments.
+	checkWebsiteSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Website", color.New(color.FgGreen, color.Bold))
+
+	args := ctx.Args()
+	operation := args.First()
+
+	targetURL, err := getAliasURL(args.Get(1))
+	fatalIf(err.Trace(args...), "Unable to parse argument ‘"+args.Get(1)+"’.")
+	clnt, err := url2Client(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+	switch operation {
+	case "set":
+		website := client.Website{IndexDocument: ctx.String("index"), ErrorDocument: ctx.String("error")}
+		err = clnt.SetWebsite(globalContext, website)
+		fatalIf(err.Trace(targetURL), "Unable to set website configuration of ‘"+targetURL+"’.")
+		printMsg(websiteMessage{Operation: operation, URL: targetURL})
+	case "get":
+		website, err := clnt.GetWebsite(globalContext)
+		fatalIf(err.Trace(targetURL), "Unable to get website configuration of ‘"+targetURL+"’.")
+		printMsg(websiteMessage{
+			Operation:     operation,
+			URL:           targetURL,
+			IndexDocument: website.IndexDocument,
+			ErrorDocument: website.ErrorDocument,
+		})
+	case "remove":
+		err = clnt.DeleteWebsite(globalContext)
+		fatalIf(err.Trace(targetURL), "Unable to remove website configuration of ‘"+targetURL+"’.")
+		printMsg(websiteMessage{Operation: operation, URL: targetURL})
+	}
+}